@@ -9,6 +9,7 @@ import (
 
 	"github.com/suprt/planica_bi/backend/internal/cache"
 	"github.com/suprt/planica_bi/backend/internal/config"
+	"github.com/suprt/planica_bi/backend/internal/connectors"
 	"github.com/suprt/planica_bi/backend/internal/cron"
 	"github.com/suprt/planica_bi/backend/internal/database"
 	"github.com/suprt/planica_bi/backend/internal/integrations"
@@ -87,39 +88,64 @@ func main() {
 	counterRepo := repositories.NewCounterRepository(db, cacheClient) // Cached - only changes on manual admin actions
 	goalRepo := repositories.NewGoalRepository(db, cacheClient)
 	seoRepo := repositories.NewSEORepository(db)
-
-	// Initialize integration clients
-	// Note: OAuth token may be empty initially, clients will handle this
-	metricaClient := integrations.NewYandexMetricaClient(cfg.YandexOAuthToken)
-	directClient := integrations.NewYandexDirectClient(
-		cfg.YandexOAuthToken,
-		"", // clientLogin will be set per project
-		cfg.YandexDirectSandbox,
-	)
+	credentialRepo := repositories.NewOAuthCredentialRepository(db)
+	backfillRepo := repositories.NewBackfillRepository(db)
+	syncRunRepo := repositories.NewSyncRunRepository(db)
+	visitRepo := repositories.NewMetricaVisitRepository(db)
+	rateRepo := repositories.NewExchangeRateRepository(db)
+	channelRepo := repositories.NewChannelRepository(db)
+	vkAdsRepo := repositories.NewVKAdsRepository(db)
+	crmRepo := repositories.NewCRMRepository(db)
 
 	// Initialize services
+	// OAuth tokens are stored per project in DB; YANDEX_OAUTH_TOKEN is used as a fallback
+	oauthClient := integrations.NewYandexOAuthClient(cfg.YandexClientID, cfg.YandexClientSecret)
+	credentialService := services.NewOAuthCredentialService(credentialRepo, directRepo, oauthClient, cfg.AppKey, cfg.YandexOAuthToken)
 	projectService := services.NewProjectService(projectRepo)
-	reportService := services.NewReportService(metricsRepo, directRepo, seoRepo, projectRepo, cfg)
+	// Without a provider only manually entered exchange rates are used
+	var rateProvider services.ExchangeRateProvider
+	if cfg.ExchangeRateProvider == integrations.ExchangeRateProviderCBR {
+		rateProvider = integrations.NewCBRClient()
+	}
+	rateService := services.NewExchangeRateService(rateRepo, rateProvider)
+	reportService := services.NewReportService(metricsRepo, directRepo, seoRepo, projectRepo, channelRepo, crmRepo, rateService, cfg)
 	syncService := services.NewSyncService(
 		projectRepo,
 		metricsRepo,
 		directRepo,
 		counterRepo,
 		goalRepo,
-		metricaClient,
-		directClient,
+		seoRepo,
+		syncRunRepo,
+		credentialService,
+		rateService,
+		cfg.YandexDirectSandbox,
+		cfg.YandexDefaultCurrency,
 	)
 	goalService := services.NewGoalService(goalRepo, counterRepo)
 	directService := services.NewDirectService(directRepo)
 	counterService := services.NewCounterService(counterRepo)
-	metricsService := services.NewMetricsService(metricsRepo)
+	metricsService := services.NewMetricsService(metricsRepo, directRepo)
 	marketingService := services.NewMarketingService(directRepo)
+	webmasterService := services.NewWebmasterService(seoRepo, credentialService)
 	authService := services.NewAuthService(
 		userRepo,
 		cfg.JWTSecret,
 		time.Duration(cfg.JWTExpiry)*time.Hour,
 	)
 	userService := services.NewUserService(userRepo)
+	backfillService := services.NewBackfillService(backfillRepo, projectRepo)
+	// Additional data sources are registered here; queue, cron and sync history pick them up from the registry
+	connectorRegistry := connectors.NewRegistry()
+	vkAdsService := services.NewVKAdsService(vkAdsRepo, cfg.AppKey)
+	if err := connectorRegistry.Register(vkAdsService); err != nil {
+		log.Fatal("Failed to register VK Ads connector", zap.Error(err))
+	}
+	connectorService := services.NewConnectorService(connectorRegistry, channelRepo, projectRepo, syncRunRepo, rateService)
+	manualCostService := services.NewManualCostService(channelRepo)
+	crmService := services.NewCRMService(crmRepo, directRepo)
+	syncRunService := services.NewSyncRunService(syncRunRepo, connectorRegistry)
+	logsService := services.NewMetricaLogsService(counterRepo, visitRepo, credentialService, cfg.MetricaLogsFields)
 
 	// Initialize queue client
 	queueClient, err := queue.NewClient(cfg)
@@ -129,7 +155,7 @@ func main() {
 	defer queueClient.Close()
 
 	// Initialize queue worker
-	worker, err := queue.NewWorker(cfg, syncService, reportService, cacheClient)
+	worker, err := queue.NewWorker(cfg, syncService, reportService, credentialService, backfillService, logsService, rateService, connectorService, cacheClient)
 	if err != nil {
 		log.Fatal("Failed to initialize queue worker", zap.Error(err))
 	}
@@ -143,9 +169,13 @@ func main() {
 	}()
 
 	// Initialize cron scheduler
-	scheduler := cron.NewScheduler(queueClient, projectService)
+	scheduler := cron.NewScheduler(queueClient, projectService, connectorRegistry)
 	scheduler.StartDailySync()
 	scheduler.StartMonthlyFinalization()
+	scheduler.StartTokenRefresh()
+	if rateProvider != nil {
+		scheduler.StartRatesFetch()
+	}
 	scheduler.Start()
 	defer scheduler.Stop()
 
@@ -164,6 +194,14 @@ func main() {
 		marketingService,
 		authService,
 		userService,
+		credentialService,
+		webmasterService,
+		vkAdsService,
+		manualCostService,
+		crmService,
+		backfillService,
+		syncRunService,
+		rateService,
 		userRepo,
 		cacheClient,
 	)
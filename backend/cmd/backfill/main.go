@@ -0,0 +1,111 @@
+// Command backfill enqueues historical Metrica/Direct sync of a project for a range of months
+//
+// Usage:
+//
+//	backfill -project 1 -from 2024-01 -to 2024-06 [-sources metrica,direct] [-wait]
+//
+// Tasks are processed by the queue worker of the API server; with -wait the command
+// prints job progress until all tasks are finished.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/config"
+	"github.com/suprt/planica_bi/backend/internal/database"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/queue"
+	"github.com/suprt/planica_bi/backend/internal/repositories"
+	"github.com/suprt/planica_bi/backend/internal/services"
+)
+
+func main() {
+	projectID := flag.Uint("project", 0, "project ID")
+	from := flag.String("from", "", "first month, YYYY-MM")
+	to := flag.String("to", "", "last month, YYYY-MM")
+	sources := flag.String("sources", "", "comma-separated sources: metrica,direct (default: all)")
+	wait := flag.Bool("wait", false, "wait until all tasks are finished and print progress")
+	flag.Parse()
+
+	if *projectID == 0 || *from == "" || *to == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(uint(*projectID), *from, *to, splitSources(*sources), *wait); err != nil {
+		fmt.Fprintln(os.Stderr, "backfill:", err)
+		os.Exit(1)
+	}
+}
+
+// run creates backfill job and enqueues its tasks
+func run(projectID uint, from, to string, sources []string, wait bool) error {
+	cfg := config.Load()
+	ctx := context.Background()
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	backfillRepo := repositories.NewBackfillRepository(db)
+	backfillService := services.NewBackfillService(backfillRepo, repositories.NewProjectRepository(db))
+
+	queueClient, err := queue.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize queue client: %w", err)
+	}
+	defer queueClient.Close()
+
+	job, months, err := backfillService.CreateJob(ctx, projectID, from, to, sources)
+	if err != nil {
+		return err
+	}
+
+	enqueued, err := queueClient.EnqueueBackfillTasks(job, months)
+	if err != nil {
+		if recordErr := backfillService.RecordNotEnqueued(ctx, job.ID, job.TotalTasks-enqueued); recordErr != nil {
+			fmt.Fprintln(os.Stderr, "backfill: failed to record not enqueued tasks:", recordErr)
+		}
+		return fmt.Errorf("failed to enqueue tasks (%d of %d enqueued): %w", enqueued, job.TotalTasks, err)
+	}
+
+	fmt.Printf("Backfill job %d: %d tasks enqueued for %s..%s (%s)\n", job.ID, enqueued, job.FromMonth, job.ToMonth, job.Sources)
+	if !wait {
+		return nil
+	}
+
+	for {
+		time.Sleep(10 * time.Second)
+
+		job, err = backfillService.GetJob(ctx, projectID, job.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d/%d completed, %d failed\n", job.Status, job.CompletedTasks, job.TotalTasks, job.FailedTasks)
+
+		if job.Status != models.BackfillStatusRunning {
+			if job.Status == models.BackfillStatusFailed {
+				return fmt.Errorf("%d of %d tasks failed", job.FailedTasks, job.TotalTasks)
+			}
+			return nil
+		}
+	}
+}
+
+// splitSources parses comma-separated sources flag
+func splitSources(value string) []string {
+	var sources []string
+	for _, source := range strings.Split(value, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
@@ -31,6 +31,8 @@ type Config struct {
 	YandexDefaultCurrency string
 	YandexDirectSandbox   bool // Use sandbox environment for Yandex Direct API
 	DefaultTimezone       string
+	MetricaLogsFields     []string // Field names loaded from Metrica Logs API (default: services.DefaultMetricaLogFields)
+	ExchangeRateProvider  string   // Source of daily exchange rates: "cbr" or "none" for manual rates only
 
 	JWTSecret    string // Secret key for JWT tokens
 	JWTExpiry    int    // JWT token expiry in hours (default 24)
@@ -77,6 +79,8 @@ func Load() *Config {
 		YandexDefaultCurrency: getEnv("YANDEX_DEFAULT_CURRENCY", "RUB"),
 		YandexDirectSandbox:   getEnv("YANDEX_DIRECT_SANDBOX", "false") == "true", // Use sandbox for testing
 		DefaultTimezone:       getEnv("DEFAULT_TIMEZONE", "Europe/Moscow"),
+		MetricaLogsFields:     getEnvList("METRICA_LOGS_FIELDS"), // e.g. client_id,start_url,traffic_source,goals
+		ExchangeRateProvider:  getEnv("EXCHANGE_RATE_PROVIDER", "cbr"),
 		JWTSecret:             getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 		JWTExpiry:             getEnvInt("JWT_EXPIRY", 24), // Default 24 hours
 		OllamaAPIKey:          getEnv("OLLAMA_API_KEY", ""),
@@ -106,6 +110,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList parses comma-separated value, empty items are skipped
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -0,0 +1,93 @@
+// Package connectors defines pluggable data sources and their registry
+// A connector loads metrics of a project for a month and normalizes them into common rows.
+// The sync service stores the rows and records runs in sync history; queue and cron
+// find connectors in the registry, so a new source only implements Connector and is registered
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotConfigured is returned by Credentials when a project does not use the source
+var ErrNotConfigured = errors.New("source is not configured for the project")
+
+// Connector is a data source synced into common metric rows
+type Connector interface {
+	// Source is a unique name of the connector used in queue tasks and sync history, e.g. "vk_ads"
+	Source() string
+	// Periods describes months the source can load
+	Periods() PeriodSupport
+	// Credentials resolves access to the source for a project, ErrNotConfigured if the project does not use it
+	Credentials(ctx context.Context, projectID uint) (*Credentials, error)
+	// Fetch loads raw data of a month from the source
+	Fetch(ctx context.Context, credentials *Credentials, period Period) (RawData, error)
+	// Normalize converts raw data returned by Fetch into metric rows
+	Normalize(raw RawData) ([]MetricRow, error)
+}
+
+// RawData is a connector-specific payload passed from Fetch to Normalize
+type RawData interface{}
+
+// Credentials are access parameters of a project for a source
+type Credentials struct {
+	Token    string            // Access token or client secret, empty for sources without API
+	Account  string            // Account of the project in the source, e.g. advertiser ID
+	Currency string            // Currency of costs of the account, empty means the project currency
+	Settings map[string]string // Source-specific settings
+}
+
+// Period is a calendar month to sync
+type Period struct {
+	Year  int
+	Month int
+}
+
+// Range returns the first and the last day of the month
+func (p Period) Range() (time.Time, time.Time) {
+	start := time.Date(p.Year, time.Month(p.Month), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, -1)
+}
+
+// String formats the period as YYYY-MM
+func (p Period) String() string {
+	return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+}
+
+// PeriodSupport describes months a source can load
+type PeriodSupport struct {
+	MaxMonthsBack int  // How many past months the source keeps, 0 means no limit
+	CurrentMonth  bool // Whether the current incomplete month can be loaded
+}
+
+// Check returns an error if the period cannot be loaded from the source
+func (s PeriodSupport) Check(period Period, now time.Time) error {
+	if period.Month < 1 || period.Month > 12 {
+		return fmt.Errorf("invalid period: %s", period)
+	}
+
+	monthsBack := (now.Year()-period.Year)*12 + int(now.Month()) - period.Month
+	switch {
+	case monthsBack < 0:
+		return fmt.Errorf("invalid period: %s is in the future", period)
+	case monthsBack == 0 && !s.CurrentMonth:
+		return fmt.Errorf("invalid period: %s is not finished yet", period)
+	case s.MaxMonthsBack > 0 && monthsBack > s.MaxMonthsBack:
+		return fmt.Errorf("invalid period: %s is older than %d months", period, s.MaxMonthsBack)
+	}
+	return nil
+}
+
+// MetricRow is a normalized row of a source: metrics of a campaign for a day
+type MetricRow struct {
+	Date         time.Time
+	Channel      string // Channel of the row in reports, the source name if empty
+	CampaignID   string
+	CampaignName string
+	Impressions  int
+	Clicks       int
+	Cost         float64 // In the currency of Credentials
+	Conversions  *int
+}
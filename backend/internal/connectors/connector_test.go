@@ -0,0 +1,80 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// stubConnector implements Connector without data
+type stubConnector struct {
+	source string
+}
+
+func (c stubConnector) Source() string         { return c.source }
+func (c stubConnector) Periods() PeriodSupport { return PeriodSupport{} }
+func (c stubConnector) Credentials(ctx context.Context, projectID uint) (*Credentials, error) {
+	return nil, ErrNotConfigured
+}
+func (c stubConnector) Fetch(ctx context.Context, credentials *Credentials, period Period) (RawData, error) {
+	return nil, nil
+}
+func (c stubConnector) Normalize(raw RawData) ([]MetricRow, error) { return nil, nil }
+
+// TestRegistry_Register tests source validation and registration order
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+
+	for _, source := range []string{"vk_ads", "manual"} {
+		if err := registry.Register(stubConnector{source: source}); err != nil {
+			t.Fatalf("Register(%q) failed: %v", source, err)
+		}
+	}
+	for _, source := range []string{"vk_ads", "direct", "VK", "", "a_very_long_source_name"} {
+		if err := registry.Register(stubConnector{source: source}); err == nil {
+			t.Errorf("Expected Register(%q) to fail", source)
+		}
+	}
+
+	sources := registry.Sources()
+	if len(sources) != 2 || sources[0] != "vk_ads" || sources[1] != "manual" {
+		t.Errorf("Expected sources [vk_ads manual], got %v", sources)
+	}
+	if _, ok := registry.Get("manual"); !ok {
+		t.Error("Expected manual connector to be registered")
+	}
+
+	var empty *Registry
+	if _, ok := empty.Get("manual"); ok || len(empty.Sources()) != 0 {
+		t.Error("Expected nil registry to have no connectors")
+	}
+}
+
+// TestPeriodSupport_Check tests supported months of a source
+func TestPeriodSupport_Check(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	support := PeriodSupport{MaxMonthsBack: 12}
+
+	tests := []struct {
+		period  Period
+		wantErr bool
+	}{
+		{Period{Year: 2025, Month: 2}, false},
+		{Period{Year: 2024, Month: 3}, false},
+		{Period{Year: 2024, Month: 2}, true},
+		{Period{Year: 2025, Month: 3}, true},
+		{Period{Year: 2025, Month: 4}, true},
+		{Period{Year: 2025, Month: 13}, true},
+	}
+
+	for _, tt := range tests {
+		err := support.Check(tt.period, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Check(%s) error = %v, wantErr %v", tt.period, err, tt.wantErr)
+		}
+	}
+
+	if err := (PeriodSupport{CurrentMonth: true}).Check(Period{Year: 2025, Month: 3}, now); err != nil {
+		t.Errorf("Expected current month to be supported, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package connectors
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// sourcePattern limits source names to what fits sync history and queue task payloads
+var sourcePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+// reservedSources are synced by SyncService itself and cannot be taken by connectors
+var reservedSources = map[string]bool{
+	"metrica":   true,
+	"direct":    true,
+	"webmaster": true,
+}
+
+// Registry keeps connectors available to sync, queue and cron
+type Registry struct {
+	mu         sync.RWMutex
+	connectors map[string]Connector
+	order      []string
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		connectors: make(map[string]Connector),
+	}
+}
+
+// Register adds a connector; its source must be unique
+func (r *Registry) Register(connector Connector) error {
+	source := connector.Source()
+	if !sourcePattern.MatchString(source) {
+		return fmt.Errorf("invalid connector source %q: expected lowercase name up to 20 characters", source)
+	}
+	if reservedSources[source] {
+		return fmt.Errorf("connector source %q is reserved", source)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.connectors[source]; ok {
+		return fmt.Errorf("connector %q is already registered", source)
+	}
+	r.connectors[source] = connector
+	r.order = append(r.order, source)
+	return nil
+}
+
+// Get returns the connector of a source
+func (r *Registry) Get(source string) (Connector, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	connector, ok := r.connectors[source]
+	return connector, ok
+}
+
+// Sources returns sources of registered connectors in registration order
+func (r *Registry) Sources() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"github.com/suprt/planica_bi/backend/internal/connectors"
 	"github.com/suprt/planica_bi/backend/internal/logger"
 	"github.com/suprt/planica_bi/backend/internal/queue"
 	"github.com/suprt/planica_bi/backend/internal/services"
@@ -16,10 +17,11 @@ type Scheduler struct {
 	cron           *cron.Cron
 	queueClient    *queue.Client
 	projectService *services.ProjectService
+	registry       *connectors.Registry // Connector sources are synced along with Yandex sources
 }
 
 // NewScheduler creates a new scheduler
-func NewScheduler(queueClient *queue.Client, projectService *services.ProjectService) *Scheduler {
+func NewScheduler(queueClient *queue.Client, projectService *services.ProjectService, registry *connectors.Registry) *Scheduler {
 	// Create cron with timezone support (MSK = Europe/Moscow)
 	loc, err := time.LoadLocation("Europe/Moscow")
 	if err != nil {
@@ -36,6 +38,7 @@ func NewScheduler(queueClient *queue.Client, projectService *services.ProjectSer
 		cron:           c,
 		queueClient:    queueClient,
 		projectService: projectService,
+		registry:       registry,
 	}
 }
 
@@ -77,6 +80,44 @@ func (s *Scheduler) StartMonthlyFinalization() {
 	}
 }
 
+// StartTokenRefresh starts OAuth token refresh and health check task
+// Runs every day at 01:00 MSK, before the daily sync
+func (s *Scheduler) StartTokenRefresh() {
+	// Schedule: daily at 01:00 MSK (0 0 1 * * *)
+	_, err := s.cron.AddFunc("0 0 1 * * *", func() {
+		s.runTokenRefresh()
+	})
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Fatal("Failed to schedule token refresh", zap.Error(err))
+		}
+		return
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Token refresh scheduled", zap.String("schedule", "01:00 MSK daily"))
+	}
+}
+
+// StartRatesFetch starts daily exchange rates loading
+// Runs every day at 00:30 MSK, before the daily sync converts costs of foreign currency accounts
+func (s *Scheduler) StartRatesFetch() {
+	// Schedule: daily at 00:30 MSK (0 30 0 * * *)
+	_, err := s.cron.AddFunc("0 30 0 * * *", func() {
+		s.runRatesFetch()
+	})
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Fatal("Failed to schedule exchange rates fetch", zap.Error(err))
+		}
+		return
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Exchange rates fetch scheduled", zap.String("schedule", "00:30 MSK daily"))
+	}
+}
+
 // Start starts the cron scheduler
 func (s *Scheduler) Start() {
 	s.cron.Start()
@@ -132,6 +173,11 @@ func (s *Scheduler) runDailySync() {
 		}
 
 		successCount++
+
+		enqueued, failed := s.enqueueConnectorSyncs(project.ID, year, month)
+		successCount += enqueued
+		errorCount += failed
+
 		if logger.Log != nil {
 			logger.Log.Info("Enqueued sync task for project",
 				zap.Uint("project_id", project.ID),
@@ -203,6 +249,23 @@ func (s *Scheduler) runMonthlyFinalization() {
 			successCount++
 		}
 
+		_, err = s.queueClient.EnqueueSyncWebmasterTask(project.ID, year, month)
+		if err != nil {
+			errorCount++
+			if logger.Log != nil {
+				logger.Log.Error("Failed to enqueue Webmaster finalization task",
+					zap.Uint("project_id", project.ID),
+					zap.Error(err),
+				)
+			}
+		} else {
+			successCount++
+		}
+
+		enqueued, failed := s.enqueueConnectorSyncs(project.ID, year, month)
+		successCount += enqueued
+		errorCount += failed
+
 		if logger.Log != nil {
 			logger.Log.Info("Enqueued finalization tasks for project",
 				zap.Uint("project_id", project.ID),
@@ -222,3 +285,61 @@ func (s *Scheduler) runMonthlyFinalization() {
 		)
 	}
 }
+
+// enqueueConnectorSyncs enqueues sync tasks of registered connectors that support the month
+// Returns numbers of enqueued and failed tasks
+func (s *Scheduler) enqueueConnectorSyncs(projectID uint, year, month int) (int, int) {
+	period := connectors.Period{Year: year, Month: month}
+	now := time.Now()
+
+	enqueued, failed := 0, 0
+	for _, source := range s.registry.Sources() {
+		connector, ok := s.registry.Get(source)
+		if !ok || connector.Periods().Check(period, now) != nil {
+			continue
+		}
+
+		if _, err := s.queueClient.EnqueueSyncConnectorTask(source, projectID, year, month); err != nil {
+			failed++
+			if logger.Log != nil {
+				logger.Log.Error("Failed to enqueue connector sync task",
+					zap.String("source", source),
+					zap.Uint("project_id", projectID),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+		enqueued++
+	}
+	return enqueued, failed
+}
+
+// runTokenRefresh enqueues OAuth token refresh task
+func (s *Scheduler) runTokenRefresh() {
+	if _, err := s.queueClient.EnqueueRefreshOAuthTask(); err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to enqueue OAuth token refresh task", zap.Error(err))
+		}
+		return
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Enqueued OAuth token refresh task")
+	}
+}
+
+// runRatesFetch enqueues exchange rates fetch task for today
+func (s *Scheduler) runRatesFetch() {
+	date := time.Now().Format("2006-01-02")
+	if _, err := s.queueClient.EnqueueFetchRatesTask(date); err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to enqueue exchange rates fetch task", zap.Error(err))
+		}
+		return
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Enqueued exchange rates fetch task", zap.String("date", date))
+	}
+}
@@ -96,6 +96,10 @@ func AutoMigrate() error {
 
 	applogger.Log.Info("Starting GORM auto-migration...")
 
+	if err := dedupeGoals(); err != nil {
+		return fmt.Errorf("failed to remove duplicate goals: %w", err)
+	}
+
 	// Run GORM auto migrations for models
 	err := DB.AutoMigrate(
 		&models.Project{},
@@ -107,9 +111,28 @@ func AutoMigrate() error {
 		&models.Goal{},
 		&models.MetricsMonthly{},
 		&models.MetricsAgeMonthly{},
+		&models.MetricsBreakdownMonthly{},
 		&models.DirectCampaignMonthly{},
 		&models.DirectTotalsMonthly{},
 		&models.SEOQueriesMonthly{},
+		&models.OAuthCredential{},
+		&models.MetricsDaily{},
+		&models.DirectCampaignDaily{},
+		&models.DirectAdGroupMonthly{},
+		&models.DirectKeywordMonthly{},
+		&models.MetricsTrafficSourceMonthly{},
+		&models.WebmasterHost{},
+		&models.MetricsGoalMonthly{},
+		&models.BackfillJob{},
+		&models.SyncRun{},
+		&models.MetricaVisit{},
+		&models.ExchangeRate{},
+		&models.ChannelCampaignMonthly{},
+		&models.VKAdsAccount{},
+		&models.ManualUpload{},
+		&models.CRMDeal{},
+		&models.CRMWebhook{},
+		&models.MetricsCampaignRevenueMonthly{},
 	)
 
 	if err != nil {
@@ -120,6 +143,25 @@ func AutoMigrate() error {
 	return nil
 }
 
+// dedupeGoals removes duplicate goals of a counter left by concurrent syncs before the unique index
+// of (counter_id, goal_id) is created. The oldest row is kept with the conversion flag of any duplicate
+func dedupeGoals() error {
+	if !DB.Migrator().HasTable(&models.Goal{}) {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE goals g
+			JOIN (SELECT MIN(id) AS id, MAX(is_conversion) AS is_conversion
+				FROM goals GROUP BY counter_id, goal_id HAVING COUNT(*) > 1) d ON g.id = d.id
+			SET g.is_conversion = d.is_conversion`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`DELETE g FROM goals g
+			JOIN goals k ON k.counter_id = g.counter_id AND k.goal_id = g.goal_id AND k.id < g.id`).Error
+	})
+}
+
 // SeedData inserts seed data into the database
 func SeedData() error {
 	if DB == nil {
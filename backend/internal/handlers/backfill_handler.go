@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/queue"
+	"github.com/suprt/planica_bi/backend/internal/services"
+	"go.uber.org/zap"
+)
+
+// BackfillServiceInterface defines methods for backfill job operations
+type BackfillServiceInterface interface {
+	CreateJob(ctx context.Context, projectID uint, from, to string, sources []string) (*models.BackfillJob, []services.BackfillMonth, error)
+	GetJob(ctx context.Context, projectID uint, id uint) (*models.BackfillJob, error)
+	GetJobsByProject(ctx context.Context, projectID uint) ([]*models.BackfillJob, error)
+	RecordNotEnqueued(ctx context.Context, jobID uint, count int) error
+}
+
+// BackfillHandler handles HTTP requests for historical sync
+type BackfillHandler struct {
+	backfillService BackfillServiceInterface
+	queueClient     *queue.Client
+}
+
+// NewBackfillHandler creates a new backfill handler
+func NewBackfillHandler(backfillService BackfillServiceInterface, queueClient *queue.Client) *BackfillHandler {
+	return &BackfillHandler{
+		backfillService: backfillService,
+		queueClient:     queueClient,
+	}
+}
+
+// StartBackfillRequest represents request body for starting a backfill
+type StartBackfillRequest struct {
+	From    string   `json:"from"`    // YYYY-MM
+	To      string   `json:"to"`      // YYYY-MM
+	Sources []string `json:"sources"` // metrica, direct; empty means both
+}
+
+// StartBackfill handles POST /api/projects/:id/backfill
+// Creates a job and enqueues Metrica/Direct sync tasks for every month of the range
+func (h *BackfillHandler) StartBackfill(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	var req StartBackfillRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+
+	job, months, err := h.backfillService.CreateJob(ctx, uint(projectID), req.From, req.To, req.Sources)
+	if err != nil {
+		if err.Error() == "project not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if isBackfillValidationError(err) {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return err
+	}
+
+	enqueued, err := h.queueClient.EnqueueBackfillTasks(job, months)
+	if err != nil {
+		// Tasks that were not enqueued will never finish, count them as failed so the job can complete
+		if recordErr := h.backfillService.RecordNotEnqueued(ctx, job.ID, job.TotalTasks-enqueued); recordErr != nil && logger.Log != nil {
+			logger.Log.Warn("Failed to record not enqueued backfill tasks",
+				zap.Uint("backfill_job_id", job.ID),
+				zap.Error(recordErr),
+			)
+		}
+		return echo.NewHTTPError(500, "Failed to enqueue backfill tasks: "+err.Error())
+	}
+
+	return c.JSON(202, job)
+}
+
+// GetBackfills handles GET /api/projects/:id/backfill
+func (h *BackfillHandler) GetBackfills(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	jobs, err := h.backfillService.GetJobsByProject(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, jobs)
+}
+
+// GetBackfill handles GET /api/projects/:id/backfill/:jobId
+// Returns job with aggregate progress: total, completed and failed tasks
+func (h *BackfillHandler) GetBackfill(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid backfill job ID")
+	}
+
+	job, err := h.backfillService.GetJob(ctx, uint(projectID), uint(jobID))
+	if err != nil {
+		if err.Error() == "backfill job not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(200, job)
+}
+
+// isBackfillValidationError checks whether error is caused by invalid request parameters
+func isBackfillValidationError(err error) bool {
+	message := err.Error()
+	return strings.HasPrefix(message, "from ") ||
+		strings.HasPrefix(message, "to ") ||
+		strings.HasPrefix(message, "invalid source") ||
+		strings.HasPrefix(message, "backfill range")
+}
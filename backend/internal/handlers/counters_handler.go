@@ -5,7 +5,10 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/logger"
 	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/queue"
+	"go.uber.org/zap"
 )
 
 // CounterServiceInterface defines methods for counter operations
@@ -17,12 +20,15 @@ type CounterServiceInterface interface {
 // CountersHandler handles HTTP requests for Yandex counters
 type CountersHandler struct {
 	counterService CounterServiceInterface
+	queueClient    *queue.Client
 }
 
 // NewCountersHandler creates a new counters handler
-func NewCountersHandler(counterService CounterServiceInterface) *CountersHandler {
+// queueClient is used to start goal discovery for new counters (may be nil)
+func NewCountersHandler(counterService CounterServiceInterface, queueClient *queue.Client) *CountersHandler {
 	return &CountersHandler{
 		counterService: counterService,
+		queueClient:    queueClient,
 	}
 }
 
@@ -55,6 +61,16 @@ func (h *CountersHandler) AddCounter(c echo.Context) error {
 		return err
 	}
 
+	// Load goals of the new counter in background; failure does not affect the response
+	if h.queueClient != nil {
+		if _, err := h.queueClient.EnqueueSyncGoalsTask(counter.ID); err != nil && logger.Log != nil {
+			logger.Log.Warn("Failed to enqueue goals sync task",
+				zap.Uint("counter_id", counter.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	return c.JSON(201, counter)
 }
 
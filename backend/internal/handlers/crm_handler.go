@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/services"
+)
+
+// crmMaxBodySize limits size of deal files and JSON bodies
+const crmMaxBodySize = 5 << 20 // 5 MB
+
+// multipartOverhead is allowed on top of the file size limit for multipart headers and boundaries
+const multipartOverhead = 64 << 10 // 64 KB
+
+// CRMServiceInterface defines methods for CRM deals import
+type CRMServiceInterface interface {
+	ImportDeals(ctx context.Context, projectID uint, inputs []services.CRMDealInput) (*services.CRMImportResult, error)
+	ImportDealsFile(ctx context.Context, projectID uint, fileName string, file io.Reader) (*services.CRMImportResult, error)
+	CreateWebhookToken(ctx context.Context, projectID uint) (string, error)
+	ImportWebhook(ctx context.Context, token string, inputs []services.CRMDealInput) (*services.CRMImportResult, error)
+}
+
+// CRMHandler handles HTTP requests for CRM deals
+type CRMHandler struct {
+	crmService CRMServiceInterface
+}
+
+// NewCRMHandler creates a new CRM handler
+func NewCRMHandler(crmService CRMServiceInterface) *CRMHandler {
+	return &CRMHandler{
+		crmService: crmService,
+	}
+}
+
+// ImportDeals handles POST /api/projects/:id/crm/deals
+// Body is an array of deals or {"deals": [...]}; deals are unique by deal_id, a repeated import updates them
+func (h *CRMHandler) ImportDeals(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	inputs, err := readCRMDeals(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.crmService.ImportDeals(ctx, uint(projectID), inputs)
+	if err != nil {
+		return crmImportError(c, err)
+	}
+
+	return c.JSON(200, result)
+}
+
+// UploadDeals handles POST /api/projects/:id/crm/deals/upload
+// Multipart field "file" is a CSV or XLSX file with columns deal_id, date, status, revenue,
+// utm_source, utm_medium, utm_campaign and campaign_id
+func (h *CRMHandler) UploadDeals(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	fileHeader, err := readFormFile(c, "file", crmMaxBodySize)
+	if err != nil {
+		return err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid file")
+	}
+	defer file.Close()
+
+	result, err := h.crmService.ImportDealsFile(ctx, uint(projectID), fileHeader.Filename, file)
+	if err != nil {
+		return crmImportError(c, err)
+	}
+
+	return c.JSON(200, result)
+}
+
+// CreateWebhookToken handles POST /api/projects/:id/crm/webhook-token
+// Generates a new token of the CRM webhook, the previous token stops working; the token is shown only once
+func (h *CRMHandler) CreateWebhookToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	token, err := h.crmService.CreateWebhookToken(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(201, map[string]string{
+		"token": token,
+		"url":   "/api/webhooks/crm/" + token,
+	})
+}
+
+// Webhook handles POST /api/webhooks/crm/:token (public, authorized by the token)
+// Body is a single deal, an array of deals or {"deals": [...]}
+func (h *CRMHandler) Webhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	inputs, err := readCRMDeals(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.crmService.ImportWebhook(ctx, c.Param("token"), inputs)
+	if err != nil {
+		if err.Error() == "invalid webhook token" {
+			return echo.NewHTTPError(401, "Invalid webhook token")
+		}
+		return crmImportError(c, err)
+	}
+
+	return c.JSON(200, result)
+}
+
+// readCRMDeals decodes deals of a JSON body: an array, {"deals": [...]} or a single deal
+// Returns HTTP errors: 413 for bodies over crmMaxBodySize, 400 for invalid bodies
+func readCRMDeals(c echo.Context) ([]services.CRMDealInput, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, crmMaxBodySize+1))
+	if err != nil {
+		return nil, echo.NewHTTPError(400, "Invalid request body")
+	}
+	if len(body) > crmMaxBodySize {
+		return nil, echo.NewHTTPError(413, "Request body too large, max 5 MB")
+	}
+
+	inputs, err := decodeCRMDeals(bytes.TrimSpace(body))
+	if err != nil {
+		return nil, echo.NewHTTPError(400, "Invalid request body")
+	}
+	return inputs, nil
+}
+
+// readFormFile reads a multipart file field of a body limited to maxSize plus multipart overhead
+// Returns HTTP errors: 413 for larger bodies and files, read no further than the limit, 400 without the field
+func readFormFile(c echo.Context, field string, maxSize int64) (*multipart.FileHeader, error) {
+	tooLarge := echo.NewHTTPError(413, fmt.Sprintf("File is too large, max %d MB", maxSize>>20))
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize+multipartOverhead)
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, tooLarge
+		}
+		return nil, echo.NewHTTPError(400, fmt.Sprintf("Field '%s' is required", field))
+	}
+	if fileHeader.Size > maxSize {
+		return nil, tooLarge
+	}
+	return fileHeader, nil
+}
+
+// decodeCRMDeals decodes deals of a JSON body
+func decodeCRMDeals(body []byte) ([]services.CRMDealInput, error) {
+	if bytes.HasPrefix(body, []byte("[")) {
+		var inputs []services.CRMDealInput
+		if err := json.Unmarshal(body, &inputs); err != nil {
+			return nil, err
+		}
+		return inputs, nil
+	}
+
+	var request struct {
+		Deals []services.CRMDealInput `json:"deals"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, err
+	}
+	if request.Deals != nil {
+		return request.Deals, nil
+	}
+
+	var input services.CRMDealInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		return nil, err
+	}
+	if input.DealID == "" && input.Date == "" && input.Status == "" {
+		return nil, errors.New("no deals")
+	}
+	return []services.CRMDealInput{input}, nil
+}
+
+// crmImportError maps errors of a deals import to responses, row errors are returned with their rows
+func crmImportError(c echo.Context, err error) error {
+	var importErr *services.ImportError
+	if errors.As(err, &importErr) {
+		return c.JSON(400, map[string]interface{}{
+			"error": err.Error(),
+			"rows":  importErr.Rows,
+		})
+	}
+	if strings.HasPrefix(err.Error(), "invalid") {
+		return echo.NewHTTPError(400, err.Error())
+	}
+	return err
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"github.com/suprt/planica_bi/backend/internal/services"
 )
@@ -14,6 +16,8 @@ type DirectServiceInterface interface {
 	CreateAccount(ctx context.Context, account *models.DirectAccount) error
 	GetAccountsByProject(ctx context.Context, projectID uint) ([]*models.DirectAccount, error)
 	GetCampaignsWithMetrics(ctx context.Context, projectID uint) ([]services.CampaignWithMetrics, error)
+	GetCampaignAdGroups(ctx context.Context, projectID uint, campaignID int64, period string, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error)
+	GetCampaignKeywords(ctx context.Context, projectID uint, campaignID int64, period string, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error)
 }
 
 // DirectHandler handles HTTP requests for Direct accounts
@@ -95,3 +99,80 @@ func (h *DirectHandler) GetCampaigns(c echo.Context) error {
 
 	return c.JSON(200, campaigns)
 }
+
+// GetCampaignAdGroups handles GET /api/projects/:id/campaigns/:campaignId/ad-groups
+// Query params: period (YYYY-MM, current month by default), sort (cost, ctr, cpa, clicks, impressions, conversions),
+// order (asc, desc), page, per_page
+func (h *DirectHandler) GetCampaignAdGroups(c echo.Context) error {
+	projectID, campaignID, filter, err := campaignStatsParams(c)
+	if err != nil {
+		return err
+	}
+
+	adGroups, total, err := h.directService.GetCampaignAdGroups(c.Request().Context(), projectID, campaignID,
+		c.QueryParam("period"), filter, middleware.GetPagination(c))
+	if err != nil {
+		return campaignStatsError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"data":  adGroups,
+		"total": total,
+	})
+}
+
+// GetCampaignKeywords handles GET /api/projects/:id/campaigns/:campaignId/keywords
+// Query params: period, sort, order, ad_group_id, page, per_page
+func (h *DirectHandler) GetCampaignKeywords(c echo.Context) error {
+	projectID, campaignID, filter, err := campaignStatsParams(c)
+	if err != nil {
+		return err
+	}
+
+	keywords, total, err := h.directService.GetCampaignKeywords(c.Request().Context(), projectID, campaignID,
+		c.QueryParam("period"), filter, middleware.GetPagination(c))
+	if err != nil {
+		return campaignStatsError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"data":  keywords,
+		"total": total,
+	})
+}
+
+// campaignStatsParams parses path and query params of campaign drill-down endpoints
+func campaignStatsParams(c echo.Context) (uint, int64, models.DirectStatsFilter, error) {
+	filter := models.DirectStatsFilter{
+		Sort:  c.QueryParam("sort"),
+		Order: c.QueryParam("order"),
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, 0, filter, echo.NewHTTPError(400, "Invalid project ID")
+	}
+	campaignID, err := strconv.ParseInt(c.Param("campaignId"), 10, 64)
+	if err != nil {
+		return 0, 0, filter, echo.NewHTTPError(400, "Invalid campaign ID")
+	}
+	if value := c.QueryParam("ad_group_id"); value != "" {
+		filter.AdGroupID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, 0, filter, echo.NewHTTPError(400, "Invalid ad_group_id")
+		}
+	}
+
+	return uint(projectID), campaignID, filter, nil
+}
+
+// campaignStatsError maps drill-down service errors to HTTP errors
+func campaignStatsError(err error) error {
+	if err.Error() == "campaign not found" {
+		return echo.NewHTTPError(404, err.Error())
+	}
+	if strings.HasPrefix(err.Error(), "invalid ") {
+		return echo.NewHTTPError(400, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/queue"
+)
+
+// ExchangeRateServiceInterface defines methods for exchange rates
+type ExchangeRateServiceInterface interface {
+	GetRates(ctx context.Context, filter models.ExchangeRateFilter, pagination *middleware.Pagination) ([]*models.ExchangeRate, int64, error)
+	SetRate(ctx context.Context, base, quote string, date time.Time, rate float64) (*models.ExchangeRate, error)
+}
+
+// ExchangeRateHandler handles HTTP requests for exchange rates
+type ExchangeRateHandler struct {
+	rateService ExchangeRateServiceInterface
+	queueClient *queue.Client
+}
+
+// NewExchangeRateHandler creates a new exchange rate handler
+func NewExchangeRateHandler(rateService ExchangeRateServiceInterface, queueClient *queue.Client) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		rateService: rateService,
+		queueClient: queueClient,
+	}
+}
+
+// SetExchangeRateRequest represents request body for manual rate entry: 1 base = rate quote
+type SetExchangeRateRequest struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Date  string  `json:"date"` // YYYY-MM-DD
+	Rate  float64 `json:"rate"`
+}
+
+// FetchExchangeRatesRequest represents request body for loading provider rates of a date
+type FetchExchangeRatesRequest struct {
+	Date string `json:"date"` // YYYY-MM-DD, today if empty
+}
+
+// GetRates handles GET /api/exchange-rates
+// Query params: base, quote, date_from, date_to (YYYY-MM-DD), page, per_page
+func (h *ExchangeRateHandler) GetRates(c echo.Context) error {
+	filter := models.ExchangeRateFilter{
+		Base:  c.QueryParam("base"),
+		Quote: c.QueryParam("quote"),
+	}
+	if value := c.QueryParam("date_from"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid date_from, expected YYYY-MM-DD")
+		}
+		filter.DateFrom = &date
+	}
+	if value := c.QueryParam("date_to"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid date_to, expected YYYY-MM-DD")
+		}
+		filter.DateTo = &date
+	}
+
+	rates, total, err := h.rateService.GetRates(c.Request().Context(), filter, middleware.GetPagination(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"data":  rates,
+		"total": total,
+	})
+}
+
+// SetRate handles POST /api/exchange-rates
+// Manual rates take precedence over provider rates of the same date
+func (h *ExchangeRateHandler) SetRate(c echo.Context) error {
+	var req SetExchangeRateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid date, expected YYYY-MM-DD")
+	}
+
+	rate, err := h.rateService.SetRate(c.Request().Context(), req.Base, req.Quote, date, req.Rate)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(201, map[string]interface{}{
+		"data": rate,
+	})
+}
+
+// FetchRates handles POST /api/exchange-rates/fetch
+// Enqueues loading of provider rates, e.g. to fill past dates before syncing old months
+func (h *ExchangeRateHandler) FetchRates(c echo.Context) error {
+	var req FetchExchangeRatesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+	if req.Date == "" {
+		req.Date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return echo.NewHTTPError(400, "Invalid date, expected YYYY-MM-DD")
+	}
+
+	taskInfo, err := h.queueClient.EnqueueFetchRatesTask(req.Date)
+	if err != nil {
+		return echo.NewHTTPError(500, "Failed to enqueue exchange rates task: "+err.Error())
+	}
+
+	return c.JSON(202, map[string]interface{}{
+		"message": "Exchange rates task enqueued",
+		"date":    req.Date,
+		"task_id": taskInfo.ID,
+		"queue":   taskInfo.Queue,
+	})
+}
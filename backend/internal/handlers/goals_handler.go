@@ -12,6 +12,7 @@ import (
 type GoalServiceInterface interface {
 	CreateGoal(ctx context.Context, goal *models.Goal) error
 	GetGoalsByProject(ctx context.Context, projectID uint) ([]*models.Goal, error)
+	SetConversion(ctx context.Context, projectID uint, id uint, isConversion bool) (*models.Goal, error)
 }
 
 // GoalsHandler handles HTTP requests for goals
@@ -76,3 +77,38 @@ func (h *GoalsHandler) GetGoals(c echo.Context) error {
 
 	return c.JSON(200, goals)
 }
+
+// UpdateGoal handles PATCH /api/projects/:id/goals/:goalId
+// Only is_conversion can be changed; name and type come from Metrica
+func (h *GoalsHandler) UpdateGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+	goalID, err := strconv.ParseUint(c.Param("goalId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid goal ID")
+	}
+
+	var req struct {
+		IsConversion *bool `json:"is_conversion"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+	if req.IsConversion == nil {
+		return echo.NewHTTPError(400, "is_conversion is required")
+	}
+
+	goal, err := h.goalService.SetConversion(ctx, uint(projectID), uint(goalID), *req.IsConversion)
+	if err != nil {
+		if err.Error() == "goal not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(200, goal)
+}
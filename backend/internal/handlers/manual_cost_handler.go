@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/services"
+)
+
+// manualCostMaxFileSize limits size of uploaded files
+const manualCostMaxFileSize = 5 << 20 // 5 MB
+
+// ManualCostServiceInterface defines methods for manual channel cost uploads
+type ManualCostServiceInterface interface {
+	Import(ctx context.Context, projectID, userID uint, fileName string, file io.Reader) (*models.ManualUpload, error)
+	GetUploads(ctx context.Context, projectID uint) ([]*models.ManualUpload, error)
+}
+
+// ManualCostHandler handles HTTP requests for manual channel costs
+type ManualCostHandler struct {
+	manualCostService ManualCostServiceInterface
+}
+
+// NewManualCostHandler creates a new manual cost handler
+func NewManualCostHandler(manualCostService ManualCostServiceInterface) *ManualCostHandler {
+	return &ManualCostHandler{
+		manualCostService: manualCostService,
+	}
+}
+
+// Upload handles POST /api/projects/:id/manual-costs
+// Multipart field "file" is a CSV or XLSX file with columns channel, month, cost, impressions, clicks, conversions
+// Costs are in the native currency of the project; rows replace earlier uploads of the same channel and month
+func (h *ManualCostHandler) Upload(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+	userID, ok := c.Get("user_id").(uint)
+	if !ok {
+		return echo.NewHTTPError(401, "User not authenticated")
+	}
+
+	fileHeader, err := readFormFile(c, "file", manualCostMaxFileSize)
+	if err != nil {
+		return err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid file")
+	}
+	defer file.Close()
+
+	upload, err := h.manualCostService.Import(ctx, uint(projectID), userID, fileHeader.Filename, file)
+	if err != nil {
+		var importErr *services.ImportError
+		if errors.As(err, &importErr) {
+			return c.JSON(400, map[string]interface{}{
+				"error": err.Error(),
+				"rows":  importErr.Rows,
+			})
+		}
+		if strings.HasPrefix(err.Error(), "invalid file") {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(201, upload)
+}
+
+// GetUploads handles GET /api/projects/:id/manual-costs/uploads
+// Returns the audit of manual uploads: who uploaded which file, its channels, months and total cost
+func (h *ManualCostHandler) GetUploads(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	uploads, err := h.manualCostService.GetUploads(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, uploads)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/suprt/planica_bi/backend/internal/services"
@@ -11,8 +12,12 @@ import (
 // MetricsServiceInterface defines methods for Metrics operations
 type MetricsServiceInterface interface {
 	GetMetricsWithData(ctx context.Context, projectID uint) (*services.MetricsWithData, error)
+	GetDailySeries(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) (*services.DailySeries, error)
 }
 
+// maxDailySeriesDays limits date range of daily series requests
+const maxDailySeriesDays = 366
+
 // MetricsHandler handles HTTP requests for Metrics
 type MetricsHandler struct {
 	metricsService MetricsServiceInterface
@@ -42,3 +47,46 @@ func (h *MetricsHandler) GetMetrics(c echo.Context) error {
 
 	return c.JSON(200, metrics)
 }
+
+// GetDailyMetrics handles GET /api/projects/:id/metrics/daily?date_from=YYYY-MM-DD&date_to=YYYY-MM-DD
+// Defaults to the current month up to today
+func (h *MetricsHandler) GetDailyMetrics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	now := time.Now()
+	dateFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if value := c.QueryParam("date_from"); value != "" {
+		dateFrom, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid date_from (expected YYYY-MM-DD)")
+		}
+	}
+	if value := c.QueryParam("date_to"); value != "" {
+		dateTo, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid date_to (expected YYYY-MM-DD)")
+		}
+	}
+
+	if dateTo.Before(dateFrom) {
+		return echo.NewHTTPError(400, "date_to must not be before date_from")
+	}
+	if dateTo.Sub(dateFrom) > maxDailySeriesDays*24*time.Hour {
+		return echo.NewHTTPError(400, "Date range must not exceed 366 days")
+	}
+
+	series, err := h.metricsService.GetDailySeries(ctx, uint(projectID), dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, series)
+}
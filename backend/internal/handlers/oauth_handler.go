@@ -2,25 +2,57 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/suprt/planica_bi/backend/internal/config"
+	"github.com/suprt/planica_bi/backend/internal/integrations"
 	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/services"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
 	"go.uber.org/zap"
 )
 
 const (
 	yandexOAuthAuthorizeURL = "https://oauth.yandex.ru/authorize"
 	yandexOAuthTokenURL     = "https://oauth.yandex.ru/token"
+	yandexLoginInfoURL      = "https://login.yandex.ru/info"
+
+	// oauthStateTTL limits how long an authorization started by InitiateAuth stays valid
+	oauthStateTTL = time.Hour
 )
 
+// OAuthCredentialServiceInterface defines methods for OAuth credential operations
+type OAuthCredentialServiceInterface interface {
+	SaveToken(ctx context.Context, projectID *uint, token *integrations.OAuthToken) (*models.OAuthCredential, error)
+	BindDirectAccount(ctx context.Context, accountID uint, credentialID uint) error
+	GetProjectCredentials(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error)
+	DeleteCredential(ctx context.Context, projectID uint, id uint) error
+	ResolveToken(ctx context.Context, projectID uint, credentialID *uint) (string, error)
+	GetCredentialsHealth(ctx context.Context, projectID *uint) ([]*models.OAuthCredential, error)
+}
+
+// oauthState is passed through Yandex OAuth as encrypted "state" parameter
+// It tells the callback who started the authorization and which project/Direct account the token belongs to
+type oauthState struct {
+	UserID          uint   `json:"user_id"`
+	Nonce           string `json:"nonce"`
+	ProjectID       *uint  `json:"project_id,omitempty"`
+	DirectAccountID *uint  `json:"direct_account_id,omitempty"`
+	IssuedAt        int64  `json:"issued_at"`
+}
+
 // TokenResponse represents the response from Yandex OAuth token endpoint
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -37,24 +69,66 @@ type TokenErrorResponse struct {
 
 // OAuthHandler handles OAuth authentication with Yandex
 type OAuthHandler struct {
-	cfg *config.Config
+	cfg               *config.Config
+	credentialService OAuthCredentialServiceInterface
+	userRepo          services.UserRepositoryInterface
 }
 
 // NewOAuthHandler creates a new OAuth handler
-func NewOAuthHandler(cfg *config.Config) *OAuthHandler {
+func NewOAuthHandler(cfg *config.Config, credentialService OAuthCredentialServiceInterface, userRepo services.UserRepositoryInterface) *OAuthHandler {
 	return &OAuthHandler{
-		cfg: cfg,
+		cfg:               cfg,
+		credentialService: credentialService,
+		userRepo:          userRepo,
 	}
 }
 
-// InitiateAuth handles GET /api/oauth/yandex
-// Redirects user to Yandex OAuth authorization page
+// InitiateAuth handles GET /api/oauth/yandex (admin only) and GET /api/projects/:id/oauth/yandex
+// Returns URL of Yandex OAuth authorization page, the frontend redirects user to it
+// The project route binds the token to the project, optional query param direct_account_id
+// also binds it to a Direct account; without a project the token is stored as agency-wide credential
 // Documentation: https://yandex.ru/dev/id/doc/ru/concepts/ya-oauth-intro
 func (h *OAuthHandler) InitiateAuth(c echo.Context) error {
 	if h.cfg.YandexClientID == "" {
 		return echo.NewHTTPError(500, "Yandex Client ID is not configured")
 	}
 
+	userID, ok := c.Get("user_id").(uint)
+	if !ok {
+		return echo.NewHTTPError(401, "User not authenticated")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate OAuth nonce: %w", err)
+	}
+
+	state := oauthState{UserID: userID, Nonce: hex.EncodeToString(nonce), IssuedAt: time.Now().Unix()}
+	if projectIDStr := c.Param("id"); projectIDStr != "" {
+		projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid project ID")
+		}
+		id := uint(projectID)
+		state.ProjectID = &id
+	}
+	if accountIDStr := c.QueryParam("direct_account_id"); accountIDStr != "" {
+		if state.ProjectID == nil {
+			return echo.NewHTTPError(400, "Direct account can be bound only from project route")
+		}
+		accountID, err := strconv.ParseUint(accountIDStr, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid Direct account ID")
+		}
+		id := uint(accountID)
+		state.DirectAccountID = &id
+	}
+
+	encodedState, err := h.encodeState(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode OAuth state: %w", err)
+	}
+
 	// Build redirect URI
 	redirectURI := fmt.Sprintf("%s/api/oauth/yandex/callback", h.cfg.AppURL)
 
@@ -63,6 +137,7 @@ func (h *OAuthHandler) InitiateAuth(c echo.Context) error {
 	params.Set("response_type", "code")
 	params.Set("client_id", h.cfg.YandexClientID)
 	params.Set("redirect_uri", redirectURI)
+	params.Set("state", encodedState)
 
 	// Set OAuth scopes from config (space-separated list)
 	// If scopes are not specified, Yandex will use scopes configured during app registration
@@ -82,7 +157,7 @@ func (h *OAuthHandler) InitiateAuth(c echo.Context) error {
 		)
 	}
 
-	return c.Redirect(http.StatusFound, authURL)
+	return c.JSON(200, map[string]string{"url": authURL})
 }
 
 // HandleCallback handles GET /api/oauth/yandex/callback
@@ -143,19 +218,18 @@ func (h *OAuthHandler) HandleCallback(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("%s/dashboard?oauth=error", frontendURL))
 	}
 
-	// Save token to .env file (for MVP)
-	// TODO: In production, save to database instead
-	if err := h.saveTokenToEnv(tokenResp.AccessToken); err != nil {
+	// Save encrypted token to database
+	if err := h.saveToken(ctx, c.QueryParam("state"), tokenResp); err != nil {
 		if logger.Log != nil {
-			logger.Log.Warn("Failed to save OAuth token to .env file",
+			logger.Log.Error("OAuth callback: failed to save token",
 				zap.Error(err),
 			)
 		}
-		// Don't fail the request, but log the warning
-	} else {
-		if logger.Log != nil {
-			logger.Log.Info("OAuth token saved to .env file")
+		frontendURL := h.cfg.FrontendURL
+		if frontendURL == "" {
+			frontendURL = h.cfg.AppURL
 		}
+		return c.Redirect(http.StatusFound, fmt.Sprintf("%s/dashboard?oauth=error", frontendURL))
 	}
 
 	// Log token for debugging
@@ -189,20 +263,58 @@ func (h *OAuthHandler) HandleCallback(c echo.Context) error {
 
 // GetOAuthStatus handles GET /api/oauth/status
 // Returns OAuth authorization status by validating the token with Yandex API
+// Optional query param project_id checks the token resolved for that project, it requires admin role
+// or admin/manager role on the project
+// Admins also get health (valid, expiring, expired, revoked) of stored credentials
 func (h *OAuthHandler) GetOAuthStatus(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	// If no token in config, definitely not authorized
-	if h.cfg.YandexOAuthToken == "" {
-		return c.JSON(http.StatusOK, map[string]interface{}{
+	var projectID uint
+	if projectIDStr := c.QueryParam("project_id"); projectIDStr != "" {
+		id, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid project ID")
+		}
+		projectID = uint(id)
+
+		userID, ok := c.Get("user_id").(uint)
+		if !ok {
+			return echo.NewHTTPError(401, "User not authenticated")
+		}
+		if err := h.authorizeProject(ctx, userID, &projectID); err != nil {
+			if strings.HasPrefix(err.Error(), "access denied") {
+				return echo.NewHTTPError(403, "Access denied: no permission for this project")
+			}
+			return err
+		}
+	}
+
+	token, err := h.credentialService.ResolveToken(ctx, projectID, nil)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Warn("Failed to resolve OAuth token",
+				zap.Uint("project_id", projectID),
+				zap.Error(err),
+			)
+		}
+		token = ""
+	}
+
+	// If no token configured, definitely not authorized
+	if token == "" {
+		response := map[string]interface{}{
 			"authorized": false,
 			"has_token":  false,
-		})
+		}
+		if credentials := h.credentialsHealth(c, projectID); credentials != nil {
+			response["credentials"] = credentials
+		}
+		return c.JSON(http.StatusOK, response)
 	}
 
 	// Validate token by making a request to Yandex API
 	// Using a simple info endpoint to check if token is valid
-	isValid, err := h.validateToken(ctx, h.cfg.YandexOAuthToken)
+	isValid, err := h.validateToken(ctx, token)
 	if err != nil {
 		if logger.Log != nil {
 			logger.Log.Warn("Failed to validate OAuth token",
@@ -214,23 +326,92 @@ func (h *OAuthHandler) GetOAuthStatus(c echo.Context) error {
 	}
 
 	if logger.Log != nil {
-		tokenPreview := ""
-		if len(h.cfg.YandexOAuthToken) > 20 {
-			tokenPreview = h.cfg.YandexOAuthToken[:20] + "..."
-		} else {
-			tokenPreview = h.cfg.YandexOAuthToken
-		}
 		logger.Log.Info("OAuth status check",
-			zap.String("token_preview", tokenPreview),
+			zap.Uint("project_id", projectID),
 			zap.Bool("is_valid", isValid),
-			zap.Bool("has_token", h.cfg.YandexOAuthToken != ""),
 		)
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"authorized": isValid,
-		"has_token":  h.cfg.YandexOAuthToken != "",
-	})
+		"has_token":  true,
+	}
+	if credentials := h.credentialsHealth(c, projectID); credentials != nil {
+		response["credentials"] = credentials
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// credentialsHealth returns stored credentials health for admins, nil otherwise
+// projectID 0 returns credentials of all projects
+func (h *OAuthHandler) credentialsHealth(c echo.Context, projectID uint) []*models.OAuthCredential {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(uint)
+	if !ok {
+		return nil
+	}
+	isAdmin, err := h.userRepo.IsAdmin(ctx, userID)
+	if err != nil || !isAdmin {
+		return nil
+	}
+
+	var projectFilter *uint
+	if projectID != 0 {
+		projectFilter = &projectID
+	}
+
+	credentials, err := h.credentialService.GetCredentialsHealth(ctx, projectFilter)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Warn("Failed to get OAuth credentials health", zap.Error(err))
+		}
+		return nil
+	}
+
+	return credentials
+}
+
+// GetProjectCredentials handles GET /api/projects/:id/oauth-credentials
+// Returns stored credentials of a project without tokens
+func (h *OAuthHandler) GetProjectCredentials(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	credentials, err := h.credentialService.GetProjectCredentials(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, credentials)
+}
+
+// DeleteProjectCredential handles DELETE /api/projects/:id/oauth-credentials/:credentialId
+func (h *OAuthHandler) DeleteProjectCredential(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+	credentialID, err := strconv.ParseUint(c.Param("credentialId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid credential ID")
+	}
+
+	if err := h.credentialService.DeleteCredential(ctx, uint(projectID), uint(credentialID)); err != nil {
+		if err.Error() == "credential not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return err
+	}
+
+	return c.NoContent(204)
 }
 
 // validateToken validates OAuth token by making a request to Yandex ID API
@@ -238,7 +419,7 @@ func (h *OAuthHandler) GetOAuthStatus(c echo.Context) error {
 func (h *OAuthHandler) validateToken(ctx context.Context, token string) (bool, error) {
 	// Use Yandex ID API info endpoint to validate token
 	// Documentation: https://yandex.ru/dev/id/doc/ru/user-information
-	infoURL := "https://login.yandex.ru/info"
+	infoURL := yandexLoginInfoURL
 
 	req, err := http.NewRequestWithContext(ctx, "GET", infoURL, nil)
 	if err != nil {
@@ -389,8 +570,101 @@ func (h *OAuthHandler) exchangeCodeForToken(ctx context.Context, code string) (*
 	return &tokenResp, nil
 }
 
-// saveTokenToEnv saves OAuth token to .env file
-// For MVP: stores token in .env file (can be moved to DB later)
-func (h *OAuthHandler) saveTokenToEnv(accessToken string) error {
-	return config.UpdateEnvFile("YANDEX_OAUTH_TOKEN", accessToken)
+// saveToken stores token for the project/account encoded in state
+func (h *OAuthHandler) saveToken(ctx context.Context, encodedState string, tokenResp *TokenResponse) error {
+	state, err := h.decodeState(encodedState)
+	if err != nil {
+		return err
+	}
+	if err := h.authorizeState(ctx, state); err != nil {
+		return err
+	}
+
+	credential, err := h.credentialService.SaveToken(ctx, state.ProjectID, &integrations.OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		ExpiresIn:    tokenResp.ExpiresIn,
+	})
+	if err != nil {
+		return err
+	}
+
+	if state.DirectAccountID != nil {
+		if err := h.credentialService.BindDirectAccount(ctx, *state.DirectAccountID, credential.ID); err != nil {
+			return fmt.Errorf("failed to bind Direct account: %w", err)
+		}
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("OAuth token saved",
+			zap.Uint("credential_id", credential.ID),
+			zap.String("login", credential.Login),
+			zap.Bool("has_refresh_token", tokenResp.RefreshToken != ""),
+		)
+	}
+
+	return nil
+}
+
+// authorizeState checks that the user who started the authorization still may store its token
+func (h *OAuthHandler) authorizeState(ctx context.Context, state *oauthState) error {
+	return h.authorizeProject(ctx, state.UserID, state.ProjectID)
+}
+
+// authorizeProject checks that a user may use OAuth tokens of a project:
+// agency-wide tokens (projectID nil) are used by admins, project tokens by admins and project managers
+func (h *OAuthHandler) authorizeProject(ctx context.Context, userID uint, projectID *uint) error {
+	isAdmin, err := h.userRepo.IsAdmin(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check user role: %w", err)
+	}
+	if isAdmin {
+		return nil
+	}
+	if projectID == nil {
+		return errors.New("access denied: admin role required")
+	}
+
+	role, err := h.userRepo.GetUserProjectRole(ctx, userID, *projectID)
+	if err != nil || role == nil || (role.Role != "admin" && role.Role != "manager") {
+		return errors.New("access denied: no permission for this project")
+	}
+	return nil
+}
+
+// encodeState encrypts OAuth state with APP_KEY so it cannot be forged
+func (h *OAuthHandler) encodeState(state oauthState) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return utils.EncryptString(h.cfg.AppKey, string(data))
+}
+
+// decodeState decrypts and validates OAuth state
+// State is required, so a callback not started by InitiateAuth cannot replace credentials
+func (h *OAuthHandler) decodeState(encoded string) (*oauthState, error) {
+	if encoded == "" {
+		return nil, errors.New("OAuth state is missing")
+	}
+
+	data, err := utils.DecryptString(h.cfg.AppKey, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OAuth state: %w", err)
+	}
+
+	var state oauthState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("invalid OAuth state: %w", err)
+	}
+
+	if state.UserID == 0 || state.Nonce == "" {
+		return nil, errors.New("invalid OAuth state: user or nonce is missing")
+	}
+	if time.Since(time.Unix(state.IssuedAt, 0)) > oauthStateTTL {
+		return nil, errors.New("OAuth state has expired")
+	}
+
+	return &state, nil
 }
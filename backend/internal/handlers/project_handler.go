@@ -57,7 +57,10 @@ func (h *ProjectHandler) CreateProject(c echo.Context) error {
 
 	if err := h.projectService.CreateProject(ctx, &project); err != nil {
 		// Validation errors should return 400, other errors will be handled by error handler
-		if err.Error() == "name is required" || err.Error() == "slug is required" {
+		if err.Error() == "name is required" || err.Error() == "slug is required" ||
+			err.Error() == "invalid attribution_model" || err.Error() == "invalid counter_aggregation" ||
+			err.Error() == "invalid currency" || err.Error() == "invalid reporting_currency" ||
+			err.Error() == "invalid cost_markup_pct" {
 			return echo.NewHTTPError(400, err.Error())
 		}
 		return err
@@ -180,7 +183,10 @@ func (h *ProjectHandler) UpdateProject(c echo.Context) error {
 
 	if err := h.projectService.UpdateProject(ctx, &project); err != nil {
 		// Check if it's a validation error or not found error
-		if err.Error() == "name is required" || err.Error() == "slug is required" {
+		if err.Error() == "name is required" || err.Error() == "slug is required" ||
+			err.Error() == "invalid attribution_model" || err.Error() == "invalid counter_aggregation" ||
+			err.Error() == "invalid currency" || err.Error() == "invalid reporting_currency" ||
+			err.Error() == "invalid cost_markup_pct" {
 			return echo.NewHTTPError(400, err.Error())
 		}
 		return err
@@ -17,7 +17,8 @@ import (
 // ReportServiceInterface defines methods for report operations
 type ReportServiceInterface interface {
 	GetReport(ctx context.Context, projectID uint) (*services.Report, error)
-	GetChannelMetrics(ctx context.Context, projectID uint, periods []string) (*services.ChannelMetricsOutput, error)
+	ConvertReport(ctx context.Context, report *services.Report, currency string) (*services.Report, error)
+	GetChannelMetrics(ctx context.Context, projectID uint, periods []string, currency string) (*services.ChannelMetricsOutput, error)
 	AnalyzeChannelMetrics(ctx context.Context, metricsData *services.ChannelMetricsOutput) (*services.MetricsAnalysisResult, error)
 	CalculateDynamics(current, previous float64) float64
 }
@@ -44,8 +45,9 @@ func (h *ReportHandler) SetProjectService(projectService ProjectServiceInterface
 	h.projectService = projectService
 }
 
-// GetReport handles GET /api/report/:id
+// GetReport handles GET /api/report/:id?currency=USD
 // Returns JSON with report data for 3 months (M, M-1, M-2)
+// Money is shown in the currency param, "native" for the project currency or reporting currency of the project if empty
 // If report is not in cache, enqueues generation task and returns task_id
 func (h *ReportHandler) GetReport(c echo.Context) error {
 	// Force log immediately - this should always appear
@@ -84,7 +86,11 @@ func (h *ReportHandler) GetReport(c echo.Context) error {
 				zap.Uint("project_id", projectID),
 				zap.String("cache_key", cacheKey),
 			)
-			return c.JSON(200, report)
+			converted, err := h.reportService.ConvertReport(c.Request().Context(), &report, c.QueryParam("currency"))
+			if err != nil {
+				return currencyError(err)
+			}
+			return c.JSON(200, converted)
 		}
 		// Cache miss - will enqueue task
 		logger.Log.Info("Report not in cache, enqueuing generation task",
@@ -119,7 +125,7 @@ func (h *ReportHandler) GetReport(c echo.Context) error {
 	})
 }
 
-// GetPublicReport handles GET /api/public/report/:token
+// GetPublicReport handles GET /api/public/report/:token?currency=USD
 // Returns JSON with report data for 3 months without authentication
 func (h *ReportHandler) GetPublicReport(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -148,11 +154,16 @@ func (h *ReportHandler) GetPublicReport(c echo.Context) error {
 		return err
 	}
 
+	report, err = h.reportService.ConvertReport(ctx, report, c.QueryParam("currency"))
+	if err != nil {
+		return currencyError(err)
+	}
+
 	return c.JSON(200, report)
 }
 
-// GetChannelMetrics handles GET /api/channel-metrics/:id?periods=2025-08,2024-09,2024-10
-// Returns JSON with channel metrics data from database
+// GetChannelMetrics handles GET /api/channel-metrics/:id?periods=2025-08,2024-09,2024-10&currency=USD
+// Returns JSON with channel metrics data from database, currency param works as in GetReport
 func (h *ReportHandler) GetChannelMetrics(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -173,9 +184,9 @@ func (h *ReportHandler) GetChannelMetrics(c echo.Context) error {
 		periods[i] = strings.TrimSpace(period)
 	}
 
-	output, err := h.reportService.GetChannelMetrics(ctx, uint(id), periods)
+	output, err := h.reportService.GetChannelMetrics(ctx, uint(id), periods, c.QueryParam("currency"))
 	if err != nil {
-		return err
+		return currencyError(err)
 	}
 
 	return c.JSON(200, output)
@@ -219,3 +230,11 @@ func (h *ReportHandler) AnalyzeChannelMetrics(c echo.Context) error {
 		"status":     "pending",
 	})
 }
+
+// currencyError maps unknown currency and missing exchange rate errors to 400
+func currencyError(err error) error {
+	if strings.HasPrefix(err.Error(), "invalid currency") || strings.HasPrefix(err.Error(), "no exchange rate") {
+		return echo.NewHTTPError(400, err.Error())
+	}
+	return err
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/suprt/planica_bi/backend/internal/queue"
+	"github.com/suprt/planica_bi/backend/internal/services"
 )
 
 // SyncHandler handles HTTP requests for synchronization
@@ -40,3 +42,45 @@ func (h *SyncHandler) SyncProject(c echo.Context) error {
 		"queue":      taskInfo.Queue,
 	})
 }
+
+// SyncMetricaLogsRequest represents request body for Metrica Logs API load
+type SyncMetricaLogsRequest struct {
+	DateFrom string   `json:"date_from"` // YYYY-MM-DD
+	DateTo   string   `json:"date_to"`   // YYYY-MM-DD, before today
+	Fields   []string `json:"fields"`    // Optional field names: client_id, start_url, traffic_source, goals, ...
+}
+
+// SyncMetricaLogs handles POST /api/sync/:id/metrica-logs
+// Enqueues a task to load raw visits of the project counters from Metrica Logs API
+func (h *SyncHandler) SyncMetricaLogs(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	var req SyncMetricaLogsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+	if _, err := time.Parse("2006-01-02", req.DateFrom); err != nil {
+		return echo.NewHTTPError(400, "Invalid date_from, expected YYYY-MM-DD")
+	}
+	if _, err := time.Parse("2006-01-02", req.DateTo); err != nil {
+		return echo.NewHTTPError(400, "Invalid date_to, expected YYYY-MM-DD")
+	}
+	if _, err := services.MetricaLogFields(req.Fields); err != nil {
+		return echo.NewHTTPError(400, err.Error())
+	}
+
+	taskInfo, err := h.queueClient.EnqueueSyncMetricaLogsTask(uint(id), req.DateFrom, req.DateTo, req.Fields)
+	if err != nil {
+		return echo.NewHTTPError(500, "Failed to enqueue Metrica logs task: "+err.Error())
+	}
+
+	return c.JSON(202, map[string]interface{}{
+		"message":    "Metrica logs task enqueued",
+		"project_id": uint(id),
+		"task_id":    taskInfo.ID,
+		"queue":      taskInfo.Queue,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// SyncRunServiceInterface defines methods for sync run history
+type SyncRunServiceInterface interface {
+	GetRuns(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error)
+}
+
+// SyncRunHandler handles HTTP requests for sync run history
+type SyncRunHandler struct {
+	syncRunService SyncRunServiceInterface
+}
+
+// NewSyncRunHandler creates a new sync run handler
+func NewSyncRunHandler(syncRunService SyncRunServiceInterface) *SyncRunHandler {
+	return &SyncRunHandler{
+		syncRunService: syncRunService,
+	}
+}
+
+// GetProjectSyncRuns handles GET /api/projects/:id/sync-runs
+// Query params: source, status (comma-separated), since (YYYY-MM-DD), page, per_page
+func (h *SyncRunHandler) GetProjectSyncRuns(c echo.Context) error {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	filter, err := syncRunFilterFromQuery(c)
+	if err != nil {
+		return err
+	}
+	filter.ProjectID = uint(projectID)
+
+	return h.listRuns(c, filter)
+}
+
+// GetSyncRuns handles GET /api/sync-runs
+// Runs of all projects for admins, e.g. ?status=failed,partial&since=2025-03-01 shows recent failures
+// Query params: project_id, source, status (comma-separated), since (YYYY-MM-DD), page, per_page
+func (h *SyncRunHandler) GetSyncRuns(c echo.Context) error {
+	filter, err := syncRunFilterFromQuery(c)
+	if err != nil {
+		return err
+	}
+	if value := c.QueryParam("project_id"); value != "" {
+		projectID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid project_id")
+		}
+		filter.ProjectID = uint(projectID)
+	}
+
+	return h.listRuns(c, filter)
+}
+
+// listRuns returns paginated runs in { data: [...], total: N } format
+func (h *SyncRunHandler) listRuns(c echo.Context, filter models.SyncRunFilter) error {
+	runs, total, err := h.syncRunService.GetRuns(c.Request().Context(), filter, middleware.GetPagination(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"data":  runs,
+		"total": total,
+	})
+}
+
+// syncRunFilterFromQuery parses filters shared by project and admin views
+func syncRunFilterFromQuery(c echo.Context) (models.SyncRunFilter, error) {
+	filter := models.SyncRunFilter{
+		Source: c.QueryParam("source"),
+	}
+	if value := c.QueryParam("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+	if value := c.QueryParam("since"); value != "" {
+		since, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return filter, echo.NewHTTPError(400, "Invalid since date, expected YYYY-MM-DD")
+		}
+		filter.Since = &since
+	}
+	return filter, nil
+}
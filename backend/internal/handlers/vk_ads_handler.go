@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// VKAdsServiceInterface defines methods for VK Ads cabinet operations
+type VKAdsServiceInterface interface {
+	CreateAccount(ctx context.Context, account *models.VKAdsAccount) error
+	GetAccountByProject(ctx context.Context, projectID uint) (*models.VKAdsAccount, error)
+	DeleteAccount(ctx context.Context, projectID uint) error
+}
+
+// VKAdsHandler handles HTTP requests for VK Ads cabinets
+type VKAdsHandler struct {
+	vkAdsService VKAdsServiceInterface
+}
+
+// NewVKAdsHandler creates a new VK Ads handler
+func NewVKAdsHandler(vkAdsService VKAdsServiceInterface) *VKAdsHandler {
+	return &VKAdsHandler{
+		vkAdsService: vkAdsService,
+	}
+}
+
+// AddVKAdsAccountRequest represents request body for binding a VK Ads cabinet
+type AddVKAdsAccountRequest struct {
+	ClientID     string  `json:"client_id"`
+	ClientSecret string  `json:"client_secret"`
+	AccountName  *string `json:"account_name"`
+	Currency     string  `json:"currency"` // Cabinet currency, RUB by default
+}
+
+// AddAccount handles POST /api/projects/:id/vk-ads-account
+func (h *VKAdsHandler) AddAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	var req AddVKAdsAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+
+	account := models.VKAdsAccount{
+		ProjectID:    uint(projectID),
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		AccountName:  req.AccountName,
+		Currency:     req.Currency,
+	}
+	if err := h.vkAdsService.CreateAccount(ctx, &account); err != nil {
+		switch err.Error() {
+		case "client_id is required", "client_secret is required", "invalid currency":
+			return echo.NewHTTPError(400, err.Error())
+		case "VK Ads account is already bound to this project":
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(201, account)
+}
+
+// GetAccount handles GET /api/projects/:id/vk-ads-account
+func (h *VKAdsHandler) GetAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	account, err := h.vkAdsService.GetAccountByProject(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return echo.NewHTTPError(404, "VK Ads account not found")
+	}
+
+	return c.JSON(200, account)
+}
+
+// DeleteAccount handles DELETE /api/projects/:id/vk-ads-account
+func (h *VKAdsHandler) DeleteAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	if err := h.vkAdsService.DeleteAccount(ctx, uint(projectID)); err != nil {
+		if err.Error() == "VK Ads account not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return err
+	}
+
+	return c.NoContent(204)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// WebmasterServiceInterface defines methods for Webmaster host operations
+type WebmasterServiceInterface interface {
+	BindHost(ctx context.Context, host *models.WebmasterHost) error
+	GetHostsByProject(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error)
+	DeleteHost(ctx context.Context, projectID uint, id uint) error
+	GetAvailableHosts(ctx context.Context, projectID uint, credentialID *uint) ([]integrations.WebmasterHost, error)
+}
+
+// WebmasterHandler handles HTTP requests for Webmaster hosts
+type WebmasterHandler struct {
+	webmasterService WebmasterServiceInterface
+}
+
+// NewWebmasterHandler creates a new Webmaster handler
+func NewWebmasterHandler(webmasterService WebmasterServiceInterface) *WebmasterHandler {
+	return &WebmasterHandler{
+		webmasterService: webmasterService,
+	}
+}
+
+// AddHost handles POST /api/projects/:id/webmaster-hosts
+func (h *WebmasterHandler) AddHost(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	var host models.WebmasterHost
+	if err := c.Bind(&host); err != nil {
+		return echo.NewHTTPError(400, "Invalid request body")
+	}
+
+	// Set project ID from URL
+	host.ID = 0
+	host.ProjectID = uint(projectID)
+
+	if err := h.webmasterService.BindHost(ctx, &host); err != nil {
+		if err.Error() == "project_id is required" || err.Error() == "host_id is required" {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err.Error() == "host is already bound to this project" {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(201, host)
+}
+
+// GetHosts handles GET /api/projects/:id/webmaster-hosts
+func (h *WebmasterHandler) GetHosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	hosts, err := h.webmasterService.GetHostsByProject(ctx, uint(projectID))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, hosts)
+}
+
+// DeleteHost handles DELETE /api/projects/:id/webmaster-hosts/:hostId
+func (h *WebmasterHandler) DeleteHost(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+	hostID, err := strconv.ParseUint(c.Param("hostId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid host ID")
+	}
+
+	if err := h.webmasterService.DeleteHost(ctx, uint(projectID), uint(hostID)); err != nil {
+		if err.Error() == "host not found" {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return err
+	}
+
+	return c.NoContent(204)
+}
+
+// GetAvailableHosts handles GET /api/projects/:id/webmaster-hosts/available
+// Optional query parameter oauth_credential_id selects the token to list sites with
+func (h *WebmasterHandler) GetAvailableHosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(400, "Invalid project ID")
+	}
+
+	var credentialID *uint
+	if value := c.QueryParam("oauth_credential_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(400, "Invalid oauth_credential_id")
+		}
+		credential := uint(id)
+		credentialID = &credential
+	}
+
+	hosts, err := h.webmasterService.GetAvailableHosts(ctx, uint(projectID), credentialID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, hosts)
+}
@@ -0,0 +1,139 @@
+package integrations
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cbrRatesURL = "https://www.cbr.ru/scripts/XML_daily.asp"
+
+	// ExchangeRateProviderCBR is the name of the Bank of Russia rates provider
+	ExchangeRateProviderCBR = "cbr"
+)
+
+// CurrencyRate represents a rate returned by an exchange rate provider: 1 Base = Rate Quote
+type CurrencyRate struct {
+	Base  string
+	Quote string
+	Date  time.Time // Date the rate is set for
+	Rate  float64
+}
+
+// CBRClient loads official exchange rates of the Bank of Russia, all rates are quoted in RUB
+// Documentation: https://www.cbr.ru/development/SXML/
+type CBRClient struct {
+	httpClient *http.Client
+	baseURL    string // For testing: allows overriding base URL
+}
+
+// NewCBRClient creates a new Bank of Russia rates client
+func NewCBRClient() *CBRClient {
+	return NewCBRClientWithURL(cbrRatesURL)
+}
+
+// NewCBRClientWithURL creates a new Bank of Russia rates client with custom URL (for testing)
+func NewCBRClientWithURL(baseURL string) *CBRClient {
+	return &CBRClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    baseURL,
+	}
+}
+
+// cbrValCurs represents XML_daily.asp response
+type cbrValCurs struct {
+	Date    string `xml:"Date,attr"` // DD.MM.YYYY
+	Valutes []struct {
+		CharCode string `xml:"CharCode"`
+		Nominal  string `xml:"Nominal"`
+		Value    string `xml:"Value"` // Rate of Nominal units with decimal comma, e.g. "88,8064"
+	} `xml:"Valute"`
+}
+
+// Name returns provider name stored with the rates
+func (c *CBRClient) Name() string {
+	return ExchangeRateProviderCBR
+}
+
+// GetRates retrieves rates of all currencies to RUB on a date
+// On weekends and holidays the Bank of Russia returns rates of the last working day with that day's date
+func (c *CBRClient) GetRates(ctx context.Context, date time.Time) ([]CurrencyRate, error) {
+	reqURL := c.baseURL + "?date_req=" + date.Format("02/01/2006")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("CBR request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var valCurs cbrValCurs
+	decoder := xml.NewDecoder(resp.Body)
+	decoder.CharsetReader = asciiCharsetReader
+	if err := decoder.Decode(&valCurs); err != nil {
+		return nil, fmt.Errorf("failed to decode CBR rates: %w", err)
+	}
+
+	rateDate, err := time.Parse("02.01.2006", valCurs.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CBR rates date %q: %w", valCurs.Date, err)
+	}
+
+	rates := make([]CurrencyRate, 0, len(valCurs.Valutes))
+	for _, valute := range valCurs.Valutes {
+		nominal, err := strconv.ParseFloat(strings.TrimSpace(valute.Nominal), 64)
+		if err != nil || nominal <= 0 {
+			return nil, fmt.Errorf("invalid CBR nominal %q of %s", valute.Nominal, valute.CharCode)
+		}
+		value, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(valute.Value), ",", ".", 1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CBR rate %q of %s", valute.Value, valute.CharCode)
+		}
+		rates = append(rates, CurrencyRate{
+			Base:  strings.TrimSpace(valute.CharCode),
+			Quote: "RUB",
+			Date:  rateDate,
+			Rate:  value / nominal,
+		})
+	}
+
+	return rates, nil
+}
+
+// asciiCharsetReader decodes single-byte encodings like windows-1251 for fields with ASCII values
+// Non-ASCII bytes are only used in currency names, which are not read, so they are replaced
+func asciiCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	return &asciiReader{reader: bufio.NewReader(input)}, nil
+}
+
+// asciiReader replaces non-ASCII bytes with '?'
+type asciiReader struct {
+	reader io.Reader
+}
+
+// Read implements io.Reader
+func (r *asciiReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] >= 0x80 {
+			p[i] = '?'
+		}
+	}
+	return n, err
+}
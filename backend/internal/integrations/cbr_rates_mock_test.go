@@ -0,0 +1,49 @@
+package integrations
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCBRClient_GetRates_Mock tests windows-1251 rates parsing with mocked HTTP server
+func TestCBRClient_GetRates_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date_req") != "02/03/2025" {
+			t.Errorf("Expected date_req 02/03/2025, got %s", r.URL.Query().Get("date_req"))
+		}
+
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		// Currency names in windows-1251, as returned by the Bank of Russia
+		w.Write([]byte("<?xml version=\"1.0\" encoding=\"windows-1251\"?>" +
+			"<ValCurs Date=\"01.03.2025\" name=\"Foreign Currency Market\">" +
+			"<Valute ID=\"R01235\"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal>" +
+			"<Name>\xc4\xee\xeb\xeb\xe0\xf0 \xd1\xd8\xc0</Name><Value>88,8064</Value></Valute>" +
+			"<Valute ID=\"R01335\"><NumCode>398</NumCode><CharCode>KZT</CharCode><Nominal>100</Nominal>" +
+			"<Name>\xd2\xe5\xed\xe3\xe5</Name><Value>17,6543</Value></Valute>" +
+			"</ValCurs>"))
+	}))
+	defer mockServer.Close()
+
+	client := NewCBRClientWithURL(mockServer.URL)
+	rates, err := client.GetRates(context.Background(), time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetRates failed: %v", err)
+	}
+
+	if len(rates) != 2 {
+		t.Fatalf("Expected 2 rates, got %d", len(rates))
+	}
+	if rates[0].Base != "USD" || rates[0].Quote != "RUB" || rates[0].Rate != 88.8064 {
+		t.Errorf("Unexpected USD rate: %+v", rates[0])
+	}
+	if rates[1].Base != "KZT" || math.Abs(rates[1].Rate-0.176543) > 1e-9 {
+		t.Errorf("Expected KZT rate per one unit 0.176543, got %+v", rates[1])
+	}
+	if !rates[0].Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected rate date 2025-03-01 from response, got %v", rates[0].Date)
+	}
+}
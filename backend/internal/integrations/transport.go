@@ -0,0 +1,282 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retryableDirectErrorCodes lists Direct API error codes worth retrying
+// 52 - authorization server temporarily unavailable, 152 - not enough units,
+// 506 - too many concurrent requests
+// Documentation: https://yandex.ru/dev/direct/doc/dg/concepts/errors.html
+var retryableDirectErrorCodes = map[string]bool{
+	"52":  true,
+	"152": true,
+	"506": true,
+}
+
+// TransportOptions configures retries and concurrency of Transport
+type TransportOptions struct {
+	MaxRetries            int           // Retries after the first attempt
+	BaseDelay             time.Duration // Backoff delay before the first retry
+	MaxDelay              time.Duration // Upper bound of a single backoff delay
+	AttemptTimeout        time.Duration // Timeout of one attempt including reading the body
+	MaxConcurrentPerToken int           // Parallel requests with the same OAuth token, 0 - unlimited
+}
+
+// DefaultTransportOptions returns options used by Yandex API clients
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxRetries:            4,
+		BaseDelay:             time.Second,
+		MaxDelay:              30 * time.Second,
+		AttemptTimeout:        30 * time.Second,
+		MaxConcurrentPerToken: 5,
+	}
+}
+
+// Transport is http.RoundTripper shared by Yandex API clients
+// Retries 429, 5xx and retryable Direct errors with exponential backoff and jitter,
+// limits concurrent requests per OAuth token and records Direct Units from response headers
+type Transport struct {
+	base    http.RoundTripper
+	options TransportOptions
+	quota   *QuotaTracker
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewTransport creates a transport on top of http.DefaultTransport
+func NewTransport(options TransportOptions) *Transport {
+	return &Transport{
+		base:    http.DefaultTransport,
+		options: options,
+		quota:   NewQuotaTracker(),
+		slots:   make(map[string]chan struct{}),
+	}
+}
+
+var defaultTransport = NewTransport(DefaultTransportOptions())
+
+// DefaultTransport returns transport shared by all Yandex API clients of the process
+func DefaultTransport() *Transport {
+	return defaultTransport
+}
+
+// newAPIHTTPClient creates HTTP client using the shared transport
+// Timeouts are applied per attempt by the transport, so backoff delays are not cut short
+func newAPIHTTPClient() *http.Client {
+	return &http.Client{Transport: DefaultTransport()}
+}
+
+// Quota returns tracker of Direct Units spent through the transport
+func (t *Transport) Quota() *QuotaTracker {
+	return t.quota
+}
+
+// RoundTrip executes request with retries
+// Response body is read by the transport, so it can be inspected for API error codes
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, retry, err := t.attempt(req)
+		if !retry || attempt >= t.options.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt performs one request holding a concurrency slot of the token
+// Returns response with buffered body and whether the request should be retried
+func (t *Transport) attempt(req *http.Request) (*http.Response, bool, error) {
+	release, err := t.acquire(req.Context(), req.Header.Get("Authorization"))
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	ctx := req.Context()
+	if t.options.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.options.AttemptTimeout)
+		defer cancel()
+	}
+
+	attemptReq := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to copy request body: %w", err)
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := t.base.RoundTrip(attemptReq)
+	if err != nil {
+		// Network errors are retried unless the caller gave up
+		return nil, req.Context().Err() == nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, req.Context().Err() == nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.quota.record(resp.Header.Get("Units-Used-Login"), req.Header.Get("Client-Login"), resp.Header.Get("Units"))
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return resp, true, nil
+	}
+	return resp, retryableDirectErrorCodes[directErrorCode(body)], nil
+}
+
+// acquire takes a concurrency slot of the token, returned function releases it
+func (t *Transport) acquire(ctx context.Context, token string) (func(), error) {
+	if t.options.MaxConcurrentPerToken <= 0 {
+		return func() {}, nil
+	}
+
+	t.mu.Lock()
+	slots, ok := t.slots[token]
+	if !ok {
+		slots = make(chan struct{}, t.options.MaxConcurrentPerToken)
+		t.slots[token] = slots
+	}
+	t.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// backoff returns delay before the next attempt: exponential with full jitter,
+// but not shorter than Retry-After of the response
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	limit := t.options.BaseDelay << attempt
+	if limit <= 0 || (t.options.MaxDelay > 0 && limit > t.options.MaxDelay) {
+		limit = t.options.MaxDelay
+	}
+	var delay time.Duration
+	if limit > 0 {
+		delay = time.Duration(rand.Int63n(int64(limit) + 1))
+	}
+
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if retryAfter := time.Duration(seconds) * time.Second; retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+	}
+	return delay
+}
+
+// directErrorCode extracts error_code from Direct API error body
+// JSON API returns code as number, Reports API as string
+func directErrorCode(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return ""
+	}
+	var response struct {
+		Error *struct {
+			ErrorCode json.RawMessage `json:"error_code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(trimmed, &response); err != nil || response.Error == nil {
+		return ""
+	}
+	return strings.Trim(string(response.Error.ErrorCode), `"`)
+}
+
+// UnitsQuota is Direct API points balance of an account
+// Documentation: https://yandex.ru/dev/direct/doc/dg/concepts/units.html
+type UnitsQuota struct {
+	Spent     int64     `json:"spent"`     // Spent by the last request
+	Remaining int64     `json:"remaining"` // Left for the current day
+	Limit     int64     `json:"limit"`     // Daily limit
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// QuotaTracker keeps the latest Units balance of every Direct account
+type QuotaTracker struct {
+	mu       sync.RWMutex
+	accounts map[string]UnitsQuota
+}
+
+// NewQuotaTracker creates an empty tracker
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{accounts: make(map[string]UnitsQuota)}
+}
+
+// Get returns the latest known balance of an account (client login)
+func (q *QuotaTracker) Get(login string) (UnitsQuota, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	quota, ok := q.accounts[strings.ToLower(login)]
+	return quota, ok
+}
+
+// record parses Units header ("spent/remaining/limit") of a response
+// Account is taken from Units-Used-Login header, falling back to Client-Login of the request
+func (q *QuotaTracker) record(usedLogin, clientLogin, header string) {
+	if header == "" {
+		return
+	}
+	login := usedLogin
+	if login == "" {
+		login = clientLogin
+	}
+
+	quota, ok := parseUnits(header)
+	if !ok {
+		return
+	}
+	quota.UpdatedAt = time.Now()
+
+	q.mu.Lock()
+	q.accounts[strings.ToLower(login)] = quota
+	q.mu.Unlock()
+}
+
+// parseUnits parses Units header value
+func parseUnits(header string) (UnitsQuota, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "/")
+	if len(parts) != 3 {
+		return UnitsQuota{}, false
+	}
+	values := make([]int64, 3)
+	for i, part := range parts {
+		value, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return UnitsQuota{}, false
+		}
+		values[i] = value
+	}
+	return UnitsQuota{Spent: values[0], Remaining: values[1], Limit: values[2]}, true
+}
@@ -0,0 +1,177 @@
+package integrations
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport creates transport with short delays for tests
+func newTestTransport(maxConcurrent int) *Transport {
+	return NewTransport(TransportOptions{
+		MaxRetries:            3,
+		BaseDelay:             time.Millisecond,
+		MaxDelay:              5 * time.Millisecond,
+		AttemptTimeout:        5 * time.Second,
+		MaxConcurrentPerToken: maxConcurrent,
+	})
+}
+
+// TestTransport_RetriesServerErrors tests retries of 429 and 5xx responses with request body resent
+func TestTransport_RetriesServerErrors(t *testing.T) {
+	var attempts int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"method":"get"}` {
+			t.Errorf("Expected request body to be resent, got %q", string(body))
+		}
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"result":{}}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &http.Client{Transport: newTestTransport(1)}
+	resp, err := client.Post(mockServer.URL, "application/json", bytes.NewBufferString(`{"method":"get"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 after retries, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"result":{}}` {
+		t.Errorf("Unexpected body: %s", string(body))
+	}
+}
+
+// TestTransport_RetriesDirectErrorCodes tests retries of Direct errors returned with any status
+func TestTransport_RetriesDirectErrorCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantAttempts int32
+	}{
+		{name: "JSON API not enough units", status: http.StatusOK, body: `{"error":{"error_code":152,"error_string":"Not enough units"}}`, wantAttempts: 4},
+		{name: "Reports API concurrent requests", status: http.StatusBadRequest, body: `{"error":{"error_code":"506","error_string":"Limit exceeded"}}`, wantAttempts: 4},
+		{name: "invalid request is not retried", status: http.StatusBadRequest, body: `{"error":{"error_code":"8000","error_string":"Invalid request"}}`, wantAttempts: 1},
+		{name: "TSV report is not inspected", status: http.StatusOK, body: "CampaignId\tClicks\n1\t10\n", wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer mockServer.Close()
+
+			client := &http.Client{Transport: newTestTransport(1)}
+			resp, err := client.Get(mockServer.URL)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+		})
+	}
+}
+
+// TestTransport_ConcurrencyPerToken tests that requests with the same token are limited
+func TestTransport_ConcurrencyPerToken(t *testing.T) {
+	var current, maxSeen int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := atomic.AddInt32(&current, 1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if value <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, value) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+	}))
+	defer mockServer.Close()
+
+	client := &http.Client{Transport: newTestTransport(2)}
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", mockServer.URL, nil)
+			req.Header.Set("Authorization", "Bearer same_token")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Errorf("Request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen > 2 {
+		t.Errorf("Expected at most 2 concurrent requests per token, got %d", maxSeen)
+	}
+}
+
+// TestTransport_UnitsQuota tests parsing of Direct Units header into quota tracker
+func TestTransport_UnitsQuota(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Units", "10/20828/64000")
+		if r.Header.Get("Client-Login") == "agency-client" {
+			w.Header().Set("Units-Used-Login", "Agency-Login")
+		}
+		w.Write([]byte(`{"result":{}}`))
+	}))
+	defer mockServer.Close()
+
+	transport := newTestTransport(0)
+	client := &http.Client{Transport: transport}
+
+	for _, login := range []string{"direct-client", "agency-client"} {
+		req, _ := http.NewRequest("POST", mockServer.URL, bytes.NewBufferString(`{}`))
+		req.Header.Set("Client-Login", login)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	quota, ok := transport.Quota().Get("direct-client")
+	if !ok {
+		t.Fatal("Expected quota for direct-client")
+	}
+	if quota.Spent != 10 || quota.Remaining != 20828 || quota.Limit != 64000 {
+		t.Errorf("Unexpected quota: %+v", quota)
+	}
+	if _, ok := transport.Quota().Get("agency-login"); !ok {
+		t.Error("Expected quota recorded for Units-Used-Login account")
+	}
+	if _, ok := transport.Quota().Get("agency-client"); ok {
+		t.Error("Quota should be recorded for the login that spent units")
+	}
+}
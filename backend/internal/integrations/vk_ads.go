@@ -0,0 +1,269 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	vkAdsAPIURL = "https://ads.vk.com"
+
+	// vkAdsPageLimit is the maximum page size of list endpoints
+	vkAdsPageLimit = 250
+	// vkAdsStatsIDsLimit is the maximum number of objects in one statistics request
+	vkAdsStatsIDsLimit = 200
+)
+
+// VKAdsClient handles integration with VK Ads API
+// Access token is obtained with client credentials of the advertiser cabinet and reused until it expires
+// Documentation: https://ads.vk.com/doc/api/info/api_reference
+type VKAdsClient struct {
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+	baseURL      string // For testing: allows overriding base URL
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewVKAdsClient creates a new VK Ads client
+func NewVKAdsClient(clientID, clientSecret string) *VKAdsClient {
+	return NewVKAdsClientWithURL(clientID, clientSecret, vkAdsAPIURL)
+}
+
+// NewVKAdsClientWithURL creates a new VK Ads client with custom base URL (for testing)
+func NewVKAdsClientWithURL(clientID, clientSecret, baseURL string) *VKAdsClient {
+	return &VKAdsClient{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		baseURL:      baseURL,
+		httpClient:   newAPIHTTPClient(),
+	}
+}
+
+// VKAdsToken represents VK Ads OAuth token response
+type VKAdsToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // Seconds
+	RefreshToken string `json:"refresh_token"`
+}
+
+// VKAdPlan represents a VK Ads ad plan (campaign)
+type VKAdPlan struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // active, blocked or deleted
+}
+
+// VKAdsStatRow represents daily statistics of an ad plan
+type VKAdsStatRow struct {
+	AdPlanID int64
+	Date     string // YYYY-MM-DD
+	Shows    int64
+	Clicks   int64
+	Spent    float64 // In the cabinet currency
+	Goals    int64   // Conversions counted by VK Ads
+}
+
+// GetToken obtains an access token with client credentials
+// Documentation: https://ads.vk.com/doc/api/info/api_access
+func (c *VKAdsClient) GetToken(ctx context.Context) (*VKAdsToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/oauth2/token.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token VKAdsToken
+	if err := c.do(req, &token); err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("failed to get token: empty access token")
+	}
+	return &token, nil
+}
+
+// GetAdPlans retrieves all ad plans of the cabinet including deleted ones, so that old statistics keep names
+// Documentation: https://ads.vk.com/doc/api/resource/AdPlan
+func (c *VKAdsClient) GetAdPlans(ctx context.Context) ([]VKAdPlan, error) {
+	var plans []VKAdPlan
+	for offset := 0; ; offset += vkAdsPageLimit {
+		params := url.Values{}
+		params.Set("fields", "id,name,status")
+		params.Set("_status__in", "active,blocked,deleted")
+		params.Set("limit", strconv.Itoa(vkAdsPageLimit))
+		params.Set("offset", strconv.Itoa(offset))
+
+		var response struct {
+			Count int        `json:"count"`
+			Items []VKAdPlan `json:"items"`
+		}
+		if err := c.get(ctx, "/api/v2/ad_plans.json", params, &response); err != nil {
+			return nil, fmt.Errorf("failed to get ad plans: %w", err)
+		}
+		plans = append(plans, response.Items...)
+
+		if len(response.Items) < vkAdsPageLimit || len(plans) >= response.Count {
+			return plans, nil
+		}
+	}
+}
+
+// GetAdPlanDailyStats retrieves daily base statistics of ad plans, days without shows are omitted
+// Documentation: https://ads.vk.com/doc/api/info/statistics
+func (c *VKAdsClient) GetAdPlanDailyStats(ctx context.Context, adPlanIDs []int64, dateFrom, dateTo string) ([]VKAdsStatRow, error) {
+	var rows []VKAdsStatRow
+	for start := 0; start < len(adPlanIDs); start += vkAdsStatsIDsLimit {
+		end := start + vkAdsStatsIDsLimit
+		if end > len(adPlanIDs) {
+			end = len(adPlanIDs)
+		}
+		ids := make([]string, 0, end-start)
+		for _, id := range adPlanIDs[start:end] {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+
+		params := url.Values{}
+		params.Set("id", strings.Join(ids, ","))
+		params.Set("date_from", dateFrom)
+		params.Set("date_to", dateTo)
+		params.Set("metrics", "base")
+
+		var response struct {
+			Items []struct {
+				ID   int64 `json:"id"`
+				Rows []struct {
+					Date string `json:"date"`
+					Base struct {
+						Shows  int64       `json:"shows"`
+						Clicks int64       `json:"clicks"`
+						Goals  int64       `json:"goals"`
+						Spent  json.Number `json:"spent"` // Decimal string, e.g. "1234.50"
+					} `json:"base"`
+				} `json:"rows"`
+			} `json:"items"`
+		}
+		if err := c.get(ctx, "/api/v2/statistics/ad_plans/day.json", params, &response); err != nil {
+			return nil, fmt.Errorf("failed to get statistics: %w", err)
+		}
+
+		for _, item := range response.Items {
+			for _, row := range item.Rows {
+				spent, err := parseVKAdsMoney(row.Base.Spent)
+				if err != nil {
+					return nil, fmt.Errorf("invalid spent %q of ad plan %d: %w", row.Base.Spent, item.ID, err)
+				}
+				if row.Base.Shows == 0 && row.Base.Clicks == 0 && spent == 0 {
+					continue
+				}
+				rows = append(rows, VKAdsStatRow{
+					AdPlanID: item.ID,
+					Date:     row.Date,
+					Shows:    row.Base.Shows,
+					Clicks:   row.Base.Clicks,
+					Spent:    spent,
+					Goals:    row.Base.Goals,
+				})
+			}
+		}
+	}
+	return rows, nil
+}
+
+// parseVKAdsMoney parses money value that API returns as a string or a number
+func parseVKAdsMoney(value json.Number) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(string(value), 64)
+}
+
+// token returns a valid access token, obtaining a new one when it is missing or expires soon
+// VK Ads limits active tokens per client, so tokens are not requested on every call
+func (c *VKAdsClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Add(time.Minute).Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	token, err := c.GetToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.accessToken = token.AccessToken
+	c.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
+
+// get performs authorized GET request to VK Ads API
+func (c *VKAdsClient) get(ctx context.Context, path string, params url.Values, response interface{}) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqURL := c.baseURL + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return c.do(req, response)
+}
+
+// do executes request and decodes JSON response
+func (c *VKAdsClient) do(req *http.Request, response interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(body, &apiError); err == nil && apiError.Error.Code != "" {
+			return fmt.Errorf("API error: %s (code: %s)", apiError.Error.Message, apiError.Error.Code)
+		}
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
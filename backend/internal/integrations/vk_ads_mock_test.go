@@ -0,0 +1,118 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newVKAdsMockServer creates VK Ads API mock with token, ad plans and statistics endpoints
+func newVKAdsMockServer(t *testing.T, tokenRequests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/v2/oauth2/token.json" {
+			*tokenRequests++
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("Failed to parse form: %v", err)
+			}
+			if r.Method != "POST" || r.PostForm.Get("grant_type") != "client_credentials" ||
+				r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": {"code": "invalid_client", "message": "Invalid client credentials"}}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "vk_token", "token_type": "Bearer", "expires_in": 86400, "refresh_token": "refresh"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer vk_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"code": "invalid_token", "message": "Invalid token"}}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/ad_plans.json":
+			if r.URL.Query().Get("fields") != "id,name,status" {
+				t.Errorf("Unexpected fields: %s", r.URL.Query().Get("fields"))
+			}
+			w.Write([]byte(`{"count": 2, "offset": 0, "items": [
+				{"id": 101, "name": "Brand", "status": "active"},
+				{"id": 102, "name": "Retargeting", "status": "deleted"}
+			]}`))
+		case "/api/v2/statistics/ad_plans/day.json":
+			query := r.URL.Query()
+			if query.Get("id") != "101,102" || query.Get("date_from") != "2025-03-01" || query.Get("date_to") != "2025-03-31" || query.Get("metrics") != "base" {
+				t.Errorf("Unexpected statistics query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"items": [
+				{"id": 101, "rows": [
+					{"date": "2025-03-01", "base": {"shows": 1000, "clicks": 25, "goals": 2, "spent": "1250.50"}},
+					{"date": "2025-03-02", "base": {"shows": 0, "clicks": 0, "goals": 0, "spent": "0"}}
+				]},
+				{"id": 102, "rows": [
+					{"date": "2025-03-01", "base": {"shows": 300, "clicks": 3, "goals": 0, "spent": 99.9}}
+				]}
+			]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// TestVKAdsClient_GetAdPlansAndStats_Mock tests ad plans and daily statistics parsing with a reused token
+func TestVKAdsClient_GetAdPlansAndStats_Mock(t *testing.T) {
+	tokenRequests := 0
+	mockServer := newVKAdsMockServer(t, &tokenRequests)
+	defer mockServer.Close()
+
+	client := NewVKAdsClientWithURL("client", "secret", mockServer.URL)
+
+	plans, err := client.GetAdPlans(context.Background())
+	if err != nil {
+		t.Fatalf("GetAdPlans failed: %v", err)
+	}
+	if len(plans) != 2 || plans[0].ID != 101 || plans[0].Name != "Brand" || plans[1].Status != "deleted" {
+		t.Errorf("Unexpected ad plans: %+v", plans)
+	}
+
+	rows, err := client.GetAdPlanDailyStats(context.Background(), []int64{101, 102}, "2025-03-01", "2025-03-31")
+	if err != nil {
+		t.Fatalf("GetAdPlanDailyStats failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows without empty days, got %d", len(rows))
+	}
+	if rows[0].AdPlanID != 101 || rows[0].Date != "2025-03-01" || rows[0].Shows != 1000 || rows[0].Clicks != 25 ||
+		rows[0].Goals != 2 || rows[0].Spent != 1250.5 {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].AdPlanID != 102 || rows[1].Spent != 99.9 {
+		t.Errorf("Expected numeric spent to be parsed, got %+v", rows[1])
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("Expected token to be requested once, got %d", tokenRequests)
+	}
+}
+
+// TestVKAdsClient_GetToken_InvalidCredentials tests API error of the token endpoint
+func TestVKAdsClient_GetToken_InvalidCredentials(t *testing.T) {
+	tokenRequests := 0
+	mockServer := newVKAdsMockServer(t, &tokenRequests)
+	defer mockServer.Close()
+
+	client := NewVKAdsClientWithURL("client", "wrong", mockServer.URL)
+
+	_, err := client.GetAdPlans(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for invalid credentials")
+	}
+	if !strings.Contains(err.Error(), "Invalid client credentials (code: invalid_client)") {
+		t.Errorf("Expected API error message, got %v", err)
+	}
+}
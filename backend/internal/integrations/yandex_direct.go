@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 )
 
 const (
@@ -15,13 +14,24 @@ const (
 	yandexDirectSandboxURL = "https://api-sandbox.direct.yandex.com/json/v5"
 )
 
+// Ad network types of report rows
+const (
+	AdNetworkTypeSearch  = "SEARCH"
+	AdNetworkTypeNetwork = "AD_NETWORK" // РСЯ
+)
+
 // YandexDirectClient handles integration with Yandex.Direct API
 type YandexDirectClient struct {
-	token       string
-	clientLogin string
-	httpClient  *http.Client
-	useSandbox  bool
-	baseURL     string // For testing: allows overriding base URL
+	token         string
+	clientLogin   string
+	httpClient    *http.Client
+	useSandbox    bool
+	baseURL       string // For testing: allows overriding base URL
+	reportOptions ReportOptions
+	// conversionSettings selects goals and attribution model for conversions in reports
+	conversionSettings ConversionSettings
+	// costSettings selects VAT and discount inclusion of money fields in reports
+	costSettings CostSettings
 }
 
 // NewYandexDirectClient creates a new Direct client
@@ -32,32 +42,79 @@ func NewYandexDirectClient(token, clientLogin string, useSandbox bool) *YandexDi
 		baseURL = yandexDirectSandboxURL
 	}
 	return &YandexDirectClient{
-		token:       token,
-		clientLogin: clientLogin,
-		useSandbox:  useSandbox,
-		baseURL:     baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		token:         token,
+		clientLogin:   clientLogin,
+		useSandbox:    useSandbox,
+		baseURL:       baseURL,
+		httpClient:    newAPIHTTPClient(),
+		reportOptions: DefaultReportOptions(),
 	}
 }
 
 // NewYandexDirectClientWithURL creates a new Direct client with custom base URL (for testing)
 func NewYandexDirectClientWithURL(token, clientLogin, baseURL string) *YandexDirectClient {
 	return &YandexDirectClient{
-		token:       token,
-		clientLogin: clientLogin,
-		baseURL:     baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		token:         token,
+		clientLogin:   clientLogin,
+		baseURL:       baseURL,
+		httpClient:    newAPIHTTPClient(),
+		reportOptions: DefaultReportOptions(),
 	}
 }
 
 // Campaign represents a Yandex Direct campaign
 type Campaign struct {
-	Id   int64  `json:"Id"`
-	Name string `json:"Name"`
+	Id     int64  `json:"Id"`
+	Name   string `json:"Name"`
+	Type   string `json:"Type,omitempty"`   // TEXT_CAMPAIGN, UNIFIED_CAMPAIGN, SMART_CAMPAIGN, ...
+	State  string `json:"State,omitempty"`  // ON, OFF, SUSPENDED, ENDED, CONVERTED, ARCHIVED
+	Status string `json:"Status,omitempty"` // ACCEPTED, DRAFT, MODERATION, REJECTED
+
+	// Type-specific settings, only the one matching Type is returned
+	TextCampaign        *CampaignSettings `json:"TextCampaign,omitempty"`
+	DynamicTextCampaign *CampaignSettings `json:"DynamicTextCampaign,omitempty"`
+	SmartCampaign       *CampaignSettings `json:"SmartCampaign,omitempty"`
+	UnifiedCampaign     *CampaignSettings `json:"UnifiedCampaign,omitempty"`
+}
+
+// CampaignSettings represents type-specific campaign settings requested by GetCampaigns
+type CampaignSettings struct {
+	BiddingStrategy *struct {
+		Search *struct {
+			BiddingStrategyType string `json:"BiddingStrategyType"`
+		} `json:"Search,omitempty"`
+		Network *struct {
+			BiddingStrategyType string `json:"BiddingStrategyType"`
+		} `json:"Network,omitempty"`
+	} `json:"BiddingStrategy,omitempty"`
+}
+
+// settings returns type-specific settings returned for the campaign
+func (c Campaign) settings() *CampaignSettings {
+	for _, settings := range []*CampaignSettings{c.TextCampaign, c.DynamicTextCampaign, c.SmartCampaign, c.UnifiedCampaign} {
+		if settings != nil {
+			return settings
+		}
+	}
+	return nil
+}
+
+// SearchStrategy returns bidding strategy type on search, empty if unknown
+func (c Campaign) SearchStrategy() string {
+	settings := c.settings()
+	if settings == nil || settings.BiddingStrategy == nil || settings.BiddingStrategy.Search == nil {
+		return ""
+	}
+	return settings.BiddingStrategy.Search.BiddingStrategyType
+}
+
+// NetworkStrategy returns bidding strategy type in ad networks (РСЯ), empty if unknown
+func (c Campaign) NetworkStrategy() string {
+	settings := c.settings()
+	if settings == nil || settings.BiddingStrategy == nil || settings.BiddingStrategy.Network == nil {
+		return ""
+	}
+	return settings.BiddingStrategy.Network.BiddingStrategyType
 }
 
 // CampaignsResponse represents response from campaigns.get
@@ -68,27 +125,19 @@ type CampaignsResponse struct {
 	Error *APIError `json:"error"`
 }
 
-// ReportResponse represents response from reports
-type ReportResponse struct {
-	Result struct {
-		Report struct {
-			Rows []ReportRow `json:"Rows"`
-		} `json:"Report"`
-	} `json:"result"`
-	Error *APIError `json:"error"`
-}
-
-// ReportRow represents a row in report data
+// ReportRow represents a row of campaign performance report
 type ReportRow struct {
-	CampaignId   int64   `json:"CampaignId"`
-	CampaignName string  `json:"CampaignName"`
-	Impressions  int64   `json:"Impressions"`
-	Clicks       int64   `json:"Clicks"`
-	Cost         float64 `json:"Cost"`
-	CTR          float64 `json:"Ctr"`
-	AvgCpc       float64 `json:"AvgCpc"`
-	Conversions  int64   `json:"Conversions,omitempty"`
-	CPA          float64 `json:"Cpa,omitempty"`
+	Date          string  `json:"Date,omitempty"` // YYYY-MM-DD, only in daily reports
+	CampaignId    int64   `json:"CampaignId"`
+	CampaignName  string  `json:"CampaignName"`
+	AdNetworkType string  `json:"AdNetworkType,omitempty"` // SEARCH or AD_NETWORK, only in daily reports
+	Impressions   int64   `json:"Impressions"`
+	Clicks        int64   `json:"Clicks"`
+	Cost          float64 `json:"Cost"`
+	CTR           float64 `json:"Ctr"`
+	AvgCpc        float64 `json:"AvgCpc"`
+	Conversions   int64   `json:"Conversions,omitempty"`
+	CPA           float64 `json:"CostPerConversion,omitempty"`
 }
 
 // APIError represents an error from Yandex Direct API
@@ -97,24 +146,20 @@ type APIError struct {
 	ErrorCode   int    `json:"error_code"`
 }
 
-// GetCampaigns retrieves campaigns for an account
+// GetCampaigns retrieves campaigns for an account with their type, state, status and bidding strategies
 // Documentation: https://yandex.ru/dev/direct/doc/ref-v5/campaigns/get.html
 func (c *YandexDirectClient) GetCampaigns(ctx context.Context) ([]Campaign, error) {
-	baseURL := c.baseURL
-	if baseURL == "" {
-		// Fallback for old clients without baseURL set
-		baseURL = yandexDirectAPIURL
-		if c.useSandbox {
-			baseURL = yandexDirectSandboxURL
-		}
-	}
-	url := baseURL + "/campaigns"
+	url := c.apiURL() + "/campaigns"
 
 	requestBody := map[string]interface{}{
 		"method": "get",
 		"params": map[string]interface{}{
-			"SelectionCriteria": map[string]interface{}{},
-			"FieldNames":        []string{"Id", "Name"},
+			"SelectionCriteria":             map[string]interface{}{},
+			"FieldNames":                    []string{"Id", "Name", "Type", "State", "Status"},
+			"TextCampaignFieldNames":        []string{"BiddingStrategy"},
+			"DynamicTextCampaignFieldNames": []string{"BiddingStrategy"},
+			"SmartCampaignFieldNames":       []string{"BiddingStrategy"},
+			"UnifiedCampaignFieldNames":     []string{"BiddingStrategy"},
 		},
 	}
 
@@ -130,59 +175,227 @@ func (c *YandexDirectClient) GetCampaigns(ctx context.Context) ([]Campaign, erro
 	return response.Result.Campaigns, nil
 }
 
-// GetCampaignReport retrieves report data for campaigns
-// Documentation: https://yandex.ru/dev/direct/doc/reports/reports.html
-func (c *YandexDirectClient) GetCampaignReport(ctx context.Context, dateFrom, dateTo string) ([]ReportRow, error) {
-	baseURL := c.baseURL
-	if baseURL == "" {
-		// Fallback for old clients without baseURL set
-		baseURL = yandexDirectAPIURL
-		if c.useSandbox {
-			baseURL = yandexDirectSandboxURL
-		}
-	}
-	url := baseURL + "/reports"
+// ClientsResponse represents response from Clients.get method
+type ClientsResponse struct {
+	Result struct {
+		Clients []struct {
+			Login    string `json:"Login"`
+			Currency string `json:"Currency"`
+		} `json:"Clients"`
+	} `json:"result"`
+	Error *APIError `json:"error"`
+}
+
+// GetClientCurrency retrieves currency of the account; report costs are returned in it
+// Documentation: https://yandex.ru/dev/direct/doc/ref-v5/clients/get.html
+func (c *YandexDirectClient) GetClientCurrency(ctx context.Context) (string, error) {
+	url := c.apiURL() + "/clients"
 
 	requestBody := map[string]interface{}{
+		"method": "get",
 		"params": map[string]interface{}{
-			"SelectionCriteria": map[string]interface{}{},
-			"FieldNames": []string{
-				"CampaignId",
-				"CampaignName",
-				"Impressions",
-				"Clicks",
-				"Cost",
-				"Ctr",
-				"AvgCpc",
-				"Conversions",
-				"Cpa",
-			},
-			"ReportName":      "Campaign Report",
-			"ReportType":      "CAMPAIGN_PERFORMANCE_REPORT",
-			"DateRangeType":   "CUSTOM_DATE",
-			"Format":          "TSV",
-			"IncludeVAT":      "NO",
-			"IncludeDiscount": "NO",
+			"FieldNames": []string{"Login", "Currency"},
 		},
 	}
 
-	// Add date range
-	if dateFrom != "" && dateTo != "" {
-		params := requestBody["params"].(map[string]interface{})
-		params["DateFrom"] = dateFrom
-		params["DateTo"] = dateTo
+	var response ClientsResponse
+	if err := c.makeRequest(ctx, url, requestBody, &response); err != nil {
+		return "", fmt.Errorf("failed to get client info: %w", err)
 	}
 
-	var response ReportResponse
-	if err := c.makeRequest(ctx, url, requestBody, &response); err != nil {
+	if response.Error != nil {
+		return "", fmt.Errorf("API error: %s (code: %d)", response.Error.ErrorString, response.Error.ErrorCode)
+	}
+	if len(response.Result.Clients) == 0 || response.Result.Clients[0].Currency == "" {
+		return "", fmt.Errorf("client info has no currency")
+	}
+
+	return response.Result.Clients[0].Currency, nil
+}
+
+// GetCampaignReport retrieves campaign performance report
+// Documentation: https://yandex.ru/dev/direct/doc/reports/reports.html
+func (c *YandexDirectClient) GetCampaignReport(ctx context.Context, dateFrom, dateTo string) ([]ReportRow, error) {
+	return c.getCampaignReport(ctx, "Campaign Report", false, dateFrom, dateTo)
+}
+
+// GetCampaignDailyReport retrieves campaign performance report split by date and ad network type
+// Each row contains metrics of one campaign for one day on search or in ad networks
+func (c *YandexDirectClient) GetCampaignDailyReport(ctx context.Context, dateFrom, dateTo string) ([]ReportRow, error) {
+	return c.getCampaignReport(ctx, "Campaign Daily Report", true, dateFrom, dateTo)
+}
+
+// getCampaignReport requests CAMPAIGN_PERFORMANCE_REPORT, optionally grouped by Date
+func (c *YandexDirectClient) getCampaignReport(ctx context.Context, name string, daily bool, dateFrom, dateTo string) ([]ReportRow, error) {
+	fieldNames := []string{
+		"CampaignId",
+		"CampaignName",
+		"Impressions",
+		"Clicks",
+		"Cost",
+		"Ctr",
+		"AvgCpc",
+		"Conversions",
+		"CostPerConversion",
+	}
+	if daily {
+		fieldNames = append([]string{"Date"}, fieldNames...)
+		fieldNames = append(fieldNames, "AdNetworkType")
+	}
+
+	definition := ReportDefinition{
+		ReportName: name,
+		ReportType: "CAMPAIGN_PERFORMANCE_REPORT",
+		FieldNames: fieldNames,
+		DateFrom:   dateFrom,
+		DateTo:     dateTo,
+	}
+	c.applyConversionSettings(&definition)
+
+	rows, err := c.GetReport(ctx, definition)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get campaign report: %w", err)
 	}
 
-	if response.Error != nil {
-		return nil, fmt.Errorf("API error: %s (code: %d)", response.Error.ErrorString, response.Error.ErrorCode)
+	report := make([]ReportRow, 0, len(rows))
+	for _, row := range rows {
+		// With goals the API splits conversions into columns per goal, CPA is recalculated from their sum
+		conversions := reportSum(row, "Conversions")
+		var cpa float64
+		if conversions > 0 {
+			cpa = reportFloat(row, "Cost") / float64(conversions)
+		}
+
+		report = append(report, ReportRow{
+			Date:          row["Date"],
+			CampaignId:    reportInt(row, "CampaignId"),
+			CampaignName:  row["CampaignName"],
+			AdNetworkType: row["AdNetworkType"],
+			Impressions:   reportInt(row, "Impressions"),
+			Clicks:        reportInt(row, "Clicks"),
+			Cost:          reportFloat(row, "Cost"),
+			CTR:           reportFloat(row, "Ctr"),
+			AvgCpc:        reportFloat(row, "AvgCpc"),
+			Conversions:   conversions,
+			CPA:           cpa,
+		})
+	}
+
+	return report, nil
+}
+
+// AdGroupReportRow represents a row of ad group report
+type AdGroupReportRow struct {
+	CampaignId  int64   `json:"CampaignId"`
+	AdGroupId   int64   `json:"AdGroupId"`
+	AdGroupName string  `json:"AdGroupName"`
+	Impressions int64   `json:"Impressions"`
+	Clicks      int64   `json:"Clicks"`
+	Cost        float64 `json:"Cost"`
+	Conversions int64   `json:"Conversions,omitempty"`
+}
+
+// CriterionReportRow represents a row of keyword (criterion) report
+type CriterionReportRow struct {
+	CampaignId    int64   `json:"CampaignId"`
+	AdGroupId     int64   `json:"AdGroupId"`
+	CriterionId   int64   `json:"CriterionId"`
+	Criterion     string  `json:"Criterion"`     // Keyword phrase, autotargeting or audience
+	CriterionType string  `json:"CriterionType"` // KEYWORD, AUTOTARGETING, AUDIENCE_TARGET, ...
+	Impressions   int64   `json:"Impressions"`
+	Clicks        int64   `json:"Clicks"`
+	Cost          float64 `json:"Cost"`
+	Conversions   int64   `json:"Conversions,omitempty"`
+}
+
+// GetAdGroupReport retrieves ADGROUP_PERFORMANCE_REPORT for a date range
+// Documentation: https://yandex.ru/dev/direct/doc/reports/report-format.html
+func (c *YandexDirectClient) GetAdGroupReport(ctx context.Context, dateFrom, dateTo string) ([]AdGroupReportRow, error) {
+	definition := ReportDefinition{
+		ReportName: "Ad Group Report",
+		ReportType: "ADGROUP_PERFORMANCE_REPORT",
+		FieldNames: []string{"CampaignId", "AdGroupId", "AdGroupName", "Impressions", "Clicks", "Cost", "Conversions"},
+		DateFrom:   dateFrom,
+		DateTo:     dateTo,
 	}
+	c.applyConversionSettings(&definition)
 
-	return response.Result.Report.Rows, nil
+	rows, err := c.GetReport(ctx, definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ad group report: %w", err)
+	}
+
+	report := make([]AdGroupReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, AdGroupReportRow{
+			CampaignId:  reportInt(row, "CampaignId"),
+			AdGroupId:   reportInt(row, "AdGroupId"),
+			AdGroupName: row["AdGroupName"],
+			Impressions: reportInt(row, "Impressions"),
+			Clicks:      reportInt(row, "Clicks"),
+			Cost:        reportFloat(row, "Cost"),
+			Conversions: reportSum(row, "Conversions"),
+		})
+	}
+
+	return report, nil
+}
+
+// GetCriteriaReport retrieves CRITERIA_PERFORMANCE_REPORT (keywords and other targeting criteria) for a date range
+func (c *YandexDirectClient) GetCriteriaReport(ctx context.Context, dateFrom, dateTo string) ([]CriterionReportRow, error) {
+	definition := ReportDefinition{
+		ReportName: "Criteria Report",
+		ReportType: "CRITERIA_PERFORMANCE_REPORT",
+		FieldNames: []string{"CampaignId", "AdGroupId", "CriterionId", "Criterion", "CriterionType", "Impressions", "Clicks", "Cost", "Conversions"},
+		DateFrom:   dateFrom,
+		DateTo:     dateTo,
+	}
+	c.applyConversionSettings(&definition)
+
+	rows, err := c.GetReport(ctx, definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get criteria report: %w", err)
+	}
+
+	report := make([]CriterionReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, CriterionReportRow{
+			CampaignId:    reportInt(row, "CampaignId"),
+			AdGroupId:     reportInt(row, "AdGroupId"),
+			CriterionId:   reportInt(row, "CriterionId"),
+			Criterion:     row["Criterion"],
+			CriterionType: row["CriterionType"],
+			Impressions:   reportInt(row, "Impressions"),
+			Clicks:        reportInt(row, "Clicks"),
+			Cost:          reportFloat(row, "Cost"),
+			Conversions:   reportSum(row, "Conversions"),
+		})
+	}
+
+	return report, nil
+}
+
+// applyConversionSettings limits conversion fields of a report to configured goals and attribution model
+func (c *YandexDirectClient) applyConversionSettings(definition *ReportDefinition) {
+	if len(c.conversionSettings.Goals) == 0 {
+		return
+	}
+	definition.Goals = c.conversionSettings.Goals
+	if c.conversionSettings.AttributionModel != "" {
+		definition.AttributionModels = []string{c.conversionSettings.AttributionModel}
+	}
+}
+
+// apiURL returns base URL of Direct API
+func (c *YandexDirectClient) apiURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	// Fallback for old clients without baseURL set
+	if c.useSandbox {
+		return yandexDirectSandboxURL
+	}
+	return yandexDirectAPIURL
 }
 
 // makeRequest performs HTTP request to Yandex Direct API
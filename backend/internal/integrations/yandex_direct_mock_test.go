@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestYandexDirectClient_GetCampaigns_Mock tests GetCampaigns with mocked HTTP server
@@ -70,41 +74,126 @@ func TestYandexDirectClient_GetCampaigns_Mock(t *testing.T) {
 	}
 }
 
-// TestYandexDirectClient_GetCampaignReport_Mock tests GetCampaignReport with mocked HTTP server
+// TestYandexDirectClient_GetCampaigns_TypesAndStrategies tests requested fields and parsing of type-specific strategies
+func TestYandexDirectClient_GetCampaigns_TypesAndStrategies(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Params struct {
+				FieldNames             []string `json:"FieldNames"`
+				TextCampaignFieldNames []string `json:"TextCampaignFieldNames"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if strings.Join(requestBody.Params.FieldNames, ",") != "Id,Name,Type,State,Status" {
+			t.Errorf("Unexpected FieldNames: %v", requestBody.Params.FieldNames)
+		}
+		if len(requestBody.Params.TextCampaignFieldNames) != 1 || requestBody.Params.TextCampaignFieldNames[0] != "BiddingStrategy" {
+			t.Errorf("Unexpected TextCampaignFieldNames: %v", requestBody.Params.TextCampaignFieldNames)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"Campaigns":[
+			{"Id":1,"Name":"РСЯ","Type":"TEXT_CAMPAIGN","State":"ON","Status":"ACCEPTED",
+			 "TextCampaign":{"BiddingStrategy":{"Search":{"BiddingStrategyType":"SERVING_OFF"},"Network":{"BiddingStrategyType":"WB_MAXIMUM_CLICKS"}}}},
+			{"Id":2,"Name":"Мастер","Type":"UNIFIED_CAMPAIGN","State":"SUSPENDED","Status":"ACCEPTED",
+			 "UnifiedCampaign":{"BiddingStrategy":{"Search":{"BiddingStrategyType":"WB_MAXIMUM_CONVERSION_RATE"},"Network":{"BiddingStrategyType":"NETWORK_DEFAULT"}}}},
+			{"Id":3,"Name":"Медийная","Type":"CPM_BANNER_CAMPAIGN","State":"OFF","Status":"DRAFT"}
+		]}}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	campaigns, err := client.GetCampaigns(context.Background())
+	if err != nil {
+		t.Fatalf("GetCampaigns failed: %v", err)
+	}
+	if len(campaigns) != 3 {
+		t.Fatalf("Expected 3 campaigns, got %d", len(campaigns))
+	}
+
+	network := campaigns[0]
+	if network.Type != "TEXT_CAMPAIGN" || network.State != "ON" || network.Status != "ACCEPTED" {
+		t.Errorf("Unexpected campaign: %+v", network)
+	}
+	if network.SearchStrategy() != "SERVING_OFF" || network.NetworkStrategy() != "WB_MAXIMUM_CLICKS" {
+		t.Errorf("Unexpected strategies: %q, %q", network.SearchStrategy(), network.NetworkStrategy())
+	}
+	if campaigns[1].SearchStrategy() != "WB_MAXIMUM_CONVERSION_RATE" {
+		t.Errorf("Expected strategy of unified campaign, got %q", campaigns[1].SearchStrategy())
+	}
+	if campaigns[2].SearchStrategy() != "" || campaigns[2].NetworkStrategy() != "" {
+		t.Error("Expected empty strategies for campaign without settings")
+	}
+}
+
+// TestYandexDirectClient_GetClientCurrency_Mock tests reading account currency from Clients.get
+func TestYandexDirectClient_GetClientCurrency_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/clients" {
+			t.Errorf("Expected path /clients, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Client-Login") != "test_client_login" {
+			t.Errorf("Expected Client-Login header, got '%s'", r.Header.Get("Client-Login"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"Clients":[{"Login":"test_client_login","Currency":"KZT"}]}}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	currency, err := client.GetClientCurrency(context.Background())
+	if err != nil {
+		t.Fatalf("GetClientCurrency failed: %v", err)
+	}
+	if currency != "KZT" {
+		t.Errorf("Expected currency KZT, got %s", currency)
+	}
+}
+
+// TestYandexDirectClient_GetCampaignReport_Mock tests GetCampaignReport with mocked HTTP server and TSV fixture
 func TestYandexDirectClient_GetCampaignReport_Mock(t *testing.T) {
+	fixture := loadFixture(t, "direct_campaign_report.tsv")
+
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify request
 		if r.URL.Path != "/reports" {
 			t.Errorf("Expected path '/reports', got '%s'", r.URL.Path)
 		}
+		if r.Header.Get("Client-Login") != "test_client_login" {
+			t.Errorf("Expected Client-Login header 'test_client_login', got '%s'", r.Header.Get("Client-Login"))
+		}
+		if r.Header.Get("processingMode") != ReportProcessingModeAuto {
+			t.Errorf("Expected processingMode 'auto', got '%s'", r.Header.Get("processingMode"))
+		}
+		if r.Header.Get("returnMoneyInMicros") != "true" {
+			t.Errorf("Expected returnMoneyInMicros 'true', got '%s'", r.Header.Get("returnMoneyInMicros"))
+		}
+		if r.Header.Get("skipReportHeader") != "true" {
+			t.Errorf("Expected skipReportHeader 'true', got '%s'", r.Header.Get("skipReportHeader"))
+		}
 
-		// Return mock report response
-		response := ReportResponse{
-			Result: struct {
-				Report struct {
-					Rows []ReportRow `json:"Rows"`
-				} `json:"Report"`
-			}{
-				Report: struct {
-					Rows []ReportRow `json:"Rows"`
-				}{
-					Rows: []ReportRow{
-						{
-							CampaignId:   1,
-							CampaignName: "Test Campaign",
-							Impressions:  1000,
-							Clicks:       50,
-							Cost:         500.0,
-							CTR:          5.0,
-							AvgCpc:       10.0,
-						},
-					},
-				},
-			},
+		var requestBody struct {
+			Params struct {
+				SelectionCriteria map[string]interface{} `json:"SelectionCriteria"`
+				Format            string                 `json:"Format"`
+				DateRangeType     string                 `json:"DateRangeType"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if requestBody.Params.Format != "TSV" || requestBody.Params.DateRangeType != "CUSTOM_DATE" {
+			t.Errorf("Unexpected report params: %+v", requestBody.Params)
+		}
+		if requestBody.Params.SelectionCriteria["DateFrom"] != "2024-01-01" {
+			t.Errorf("Expected DateFrom in SelectionCriteria, got %v", requestBody.Params.SelectionCriteria)
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		w.Write(fixture)
 	}))
 	defer mockServer.Close()
 
@@ -116,13 +205,354 @@ func TestYandexDirectClient_GetCampaignReport_Mock(t *testing.T) {
 		t.Fatalf("GetCampaignReport failed: %v", err)
 	}
 
-	if len(report) != 1 {
-		t.Errorf("Expected 1 report row, got %d", len(report))
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 report rows, got %d", len(report))
 	}
 
 	if report[0].Impressions != 1000 || report[0].Clicks != 50 {
 		t.Errorf("Expected {Impressions: 1000, Clicks: 50}, got {Impressions: %d, Clicks: %d}", report[0].Impressions, report[0].Clicks)
 	}
+	if report[0].Cost != 500.0 || report[0].AvgCpc != 10.0 || report[0].CPA != 100.0 {
+		t.Errorf("Expected money converted from micros {Cost: 500, AvgCpc: 10, CPA: 100}, got {Cost: %v, AvgCpc: %v, CPA: %v}", report[0].Cost, report[0].AvgCpc, report[0].CPA)
+	}
+	if report[0].CampaignName != "Test Campaign" || report[0].Conversions != 5 {
+		t.Errorf("Unexpected first row: %+v", report[0])
+	}
+	if report[1].AvgCpc != 0 || report[1].Conversions != 0 {
+		t.Errorf("Expected '--' values parsed as 0, got %+v", report[1])
+	}
+}
+
+// TestYandexDirectClient_GetCampaignDailyReport_Mock tests daily report parsing
+func TestYandexDirectClient_GetCampaignDailyReport_Mock(t *testing.T) {
+	fixture := loadFixture(t, "direct_campaign_daily_report.tsv")
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Params struct {
+				FieldNames []string `json:"FieldNames"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		fieldNames := requestBody.Params.FieldNames
+		if len(fieldNames) == 0 || fieldNames[0] != "Date" || fieldNames[len(fieldNames)-1] != "AdNetworkType" {
+			t.Errorf("Expected Date as first field and AdNetworkType as last field, got %v", fieldNames)
+		}
+
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		w.Write(fixture)
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+
+	report, err := client.GetCampaignDailyReport(context.Background(), "2024-01-01", "2024-01-02")
+	if err != nil {
+		t.Fatalf("GetCampaignDailyReport failed: %v", err)
+	}
+
+	if len(report) != 3 {
+		t.Fatalf("Expected 3 report rows, got %d", len(report))
+	}
+	if report[0].Date != "2024-01-01" || report[1].Date != "2024-01-01" || report[2].Date != "2024-01-02" {
+		t.Errorf("Unexpected dates: %s, %s, %s", report[0].Date, report[1].Date, report[2].Date)
+	}
+	if report[0].AdNetworkType != AdNetworkTypeSearch || report[1].AdNetworkType != AdNetworkTypeNetwork {
+		t.Errorf("Unexpected ad network types: %s, %s", report[0].AdNetworkType, report[1].AdNetworkType)
+	}
+	if report[1].Cost != 50.0 || report[1].Conversions != 0 {
+		t.Errorf("Unexpected network row: %+v", report[1])
+	}
+	if report[2].Cost != 200.0 || report[2].Conversions != 2 {
+		t.Errorf("Unexpected third row: %+v", report[2])
+	}
+}
+
+// TestYandexDirectClient_GetCampaignReport_Goals tests conversions by goals and attribution model
+func TestYandexDirectClient_GetCampaignReport_Goals(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Params struct {
+				Goals             []int64  `json:"Goals"`
+				AttributionModels []string `json:"AttributionModels"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(requestBody.Params.Goals) != MaxReportGoals || requestBody.Params.Goals[0] != 1 {
+			t.Errorf("Expected first %d goals, got %v", MaxReportGoals, requestBody.Params.Goals)
+		}
+		if len(requestBody.Params.AttributionModels) != 1 || requestBody.Params.AttributionModels[0] != AttributionModelLastSignificantClick {
+			t.Errorf("Expected AttributionModels [LSC], got %v", requestBody.Params.AttributionModels)
+		}
+
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		w.Write([]byte("CampaignId\tCampaignName\tImpressions\tClicks\tCost\tCtr\tAvgCpc\tConversions_1_LSC\tCostPerConversion_1_LSC\tConversions_2_LSC\tCostPerConversion_2_LSC\n" +
+			"111\tTest Campaign\t1000\t50\t600000000\t5.00\t12000000\t4\t150000000\t2\t300000000\n" +
+			"222\tNo Conversions\t100\t3\t30000000\t3.00\t10000000\t--\t--\t--\t--\n"))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	client.SetConversionSettings(ConversionSettings{
+		Goals:            []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		AttributionModel: AttributionModelLastSignificantClick,
+	})
+
+	report, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetCampaignReport failed: %v", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 report rows, got %d", len(report))
+	}
+	if report[0].Conversions != 6 || report[0].CPA != 100.0 {
+		t.Errorf("Expected conversions summed over goals {Conversions: 6, CPA: 100}, got {Conversions: %d, CPA: %v}", report[0].Conversions, report[0].CPA)
+	}
+	if report[1].Conversions != 0 || report[1].CPA != 0 {
+		t.Errorf("Expected no conversions for second row, got %+v", report[1])
+	}
+}
+
+// TestYandexDirectClient_GetCampaignReport_CostSettings tests VAT and discount flags of report requests
+func TestYandexDirectClient_GetCampaignReport_CostSettings(t *testing.T) {
+	var reportNames []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Params struct {
+				ReportName      string `json:"ReportName"`
+				IncludeVAT      string `json:"IncludeVAT"`
+				IncludeDiscount string `json:"IncludeDiscount"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		reportNames = append(reportNames, requestBody.Params.ReportName)
+		if len(reportNames) == 1 && (requestBody.Params.IncludeVAT != "NO" || requestBody.Params.IncludeDiscount != "NO") {
+			t.Errorf("Expected default IncludeVAT=NO, IncludeDiscount=NO, got %s, %s", requestBody.Params.IncludeVAT, requestBody.Params.IncludeDiscount)
+		}
+		if len(reportNames) == 2 && (requestBody.Params.IncludeVAT != "YES" || requestBody.Params.IncludeDiscount != "YES") {
+			t.Errorf("Expected IncludeVAT=YES, IncludeDiscount=YES, got %s, %s", requestBody.Params.IncludeVAT, requestBody.Params.IncludeDiscount)
+		}
+
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		w.Write([]byte("CampaignId\tCampaignName\tImpressions\tClicks\tCost\tCtr\tAvgCpc\tConversions\tCostPerConversion\n" +
+			"111\tTest Campaign\t1000\t50\t600000000\t5.00\t12000000\t4\t150000000\n"))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	if _, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31"); err != nil {
+		t.Fatalf("GetCampaignReport failed: %v", err)
+	}
+
+	client.SetCostSettings(CostSettings{IncludeVAT: true, IncludeDiscount: true})
+	if _, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31"); err != nil {
+		t.Fatalf("GetCampaignReport failed: %v", err)
+	}
+
+	if len(reportNames) != 2 || reportNames[0] == reportNames[1] {
+		t.Errorf("Expected different report names for different cost settings, got %v", reportNames)
+	}
+}
+
+// TestYandexDirectClient_GetAdGroupAndCriteriaReports_Mock tests ad group and keyword report parsing
+func TestYandexDirectClient_GetAdGroupAndCriteriaReports_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody struct {
+			Params struct {
+				ReportType string  `json:"ReportType"`
+				Goals      []int64 `json:"Goals"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(requestBody.Params.Goals) != 1 || requestBody.Params.Goals[0] != 1 {
+			t.Errorf("Expected Goals [1], got %v", requestBody.Params.Goals)
+		}
+
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		switch requestBody.Params.ReportType {
+		case "ADGROUP_PERFORMANCE_REPORT":
+			w.Write([]byte("CampaignId\tAdGroupId\tAdGroupName\tImpressions\tClicks\tCost\tConversions_1_AUTO\n" +
+				"111\t501\tBrand\t1000\t50\t600000000\t4\n" +
+				"111\t502\tGeneric\t200\t4\t80000000\t--\n"))
+		case "CRITERIA_PERFORMANCE_REPORT":
+			w.Write([]byte("CampaignId\tAdGroupId\tCriterionId\tCriterion\tCriterionType\tImpressions\tClicks\tCost\tConversions_1_AUTO\n" +
+				"111\t501\t9001\tкупить диван\tKEYWORD\t700\t30\t450000000\t3\n"))
+		default:
+			t.Errorf("Unexpected report type %q", requestBody.Params.ReportType)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	client.SetConversionSettings(ConversionSettings{Goals: []int64{1}, AttributionModel: AttributionModelAuto})
+
+	adGroups, err := client.GetAdGroupReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetAdGroupReport failed: %v", err)
+	}
+	if len(adGroups) != 2 {
+		t.Fatalf("Expected 2 ad group rows, got %d", len(adGroups))
+	}
+	if adGroups[0].AdGroupId != 501 || adGroups[0].AdGroupName != "Brand" || adGroups[0].Cost != 600.0 || adGroups[0].Conversions != 4 {
+		t.Errorf("Unexpected first ad group row: %+v", adGroups[0])
+	}
+	if adGroups[1].Conversions != 0 {
+		t.Errorf("Expected no conversions for second ad group, got %+v", adGroups[1])
+	}
+
+	criteria, err := client.GetCriteriaReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetCriteriaReport failed: %v", err)
+	}
+	if len(criteria) != 1 {
+		t.Fatalf("Expected 1 criterion row, got %d", len(criteria))
+	}
+	if criteria[0].CriterionId != 9001 || criteria[0].Criterion != "купить диван" || criteria[0].CriterionType != "KEYWORD" ||
+		criteria[0].AdGroupId != 501 || criteria[0].Cost != 450.0 || criteria[0].Conversions != 3 {
+		t.Errorf("Unexpected criterion row: %+v", criteria[0])
+	}
+}
+
+// TestYandexDirectClient_GetReport_OfflineMode tests polling while report is being built
+func TestYandexDirectClient_GetReport_OfflineMode(t *testing.T) {
+	fixture := loadFixture(t, "direct_campaign_report.tsv")
+
+	attempts := 0
+	var reportNames []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		var requestBody struct {
+			Params struct {
+				ReportName string `json:"ReportName"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&requestBody)
+		reportNames = append(reportNames, requestBody.Params.ReportName)
+
+		switch attempts {
+		case 1:
+			w.Header().Set("retryIn", "0")
+			w.WriteHeader(http.StatusCreated) // Report queued
+		case 2:
+			w.Header().Set("retryIn", "0")
+			w.WriteHeader(http.StatusAccepted) // Report is being built
+		default:
+			w.Write(fixture)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	options := DefaultReportOptions()
+	options.ProcessingMode = ReportProcessingModeOffline
+	client.SetReportOptions(options)
+
+	report, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetCampaignReport failed: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(report) != 2 {
+		t.Errorf("Expected 2 report rows, got %d", len(report))
+	}
+	// Offline report is identified by its name, it must not change between attempts
+	if reportNames[0] != reportNames[2] {
+		t.Errorf("Expected the same ReportName on every attempt, got %v", reportNames)
+	}
+}
+
+// TestYandexDirectClient_GetReport_Timeout tests that polling stops after MaxWait
+func TestYandexDirectClient_GetReport_Timeout(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("retryIn", "60")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	options := DefaultReportOptions()
+	options.MaxWait = time.Second
+	client.SetReportOptions(options)
+
+	if _, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31"); err == nil {
+		t.Fatal("Expected error when report is not ready in time, got nil")
+	}
+}
+
+// TestYandexDirectClient_GetReport_WithHeaderAndSummary tests parsing when report header and summary are requested
+func TestYandexDirectClient_GetReport_WithHeaderAndSummary(t *testing.T) {
+	fixture := loadFixture(t, "direct_campaign_report_with_header.tsv")
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("returnMoneyInMicros") != "false" || r.Header.Get("skipReportHeader") != "false" || r.Header.Get("skipReportSummary") != "false" {
+			t.Errorf("Unexpected report headers: %v", r.Header)
+		}
+		w.Write(fixture)
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+	client.SetReportOptions(ReportOptions{
+		ProcessingMode:      ReportProcessingModeOnline,
+		ReturnMoneyInMicros: false,
+		SkipReportHeader:    false,
+		SkipReportSummary:   false,
+	})
+
+	report, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetCampaignReport failed: %v", err)
+	}
+
+	if len(report) != 1 {
+		t.Fatalf("Expected 1 report row, got %d", len(report))
+	}
+	if report[0].Cost != 500.0 || report[0].CampaignId != 1 {
+		t.Errorf("Unexpected row: %+v", report[0])
+	}
+}
+
+// TestYandexDirectClient_GetReport_Error tests Reports API error response
+func TestYandexDirectClient_GetReport_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"request_id":"1","error_code":"8000","error_string":"Invalid request","error_detail":"Field Cpa is not supported"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexDirectClientWithURL("test_token", "test_client_login", mockServer.URL)
+
+	_, err := client.GetCampaignReport(context.Background(), "2024-01-01", "2024-01-31")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "Invalid request (code: 8000)") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// loadFixture reads test fixture from testdata directory
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return data
 }
 
 // TestYandexDirectClient_ErrorHandling tests error handling
@@ -0,0 +1,395 @@
+package integrations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report processing modes
+// Documentation: https://yandex.ru/dev/direct/doc/reports/mode.html
+const (
+	ReportProcessingModeAuto    = "auto"
+	ReportProcessingModeOnline  = "online"
+	ReportProcessingModeOffline = "offline"
+)
+
+const (
+	// defaultReportRetryIn is used when the API does not return retryIn header
+	defaultReportRetryIn = 5 * time.Second
+	// moneyMicros is the multiplier used by Reports API when returnMoneyInMicros is enabled
+	moneyMicros = 1000000
+	// MaxReportGoals is the maximum number of goals in Goals parameter of a report
+	MaxReportGoals = 10
+)
+
+// Attribution models for conversions in Reports API
+// Documentation: https://yandex.ru/dev/direct/doc/reports/attribution.html
+const (
+	AttributionModelFirstClick                  = "FC"
+	AttributionModelLastClick                   = "LC"
+	AttributionModelLastSignificantClick        = "LSC"
+	AttributionModelLastYandexDirectClick       = "LYDC"
+	AttributionModelFirstClickCrossDevice       = "FCCD"
+	AttributionModelLastSignificantCrossDevice  = "LSCCD"
+	AttributionModelLastYandexDirectCrossDevice = "LYDCCD"
+	AttributionModelAuto                        = "AUTO"
+)
+
+// attributionModels lists attribution models accepted by Reports API
+var attributionModels = map[string]bool{
+	AttributionModelFirstClick:                  true,
+	AttributionModelLastClick:                   true,
+	AttributionModelLastSignificantClick:        true,
+	AttributionModelLastYandexDirectClick:       true,
+	AttributionModelFirstClickCrossDevice:       true,
+	AttributionModelLastSignificantCrossDevice:  true,
+	AttributionModelLastYandexDirectCrossDevice: true,
+	AttributionModelAuto:                        true,
+}
+
+// IsValidAttributionModel reports whether model is supported by Reports API
+func IsValidAttributionModel(model string) bool {
+	return attributionModels[model]
+}
+
+// ReportOptions configures how Reports API builds and returns reports
+// Documentation: https://yandex.ru/dev/direct/doc/reports/headers.html
+type ReportOptions struct {
+	ProcessingMode      string        // auto, online or offline
+	ReturnMoneyInMicros bool          // Money values come as integers multiplied by 1 000 000
+	SkipReportHeader    bool          // Omit report name and date range line
+	SkipReportSummary   bool          // Omit "Total rows" line
+	MaxWait             time.Duration // Upper bound for offline report polling
+}
+
+// DefaultReportOptions returns options suitable for monthly sync
+func DefaultReportOptions() ReportOptions {
+	return ReportOptions{
+		ProcessingMode:      ReportProcessingModeAuto,
+		ReturnMoneyInMicros: true,
+		SkipReportHeader:    true,
+		SkipReportSummary:   true,
+		MaxWait:             10 * time.Minute,
+	}
+}
+
+// ReportDefinition describes a Reports API report
+// Documentation: https://yandex.ru/dev/direct/doc/reports/spec.html
+type ReportDefinition struct {
+	ReportName string
+	ReportType string
+	FieldNames []string
+	DateFrom   string
+	DateTo     string
+	Filter     []ReportFilter
+	// Goals limits Conversions and CostPerConversion to these Metrica goals (up to 10)
+	// With goals set, the API returns one column per goal and model, e.g. Conversions_123_LSC
+	Goals             []int64
+	AttributionModels []string
+}
+
+// ReportFilter represents a SelectionCriteria filter item
+type ReportFilter struct {
+	Field    string   `json:"Field"`
+	Operator string   `json:"Operator"`
+	Values   []string `json:"Values"`
+}
+
+// moneyReportFields lists Reports API fields that are affected by returnMoneyInMicros
+var moneyReportFields = map[string]bool{
+	"Cost":              true,
+	"AvgCpc":            true,
+	"AvgCpm":            true,
+	"CostPerConversion": true,
+	"Revenue":           true,
+	"Profit":            true,
+}
+
+// ConversionSettings selects goals and attribution model for conversion fields of reports
+type ConversionSettings struct {
+	Goals            []int64
+	AttributionModel string
+}
+
+// CostSettings selects the basis of money fields of reports
+// Documentation: https://yandex.ru/dev/direct/doc/reports/spec.html
+type CostSettings struct {
+	IncludeVAT      bool // Money fields include VAT
+	IncludeDiscount bool // Money fields account for the discount of the client
+}
+
+// SetReportOptions overrides Reports API options of the client
+func (c *YandexDirectClient) SetReportOptions(options ReportOptions) {
+	c.reportOptions = options
+}
+
+// SetConversionSettings sets goals and attribution model used for Conversions and CostPerConversion
+// Only the first 10 goals are used as Reports API does not accept more
+func (c *YandexDirectClient) SetConversionSettings(settings ConversionSettings) {
+	if len(settings.Goals) > MaxReportGoals {
+		settings.Goals = settings.Goals[:MaxReportGoals]
+	}
+	c.conversionSettings = settings
+}
+
+// SetCostSettings sets VAT and discount inclusion of Cost, AvgCpc and CostPerConversion
+func (c *YandexDirectClient) SetCostSettings(settings CostSettings) {
+	c.costSettings = settings
+}
+
+// GetReport builds a report and returns its rows keyed by field name
+// Handles offline mode: polls while API answers 201/202 waiting retryIn seconds between attempts
+// Money fields are converted to currency units when returnMoneyInMicros is enabled
+func (c *YandexDirectClient) GetReport(ctx context.Context, definition ReportDefinition) ([]map[string]string, error) {
+	requestBody := c.buildReportRequest(definition)
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	maxWait := c.reportOptions.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultReportOptions().MaxWait
+	}
+	deadline := time.Now().Add(maxWait)
+
+	for {
+		status, retryIn, body, err := c.doReportRequest(ctx, jsonBody)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status {
+		case http.StatusOK:
+			return c.parseReportTSV(body)
+		case http.StatusCreated, http.StatusAccepted:
+			// Report is queued or being built in offline mode
+			if time.Now().Add(retryIn).After(deadline) {
+				return nil, fmt.Errorf("report %q is not ready after %s", definition.ReportName, maxWait)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryIn):
+			}
+		default:
+			return nil, parseReportError(status, body)
+		}
+	}
+}
+
+// buildReportRequest creates Reports API request body
+// ReportName must be unique for different report parameters, so a hash of parameters is appended
+func (c *YandexDirectClient) buildReportRequest(definition ReportDefinition) map[string]interface{} {
+	selection := map[string]interface{}{}
+	if definition.DateFrom != "" && definition.DateTo != "" {
+		selection["DateFrom"] = definition.DateFrom
+		selection["DateTo"] = definition.DateTo
+	}
+	if len(definition.Filter) > 0 {
+		selection["Filter"] = definition.Filter
+	}
+
+	dateRangeType := "CUSTOM_DATE"
+	if definition.DateFrom == "" || definition.DateTo == "" {
+		dateRangeType = "AUTO"
+	}
+
+	params := map[string]interface{}{
+		"SelectionCriteria": selection,
+		"FieldNames":        definition.FieldNames,
+		"ReportName":        reportName(definition, c.costSettings, c.clientLogin),
+		"ReportType":        definition.ReportType,
+		"DateRangeType":     dateRangeType,
+		"Format":            "TSV",
+		"IncludeVAT":        yesNo(c.costSettings.IncludeVAT),
+		"IncludeDiscount":   yesNo(c.costSettings.IncludeDiscount),
+	}
+	if len(definition.Goals) > 0 {
+		params["Goals"] = definition.Goals
+	}
+	if len(definition.AttributionModels) > 0 {
+		params["AttributionModels"] = definition.AttributionModels
+	}
+
+	return map[string]interface{}{"params": params}
+}
+
+// reportName builds unique report name from definition parameters
+func reportName(definition ReportDefinition, costSettings CostSettings, clientLogin string) string {
+	filter, _ := json.Marshal(definition.Filter)
+	goals, _ := json.Marshal(definition.Goals)
+	key := strings.Join(definition.FieldNames, ",") + "|" + string(filter) + "|" + string(goals) + "|" +
+		strings.Join(definition.AttributionModels, ",") + "|" + clientLogin + "|" +
+		yesNo(costSettings.IncludeVAT) + yesNo(costSettings.IncludeDiscount)
+	return fmt.Sprintf("%s %s %s %08x", definition.ReportName, definition.DateFrom, definition.DateTo, crc32.ChecksumIEEE([]byte(key)))
+}
+
+// yesNo formats a flag as Reports API YES/NO value
+func yesNo(value bool) string {
+	if value {
+		return "YES"
+	}
+	return "NO"
+}
+
+// doReportRequest performs a single Reports API request
+// Returns HTTP status, retryIn delay and response body
+func (c *YandexDirectClient) doReportRequest(ctx context.Context, jsonBody []byte) (int, time.Duration, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL()+"/reports", bytes.NewReader(jsonBody))
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	processingMode := c.reportOptions.ProcessingMode
+	if processingMode == "" {
+		processingMode = ReportProcessingModeAuto
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.clientLogin != "" {
+		req.Header.Set("Client-Login", c.clientLogin)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept-Language", "ru")
+	req.Header.Set("processingMode", processingMode)
+	req.Header.Set("returnMoneyInMicros", strconv.FormatBool(c.reportOptions.ReturnMoneyInMicros))
+	req.Header.Set("skipReportHeader", strconv.FormatBool(c.reportOptions.SkipReportHeader))
+	req.Header.Set("skipReportSummary", strconv.FormatBool(c.reportOptions.SkipReportSummary))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	retryIn := defaultReportRetryIn
+	if value := resp.Header.Get("retryIn"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			retryIn = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return resp.StatusCode, retryIn, body, nil
+}
+
+// parseReportError converts Reports API error response to error
+func parseReportError(status int, body []byte) error {
+	var errorResponse struct {
+		Error struct {
+			ErrorCode   string `json:"error_code"`
+			ErrorString string `json:"error_string"`
+			ErrorDetail string `json:"error_detail"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.ErrorString != "" {
+		return fmt.Errorf("API error: %s (code: %s): %s", errorResponse.Error.ErrorString, errorResponse.Error.ErrorCode, errorResponse.Error.ErrorDetail)
+	}
+	return fmt.Errorf("API request failed with status %d: %s", status, string(body))
+}
+
+// parseReportTSV parses TSV report into rows keyed by column name
+func (c *YandexDirectClient) parseReportTSV(body []byte) ([]map[string]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	if !c.reportOptions.SkipReportHeader {
+		// First line contains report name and date range
+		if !scanner.Scan() {
+			return nil, scanner.Err()
+		}
+	}
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+		return []map[string]string{}, nil
+	}
+	columns := strings.Split(strings.TrimRight(scanner.Text(), "\r"), "\t")
+
+	rows := make([]map[string]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "Total rows:") {
+			continue
+		}
+
+		values := strings.Split(line, "\t")
+		if len(values) != len(columns) {
+			return nil, fmt.Errorf("report row has %d columns, expected %d", len(values), len(columns))
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if value == "--" {
+				value = ""
+			}
+			if value != "" && isMoneyReportField(column) && c.reportOptions.ReturnMoneyInMicros {
+				micros, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s value %q: %w", column, value, err)
+				}
+				value = strconv.FormatFloat(micros/moneyMicros, 'f', -1, 64)
+			}
+			row[column] = value
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	return rows, nil
+}
+
+// isMoneyReportField checks whether column holds money value
+// Per-goal columns like CostPerConversion_123_LSC are matched by their base field name
+func isMoneyReportField(column string) bool {
+	if moneyReportFields[column] {
+		return true
+	}
+	if i := strings.Index(column, "_"); i > 0 {
+		return moneyReportFields[column[:i]]
+	}
+	return false
+}
+
+// reportSum sums integer values of field and its per-goal columns (field_<goal>_<model>)
+func reportSum(row map[string]string, field string) int64 {
+	var sum int64
+	for column := range row {
+		if column == field || strings.HasPrefix(column, field+"_") {
+			sum += reportInt(row, column)
+		}
+	}
+	return sum
+}
+
+// reportInt parses integer report value, empty value is 0
+func reportInt(row map[string]string, field string) int64 {
+	value, _ := strconv.ParseInt(row[field], 10, 64)
+	return value
+}
+
+// reportFloat parses float report value, empty value is 0
+func reportFloat(row map[string]string, field string) float64 {
+	value, _ := strconv.ParseFloat(row[field], 64)
+	return value
+}
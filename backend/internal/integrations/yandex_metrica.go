@@ -15,35 +15,53 @@ import (
 const (
 	yandexMetricaAPIURL        = "https://api-metrika.yandex.net/stat/v1/data"
 	yandexMetricaManagementURL = "https://api-metrika.yandex.net/management/v1"
+
+	// dailyRowsLimit is enough for a year of daily rows in one request
+	dailyRowsLimit = 400
+	// goalsPerRequest keeps goal visits and reaches within the 20 metrics per request limit
+	goalsPerRequest = 10
+	// metricsPerRequest is the limit of metrics in one request
+	metricsPerRequest = 20
+	// directCampaignsLimit is enough for Direct campaigns of a project in one request
+	directCampaignsLimit = 1000
+
+	// DimensionDirectCampaign is the Direct campaign of the last click from Direct, its id is the campaign ID
+	DimensionDirectCampaign = "ym:s:lastDirectClickOrder"
 )
 
 // YandexMetricaClient handles integration with Yandex.Metrica API
 type YandexMetricaClient struct {
-	token      string
-	httpClient *http.Client
-	baseURL    string // For testing: allows overriding base URL
+	token         string
+	httpClient    *http.Client
+	logsClient    *http.Client // Streams Logs API parts, see DownloadLogRequestPart
+	baseURL       string       // For testing: allows overriding base URL
+	managementURL string       // Management API base URL (also serves Logs API)
+
+	logsPollInterval time.Duration // Pause between Logs API status checks
+	logsMaxWait      time.Duration // Upper bound for Logs API request preparation
 }
 
 // NewYandexMetricaClient creates a new Metrica client
 // token: OAuth token for authentication
 func NewYandexMetricaClient(token string) *YandexMetricaClient {
 	return &YandexMetricaClient{
-		token:   token,
-		baseURL: yandexMetricaAPIURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		token:         token,
+		baseURL:       yandexMetricaAPIURL,
+		managementURL: yandexMetricaManagementURL,
+		httpClient:    newAPIHTTPClient(),
+		logsClient:    &http.Client{},
 	}
 }
 
 // NewYandexMetricaClientWithURL creates a new Metrica client with custom base URL (for testing)
+// Management API requests are sent to the same base URL
 func NewYandexMetricaClientWithURL(token, baseURL string) *YandexMetricaClient {
 	return &YandexMetricaClient{
-		token:   token,
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		token:         token,
+		baseURL:       baseURL,
+		managementURL: baseURL,
+		httpClient:    newAPIHTTPClient(),
+		logsClient:    &http.Client{},
 	}
 }
 
@@ -60,9 +78,41 @@ type MetricsData struct {
 
 // Dimension represents a dimension value
 type Dimension struct {
-	Name string `json:"name"`
+	Name string      `json:"name"`
+	ID   DimensionID `json:"id,omitempty"` // Machine-readable value (e.g. "organic" for lastTrafficSource)
+}
+
+// DimensionID holds dimension id which API returns either as string or as number
+type DimensionID string
+
+// UnmarshalJSON accepts both string and numeric ids
+func (d *DimensionID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*d = DimensionID(value)
+		return nil
+	}
+	if string(data) == "null" {
+		*d = ""
+		return nil
+	}
+	*d = DimensionID(data)
+	return nil
 }
 
+// Metrica dimensions used for audience breakdowns
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/attrandparams/attributes/visits/demography.html
+const (
+	DimensionAge            = "ym:s:ageIntervalName"
+	DimensionGender         = "ym:s:gender"
+	DimensionDeviceCategory = "ym:s:deviceCategory"
+	DimensionRegionCity     = "ym:s:regionCity"
+	DimensionRegionCountry  = "ym:s:regionCountry"
+)
+
 // AgeMetricsResponse represents response for age breakdown
 type AgeMetricsResponse struct {
 	Data []AgeMetricsData `json:"data"`
@@ -93,6 +143,27 @@ type MetricsResult struct {
 	AvgVisitDurationSec int     `json:"avg_visit_duration_seconds"`
 }
 
+// DailyMetricsResult represents parsed metrics for one day
+type DailyMetricsResult struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	MetricsResult
+}
+
+// DailyConversionsResult represents goal reaches for one day summed over requested goals
+type DailyConversionsResult struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Conversions int64  `json:"conversions"`
+}
+
+// TrafficSourceResult represents parsed metrics for a last traffic source
+type TrafficSourceResult struct {
+	Source      string `json:"source"` // Source id: organic, ad, direct, referral, social, ...
+	Name        string `json:"name"`
+	Visits      int64  `json:"visits"`
+	Users       int64  `json:"users"`
+	Conversions *int64 `json:"conversions,omitempty"` // Sum of goal reaches, nil if no goals requested
+}
+
 // AgeMetricsResult represents parsed age metrics result
 type AgeMetricsResult struct {
 	AgeGroup              string  `json:"age_group"`
@@ -102,6 +173,16 @@ type AgeMetricsResult struct {
 	AvgSessionDurationSec int     `json:"avg_session_duration_seconds"`
 }
 
+// BreakdownResult represents parsed metrics for a value of a breakdown dimension
+type BreakdownResult struct {
+	ID                    string  `json:"id"`   // Dimension value id, e.g. "mobile" or region id; empty for undefined values
+	Name                  string  `json:"name"` // Localized name from Metrica
+	Visits                int64   `json:"visits"`
+	Users                 int64   `json:"users"`
+	BounceRate            float64 `json:"bounce_rate"`
+	AvgSessionDurationSec int     `json:"avg_session_duration_seconds"`
+}
+
 // ConversionsResult represents parsed conversions result
 type ConversionsResult struct {
 	GoalID      int64 `json:"goal_id"`
@@ -109,6 +190,16 @@ type ConversionsResult struct {
 	Conversions int64 `json:"conversions"`
 }
 
+// RevenueResult represents parsed ecommerce and goal value revenue, for a dimension value if requested
+// Revenue is in the requested currency, or in the currency of the counter if none was requested
+type RevenueResult struct {
+	ID          string  `json:"id"` // Dimension value id, empty without dimension
+	Name        string  `json:"name"`
+	Purchases   int64   `json:"purchases"`
+	Revenue     float64 `json:"revenue"`      // Ecommerce revenue
+	GoalRevenue float64 `json:"goal_revenue"` // Sum of values of requested goals
+}
+
 // Goal represents a goal from Management API
 type Goal struct {
 	ID            int64  `json:"id"`
@@ -125,7 +216,7 @@ type GoalsResponse struct {
 // GetGoals retrieves list of goals for a counter
 // Documentation: https://yandex.ru/dev/metrika/doc/api2/management_v1/goals.html
 func (c *YandexMetricaClient) GetGoals(ctx context.Context, counterID int64) ([]Goal, error) {
-	url := fmt.Sprintf("%s/counter/%d/goals", yandexMetricaManagementURL, counterID)
+	url := fmt.Sprintf("%s/counter/%d/goals", c.managementURL, counterID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -194,49 +285,331 @@ func (c *YandexMetricaClient) GetMetrics(ctx context.Context, counterID int64, d
 	}, nil
 }
 
-// GetMetricsByAge retrieves metrics broken down by age
+// GetDailyMetrics retrieves metrics for a counter grouped by day
 // Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/data.html
-func (c *YandexMetricaClient) GetMetricsByAge(ctx context.Context, counterID int64, dateFrom, dateTo string) ([]AgeMetricsResult, error) {
+func (c *YandexMetricaClient) GetDailyMetrics(ctx context.Context, counterID int64, dateFrom, dateTo string) ([]DailyMetricsResult, error) {
 	params := url.Values{}
 	params.Set("ids", strconv.FormatInt(counterID, 10))
 	params.Set("date1", dateFrom)
 	params.Set("date2", dateTo)
 	params.Set("metrics", "ym:s:visits,ym:s:users,ym:s:bounceRate,ym:s:avgVisitDurationSeconds")
-	params.Set("dimensions", "ym:s:ageIntervalName")
+	params.Set("dimensions", "ym:s:date")
+	params.Set("sort", "ym:s:date")
+	params.Set("limit", strconv.Itoa(dailyRowsLimit))
 	params.Set("accuracy", "full")
 
-	var response AgeMetricsResponse
+	var response MetricsResponse
 	if err := c.makeRequest(ctx, params, &response); err != nil {
-		return nil, fmt.Errorf("failed to get age metrics: %w", err)
+		return nil, fmt.Errorf("failed to get daily metrics: %w", err)
 	}
 
-	// Parse response
-	results := make([]AgeMetricsResult, 0, len(response.Data))
+	results := make([]DailyMetricsResult, 0, len(response.Data))
 	for _, row := range response.Data {
 		if len(row.Dimensions) == 0 || len(row.Metrics) < 4 {
 			continue
 		}
 
-		ageGroup := row.Dimensions[0].Name
+		results = append(results, DailyMetricsResult{
+			Date: row.Dimensions[0].Name,
+			MetricsResult: MetricsResult{
+				Visits:              int64(row.Metrics[0]),
+				Users:               int64(row.Metrics[1]),
+				BounceRate:          row.Metrics[2],
+				AvgVisitDurationSec: int(row.Metrics[3]),
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// GetDailyConversions retrieves goal reaches grouped by day, summed over all specified goals
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/data.html
+func (c *YandexMetricaClient) GetDailyConversions(ctx context.Context, counterID int64, goalIDs []int64, dateFrom, dateTo string) ([]DailyConversionsResult, error) {
+	if len(goalIDs) == 0 {
+		return []DailyConversionsResult{}, nil
+	}
+
+	metricsList := make([]string, 0, len(goalIDs))
+	for _, goalID := range goalIDs {
+		metricsList = append(metricsList, fmt.Sprintf("ym:s:goal%dreaches", goalID))
+	}
+
+	params := url.Values{}
+	params.Set("ids", strconv.FormatInt(counterID, 10))
+	params.Set("date1", dateFrom)
+	params.Set("date2", dateTo)
+	params.Set("metrics", strings.Join(metricsList, ","))
+	params.Set("dimensions", "ym:s:date")
+	params.Set("sort", "ym:s:date")
+	params.Set("limit", strconv.Itoa(dailyRowsLimit))
+	params.Set("accuracy", "full")
+
+	var response ConversionsResponse
+	if err := c.makeRequest(ctx, params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get daily conversions: %w", err)
+	}
+
+	results := make([]DailyConversionsResult, 0, len(response.Data))
+	for _, row := range response.Data {
+		if len(row.Dimensions) == 0 {
+			continue
+		}
+
+		var conversions int64
+		for _, value := range row.Metrics {
+			conversions += int64(value)
+		}
+		results = append(results, DailyConversionsResult{
+			Date:        row.Dimensions[0].Name,
+			Conversions: conversions,
+		})
+	}
+
+	return results, nil
+}
+
+// GetMetricsByTrafficSource retrieves visits, users and goal reaches by last traffic source
+// goalIDs may be empty, then conversions are not requested
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/attrandparams/attributes/visits/traffic-source.html
+func (c *YandexMetricaClient) GetMetricsByTrafficSource(ctx context.Context, counterID int64, goalIDs []int64, dateFrom, dateTo string) ([]TrafficSourceResult, error) {
+	metricsList := []string{"ym:s:visits", "ym:s:users"}
+	for _, goalID := range goalIDs {
+		metricsList = append(metricsList, fmt.Sprintf("ym:s:goal%dreaches", goalID))
+	}
+
+	params := url.Values{}
+	params.Set("ids", strconv.FormatInt(counterID, 10))
+	params.Set("date1", dateFrom)
+	params.Set("date2", dateTo)
+	params.Set("metrics", strings.Join(metricsList, ","))
+	params.Set("dimensions", "ym:s:lastTrafficSource")
+	params.Set("accuracy", "full")
+
+	var response MetricsResponse
+	if err := c.makeRequest(ctx, params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get traffic source metrics: %w", err)
+	}
+
+	results := make([]TrafficSourceResult, 0, len(response.Data))
+	for _, row := range response.Data {
+		if len(row.Dimensions) == 0 || len(row.Metrics) < 2 {
+			continue
+		}
+
+		dimension := row.Dimensions[0]
+		result := TrafficSourceResult{
+			Source: string(dimension.ID),
+			Name:   dimension.Name,
+			Visits: int64(row.Metrics[0]),
+			Users:  int64(row.Metrics[1]),
+		}
+		if result.Source == "" {
+			result.Source = dimension.Name
+		}
+		if len(goalIDs) > 0 {
+			var conversions int64
+			for _, value := range row.Metrics[2:] {
+				conversions += int64(value)
+			}
+			result.Conversions = &conversions
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetRevenueMetrics retrieves ecommerce purchases and revenue and goal value revenue of the goals
+// Revenue is converted by Metrica to the currency (ISO 4217 code), empty currency keeps the counter currency
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/attrandparams/attributes/visits/ecommerce.html
+func (c *YandexMetricaClient) GetRevenueMetrics(ctx context.Context, counterID int64, goalIDs []int64, currency, dateFrom, dateTo string) (*RevenueResult, error) {
+	results, err := c.getRevenue(ctx, counterID, goalIDs, "", currency, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return &RevenueResult{}, nil // Return empty result if no data
+	}
+	return &results[0], nil
+}
+
+// GetRevenueByDirectCampaign retrieves revenue metrics by Direct campaign of the last Direct click
+// Visits without clicks from Direct are not returned; revenue is in the currency as in GetRevenueMetrics
+func (c *YandexMetricaClient) GetRevenueByDirectCampaign(ctx context.Context, counterID int64, goalIDs []int64, currency, dateFrom, dateTo string) ([]RevenueResult, error) {
+	return c.getRevenue(ctx, counterID, goalIDs, DimensionDirectCampaign, currency, dateFrom, dateTo)
+}
+
+// getRevenue requests revenue metrics, optionally grouped by a dimension
+// Goal revenue metrics are split into requests within the metrics limit and summed per dimension value
+func (c *YandexMetricaClient) getRevenue(ctx context.Context, counterID int64, goalIDs []int64, dimension, currency string, dateFrom, dateTo string) ([]RevenueResult, error) {
+	byID := make(map[string]*RevenueResult)
+	var order []string
+
+	ecommerce := true
+	for ecommerce || len(goalIDs) > 0 {
+		var metricsList []string
+		if ecommerce {
+			metricsList = append(metricsList, "ym:s:ecommercePurchases", "ym:s:ecommerceRevenue")
+		}
+		count := metricsPerRequest - len(metricsList)
+		if count > len(goalIDs) {
+			count = len(goalIDs)
+		}
+		for _, goalID := range goalIDs[:count] {
+			metricsList = append(metricsList, fmt.Sprintf("ym:s:goal%drevenue", goalID))
+		}
+		goalIDs = goalIDs[count:]
+
+		params := url.Values{}
+		params.Set("ids", strconv.FormatInt(counterID, 10))
+		params.Set("date1", dateFrom)
+		params.Set("date2", dateTo)
+		params.Set("metrics", strings.Join(metricsList, ","))
+		if dimension != "" {
+			params.Set("dimensions", dimension)
+			params.Set("limit", strconv.Itoa(directCampaignsLimit))
+		}
+		if currency != "" {
+			params.Set("currency", currency)
+		}
+		params.Set("accuracy", "full")
+
+		var response MetricsResponse
+		if err := c.makeRequest(ctx, params, &response); err != nil {
+			return nil, fmt.Errorf("failed to get revenue metrics: %w", err)
+		}
+
+		for _, row := range response.Data {
+			var id, name string
+			if dimension != "" {
+				if len(row.Dimensions) == 0 {
+					continue
+				}
+				id, name = string(row.Dimensions[0].ID), row.Dimensions[0].Name
+			}
+			result, ok := byID[id]
+			if !ok {
+				result = &RevenueResult{ID: id, Name: name}
+				byID[id] = result
+				order = append(order, id)
+			}
+
+			values := row.Metrics
+			if ecommerce {
+				if len(values) < 2 {
+					continue
+				}
+				result.Purchases += int64(values[0])
+				result.Revenue += values[1]
+				values = values[2:]
+			}
+			for _, value := range values {
+				result.GoalRevenue += value
+			}
+		}
+		ecommerce = false
+	}
+
+	results := make([]RevenueResult, 0, len(order))
+	for _, id := range order {
+		results = append(results, *byID[id])
+	}
+	return results, nil
+}
+
+// GetMetricsByAge retrieves metrics broken down by age
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/data.html
+func (c *YandexMetricaClient) GetMetricsByAge(ctx context.Context, counterID int64, dateFrom, dateTo string) ([]AgeMetricsResult, error) {
+	rows, err := c.GetMetricsByDimension(ctx, counterID, DimensionAge, 0, dateFrom, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get age metrics: %w", err)
+	}
+
+	results := make([]AgeMetricsResult, 0, len(rows))
+	for _, row := range rows {
 		results = append(results, AgeMetricsResult{
-			AgeGroup:              ageGroup,
-			Visits:                int64(row.Metrics[0]),
-			Users:                 int64(row.Metrics[1]),
-			BounceRate:            row.Metrics[2],
-			AvgSessionDurationSec: int(row.Metrics[3]),
+			AgeGroup:              row.Name,
+			Visits:                row.Visits,
+			Users:                 row.Users,
+			BounceRate:            row.BounceRate,
+			AvgSessionDurationSec: row.AvgSessionDurationSec,
+		})
+	}
+
+	return results, nil
+}
+
+// GetMetricsByDimension retrieves visit metrics broken down by a single dimension
+// Rows are sorted by visits; limit restricts number of rows, 0 keeps API default (100)
+// Metrics missing in a row are treated as zero
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/data.html
+func (c *YandexMetricaClient) GetMetricsByDimension(ctx context.Context, counterID int64, dimension string, limit int, dateFrom, dateTo string) ([]BreakdownResult, error) {
+	params := url.Values{}
+	params.Set("ids", strconv.FormatInt(counterID, 10))
+	params.Set("date1", dateFrom)
+	params.Set("date2", dateTo)
+	params.Set("metrics", "ym:s:visits,ym:s:users,ym:s:bounceRate,ym:s:avgVisitDurationSeconds")
+	params.Set("dimensions", dimension)
+	params.Set("sort", "-ym:s:visits")
+	params.Set("accuracy", "full")
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var response MetricsResponse
+	if err := c.makeRequest(ctx, params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get metrics by %s: %w", dimension, err)
+	}
+
+	results := make([]BreakdownResult, 0, len(response.Data))
+	for _, row := range response.Data {
+		if len(row.Dimensions) == 0 {
+			continue
+		}
+
+		results = append(results, BreakdownResult{
+			ID:                    string(row.Dimensions[0].ID),
+			Name:                  row.Dimensions[0].Name,
+			Visits:                int64(metricAt(row.Metrics, 0)),
+			Users:                 int64(metricAt(row.Metrics, 1)),
+			BounceRate:            metricAt(row.Metrics, 2),
+			AvgSessionDurationSec: int(metricAt(row.Metrics, 3)),
 		})
 	}
 
 	return results, nil
 }
 
-// GetConversions retrieves conversions for specified goals
+// metricAt returns metric value by index, zero if the API omitted it
+func metricAt(metrics []float64, index int) float64 {
+	if index < len(metrics) {
+		return metrics[index]
+	}
+	return 0
+}
+
+// GetConversions retrieves goal visits and reaches for specified goals
+// Goals are requested in batches as the API accepts at most 20 metrics per request
 // Documentation: https://yandex.ru/dev/metrika/doc/api2/api_v1/data.html
 func (c *YandexMetricaClient) GetConversions(ctx context.Context, counterID int64, goalIDs []int64, dateFrom, dateTo string) ([]ConversionsResult, error) {
-	if len(goalIDs) == 0 {
-		return []ConversionsResult{}, nil
+	results := make([]ConversionsResult, 0, len(goalIDs))
+	for start := 0; start < len(goalIDs); start += goalsPerRequest {
+		end := start + goalsPerRequest
+		if end > len(goalIDs) {
+			end = len(goalIDs)
+		}
+		batch, err := c.getConversionsBatch(ctx, counterID, goalIDs[start:end], dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
 	}
+	return results, nil
+}
 
+// getConversionsBatch requests goal visits and reaches for up to goalsPerRequest goals
+func (c *YandexMetricaClient) getConversionsBatch(ctx context.Context, counterID int64, goalIDs []int64, dateFrom, dateTo string) ([]ConversionsResult, error) {
 	// Build metrics list for goals
 	// Format: ym:s:goal<goal_id>visits (visits with goal) and ym:s:goal<goal_id>reaches (conversions)
 	metricsList := make([]string, 0, len(goalIDs)*2)
@@ -259,22 +632,14 @@ func (c *YandexMetricaClient) GetConversions(ctx context.Context, counterID int6
 		return nil, fmt.Errorf("failed to get conversions: %w", err)
 	}
 
-	// Parse response
-	results := make([]ConversionsResult, 0, len(goalIDs))
-	if len(response.Data) == 0 || len(response.Data[0].Metrics) == 0 {
-		// Return empty results for each goal
-		for _, goalID := range goalIDs {
-			results = append(results, ConversionsResult{
-				GoalID:      goalID,
-				Visits:      0,
-				Conversions: 0,
-			})
-		}
-		return results, nil
+	// Parse metrics: each goal has 2 metrics (visits with goal, reaches/conversions)
+	// Missing data means zero for every goal
+	var metrics []float64
+	if len(response.Data) > 0 {
+		metrics = response.Data[0].Metrics
 	}
 
-	// Parse metrics: each goal has 2 metrics (visits with goal, reaches/conversions)
-	metrics := response.Data[0].Metrics
+	results := make([]ConversionsResult, 0, len(goalIDs))
 	for i, goalID := range goalIDs {
 		visitsIdx := i * 2        // ym:s:goal<id>visits
 		conversionsIdx := i*2 + 1 // ym:s:goal<id>reaches
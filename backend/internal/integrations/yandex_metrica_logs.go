@@ -0,0 +1,332 @@
+package integrations
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Logs API sources
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/intro.html
+const (
+	LogSourceVisits = "visits"
+	LogSourceHits   = "hits"
+)
+
+// Logs API request statuses
+const (
+	LogRequestStatusCreated          = "created"
+	LogRequestStatusProcessed        = "processed"
+	LogRequestStatusCanceled         = "canceled"
+	LogRequestStatusProcessingFailed = "processing_failed"
+	LogRequestStatusAwaitingRetry    = "awaiting_retry"
+)
+
+const (
+	// defaultLogsPollInterval is the pause between status checks of a log request
+	defaultLogsPollInterval = 10 * time.Second
+	// defaultLogsMaxWait is the upper bound for log request preparation
+	defaultLogsMaxWait = 30 * time.Minute
+	// logsBatchSize is the number of rows of a log part passed to handler at once
+	logsBatchSize = 5000
+)
+
+// LogRequest represents a Logs API request
+type LogRequest struct {
+	RequestID int64            `json:"request_id"`
+	CounterID int64            `json:"counter_id"`
+	Source    string           `json:"source"`
+	Date1     string           `json:"date1"`
+	Date2     string           `json:"date2"`
+	Fields    []string         `json:"fields"`
+	Status    string           `json:"status"`
+	Size      int64            `json:"size"`
+	Parts     []LogRequestPart `json:"parts"`
+}
+
+// LogRequestPart represents a downloadable part of a processed log request
+type LogRequestPart struct {
+	PartNumber int   `json:"part_number"`
+	Size       int64 `json:"size"`
+}
+
+// logRequestResponse wraps single log request in Logs API responses
+type logRequestResponse struct {
+	LogRequest LogRequest `json:"log_request"`
+}
+
+// logRequestEvaluationResponse represents response of log request evaluation
+type logRequestEvaluationResponse struct {
+	LogRequestEvaluation struct {
+		Possible               bool  `json:"possible"`
+		MaxPossibleDayQuantity int64 `json:"max_possible_day_quantity"`
+	} `json:"log_request_evaluation"`
+}
+
+// SetLogsPolling overrides how often and how long log request status is polled
+func (c *YandexMetricaClient) SetLogsPolling(interval, maxWait time.Duration) {
+	c.logsPollInterval = interval
+	c.logsMaxWait = maxWait
+}
+
+// EvaluateLogRequest checks whether a log request for the period can be created
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/queries/evaluate.html
+func (c *YandexMetricaClient) EvaluateLogRequest(ctx context.Context, counterID int64, source string, fields []string, dateFrom, dateTo string) (bool, error) {
+	var response logRequestEvaluationResponse
+	if err := c.logsRequest(ctx, "GET", fmt.Sprintf("/counter/%d/logrequests/evaluate", counterID),
+		logRequestParams(source, fields, dateFrom, dateTo), &response); err != nil {
+		return false, fmt.Errorf("failed to evaluate log request: %w", err)
+	}
+	return response.LogRequestEvaluation.Possible, nil
+}
+
+// CreateLogRequest creates a log request for raw data of the period
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/queries/createlogrequest.html
+func (c *YandexMetricaClient) CreateLogRequest(ctx context.Context, counterID int64, source string, fields []string, dateFrom, dateTo string) (*LogRequest, error) {
+	var response logRequestResponse
+	if err := c.logsRequest(ctx, "POST", fmt.Sprintf("/counter/%d/logrequests", counterID),
+		logRequestParams(source, fields, dateFrom, dateTo), &response); err != nil {
+		return nil, fmt.Errorf("failed to create log request: %w", err)
+	}
+	return &response.LogRequest, nil
+}
+
+// GetLogRequest retrieves status and parts of a log request
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/queries/getlogrequest.html
+func (c *YandexMetricaClient) GetLogRequest(ctx context.Context, counterID, requestID int64) (*LogRequest, error) {
+	var response logRequestResponse
+	if err := c.logsRequest(ctx, "GET", fmt.Sprintf("/counter/%d/logrequest/%d", counterID, requestID), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get log request: %w", err)
+	}
+	return &response.LogRequest, nil
+}
+
+// WaitLogRequest polls a log request until it is processed
+func (c *YandexMetricaClient) WaitLogRequest(ctx context.Context, counterID, requestID int64) (*LogRequest, error) {
+	interval := c.logsPollInterval
+	if interval <= 0 {
+		interval = defaultLogsPollInterval
+	}
+	maxWait := c.logsMaxWait
+	if maxWait <= 0 {
+		maxWait = defaultLogsMaxWait
+	}
+	deadline := time.Now().Add(maxWait)
+
+	for {
+		request, err := c.GetLogRequest(ctx, counterID, requestID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch request.Status {
+		case LogRequestStatusProcessed:
+			return request, nil
+		case LogRequestStatusCreated, LogRequestStatusAwaitingRetry:
+			if time.Now().Add(interval).After(deadline) {
+				return nil, fmt.Errorf("log request %d is not ready after %s", requestID, maxWait)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		default:
+			return nil, fmt.Errorf("log request %d has status %s", requestID, request.Status)
+		}
+	}
+}
+
+// DownloadLogRequestPart downloads one part of a processed log request and passes its rows to handle
+// in batches of up to logsBatchSize rows keyed by field name (e.g. "ym:s:visitID")
+// Parts may take hundreds of megabytes, so they are streamed by a client without the shared transport:
+// it buffers bodies and limits time of an attempt
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/queries/download.html
+func (c *YandexMetricaClient) DownloadLogRequestPart(ctx context.Context, counterID, requestID int64, partNumber int, handle func(rows []map[string]string) error) error {
+	reqURL := fmt.Sprintf("%s/counter/%d/logrequest/%d/part/%d/download", c.managementURL, counterID, requestID, partNumber)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "OAuth "+c.token)
+
+	resp, err := c.logsClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return fmt.Errorf("failed to download log part %d: API request failed with status %d: %s", partNumber, resp.StatusCode, string(body))
+	}
+
+	return parseLogTSV(resp.Body, logsBatchSize, handle)
+}
+
+// CleanLogRequest removes prepared log files from Metrica storage
+// Prepared logs count towards the counter storage quota until they are cleaned
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/queries/clean.html
+func (c *YandexMetricaClient) CleanLogRequest(ctx context.Context, counterID, requestID int64) error {
+	var response logRequestResponse
+	if err := c.logsRequest(ctx, "POST", fmt.Sprintf("/counter/%d/logrequest/%d/clean", counterID, requestID), nil, &response); err != nil {
+		return fmt.Errorf("failed to clean log request: %w", err)
+	}
+	return nil
+}
+
+// PrepareLogs creates a log request for the period and waits until it is processed
+// Parts of the returned request are downloaded with DownloadLogParts, then the request is cleaned
+// with CleanLogRequest. A request that fails to process is cleaned here
+func (c *YandexMetricaClient) PrepareLogs(ctx context.Context, counterID int64, source string, fields []string, dateFrom, dateTo string) (*LogRequest, error) {
+	possible, err := c.EvaluateLogRequest(ctx, counterID, source, fields, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	if !possible {
+		return nil, fmt.Errorf("log request for %s - %s exceeds Logs API quota", dateFrom, dateTo)
+	}
+
+	created, err := c.CreateLogRequest(ctx, counterID, source, fields, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := c.WaitLogRequest(ctx, counterID, created.RequestID)
+	if err != nil {
+		// Clean with a fresh context so that a cancelled task does not leave logs in the quota
+		c.CleanLogRequest(context.WithoutCancel(ctx), counterID, created.RequestID)
+		return nil, err
+	}
+	request.RequestID = created.RequestID
+	return request, nil
+}
+
+// DownloadLogParts passes rows of each part of a processed log request to handle in batches
+func (c *YandexMetricaClient) DownloadLogParts(ctx context.Context, counterID int64, request *LogRequest, handle func(rows []map[string]string) error) error {
+	for _, part := range request.Parts {
+		if err := c.DownloadLogRequestPart(ctx, counterID, request.RequestID, part.PartNumber, handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownloadLogs creates a log request, waits for it and passes rows of each part to handle in batches
+// The request is cleaned afterwards even if download or handling fails
+func (c *YandexMetricaClient) DownloadLogs(ctx context.Context, counterID int64, source string, fields []string, dateFrom, dateTo string, handle func(rows []map[string]string) error) (err error) {
+	request, err := c.PrepareLogs(ctx, counterID, source, fields, dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Clean with a fresh context so that a cancelled task does not leave logs in the quota
+		if cleanErr := c.CleanLogRequest(context.WithoutCancel(ctx), counterID, request.RequestID); cleanErr != nil && err == nil {
+			err = cleanErr
+		}
+	}()
+
+	return c.DownloadLogParts(ctx, counterID, request, handle)
+}
+
+// logRequestParams builds query params of log request creation and evaluation
+func logRequestParams(source string, fields []string, dateFrom, dateTo string) url.Values {
+	params := url.Values{}
+	params.Set("date1", dateFrom)
+	params.Set("date2", dateTo)
+	params.Set("source", source)
+	params.Set("fields", strings.Join(fields, ","))
+	return params
+}
+
+// logsRequest performs Logs API request to Management API and decodes JSON response
+func (c *YandexMetricaClient) logsRequest(ctx context.Context, method, path string, params url.Values, response interface{}) error {
+	reqURL := c.managementURL + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "OAuth "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// parseLogTSV reads Logs API part: header line with field names followed by rows
+// Rows are passed to handle in batches of up to batchSize rows
+func parseLogTSV(r io.Reader, batchSize int, handle func(rows []map[string]string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read log part: %w", err)
+		}
+		return nil
+	}
+	columns := strings.Split(strings.TrimRight(scanner.Text(), "\r"), "\t")
+
+	rows := make([]map[string]string, 0, batchSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		values := strings.Split(line, "\t")
+		if len(values) != len(columns) {
+			return fmt.Errorf("log row has %d columns, expected %d", len(values), len(columns))
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		rows = append(rows, row)
+
+		if len(rows) >= batchSize {
+			if err := handle(rows); err != nil {
+				return err
+			}
+			rows = make([]map[string]string, 0, batchSize)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log part: %w", err)
+	}
+	if len(rows) > 0 {
+		return handle(rows)
+	}
+	return nil
+}
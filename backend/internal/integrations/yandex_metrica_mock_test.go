@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestYandexMetricaClient_GetMetrics_Mock tests GetMetrics with mocked HTTP server
@@ -65,6 +66,200 @@ func TestYandexMetricaClient_GetMetrics_Mock(t *testing.T) {
 	}
 }
 
+// TestYandexMetricaClient_GetDailyMetrics_Mock tests GetDailyMetrics with mocked HTTP server
+func TestYandexMetricaClient_GetDailyMetrics_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dimensions") != "ym:s:date" {
+			t.Errorf("Expected dimensions=ym:s:date, got dimensions=%s", r.URL.Query().Get("dimensions"))
+		}
+
+		response := MetricsResponse{
+			Data: []MetricsData{
+				{Dimensions: []Dimension{{Name: "2024-01-01"}}, Metrics: []float64{100, 80, 40, 90}},
+				{Dimensions: []Dimension{{Name: "2024-01-02"}}, Metrics: []float64{150, 120, 30, 110}},
+				{Dimensions: []Dimension{}, Metrics: []float64{1, 1, 1, 1}}, // Malformed row is skipped
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	results, err := client.GetDailyMetrics(context.Background(), 12345, "2024-01-01", "2024-01-02")
+	if err != nil {
+		t.Fatalf("GetDailyMetrics failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(results))
+	}
+	if results[1].Date != "2024-01-02" || results[1].Visits != 150 || results[1].AvgVisitDurationSec != 110 {
+		t.Errorf("Unexpected second day: %+v", results[1])
+	}
+}
+
+// TestYandexMetricaClient_GetDailyConversions_Mock tests GetDailyConversions with mocked HTTP server
+func TestYandexMetricaClient_GetDailyConversions_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics := r.URL.Query().Get("metrics")
+		if metrics != "ym:s:goal1reaches,ym:s:goal2reaches" {
+			t.Errorf("Unexpected metrics=%s", metrics)
+		}
+
+		response := ConversionsResponse{
+			Data: []ConversionsData{
+				{Dimensions: []Dimension{{Name: "2024-01-01"}}, Metrics: []float64{3, 2}},
+				{Dimensions: []Dimension{{Name: "2024-01-02"}}, Metrics: []float64{0, 1}},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	results, err := client.GetDailyConversions(context.Background(), 12345, []int64{1, 2}, "2024-01-01", "2024-01-02")
+	if err != nil {
+		t.Fatalf("GetDailyConversions failed: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Conversions != 5 || results[1].Conversions != 1 {
+		t.Errorf("Unexpected conversions: %+v", results)
+	}
+}
+
+// TestYandexMetricaClient_GetMetricsByTrafficSource_Mock tests GetMetricsByTrafficSource with mocked HTTP server
+func TestYandexMetricaClient_GetMetricsByTrafficSource_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dimensions") != "ym:s:lastTrafficSource" {
+			t.Errorf("Expected dimensions=ym:s:lastTrafficSource, got dimensions=%s", r.URL.Query().Get("dimensions"))
+		}
+		if r.URL.Query().Get("metrics") != "ym:s:visits,ym:s:users,ym:s:goal7reaches,ym:s:goal8reaches" {
+			t.Errorf("Unexpected metrics=%s", r.URL.Query().Get("metrics"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [
+			{"dimensions": [{"name": "Переходы из поисковых систем", "id": "organic"}], "metrics": [500, 400, 10, 5]},
+			{"dimensions": [{"name": "Переходы по рекламе", "id": "ad"}], "metrics": [300, 250, 4, 0]}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	results, err := client.GetMetricsByTrafficSource(context.Background(), 12345, []int64{7, 8}, "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetMetricsByTrafficSource failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 sources, got %d", len(results))
+	}
+	if results[0].Source != "organic" || results[0].Visits != 500 || results[0].Users != 400 {
+		t.Errorf("Unexpected organic row: %+v", results[0])
+	}
+	if results[0].Conversions == nil || *results[0].Conversions != 15 {
+		t.Errorf("Expected 15 organic conversions, got %v", results[0].Conversions)
+	}
+}
+
+// TestYandexMetricaClient_GetRevenueMetrics_Mock tests that goal revenue is split into requests within metrics limit
+func TestYandexMetricaClient_GetRevenueMetrics_Mock(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics := strings.Split(r.URL.Query().Get("metrics"), ",")
+		requests = append(requests, r.URL.Query().Get("metrics"))
+		if len(metrics) > 20 {
+			t.Errorf("Expected at most 20 metrics per request, got %d", len(metrics))
+		}
+
+		values := make([]float64, len(metrics))
+		for i, metric := range metrics {
+			switch metric {
+			case "ym:s:ecommercePurchases":
+				values[i] = 12
+			case "ym:s:ecommerceRevenue":
+				values[i] = 60000
+			default:
+				values[i] = 100
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MetricsResponse{Data: []MetricsData{{Metrics: values}}})
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	goalIDs := make([]int64, 25)
+	for i := range goalIDs {
+		goalIDs[i] = int64(i + 1)
+	}
+	result, err := client.GetRevenueMetrics(context.Background(), 12345, goalIDs, "", "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetRevenueMetrics failed: %v", err)
+	}
+
+	if len(requests) != 2 || !strings.HasPrefix(requests[0], "ym:s:ecommercePurchases,ym:s:ecommerceRevenue,ym:s:goal1revenue,") ||
+		requests[1] != "ym:s:goal19revenue,ym:s:goal20revenue,ym:s:goal21revenue,ym:s:goal22revenue,ym:s:goal23revenue,ym:s:goal24revenue,ym:s:goal25revenue" {
+		t.Fatalf("Unexpected requests: %v", requests)
+	}
+	if result.Purchases != 12 || result.Revenue != 60000 || result.GoalRevenue != 2500 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+// TestYandexMetricaClient_GetRevenueByDirectCampaign_Mock tests GetRevenueByDirectCampaign with mocked HTTP server
+func TestYandexMetricaClient_GetRevenueByDirectCampaign_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dimensions") != "ym:s:lastDirectClickOrder" {
+			t.Errorf("Expected dimensions=ym:s:lastDirectClickOrder, got dimensions=%s", r.URL.Query().Get("dimensions"))
+		}
+		if r.URL.Query().Get("metrics") != "ym:s:ecommercePurchases,ym:s:ecommerceRevenue,ym:s:goal7revenue" {
+			t.Errorf("Unexpected metrics=%s", r.URL.Query().Get("metrics"))
+		}
+		if r.URL.Query().Get("currency") != "USD" {
+			t.Errorf("Expected currency=USD, got currency=%s", r.URL.Query().Get("currency"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [
+			{"dimensions": [{"name": "Поиск | Бренд", "id": 101}], "metrics": [3, 15000, 500]},
+			{"dimensions": [], "metrics": [1, 1, 1]}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	results, err := client.GetRevenueByDirectCampaign(context.Background(), 12345, []int64{7}, "USD", "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetRevenueByDirectCampaign failed: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != "101" || results[0].Purchases != 3 || results[0].Revenue != 15000 || results[0].GoalRevenue != 500 {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+// TestDimensionID_UnmarshalJSON tests that both string and numeric dimension ids are accepted
+func TestDimensionID_UnmarshalJSON(t *testing.T) {
+	var dimensions []Dimension
+	if err := json.Unmarshal([]byte(`[{"name": "a", "id": "organic"}, {"name": "b", "id": 213}, {"name": "c"}]`), &dimensions); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if dimensions[0].ID != "organic" || dimensions[1].ID != "213" || dimensions[2].ID != "" {
+		t.Errorf("Unexpected ids: %q, %q, %q", dimensions[0].ID, dimensions[1].ID, dimensions[2].ID)
+	}
+}
+
 // TestYandexMetricaClient_GetMetricsByAge_Mock tests GetMetricsByAge with mocked HTTP server
 func TestYandexMetricaClient_GetMetricsByAge_Mock(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -110,6 +305,47 @@ func TestYandexMetricaClient_GetMetricsByAge_Mock(t *testing.T) {
 	}
 }
 
+// TestYandexMetricaClient_GetMetricsByDimension_Mock tests breakdown request and tolerant parsing of rows
+func TestYandexMetricaClient_GetMetricsByDimension_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("dimensions") != DimensionRegionCity {
+			t.Errorf("Expected dimensions=%s, got %s", DimensionRegionCity, query.Get("dimensions"))
+		}
+		if query.Get("limit") != "50" || query.Get("sort") != "-ym:s:visits" {
+			t.Errorf("Expected limit=50 and sort by visits, got limit=%s sort=%s", query.Get("limit"), query.Get("sort"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[
+			{"dimensions":[{"id":213,"name":"Moscow"}],"metrics":[500,400,25.5,120]},
+			{"dimensions":[{"id":"2","name":"Saint Petersburg"}],"metrics":[100,90]},
+			{"dimensions":[{"id":null,"name":null}],"metrics":[10,10,50,30]},
+			{"dimensions":[],"metrics":[1,1,0,0]}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+	results, err := client.GetMetricsByDimension(context.Background(), 12345, DimensionRegionCity, 50, "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetMetricsByDimension failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(results))
+	}
+	if results[0].ID != "213" || results[0].Name != "Moscow" || results[0].Visits != 500 || results[0].AvgSessionDurationSec != 120 {
+		t.Errorf("Unexpected first row: %+v", results[0])
+	}
+	if results[1].Users != 90 || results[1].BounceRate != 0 || results[1].AvgSessionDurationSec != 0 {
+		t.Errorf("Expected missing metrics to be zero, got %+v", results[1])
+	}
+	if results[2].ID != "" || results[2].Name != "" {
+		t.Errorf("Expected empty id and name for undefined value, got %+v", results[2])
+	}
+}
+
 // TestYandexMetricaClient_GetConversions_Mock tests GetConversions with mocked HTTP server
 func TestYandexMetricaClient_GetConversions_Mock(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -155,3 +391,210 @@ func TestYandexMetricaClient_GetConversions_Mock(t *testing.T) {
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || strings.Contains(s, substr))
 }
+
+// TestYandexMetricaClient_GetGoals_Mock tests goals request to Management API
+func TestYandexMetricaClient_GetGoals_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/counter/12345/goals" {
+			t.Errorf("Expected path /counter/12345/goals, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "OAuth test_token" {
+			t.Errorf("Expected Authorization header 'OAuth test_token', got '%s'", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"goals": [
+			{"id": 1, "name": "Заявка", "type": "action", "is_retargeting": 0},
+			{"id": 2, "name": "Оформление заказа", "type": "url", "is_retargeting": 1}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	goals, err := client.GetGoals(context.Background(), 12345)
+	if err != nil {
+		t.Fatalf("GetGoals failed: %v", err)
+	}
+
+	if len(goals) != 2 {
+		t.Fatalf("Expected 2 goals, got %d", len(goals))
+	}
+	if goals[0].ID != 1 || goals[0].Name != "Заявка" || goals[0].Type != "action" {
+		t.Errorf("Unexpected first goal: %+v", goals[0])
+	}
+	if goals[1].IsRetargeting != 1 {
+		t.Errorf("Expected IsRetargeting=1, got %d", goals[1].IsRetargeting)
+	}
+}
+
+// TestYandexMetricaClient_GetConversions_Batches tests that goals are split into requests within metrics limit
+func TestYandexMetricaClient_GetConversions_Batches(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		metrics := strings.Split(r.URL.Query().Get("metrics"), ",")
+		if len(metrics) > 20 {
+			t.Errorf("Expected at most 20 metrics per request, got %d", len(metrics))
+		}
+
+		values := make([]float64, len(metrics))
+		for i := range values {
+			values[i] = float64(requests)
+		}
+		json.NewEncoder(w).Encode(ConversionsResponse{Data: []ConversionsData{{Metrics: values}}})
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+
+	goalIDs := make([]int64, 12)
+	for i := range goalIDs {
+		goalIDs[i] = int64(i + 1)
+	}
+
+	results, err := client.GetConversions(context.Background(), 12345, goalIDs, "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("GetConversions failed: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if len(results) != 12 {
+		t.Fatalf("Expected 12 results, got %d", len(results))
+	}
+	if results[0].GoalID != 1 || results[0].Conversions != 1 || results[11].GoalID != 12 || results[11].Conversions != 2 {
+		t.Errorf("Unexpected results: first %+v, last %+v", results[0], results[11])
+	}
+}
+
+// TestYandexMetricaClient_DownloadLogs_Mock tests Logs API flow: evaluate, create, poll, download parts, clean
+func TestYandexMetricaClient_DownloadLogs_Mock(t *testing.T) {
+	statusChecks := 0
+	cleaned := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth test_token" {
+			t.Errorf("Expected Authorization header 'OAuth test_token', got '%s'", r.Header.Get("Authorization"))
+		}
+
+		switch {
+		case r.URL.Path == "/counter/12345/logrequests/evaluate":
+			if r.URL.Query().Get("fields") != "ym:s:visitID,ym:s:clientID" || r.URL.Query().Get("source") != LogSourceVisits {
+				t.Errorf("Unexpected evaluate params: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"log_request_evaluation":{"possible":true,"max_possible_day_quantity":100}}`))
+		case r.URL.Path == "/counter/12345/logrequests" && r.Method == "POST":
+			if r.URL.Query().Get("date1") != "2024-01-01" || r.URL.Query().Get("date2") != "2024-01-31" {
+				t.Errorf("Unexpected create params: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"log_request":{"request_id":77,"counter_id":12345,"source":"visits","status":"created"}}`))
+		case r.URL.Path == "/counter/12345/logrequest/77":
+			statusChecks++
+			if statusChecks == 1 {
+				w.Write([]byte(`{"log_request":{"request_id":77,"status":"created"}}`))
+				return
+			}
+			w.Write([]byte(`{"log_request":{"request_id":77,"status":"processed","parts":[{"part_number":0,"size":10},{"part_number":1,"size":10}]}}`))
+		case strings.HasPrefix(r.URL.Path, "/counter/12345/logrequest/77/part/"):
+			part := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/counter/12345/logrequest/77/part/"), "/download")
+			w.Header().Set("Content-Type", "text/tab-separated-values")
+			w.Write([]byte("ym:s:visitID\tym:s:clientID\n" + "100" + part + "\t555\n"))
+		case r.URL.Path == "/counter/12345/logrequest/77/clean" && r.Method == "POST":
+			cleaned = true
+			w.Write([]byte(`{"log_request":{"request_id":77,"status":"cleaned_by_user"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+	client.SetLogsPolling(time.Millisecond, time.Second)
+
+	var visitIDs []string
+	err := client.DownloadLogs(context.Background(), 12345, LogSourceVisits, []string{"ym:s:visitID", "ym:s:clientID"}, "2024-01-01", "2024-01-31",
+		func(rows []map[string]string) error {
+			for _, row := range rows {
+				visitIDs = append(visitIDs, row["ym:s:visitID"])
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("DownloadLogs failed: %v", err)
+	}
+
+	if statusChecks != 2 {
+		t.Errorf("Expected 2 status checks, got %d", statusChecks)
+	}
+	if len(visitIDs) != 2 || visitIDs[0] != "1000" || visitIDs[1] != "1001" {
+		t.Errorf("Expected visits from both parts, got %v", visitIDs)
+	}
+	if !cleaned {
+		t.Error("Expected log request to be cleaned")
+	}
+}
+
+// TestParseLogTSV_Batches tests that log rows are passed to handler in bounded batches
+func TestParseLogTSV_Batches(t *testing.T) {
+	body := "ym:s:visitID\tym:s:clientID\r\n1\t11\r\n2\t22\n\n3\t33\n4\t44\n5\t55\n"
+
+	var batches [][]map[string]string
+	err := parseLogTSV(strings.NewReader(body), 2, func(rows []map[string]string) error {
+		batches = append(batches, rows)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("parseLogTSV failed: %v", err)
+	}
+
+	if len(batches) != 3 || len(batches[0]) != 2 || len(batches[1]) != 2 || len(batches[2]) != 1 {
+		t.Fatalf("Expected batches of 2, 2 and 1 rows, got %v", batches)
+	}
+	if batches[0][1]["ym:s:clientID"] != "22" || batches[2][0]["ym:s:visitID"] != "5" {
+		t.Errorf("Unexpected rows: %v", batches)
+	}
+
+	err = parseLogTSV(strings.NewReader("ym:s:visitID\tym:s:clientID\n1\n"), 2, func(rows []map[string]string) error {
+		t.Error("Rows must not be handled after invalid row")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 columns, expected 2") {
+		t.Errorf("Expected columns error, got %v", err)
+	}
+}
+
+// TestYandexMetricaClient_DownloadLogs_FailedRequest tests that failed log requests are reported and cleaned
+func TestYandexMetricaClient_DownloadLogs_FailedRequest(t *testing.T) {
+	cleaned := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/counter/12345/logrequests/evaluate":
+			w.Write([]byte(`{"log_request_evaluation":{"possible":true}}`))
+		case "/counter/12345/logrequests":
+			w.Write([]byte(`{"log_request":{"request_id":78,"status":"created"}}`))
+		case "/counter/12345/logrequest/78":
+			w.Write([]byte(`{"log_request":{"request_id":78,"status":"processing_failed"}}`))
+		case "/counter/12345/logrequest/78/clean":
+			cleaned = true
+			w.Write([]byte(`{"log_request":{"request_id":78}}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexMetricaClientWithURL("test_token", mockServer.URL)
+	client.SetLogsPolling(time.Millisecond, time.Second)
+
+	err := client.DownloadLogs(context.Background(), 12345, LogSourceVisits, []string{"ym:s:visitID"}, "2024-01-01", "2024-01-31",
+		func(rows []map[string]string) error {
+			t.Error("Rows must not be handled for failed request")
+			return nil
+		})
+	if err == nil || !strings.Contains(err.Error(), "processing_failed") {
+		t.Errorf("Expected processing_failed error, got %v", err)
+	}
+	if !cleaned {
+		t.Error("Expected failed log request to be cleaned")
+	}
+}
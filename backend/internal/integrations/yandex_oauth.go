@@ -0,0 +1,163 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	yandexOAuthTokenURL = "https://oauth.yandex.ru/token"
+	yandexLoginInfoURL  = "https://login.yandex.ru/info"
+)
+
+var (
+	// ErrTokenRevoked is returned when Yandex rejects an access token (revoked or expired)
+	ErrTokenRevoked = errors.New("OAuth token is revoked or expired")
+	// ErrRefreshTokenInvalid is returned when refresh token can no longer be used (invalid_grant)
+	ErrRefreshTokenInvalid = errors.New("OAuth refresh token is invalid")
+)
+
+// YandexOAuthClient handles token refresh and validation with Yandex OAuth
+type YandexOAuthClient struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	infoURL      string
+	httpClient   *http.Client
+}
+
+// NewYandexOAuthClient creates a new Yandex OAuth client
+func NewYandexOAuthClient(clientID, clientSecret string) *YandexOAuthClient {
+	return NewYandexOAuthClientWithURL(clientID, clientSecret, yandexOAuthTokenURL, yandexLoginInfoURL)
+}
+
+// NewYandexOAuthClientWithURL creates a new Yandex OAuth client with custom URLs (for testing)
+func NewYandexOAuthClientWithURL(clientID, clientSecret, tokenURL, infoURL string) *YandexOAuthClient {
+	return &YandexOAuthClient{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		infoURL:      infoURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// OAuthToken represents token issued by Yandex OAuth
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds
+}
+
+// ExpiresAt returns absolute expiry time, nil if lifetime is unknown
+func (t *OAuthToken) ExpiresAt(now time.Time) *time.Time {
+	if t.ExpiresIn <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	return &expiresAt
+}
+
+// YandexUserInfo represents token owner info from Yandex ID
+type YandexUserInfo struct {
+	ID    string `json:"id"`
+	Login string `json:"login"`
+}
+
+// oauthErrorResponse represents an error response from Yandex OAuth token endpoint
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// RefreshToken exchanges refresh token for a new access token
+// Documentation: https://yandex.ru/dev/id/doc/ru/tokens/refresh-client
+func (c *YandexOAuthClient) RefreshToken(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	if c.clientID == "" || c.clientSecret == "" {
+		return nil, fmt.Errorf("yandex OAuth credentials are not configured")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+	data.Set("client_id", c.clientID)
+	data.Set("client_secret", c.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp oauthErrorResponse
+		if jsonErr := json.Unmarshal(body, &errorResp); jsonErr == nil && errorResp.Error != "" {
+			if errorResp.Error == "invalid_grant" {
+				return nil, fmt.Errorf("%w: %s", ErrRefreshTokenInvalid, errorResp.ErrorDescription)
+			}
+			return nil, fmt.Errorf("token refresh failed: %s - %s", errorResp.Error, errorResp.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token OAuthToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("access token is missing in response")
+	}
+
+	return &token, nil
+}
+
+// GetUserInfo returns owner of the access token, ErrTokenRevoked if token is not accepted
+// Documentation: https://yandex.ru/dev/id/doc/ru/user-information
+func (c *YandexOAuthClient) GetUserInfo(ctx context.Context, accessToken string) (*YandexUserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.infoURL+"?format=json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "OAuth "+accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrTokenRevoked
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user info request failed with status %d", resp.StatusCode)
+	}
+
+	var info YandexUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse user info: %w", err)
+	}
+
+	return &info, nil
+}
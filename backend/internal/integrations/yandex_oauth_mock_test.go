@@ -0,0 +1,81 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestYandexOAuthClient_RefreshToken_Mock tests RefreshToken with mocked HTTP server
+func TestYandexOAuthClient_RefreshToken_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" {
+			t.Errorf("Expected grant_type=refresh_token, got %s", r.PostForm.Get("grant_type"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid refresh token"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"new_access","refresh_token":"new_refresh","token_type":"bearer","expires_in":31536000}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexOAuthClientWithURL("client", "secret", mockServer.URL, mockServer.URL)
+	ctx := context.Background()
+
+	token, err := client.RefreshToken(ctx, "old_refresh")
+	if err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if token.AccessToken != "new_access" || token.RefreshToken != "new_refresh" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	if token.ExpiresIn != 31536000 {
+		t.Errorf("Expected ExpiresIn=31536000, got %d", token.ExpiresIn)
+	}
+
+	_, err = client.RefreshToken(ctx, "revoked")
+	if !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("Expected ErrRefreshTokenInvalid, got %v", err)
+	}
+}
+
+// TestYandexOAuthClient_GetUserInfo_Mock tests GetUserInfo with mocked HTTP server
+func TestYandexOAuthClient_GetUserInfo_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth valid_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","login":"agency-login"}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexOAuthClientWithURL("client", "secret", mockServer.URL, mockServer.URL)
+	ctx := context.Background()
+
+	info, err := client.GetUserInfo(ctx, "valid_token")
+	if err != nil {
+		t.Fatalf("GetUserInfo failed: %v", err)
+	}
+	if info.Login != "agency-login" {
+		t.Errorf("Expected login agency-login, got %s", info.Login)
+	}
+
+	_, err = client.GetUserInfo(ctx, "revoked_token")
+	if !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+}
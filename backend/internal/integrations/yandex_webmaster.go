@@ -0,0 +1,169 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	yandexWebmasterAPIURL = "https://api.webmaster.yandex.net/v4"
+
+	// webmasterQueriesLimit is the maximum page size of popular queries endpoint
+	webmasterQueriesLimit = 500
+)
+
+// YandexWebmasterClient handles integration with Yandex.Webmaster API
+type YandexWebmasterClient struct {
+	token      string
+	httpClient *http.Client
+	baseURL    string // For testing: allows overriding base URL
+}
+
+// NewYandexWebmasterClient creates a new Webmaster client
+// token: OAuth token with webmaster:hostinfo access
+func NewYandexWebmasterClient(token string) *YandexWebmasterClient {
+	return NewYandexWebmasterClientWithURL(token, yandexWebmasterAPIURL)
+}
+
+// NewYandexWebmasterClientWithURL creates a new Webmaster client with custom base URL (for testing)
+func NewYandexWebmasterClientWithURL(token, baseURL string) *YandexWebmasterClient {
+	return &YandexWebmasterClient{
+		token:      token,
+		baseURL:    baseURL,
+		httpClient: newAPIHTTPClient(),
+	}
+}
+
+// WebmasterHost represents a site added to Webmaster
+type WebmasterHost struct {
+	HostID         string `json:"host_id"` // e.g. "https:example.com:443"
+	ASCIIHostURL   string `json:"ascii_host_url"`
+	UnicodeHostURL string `json:"unicode_host_url"`
+	Verified       bool   `json:"verified"`
+}
+
+// WebmasterQuery represents a popular search query with its indicators
+type WebmasterQuery struct {
+	QueryID     string  `json:"query_id"`
+	QueryText   string  `json:"query_text"`
+	Shows       int     `json:"shows"`
+	Clicks      int     `json:"clicks"`
+	AvgPosition float64 `json:"avg_position"` // Average show position
+}
+
+// GetUserID retrieves Webmaster user ID of the token owner
+// Documentation: https://yandex.ru/dev/webmaster/doc/dg/reference/user.html
+func (c *YandexWebmasterClient) GetUserID(ctx context.Context) (int64, error) {
+	var response struct {
+		UserID int64 `json:"user_id"`
+	}
+	if err := c.makeRequest(ctx, "/user", nil, &response); err != nil {
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	return response.UserID, nil
+}
+
+// GetHosts retrieves sites available to the user
+// Documentation: https://yandex.ru/dev/webmaster/doc/dg/reference/hosts.html
+func (c *YandexWebmasterClient) GetHosts(ctx context.Context, userID int64) ([]WebmasterHost, error) {
+	var response struct {
+		Hosts []WebmasterHost `json:"hosts"`
+	}
+	path := fmt.Sprintf("/user/%d/hosts", userID)
+	if err := c.makeRequest(ctx, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get hosts: %w", err)
+	}
+	return response.Hosts, nil
+}
+
+// GetPopularQueries retrieves popular search queries of a host ordered by shows
+// limit is capped at 500 (API maximum)
+// Documentation: https://yandex.ru/dev/webmaster/doc/dg/reference/host-search-queries-popular.html
+func (c *YandexWebmasterClient) GetPopularQueries(ctx context.Context, userID int64, hostID, dateFrom, dateTo string, limit int) ([]WebmasterQuery, error) {
+	if limit <= 0 || limit > webmasterQueriesLimit {
+		limit = webmasterQueriesLimit
+	}
+
+	params := url.Values{}
+	params.Set("order_by", "TOTAL_SHOWS")
+	params.Add("query_indicator", "TOTAL_SHOWS")
+	params.Add("query_indicator", "TOTAL_CLICKS")
+	params.Add("query_indicator", "AVG_SHOW_POSITION")
+	params.Set("date_from", dateFrom)
+	params.Set("date_to", dateTo)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var response struct {
+		Queries []struct {
+			QueryID    string             `json:"query_id"`
+			QueryText  string             `json:"query_text"`
+			Indicators map[string]float64 `json:"indicators"`
+		} `json:"queries"`
+	}
+	path := fmt.Sprintf("/user/%d/hosts/%s/search-queries/popular", userID, url.PathEscape(hostID))
+	if err := c.makeRequest(ctx, path, params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+
+	queries := make([]WebmasterQuery, 0, len(response.Queries))
+	for _, query := range response.Queries {
+		queries = append(queries, WebmasterQuery{
+			QueryID:     query.QueryID,
+			QueryText:   query.QueryText,
+			Shows:       int(query.Indicators["TOTAL_SHOWS"]),
+			Clicks:      int(query.Indicators["TOTAL_CLICKS"]),
+			AvgPosition: query.Indicators["AVG_SHOW_POSITION"],
+		})
+	}
+
+	return queries, nil
+}
+
+// makeRequest performs GET request to Yandex Webmaster API
+func (c *YandexWebmasterClient) makeRequest(ctx context.Context, path string, params url.Values, response interface{}) error {
+	reqURL := c.baseURL + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "OAuth "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			ErrorCode    string `json:"error_code"`
+			ErrorMessage string `json:"error_message"`
+		}
+		if err := json.Unmarshal(body, &apiError); err == nil && apiError.ErrorCode != "" {
+			return fmt.Errorf("API error: %s (code: %s)", apiError.ErrorMessage, apiError.ErrorCode)
+		}
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
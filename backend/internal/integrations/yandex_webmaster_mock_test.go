@@ -0,0 +1,125 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestYandexWebmasterClient_GetHosts_Mock tests user and hosts requests with mocked HTTP server
+func TestYandexWebmasterClient_GetHosts_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth test_token" {
+			t.Errorf("Expected Authorization header 'OAuth test_token', got '%s'", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"user_id": 42}`))
+		case "/user/42/hosts":
+			w.Write([]byte(`{"hosts": [
+				{"host_id": "https:example.com:443", "ascii_host_url": "https://example.com/", "unicode_host_url": "https://example.com/", "verified": true},
+				{"host_id": "http:old.example.com:80", "ascii_host_url": "http://old.example.com/", "unicode_host_url": "http://old.example.com/", "verified": false}
+			]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexWebmasterClientWithURL("test_token", mockServer.URL)
+	ctx := context.Background()
+
+	userID, err := client.GetUserID(ctx)
+	if err != nil {
+		t.Fatalf("GetUserID failed: %v", err)
+	}
+	if userID != 42 {
+		t.Errorf("Expected userID=42, got %d", userID)
+	}
+
+	hosts, err := client.GetHosts(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetHosts failed: %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d", len(hosts))
+	}
+	if hosts[0].HostID != "https:example.com:443" || !hosts[0].Verified {
+		t.Errorf("Unexpected first host: %+v", hosts[0])
+	}
+	if hosts[1].ASCIIHostURL != "http://old.example.com/" || hosts[1].Verified {
+		t.Errorf("Unexpected second host: %+v", hosts[1])
+	}
+}
+
+// TestYandexWebmasterClient_GetPopularQueries_Mock tests popular queries request and indicators parsing
+func TestYandexWebmasterClient_GetPopularQueries_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/42/hosts/https:example.com:443/search-queries/popular" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("order_by") != "TOTAL_SHOWS" {
+			t.Errorf("Expected order_by=TOTAL_SHOWS, got %s", query.Get("order_by"))
+		}
+		indicators := strings.Join(query["query_indicator"], ",")
+		if indicators != "TOTAL_SHOWS,TOTAL_CLICKS,AVG_SHOW_POSITION" {
+			t.Errorf("Unexpected query indicators: %s", indicators)
+		}
+		if query.Get("date_from") != "2024-01-01" || query.Get("date_to") != "2024-01-31" {
+			t.Errorf("Unexpected date range: %s - %s", query.Get("date_from"), query.Get("date_to"))
+		}
+		if query.Get("limit") != "500" {
+			t.Errorf("Expected limit to be capped at 500, got %s", query.Get("limit"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"queries": [
+			{"query_id": "q1", "query_text": "купить диван", "indicators": {"TOTAL_SHOWS": 1200, "TOTAL_CLICKS": 85, "AVG_SHOW_POSITION": 3.4}},
+			{"query_id": "q2", "query_text": "диван недорого", "indicators": {"TOTAL_SHOWS": 300}}
+		], "count": 2}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexWebmasterClientWithURL("test_token", mockServer.URL)
+
+	queries, err := client.GetPopularQueries(context.Background(), 42, "https:example.com:443", "2024-01-01", "2024-01-31", 1000)
+	if err != nil {
+		t.Fatalf("GetPopularQueries failed: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(queries))
+	}
+	if queries[0].QueryText != "купить диван" || queries[0].Shows != 1200 || queries[0].Clicks != 85 || queries[0].AvgPosition != 3.4 {
+		t.Errorf("Unexpected first query: %+v", queries[0])
+	}
+	if queries[1].Clicks != 0 || queries[1].AvgPosition != 0 {
+		t.Errorf("Missing indicators should be zero, got %+v", queries[1])
+	}
+}
+
+// TestYandexWebmasterClient_APIError_Mock tests Webmaster error response handling
+func TestYandexWebmasterClient_APIError_Mock(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error_code": "ACCESS_FORBIDDEN", "error_message": "Access denied"}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewYandexWebmasterClientWithURL("test_token", mockServer.URL)
+
+	_, err := client.GetUserID(context.Background())
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "ACCESS_FORBIDDEN") {
+		t.Errorf("Expected error to contain error code, got %v", err)
+	}
+}
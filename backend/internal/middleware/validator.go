@@ -28,6 +28,12 @@ func ValidateRequest(c echo.Context, request interface{}) error {
 	return nil
 }
 
+// ValidationMessage formats an error of Validate.Struct the same way as ValidateRequest
+// Used to validate values that do not come from a request body, e.g. rows of uploaded files
+func ValidationMessage(err error) string {
+	return formatValidationError(err)
+}
+
 // formatValidationError formats validator errors into a readable message
 func formatValidationError(err error) string {
 	var validationErrors validator.ValidationErrors
@@ -60,6 +66,8 @@ func formatFieldError(e validator.FieldError) string {
 		return "Field '" + e.Field() + "' must be at most " + e.Param() + " characters"
 	case "url":
 		return "Field '" + e.Field() + "' must be a valid URL"
+	case "gte":
+		return "Field '" + e.Field() + "' must be greater than or equal to " + e.Param()
 	case "numeric":
 		return "Field '" + e.Field() + "' must be a number"
 	case "oneof":
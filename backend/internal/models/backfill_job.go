@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Backfill job statuses
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed" // Finished, but some months failed after all retries
+)
+
+// BackfillJob tracks historical sync of a project over a range of months
+// Every month of every source is a separate queue task; counters are updated as tasks finish
+type BackfillJob struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProjectID      uint      `gorm:"not null;index" json:"project_id"`
+	FromMonth      string    `gorm:"type:varchar(7);not null" json:"from"`      // YYYY-MM
+	ToMonth        string    `gorm:"type:varchar(7);not null" json:"to"`        // YYYY-MM
+	Sources        string    `gorm:"type:varchar(100);not null" json:"sources"` // Comma-separated: metrica,direct
+	TotalTasks     int       `gorm:"not null;default:0" json:"total_tasks"`
+	CompletedTasks int       `gorm:"not null;default:0" json:"completed_tasks"`
+	FailedTasks    int       `gorm:"not null;default:0" json:"failed_tasks"`
+	Status         string    `gorm:"type:varchar(20);not null;default:'running'" json:"status"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for BackfillJob
+func (BackfillJob) TableName() string {
+	return "backfill_jobs"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ChannelVKAds is the source and channel of VK Ads campaigns
+const ChannelVKAds = "vk_ads"
+
+// ChannelCampaignMonthly represents monthly metrics of a campaign loaded by a connector (see internal/connectors)
+// or of a channel uploaded manually, such rows have no campaign
+// Costs are in the native currency of the project
+type ChannelCampaignMonthly struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"not null;index" json:"project_id"`
+	Source       string    `gorm:"type:varchar(20);not null;index" json:"source"` // Connector that loaded the row
+	Channel      string    `gorm:"type:varchar(50);not null" json:"channel"`      // Channel in reports
+	CampaignID   string    `gorm:"type:varchar(100)" json:"campaign_id"`          // Campaign ID in the source
+	CampaignName string    `gorm:"type:varchar(255)" json:"campaign_name"`
+	Year         int       `gorm:"not null;index" json:"year"`
+	Month        int       `gorm:"not null;index" json:"month"`
+	Impressions  int       `gorm:"not null;default:0" json:"impressions"`
+	Clicks       int       `gorm:"not null;default:0" json:"clicks"`
+	CTRPct       float64   `gorm:"type:decimal(6,2)" json:"ctr_pct"`
+	CPC          float64   `gorm:"type:decimal(12,2)" json:"cpc"`
+	Conversions  *int      `json:"conversions"`
+	CPA          *float64  `gorm:"type:decimal(12,2)" json:"cpa"`
+	Cost         float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cost"`
+	UploadID     *uint     `gorm:"index" json:"upload_id,omitempty"` // Manual upload the row came from
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for ChannelCampaignMonthly
+func (ChannelCampaignMonthly) TableName() string {
+	return "channel_campaign_monthly"
+}
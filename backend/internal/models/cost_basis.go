@@ -0,0 +1,22 @@
+package models
+
+// CostBasis describes how Direct money values (cost, CPC, CPA) are calculated
+type CostBasis struct {
+	IncludeVAT      bool    `json:"include_vat"`      // Costs include VAT
+	IncludeDiscount bool    `json:"include_discount"` // Costs are reduced by the account discount
+	MarkupPct       float64 `json:"markup_pct"`       // Agency markup added on top, %
+}
+
+// Factor returns the multiplier applying the agency markup to costs
+func (b CostBasis) Factor() float64 {
+	return 1 + b.MarkupPct/100
+}
+
+// CostBasis returns cost basis configured for the project
+func (p *Project) CostBasis() CostBasis {
+	return CostBasis{
+		IncludeVAT:      p.CostIncludeVAT,
+		IncludeDiscount: p.CostIncludeDiscount,
+		MarkupPct:       p.CostMarkupPct,
+	}
+}
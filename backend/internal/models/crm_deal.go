@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// CRM deal statuses
+const (
+	CRMDealStatusOpen = "open"
+	CRMDealStatusWon  = "won" // Sale, revenue of won deals is used for ROMI and ROAS
+	CRMDealStatusLost = "lost"
+)
+
+// CRMDeal represents a deal imported from a CRM, matched to a Direct campaign and a Metrica traffic source by UTM
+type CRMDeal struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ProjectID     uint      `gorm:"not null;uniqueIndex:idx_crm_deals_project_deal;index:idx_crm_deals_project_date" json:"project_id"`
+	DealID        string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_crm_deals_project_deal" json:"deal_id"` // Deal ID in the CRM
+	Date          time.Time `gorm:"type:date;not null;index:idx_crm_deals_project_date" json:"date"`                  // Sales are counted in the month of the date
+	Status        string    `gorm:"type:varchar(10);not null" json:"status"`
+	Revenue       float64   `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"` // In the native currency of the project
+	UTMSource     string    `gorm:"type:varchar(255)" json:"utm_source"`
+	UTMMedium     string    `gorm:"type:varchar(255)" json:"utm_medium"`
+	UTMCampaign   string    `gorm:"type:varchar(255)" json:"utm_campaign"`
+	CampaignID    *int64    `gorm:"index" json:"campaign_id"`               // Matched Direct campaign (Yandex ID)
+	TrafficSource string    `gorm:"type:varchar(50)" json:"traffic_source"` // Matched Metrica traffic source, empty if unknown
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for CRMDeal
+func (CRMDeal) TableName() string {
+	return "crm_deals"
+}
+
+// CRMWebhook holds the secret of a project's CRM webhook
+// Only a SHA-256 hash of the token is stored, the token is shown once when generated
+type CRMWebhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"not null;uniqueIndex" json:"project_id"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for CRMWebhook
+func (CRMWebhook) TableName() string {
+	return "crm_webhooks"
+}
@@ -0,0 +1,27 @@
+package models
+
+// Currencies of projects and ad accounts (ISO 4217)
+const (
+	CurrencyRUB = "RUB"
+	CurrencyUSD = "USD"
+	CurrencyEUR = "EUR"
+	CurrencyKZT = "KZT"
+	CurrencyBYN = "BYN"
+	CurrencyUAH = "UAH"
+	CurrencyTRY = "TRY"
+	CurrencyCHF = "CHF"
+	CurrencyUZS = "UZS"
+)
+
+// Currencies lists supported currencies, the same set is available in Direct accounts
+var Currencies = []string{CurrencyRUB, CurrencyUSD, CurrencyEUR, CurrencyKZT, CurrencyBYN, CurrencyUAH, CurrencyTRY, CurrencyCHF, CurrencyUZS}
+
+// IsValidCurrency checks whether currency code is supported
+func IsValidCurrency(currency string) bool {
+	for _, supported := range Currencies {
+		if currency == supported {
+			return true
+		}
+	}
+	return false
+}
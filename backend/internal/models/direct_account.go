@@ -4,10 +4,12 @@ import "time"
 
 // DirectAccount represents a Yandex.Direct account linked to a project
 type DirectAccount struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	ProjectID   uint      `gorm:"not null;index" json:"project_id"`
-	ClientLogin string    `gorm:"type:varchar(255);not null" json:"client_login"`
-	AccountName *string   `gorm:"type:varchar(255)" json:"account_name"` // Optional account name
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ProjectID         uint      `gorm:"not null;index" json:"project_id"`
+	ClientLogin       string    `gorm:"type:varchar(255);not null" json:"client_login"`
+	AccountName       *string   `gorm:"type:varchar(255)" json:"account_name"` // Optional account name
+	OAuthCredentialID *uint     `gorm:"index" json:"oauth_credential_id"`      // Optional: overrides project credential
+	Currency          string    `gorm:"type:varchar(3)" json:"currency"`       // Account currency, read from Direct on sync
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Sort fields of ad group and keyword statistics
+const (
+	DirectStatsSortCost        = "cost"
+	DirectStatsSortCTR         = "ctr"
+	DirectStatsSortCPA         = "cpa"
+	DirectStatsSortClicks      = "clicks"
+	DirectStatsSortImpressions = "impressions"
+	DirectStatsSortConversions = "conversions"
+)
+
+// DirectStatsFilter selects ad group or keyword statistics of a campaign for a month
+type DirectStatsFilter struct {
+	DirectCampaignID uint
+	AdGroupID        int64 // Yandex ad group ID, 0 means all ad groups
+	Year             int
+	Month            int
+	Sort             string // One of DirectStatsSort* values
+	Order            string // ASC or DESC
+}
+
+// DirectAdGroupMonthly represents monthly metrics for an ad group of a Direct campaign
+type DirectAdGroupMonthly struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	ProjectID        uint      `gorm:"not null;index" json:"project_id"`
+	DirectCampaignID uint      `gorm:"not null;index" json:"direct_campaign_id"`
+	AdGroupID        int64     `gorm:"not null;index" json:"ad_group_id"` // Yandex ad group ID
+	AdGroupName      string    `gorm:"type:varchar(255)" json:"ad_group_name"`
+	Year             int       `gorm:"not null;index" json:"year"`
+	Month            int       `gorm:"not null;index" json:"month"`
+	Impressions      int       `gorm:"not null;default:0" json:"impressions"`
+	Clicks           int       `gorm:"not null;default:0" json:"clicks"`
+	CTRPct           float64   `gorm:"type:decimal(6,2)" json:"ctr_pct"`
+	CPC              float64   `gorm:"type:decimal(12,2)" json:"cpc"`
+	Conversions      *int      `json:"conversions"`
+	CPA              *float64  `gorm:"type:decimal(12,2)" json:"cpa"`
+	Cost             float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cost"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for DirectAdGroupMonthly
+func (DirectAdGroupMonthly) TableName() string {
+	return "direct_adgroup_monthly"
+}
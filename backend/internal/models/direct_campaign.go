@@ -2,13 +2,67 @@ package models
 
 import "time"
 
+// Campaign types from Direct API (only types classified into channels are listed)
+// Documentation: https://yandex.ru/dev/direct/doc/ref-v5/campaigns/get.html
+const (
+	DirectCampaignTypeText        = "TEXT_CAMPAIGN"
+	DirectCampaignTypeDynamicText = "DYNAMIC_TEXT_CAMPAIGN"
+	DirectCampaignTypeSmart       = "SMART_CAMPAIGN"
+	DirectCampaignTypeUnified     = "UNIFIED_CAMPAIGN" // Master campaigns (ЕПК)
+)
+
+// DirectStrategyServingOff is bidding strategy type of a network where campaign ads are not shown
+const DirectStrategyServingOff = "SERVING_OFF"
+
+// Advertising channels of Direct campaigns
+const (
+	DirectChannelSearch  = "search"
+	DirectChannelNetwork = "network" // РСЯ
+	DirectChannelMaster  = "master"
+	DirectChannelSmart   = "smart"
+	DirectChannelOther   = "other"
+)
+
 // DirectCampaign represents a Yandex.Direct campaign
 type DirectCampaign struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
 	DirectAccountID uint      `gorm:"not null;index" json:"direct_account_id"`
 	CampaignID      int64     `gorm:"not null" json:"campaign_id"`
-	Name            string    `gorm:"type:varchar(255)" json:"name"` // Campaign name from Direct API
-	Status          string    `gorm:"type:varchar(50)" json:"status"`  // Campaign status from Direct API
+	Name            string    `gorm:"type:varchar(255)" json:"name"`            // Campaign name from Direct API
+	Type            string    `gorm:"type:varchar(50)" json:"type"`             // Campaign type from Direct API, e.g. TEXT_CAMPAIGN
+	State           string    `gorm:"type:varchar(50)" json:"state"`            // ON, OFF, SUSPENDED, ENDED, ARCHIVED, ...
+	Status          string    `gorm:"type:varchar(50)" json:"status"`           // Campaign status from Direct API
+	SearchStrategy  string    `gorm:"type:varchar(50)" json:"search_strategy"`  // Bidding strategy type on search
+	NetworkStrategy string    `gorm:"type:varchar(50)" json:"network_strategy"` // Bidding strategy type in ad networks
 	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
+
+// Channel classifies campaign by type and bidding strategy
+// Text campaigns shown both on search and in networks are classified as search,
+// their stats are split into search and networks by ad network type (see ServesBothNetworks)
+func (c *DirectCampaign) Channel() string {
+	switch c.Type {
+	case DirectCampaignTypeUnified:
+		return DirectChannelMaster
+	case DirectCampaignTypeSmart:
+		return DirectChannelSmart
+	case DirectCampaignTypeText, DirectCampaignTypeDynamicText:
+		if c.SearchStrategy == DirectStrategyServingOff {
+			return DirectChannelNetwork
+		}
+		return DirectChannelSearch
+	default:
+		return DirectChannelOther
+	}
+}
+
+// ServesBothNetworks reports whether a text campaign is shown both on search and in ad networks
+func (c *DirectCampaign) ServesBothNetworks() bool {
+	switch c.Type {
+	case DirectCampaignTypeText, DirectCampaignTypeDynamicText:
+		return c.SearchStrategy != DirectStrategyServingOff && c.NetworkStrategy != DirectStrategyServingOff
+	default:
+		return false
+	}
+}
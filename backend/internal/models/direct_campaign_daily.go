@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DirectCampaignDaily represents daily metrics for a Direct campaign
+// DirectCampaignMonthly and DirectTotalsMonthly are recomputed from these rows
+// Network* fields are the part of the values served in ad networks (РСЯ), the rest is served on search
+type DirectCampaignDaily struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	ProjectID          uint      `gorm:"not null;index" json:"project_id"`
+	DirectCampaignID   uint      `gorm:"not null;uniqueIndex:idx_direct_campaign_daily_campaign_date" json:"direct_campaign_id"`
+	Date               time.Time `gorm:"type:date;not null;index;uniqueIndex:idx_direct_campaign_daily_campaign_date" json:"date"`
+	Impressions        int       `gorm:"not null;default:0" json:"impressions"`
+	Clicks             int       `gorm:"not null;default:0" json:"clicks"`
+	CTRPct             float64   `gorm:"type:decimal(6,2)" json:"ctr_pct"`
+	CPC                float64   `gorm:"type:decimal(12,2)" json:"cpc"`
+	Conversions        *int      `json:"conversions"`
+	CPA                *float64  `gorm:"type:decimal(12,2)" json:"cpa"`
+	Cost               float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cost"`
+	NetworkImpressions int       `gorm:"not null;default:0" json:"network_impressions"`
+	NetworkClicks      int       `gorm:"not null;default:0" json:"network_clicks"`
+	NetworkConversions int       `gorm:"not null;default:0" json:"network_conversions"`
+	NetworkCost        float64   `gorm:"type:decimal(14,2);not null;default:0" json:"network_cost"`
+	CreatedAt          time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for DirectCampaignDaily
+func (DirectCampaignDaily) TableName() string {
+	return "direct_campaign_daily"
+}
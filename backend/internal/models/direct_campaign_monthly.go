@@ -3,6 +3,7 @@ package models
 import "time"
 
 // DirectCampaignMonthly represents monthly aggregated metrics for a Direct campaign
+// Network* fields are the part of the values served in ad networks (РСЯ), the rest is served on search
 type DirectCampaignMonthly struct {
 	ID               uint    `gorm:"primaryKey" json:"id"`
 	ProjectID        uint    `gorm:"not null;index" json:"project_id"`
@@ -16,5 +17,9 @@ type DirectCampaignMonthly struct {
 	Conversions      *int    `json:"conversions"`
 	CPA              *float64  `gorm:"type:decimal(12,2)" json:"cpa"`
 	Cost             float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cost"`
+	NetworkImpressions int     `gorm:"not null;default:0" json:"network_impressions"`
+	NetworkClicks      int     `gorm:"not null;default:0" json:"network_clicks"`
+	NetworkConversions int     `gorm:"not null;default:0" json:"network_conversions"`
+	NetworkCost        float64 `gorm:"type:decimal(14,2);not null;default:0" json:"network_cost"`
 	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// DirectKeywordMonthly represents monthly metrics for a keyword or other targeting criterion of a Direct campaign
+type DirectKeywordMonthly struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	ProjectID        uint      `gorm:"not null;index" json:"project_id"`
+	DirectCampaignID uint      `gorm:"not null;index" json:"direct_campaign_id"`
+	AdGroupID        int64     `gorm:"not null;index" json:"ad_group_id"`      // Yandex ad group ID
+	CriterionID      int64     `gorm:"not null" json:"criterion_id"`           // Yandex keyword or criterion ID
+	Criterion        string    `gorm:"type:varchar(500)" json:"criterion"`     // Keyword phrase or criterion description
+	CriterionType    string    `gorm:"type:varchar(50)" json:"criterion_type"` // KEYWORD, AUTOTARGETING, AUDIENCE_TARGET, ...
+	Year             int       `gorm:"not null;index" json:"year"`
+	Month            int       `gorm:"not null;index" json:"month"`
+	Impressions      int       `gorm:"not null;default:0" json:"impressions"`
+	Clicks           int       `gorm:"not null;default:0" json:"clicks"`
+	CTRPct           float64   `gorm:"type:decimal(6,2)" json:"ctr_pct"`
+	CPC              float64   `gorm:"type:decimal(12,2)" json:"cpc"`
+	Conversions      *int      `json:"conversions"`
+	CPA              *float64  `gorm:"type:decimal(12,2)" json:"cpa"`
+	Cost             float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cost"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for DirectKeywordMonthly
+func (DirectKeywordMonthly) TableName() string {
+	return "direct_keyword_monthly"
+}
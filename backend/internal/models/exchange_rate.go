@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ExchangeRateSourceManual marks rates entered by users; providers never overwrite them
+const ExchangeRateSourceManual = "manual"
+
+// ExchangeRate stores the rate of a currency pair on a date: 1 Base = Rate Quote
+type ExchangeRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Base      string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rate_pair_date,priority:1" json:"base"`
+	Quote     string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rate_pair_date,priority:2" json:"quote"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_exchange_rate_pair_date,priority:3" json:"date"`
+	Rate      float64   `gorm:"type:decimal(18,6);not null" json:"rate"`
+	Source    string    `gorm:"type:varchar(20);not null" json:"source"` // "manual" or provider name, e.g. "cbr"
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for ExchangeRate
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// ExchangeRateFilter selects exchange rates; zero values are not applied
+type ExchangeRateFilter struct {
+	Base     string
+	Quote    string
+	DateFrom *time.Time
+	DateTo   *time.Time
+}
@@ -5,10 +5,12 @@ import "time"
 // Goal represents a Yandex.Metrica goal
 type Goal struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
-	CounterID    uint      `gorm:"not null;index" json:"counter_id"`
-	GoalID       int64     `gorm:"not null" json:"goal_id"`
-	Name         string    `json:"name"` // Optional goal name
+	CounterID    uint      `gorm:"not null;index;uniqueIndex:idx_goals_counter_goal" json:"counter_id"`
+	GoalID       int64     `gorm:"not null;uniqueIndex:idx_goals_counter_goal" json:"goal_id"`
+	Name         string    `json:"name"`                         // Optional goal name
+	Type         string    `gorm:"type:varchar(50)" json:"type"` // Goal type from Metrica (url, action, form, ...)
 	IsConversion bool      `gorm:"default:false" json:"is_conversion"`
+	IsDeleted    bool      `gorm:"default:false" json:"is_deleted"` // Goal no longer exists in Metrica
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
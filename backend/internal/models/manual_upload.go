@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ChannelSourceManual is the source of channel metrics uploaded from files (outdoor, radio, influencers, etc.)
+const ChannelSourceManual = "manual"
+
+// ManualUpload records an upload of manual channel metrics: who uploaded which file and what it replaced
+// Rows of the upload are stored in channel_campaign_monthly with the manual source and the upload ID
+type ManualUpload struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"not null;index" json:"project_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	FileName  string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	RowsCount int       `gorm:"not null;default:0" json:"rows_count"`
+	Channels  []string  `gorm:"type:text;serializer:json" json:"channels"`
+	Periods   []string  `gorm:"type:text;serializer:json" json:"periods"` // Months (YYYY-MM) of the rows
+	TotalCost float64   `gorm:"type:decimal(14,2);not null;default:0" json:"total_cost"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for ManualUpload
+func (ManualUpload) TableName() string {
+	return "manual_uploads"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// MetricaVisit represents a raw visit loaded from Yandex.Metrica Logs API
+// Fields that were not requested from Logs API keep zero values
+type MetricaVisit struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProjectID      uint      `gorm:"not null;index" json:"project_id"`
+	CounterID      int64     `gorm:"not null;uniqueIndex:idx_metrica_visits_counter_visit;index:idx_metrica_visits_counter_date" json:"counter_id"` // Yandex counter ID
+	VisitID        uint64    `gorm:"not null;uniqueIndex:idx_metrica_visits_counter_visit" json:"visit_id"`
+	Date           time.Time `gorm:"type:date;not null;index:idx_metrica_visits_counter_date" json:"date"`
+	DateTime       time.Time `gorm:"not null" json:"date_time"`
+	ClientID       string    `gorm:"type:varchar(32);index" json:"client_id"` // Anonymous user ID of Metrica
+	IsNewUser      bool      `gorm:"not null;default:false" json:"is_new_user"`
+	StartURL       string    `gorm:"type:text" json:"start_url"`
+	EndURL         string    `gorm:"type:text" json:"end_url"`
+	PageViews      int       `gorm:"not null;default:0" json:"page_views"`
+	VisitDuration  int       `gorm:"not null;default:0" json:"visit_duration"` // Seconds
+	Bounce         bool      `gorm:"not null;default:false" json:"bounce"`
+	TrafficSource  string    `gorm:"type:varchar(50)" json:"traffic_source"` // organic, ad, direct, referral, ...
+	SearchEngine   string    `gorm:"type:varchar(100)" json:"search_engine"`
+	AdvEngine      string    `gorm:"type:varchar(100)" json:"adv_engine"`
+	UTMSource      string    `gorm:"type:varchar(255)" json:"utm_source"`
+	UTMMedium      string    `gorm:"type:varchar(255)" json:"utm_medium"`
+	UTMCampaign    string    `gorm:"type:varchar(255)" json:"utm_campaign"`
+	DeviceCategory string    `gorm:"type:varchar(20)" json:"device_category"`
+	RegionCity     string    `gorm:"type:varchar(255)" json:"region_city"`
+	GoalIDs        []int64   `gorm:"type:text;serializer:json" json:"goal_ids"` // Goals reached during the visit
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for MetricaVisit
+func (MetricaVisit) TableName() string {
+	return "metrica_visits"
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Breakdown dimensions of monthly Metrica metrics
+const (
+	BreakdownGender  = "gender"
+	BreakdownDevice  = "device"
+	BreakdownCity    = "city"
+	BreakdownCountry = "country"
+)
+
+// BreakdownValueUnknown is stored for visits without a defined dimension value
+const BreakdownValueUnknown = "unknown"
+
+// MetricsBreakdownMonthly represents monthly Metrica metrics broken down by an audience dimension
+type MetricsBreakdownMonthly struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	ProjectID             uint      `gorm:"not null;index:idx_breakdown_period" json:"project_id"`
+	Year                  int       `gorm:"not null;index:idx_breakdown_period" json:"year"`
+	Month                 int       `gorm:"not null;index:idx_breakdown_period" json:"month"`
+	Dimension             string    `gorm:"type:varchar(20);not null;index:idx_breakdown_period" json:"dimension"` // gender, device, city, country
+	Value                 string    `gorm:"type:varchar(100);not null" json:"value"`                               // Value id, e.g. male, mobile, 213
+	Name                  string    `gorm:"type:varchar(255)" json:"name"`                                         // Localized name from Metrica
+	Visits                int       `gorm:"not null;default:0" json:"visits"`
+	Users                 int       `gorm:"not null;default:0" json:"users"`
+	BounceRate            float64   `gorm:"type:decimal(5,2)" json:"bounce_rate"`
+	AvgSessionDurationSec int       `gorm:"not null;default:0" json:"avg_session_duration_sec"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for MetricsBreakdownMonthly
+func (MetricsBreakdownMonthly) TableName() string {
+	return "metrics_breakdown_monthly"
+}
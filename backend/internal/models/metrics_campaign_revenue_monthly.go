@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MetricsCampaignRevenueMonthly represents monthly Metrica revenue of a Direct campaign
+// Visits are attributed to the campaign of the last click from Direct; revenue is in the currency of counters
+type MetricsCampaignRevenueMonthly struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"not null;index:idx_campaign_revenue_period" json:"project_id"`
+	Year         int       `gorm:"not null;index:idx_campaign_revenue_period" json:"year"`
+	Month        int       `gorm:"not null;index:idx_campaign_revenue_period" json:"month"`
+	CampaignID   int64     `gorm:"not null" json:"campaign_id"` // Yandex campaign ID
+	CampaignName string    `gorm:"type:varchar(255)" json:"campaign_name"`
+	Purchases    int       `gorm:"not null;default:0" json:"purchases"`
+	Revenue      float64   `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"`      // Ecommerce revenue
+	GoalRevenue  float64   `gorm:"type:decimal(14,2);not null;default:0" json:"goal_revenue"` // Sum of values of conversion goals
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for MetricsCampaignRevenueMonthly
+func (MetricsCampaignRevenueMonthly) TableName() string {
+	return "metrics_campaign_revenue_monthly"
+}
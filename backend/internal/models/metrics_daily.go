@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MetricsDaily represents daily metrics from Yandex.Metrica aggregated for a project
+// MetricsMonthly is recomputed from these rows (except Users, which are not additive)
+type MetricsDaily struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	ProjectID             uint      `gorm:"not null;uniqueIndex:idx_metrics_daily_project_date" json:"project_id"`
+	Date                  time.Time `gorm:"type:date;not null;uniqueIndex:idx_metrics_daily_project_date" json:"date"`
+	Visits                int       `gorm:"not null;default:0" json:"visits"`
+	Users                 int       `gorm:"not null;default:0" json:"users"`
+	BounceRate            float64   `gorm:"type:decimal(5,2)" json:"bounce_rate"`
+	AvgSessionDurationSec int       `gorm:"not null;default:0" json:"avg_session_duration_sec"`
+	Conversions           *int      `json:"conversions"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for MetricsDaily
+func (MetricsDaily) TableName() string {
+	return "metrics_daily"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MetricsGoalMonthly represents monthly Metrica metrics of a single conversion goal
+type MetricsGoalMonthly struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProjectID      uint      `gorm:"not null;index" json:"project_id"`
+	Year           int       `gorm:"not null;index" json:"year"`
+	Month          int       `gorm:"not null;index" json:"month"`
+	CounterID      uint      `gorm:"not null;index" json:"counter_id"` // YandexCounter.ID
+	GoalID         int64     `gorm:"not null" json:"goal_id"`          // Metrica goal id
+	GoalName       string    `gorm:"type:varchar(255)" json:"goal_name"`
+	Reaches        int       `gorm:"not null;default:0" json:"reaches"`                  // Goal reaches (conversions)
+	Visits         int       `gorm:"not null;default:0" json:"visits"`                   // Visits with the goal reached
+	ConversionRate float64   `gorm:"type:decimal(5,2);default:0" json:"conversion_rate"` // Goal visits share of counter visits, %
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for MetricsGoalMonthly
+func (MetricsGoalMonthly) TableName() string {
+	return "metrics_goal_monthly"
+}
@@ -13,6 +13,10 @@ type MetricsMonthly struct {
 	BounceRate            float64 `gorm:"type:decimal(5,2)" json:"bounce_rate"`
 	AvgSessionDurationSec int     `gorm:"not null;default:0" json:"avg_session_duration_sec"`
 	Conversions           *int    `json:"conversions"`
+	// Ecommerce and goal value revenue in the currency of counters, nil if not synced
+	Purchases             *int     `json:"purchases"`
+	Revenue               *float64 `gorm:"type:decimal(14,2)" json:"revenue"`      // Ecommerce revenue
+	GoalRevenue           *float64 `gorm:"type:decimal(14,2)" json:"goal_revenue"` // Sum of values of conversion goals
 	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Traffic sources from Metrica ym:s:lastTrafficSource dimension (ids of the main ones)
+// Other ids (internal, recommend, email, messenger, ...) are stored as returned by API
+const (
+	TrafficSourceOrganic  = "organic"
+	TrafficSourceAd       = "ad"
+	TrafficSourceDirect   = "direct"
+	TrafficSourceReferral = "referral"
+	TrafficSourceSocial   = "social"
+)
+
+// MetricsTrafficSourceMonthly represents monthly Metrica metrics broken down by last traffic source
+type MetricsTrafficSourceMonthly struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ProjectID   uint      `gorm:"not null;index" json:"project_id"`
+	Year        int       `gorm:"not null;index" json:"year"`
+	Month       int       `gorm:"not null;index" json:"month"`
+	Source      string    `gorm:"type:varchar(50);not null" json:"source"` // Source id, e.g. organic
+	SourceName  string    `gorm:"type:varchar(255)" json:"source_name"`    // Localized name from Metrica
+	Visits      int       `gorm:"not null;default:0" json:"visits"`
+	Users       int       `gorm:"not null;default:0" json:"users"`
+	Conversions *int      `json:"conversions"` // Reaches of conversion goals, nil if no goals configured
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for MetricsTrafficSourceMonthly
+func (MetricsTrafficSourceMonthly) TableName() string {
+	return "metrics_traffic_source_monthly"
+}
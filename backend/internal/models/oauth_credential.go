@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// OAuthProviderYandex is the provider name for Yandex OAuth tokens
+const OAuthProviderYandex = "yandex"
+
+// OAuth credential health statuses
+const (
+	OAuthStatusValid    = "valid"
+	OAuthStatusExpiring = "expiring"
+	OAuthStatusExpired  = "expired"
+	OAuthStatusRevoked  = "revoked"
+)
+
+// OAuthCredential represents an OAuth token stored for a project
+// Counters and Direct accounts may point to a specific credential via OAuthCredentialID
+type OAuthCredential struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ProjectID     *uint      `gorm:"index" json:"project_id"` // nil for agency-wide credentials
+	Provider      string     `gorm:"type:varchar(50);not null;default:'yandex'" json:"provider"`
+	Login         string     `gorm:"type:varchar(255);index" json:"login"` // Login of the token owner
+	AccessToken   string     `gorm:"type:text;not null" json:"-"`          // Encrypted with APP_KEY
+	RefreshToken  string     `gorm:"type:text" json:"-"`                   // Encrypted with APP_KEY
+	ExpiresAt     *time.Time `gorm:"index" json:"expires_at"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'valid'" json:"status"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for OAuthCredential
+func (OAuthCredential) TableName() string {
+	return "oauth_credentials"
+}
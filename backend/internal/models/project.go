@@ -2,15 +2,27 @@ package models
 
 import "time"
 
+// Counter aggregation modes of a project
+const (
+	CounterAggregationSum     = "sum"     // Metrics of all counters are added up
+	CounterAggregationPrimary = "primary" // Only the primary counter is used
+)
+
 // Project represents a client project
 type Project struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"type:text;charset=utf8mb4;collate=utf8mb4_unicode_ci;not null" json:"name"`
-	Slug        string    `gorm:"type:varchar(191);charset=utf8mb4;collate=utf8mb4_unicode_ci;unique;not null" json:"slug"`
-	PublicToken string    `gorm:"type:varchar(64);charset=utf8mb4;collate=utf8mb4_unicode_ci;unique;index" json:"public_token"`
-	Timezone    string    `gorm:"type:varchar(191);charset=utf8mb4;collate=utf8mb4_unicode_ci;default:Europe/Moscow" json:"timezone"`
-	Currency    string    `gorm:"type:enum('RUB');charset=utf8mb4;collate=utf8mb4_unicode_ci;default:'RUB'" json:"currency"`
-	IsActive    bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	Name                string    `gorm:"type:text;charset=utf8mb4;collate=utf8mb4_unicode_ci;not null" json:"name"`
+	Slug                string    `gorm:"type:varchar(191);charset=utf8mb4;collate=utf8mb4_unicode_ci;unique;not null" json:"slug"`
+	PublicToken         string    `gorm:"type:varchar(64);charset=utf8mb4;collate=utf8mb4_unicode_ci;unique;index" json:"public_token"`
+	Timezone            string    `gorm:"type:varchar(191);charset=utf8mb4;collate=utf8mb4_unicode_ci;default:Europe/Moscow" json:"timezone"`
+	Currency            string    `gorm:"type:varchar(3);default:'RUB'" json:"currency"` // Native currency, Direct costs are stored in it
+	ReportingCurrency   *string   `gorm:"type:varchar(3)" json:"reporting_currency"`     // Optional: reports show money in it by default
+	IsActive            bool      `gorm:"default:true" json:"is_active"`
+	AttributionModel    string    `gorm:"type:varchar(10);default:'AUTO'" json:"attribution_model"`  // Direct attribution model for conversions
+	CounterAggregation  string    `gorm:"type:varchar(10);default:'sum'" json:"counter_aggregation"` // How Metrica counters are combined
+	CostIncludeVAT      bool      `gorm:"default:false" json:"cost_include_vat"`                     // Direct costs are requested with VAT
+	CostIncludeDiscount bool      `gorm:"default:false" json:"cost_include_discount"`                // Direct costs are requested with discount applied
+	CostMarkupPct       float64   `gorm:"type:decimal(6,2);default:0" json:"cost_markup_pct"`        // Agency markup added to Direct costs on sync, %
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -9,8 +9,10 @@ type SEOQueriesMonthly struct {
 	Year      int       `gorm:"not null;index" json:"year"`
 	Month     int       `gorm:"not null;index" json:"month"`
 	Query     string    `gorm:"not null" json:"query"`
-	Position  int       `gorm:"not null" json:"position"`
-	URL       *string   `json:"url"`
+	Position  int       `gorm:"not null" json:"position"` // Average show position, rounded
+	Clicks    int       `gorm:"not null;default:0" json:"clicks"`
+	Shows     int       `gorm:"not null;default:0" json:"shows"`
+	URL       *string   `json:"url"` // Site the query was found for
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Sync sources
+const (
+	SyncSourceMetrica   = "metrica"
+	SyncSourceDirect    = "direct"
+	SyncSourceWebmaster = "webmaster"
+)
+
+// Sync run statuses
+const (
+	SyncStatusRunning = "running"
+	SyncStatusSuccess = "success"
+	SyncStatusPartial = "partial" // Finished, but some counters/accounts/hosts produced warnings
+	SyncStatusFailed  = "failed"
+)
+
+// SyncRun records one attempt to sync a source of a project for a month
+type SyncRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ProjectID  uint       `gorm:"not null;index" json:"project_id"`
+	Source     string     `gorm:"type:varchar(20);not null;index" json:"source"`
+	Year       int        `gorm:"not null" json:"year"`
+	Month      int        `gorm:"not null" json:"month"`
+	Status     string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	StartedAt  time.Time  `gorm:"not null;index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	RowsSynced int        `gorm:"not null;default:0" json:"rows_synced"` // Rows saved to the database
+	Warnings   []string   `gorm:"type:text;serializer:json" json:"warnings"`
+	Error      string     `gorm:"type:text" json:"error"`
+}
+
+// TableName specifies the table name for SyncRun
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
+
+// SyncRunFilter selects sync runs; zero values are not applied
+type SyncRunFilter struct {
+	ProjectID uint
+	Source    string
+	Statuses  []string
+	Since     *time.Time // Runs started at or after this time
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// VKAdsAccount represents a VK Ads advertiser cabinet linked to a project
+// Statistics are loaded with client credentials of the cabinet API access
+type VKAdsAccount struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"not null;uniqueIndex" json:"project_id"` // One cabinet per project
+	ClientID     string    `gorm:"type:varchar(255);not null" json:"client_id"`
+	ClientSecret string    `gorm:"type:text;not null" json:"-"` // Encrypted with APP_KEY
+	AccountName  *string   `gorm:"type:varchar(255)" json:"account_name"`
+	Currency     string    `gorm:"type:varchar(3);default:'RUB'" json:"currency"` // Cabinet currency
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for VKAdsAccount
+func (VKAdsAccount) TableName() string {
+	return "vk_ads_accounts"
+}
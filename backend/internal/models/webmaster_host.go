@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// WebmasterHost represents a Yandex.Webmaster site linked to a project
+type WebmasterHost struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ProjectID         uint      `gorm:"not null;uniqueIndex:idx_webmaster_host_project" json:"project_id"`
+	HostID            string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_webmaster_host_project" json:"host_id"` // Webmaster host id, e.g. https:example.com:443
+	HostURL           string    `gorm:"type:varchar(255)" json:"host_url"`                                                // Site URL, e.g. https://example.com/
+	OAuthCredentialID *uint     `gorm:"index" json:"oauth_credential_id"`                                                 // Optional: overrides project credential
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for WebmasterHost
+func (WebmasterHost) TableName() string {
+	return "webmaster_hosts"
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 	"github.com/suprt/planica_bi/backend/internal/config"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/internal/services"
 )
 
+// backfillMonthInterval spreads backfill tasks over time so that API quotas are not spent at once
+const backfillMonthInterval = 30 * time.Second
+
 // Client wraps asynq client for task enqueueing
 type Client struct {
 	client *asynq.Client
@@ -50,6 +55,78 @@ func (c *Client) EnqueueSyncDirectTask(projectID uint, year, month int) (*asynq.
 	)
 }
 
+// EnqueueSyncWebmasterTask enqueues a task to sync Webmaster search queries
+func (c *Client) EnqueueSyncWebmasterTask(projectID uint, year, month int) (*asynq.TaskInfo, error) {
+	task := NewSyncWebmasterTask(projectID, year, month)
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(3),
+		asynq.Timeout(5*60*time.Second), // 5 minutes timeout
+		asynq.Queue("default"),
+	)
+}
+
+// EnqueueSyncConnectorTask enqueues a task to sync a month of a connector source
+func (c *Client) EnqueueSyncConnectorTask(source string, projectID uint, year, month int) (*asynq.TaskInfo, error) {
+	task := NewSyncConnectorTask(source, projectID, year, month)
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(3),
+		asynq.Timeout(10*60*time.Second), // 10 minutes timeout
+		asynq.Queue("default"),
+	)
+}
+
+// EnqueueSyncMetricaLogsTask enqueues a task to load raw Metrica visits from Logs API
+// Logs are prepared by Metrica for minutes to hours, so the task gets a long timeout and low priority
+func (c *Client) EnqueueSyncMetricaLogsTask(projectID uint, dateFrom, dateTo string, fields []string) (*asynq.TaskInfo, error) {
+	task := NewSyncMetricaLogsTask(projectID, dateFrom, dateTo, fields)
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(3),
+		asynq.Timeout(60*60*time.Second), // 1 hour timeout
+		asynq.Queue("low"),
+	)
+}
+
+// EnqueueBackfillTasks enqueues month sync tasks of a backfill job
+// Tasks of consecutive months are delayed by 30 seconds each and go to the low priority queue,
+// so regular syncs run first; Direct tasks get more retries to wait for Units to recover
+// Returns number of enqueued tasks
+func (c *Client) EnqueueBackfillTasks(job *models.BackfillJob, months []services.BackfillMonth) (int, error) {
+	enqueued := 0
+	for i, month := range months {
+		for _, source := range services.BackfillSources(job) {
+			task, err := NewBackfillSyncTask(source, job.ID, job.ProjectID, month.Year, month.Month)
+			if err != nil {
+				return enqueued, err
+			}
+
+			maxRetry := 5
+			if source == services.BackfillSourceDirect {
+				maxRetry = 10
+			}
+			if _, err := c.client.Enqueue(task,
+				asynq.MaxRetry(maxRetry),
+				asynq.Timeout(10*60*time.Second), // 10 minutes timeout
+				asynq.ProcessIn(time.Duration(i)*backfillMonthInterval),
+				asynq.Queue("low"),
+			); err != nil {
+				return enqueued, err
+			}
+			enqueued++
+		}
+	}
+	return enqueued, nil
+}
+
+// EnqueueSyncGoalsTask enqueues a task to load goals of a counter
+func (c *Client) EnqueueSyncGoalsTask(counterID uint) (*asynq.TaskInfo, error) {
+	task := NewSyncGoalsTask(counterID)
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(3),
+		asynq.Timeout(60*time.Second), // 1 minute timeout
+		asynq.Queue("default"),
+	)
+}
+
 // EnqueueSyncProjectTask enqueues a task to sync entire project
 func (c *Client) EnqueueSyncProjectTask(projectID uint) (*asynq.TaskInfo, error) {
 	task := NewSyncProjectTask(projectID)
@@ -80,6 +157,26 @@ func (c *Client) EnqueueGenerateReportTask(projectID uint) (*asynq.TaskInfo, err
 	)
 }
 
+// EnqueueRefreshOAuthTask enqueues a task to refresh expiring OAuth tokens and check their health
+func (c *Client) EnqueueRefreshOAuthTask() (*asynq.TaskInfo, error) {
+	task := NewRefreshOAuthTask()
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(3),
+		asynq.Timeout(5*60*time.Second), // 5 minutes timeout
+		asynq.Queue("critical"),         // Expired tokens break every sync
+	)
+}
+
+// EnqueueFetchRatesTask enqueues a task to load exchange rates of a date (YYYY-MM-DD) from the provider
+func (c *Client) EnqueueFetchRatesTask(date string) (*asynq.TaskInfo, error) {
+	task := NewFetchRatesTask(date)
+	return c.client.Enqueue(task,
+		asynq.MaxRetry(5),
+		asynq.Timeout(2*60*time.Second), // 2 minutes timeout
+		asynq.Queue("default"),
+	)
+}
+
 // GetRedisClient returns underlying Redis client (for worker)
 func GetRedisClient(cfg *config.Config) redis.UniversalClient {
 	return redis.NewClient(&redis.Options{
@@ -5,31 +5,73 @@ import (
 	"fmt"
 
 	"github.com/hibiken/asynq"
+	"github.com/suprt/planica_bi/backend/internal/services"
 )
 
 // Task type names
 const (
 	TypeSyncMetrica     = "sync:metrica"
 	TypeSyncDirect      = "sync:direct"
+	TypeSyncWebmaster   = "sync:webmaster"
+	TypeSyncMetricaLogs = "sync:metrica_logs"
+	TypeSyncGoals       = "sync:goals"
 	TypeSyncProject     = "sync:project"
+	TypeSyncConnector   = "sync:connector" // Any source registered in connectors.Registry
 	TypeAnalyzeMetrics  = "analyze:metrics"
 	TypeGenerateReport  = "generate:report"
+	TypeRefreshOAuth    = "oauth:refresh"
+	TypeFetchRates      = "rates:fetch"
 )
 
 // SyncMetricaPayload is the payload for Metrica sync task
 type SyncMetricaPayload struct {
-	ProjectID uint `json:"project_id"`
-	Year      int  `json:"year"`
-	Month     int  `json:"month"`
+	ProjectID     uint `json:"project_id"`
+	Year          int  `json:"year"`
+	Month         int  `json:"month"`
+	BackfillJobID uint `json:"backfill_job_id,omitempty"` // Set for tasks of a backfill job
 }
 
 // SyncDirectPayload is the payload for Direct sync task
 type SyncDirectPayload struct {
+	ProjectID     uint `json:"project_id"`
+	Year          int  `json:"year"`
+	Month         int  `json:"month"`
+	BackfillJobID uint `json:"backfill_job_id,omitempty"` // Set for tasks of a backfill job
+}
+
+// SyncWebmasterPayload is the payload for Webmaster sync task
+type SyncWebmasterPayload struct {
 	ProjectID uint `json:"project_id"`
 	Year      int  `json:"year"`
 	Month     int  `json:"month"`
 }
 
+// SyncConnectorPayload is the payload for sync task of a connector source
+type SyncConnectorPayload struct {
+	Source    string `json:"source"` // Connector source, e.g. vk_ads
+	ProjectID uint   `json:"project_id"`
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+}
+
+// SyncMetricaLogsPayload is the payload for Metrica Logs API load task
+type SyncMetricaLogsPayload struct {
+	ProjectID uint     `json:"project_id"`
+	DateFrom  string   `json:"date_from"`        // YYYY-MM-DD
+	DateTo    string   `json:"date_to"`          // YYYY-MM-DD
+	Fields    []string `json:"fields,omitempty"` // Field names, configured defaults if empty
+}
+
+// FetchRatesPayload is the payload for exchange rates fetch task
+type FetchRatesPayload struct {
+	Date string `json:"date"` // YYYY-MM-DD
+}
+
+// SyncGoalsPayload is the payload for counter goals sync task
+type SyncGoalsPayload struct {
+	CounterID uint `json:"counter_id"` // YandexCounter.ID
+}
+
 // SyncProjectPayload is the payload for project sync task
 type SyncProjectPayload struct {
 	ProjectID uint `json:"project_id"`
@@ -74,6 +116,83 @@ func NewSyncDirectTask(projectID uint, year, month int) *asynq.Task {
 	return asynq.NewTask(TypeSyncDirect, payloadBytes)
 }
 
+// NewSyncWebmasterTask creates a new Webmaster sync task
+func NewSyncWebmasterTask(projectID uint, year, month int) *asynq.Task {
+	payload := SyncWebmasterPayload{
+		ProjectID: projectID,
+		Year:      year,
+		Month:     month,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal payload: %v", err))
+	}
+	return asynq.NewTask(TypeSyncWebmaster, payloadBytes)
+}
+
+// NewSyncConnectorTask creates a new sync task of a connector source
+func NewSyncConnectorTask(source string, projectID uint, year, month int) *asynq.Task {
+	payload := SyncConnectorPayload{
+		Source:    source,
+		ProjectID: projectID,
+		Year:      year,
+		Month:     month,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal payload: %v", err))
+	}
+	return asynq.NewTask(TypeSyncConnector, payloadBytes)
+}
+
+// NewSyncMetricaLogsTask creates a new Metrica Logs API load task
+func NewSyncMetricaLogsTask(projectID uint, dateFrom, dateTo string, fields []string) *asynq.Task {
+	payload := SyncMetricaLogsPayload{
+		ProjectID: projectID,
+		DateFrom:  dateFrom,
+		DateTo:    dateTo,
+		Fields:    fields,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal payload: %v", err))
+	}
+	return asynq.NewTask(TypeSyncMetricaLogs, payloadBytes)
+}
+
+// NewBackfillSyncTask creates Metrica or Direct sync task of one month of a backfill job
+func NewBackfillSyncTask(source string, jobID, projectID uint, year, month int) (*asynq.Task, error) {
+	var taskType string
+	var payload interface{}
+	switch source {
+	case services.BackfillSourceMetrica:
+		taskType = TypeSyncMetrica
+		payload = SyncMetricaPayload{ProjectID: projectID, Year: year, Month: month, BackfillJobID: jobID}
+	case services.BackfillSourceDirect:
+		taskType = TypeSyncDirect
+		payload = SyncDirectPayload{ProjectID: projectID, Year: year, Month: month, BackfillJobID: jobID}
+	default:
+		return nil, fmt.Errorf("unknown backfill source: %s", source)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return asynq.NewTask(taskType, payloadBytes), nil
+}
+
+// NewSyncGoalsTask creates a new counter goals sync task
+func NewSyncGoalsTask(counterID uint) *asynq.Task {
+	payload := SyncGoalsPayload{
+		CounterID: counterID,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal payload: %v", err))
+	}
+	return asynq.NewTask(TypeSyncGoals, payloadBytes)
+}
+
 // NewSyncProjectTask creates a new project sync task
 func NewSyncProjectTask(projectID uint) *asynq.Task {
 	payload := SyncProjectPayload{
@@ -104,6 +223,42 @@ func ParseSyncDirectPayload(task *asynq.Task) (*SyncDirectPayload, error) {
 	return &payload, nil
 }
 
+// ParseSyncWebmasterPayload parses Webmaster sync task payload
+func ParseSyncWebmasterPayload(task *asynq.Task) (*SyncWebmasterPayload, error) {
+	var payload SyncWebmasterPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return &payload, nil
+}
+
+// ParseSyncConnectorPayload parses connector sync task payload
+func ParseSyncConnectorPayload(task *asynq.Task) (*SyncConnectorPayload, error) {
+	var payload SyncConnectorPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return &payload, nil
+}
+
+// ParseSyncMetricaLogsPayload parses Metrica Logs API load task payload
+func ParseSyncMetricaLogsPayload(task *asynq.Task) (*SyncMetricaLogsPayload, error) {
+	var payload SyncMetricaLogsPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return &payload, nil
+}
+
+// ParseSyncGoalsPayload parses counter goals sync task payload
+func ParseSyncGoalsPayload(task *asynq.Task) (*SyncGoalsPayload, error) {
+	var payload SyncGoalsPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return &payload, nil
+}
+
 // ParseSyncProjectPayload parses project sync task payload
 func ParseSyncProjectPayload(task *asynq.Task) (*SyncProjectPayload, error) {
 	var payload SyncProjectPayload
@@ -156,3 +311,27 @@ func ParseGenerateReportPayload(task *asynq.Task) (*GenerateReportPayload, error
 	return &payload, nil
 }
 
+// NewRefreshOAuthTask creates a new OAuth token refresh task
+// The task has no payload: it processes all stored credentials
+func NewRefreshOAuthTask() *asynq.Task {
+	return asynq.NewTask(TypeRefreshOAuth, nil)
+}
+
+// NewFetchRatesTask creates a new exchange rates fetch task for a date (YYYY-MM-DD)
+func NewFetchRatesTask(date string) *asynq.Task {
+	payload := FetchRatesPayload{Date: date}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal payload: %v", err))
+	}
+	return asynq.NewTask(TypeFetchRates, payloadBytes)
+}
+
+// ParseFetchRatesPayload parses exchange rates fetch task payload
+func ParseFetchRatesPayload(task *asynq.Task) (*FetchRatesPayload, error) {
+	var payload FetchRatesPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return &payload, nil
+}
@@ -15,15 +15,20 @@ import (
 
 // Worker handles task processing
 type Worker struct {
-	server        *asynq.Server
-	mux           *asynq.ServeMux
-	syncService   *services.SyncService
-	reportService *services.ReportService
-	cache         *cache.Cache
+	server            *asynq.Server
+	mux               *asynq.ServeMux
+	syncService       *services.SyncService
+	reportService     *services.ReportService
+	credentialService *services.OAuthCredentialService
+	backfillService   *services.BackfillService
+	logsService       *services.MetricaLogsService
+	ratesService      *services.ExchangeRateService
+	connectorService  *services.ConnectorService
+	cache             *cache.Cache
 }
 
 // NewWorker creates a new queue worker
-func NewWorker(cfg *config.Config, syncService *services.SyncService, reportService *services.ReportService, cacheClient *cache.Cache) (*Worker, error) {
+func NewWorker(cfg *config.Config, syncService *services.SyncService, reportService *services.ReportService, credentialService *services.OAuthCredentialService, backfillService *services.BackfillService, logsService *services.MetricaLogsService, ratesService *services.ExchangeRateService, connectorService *services.ConnectorService, cacheClient *cache.Cache) (*Worker, error) {
 	redisOpt := asynq.RedisClientOpt{
 		Addr:     cfg.RedisHost + ":" + cfg.RedisPort,
 		Password: cfg.RedisPassword,
@@ -47,11 +52,16 @@ func NewWorker(cfg *config.Config, syncService *services.SyncService, reportServ
 	mux := asynq.NewServeMux()
 
 	worker := &Worker{
-		server:        server,
-		mux:           mux,
-		syncService:   syncService,
-		reportService: reportService,
-		cache:         cacheClient,
+		server:            server,
+		mux:               mux,
+		syncService:       syncService,
+		reportService:     reportService,
+		credentialService: credentialService,
+		backfillService:   backfillService,
+		logsService:       logsService,
+		ratesService:      ratesService,
+		connectorService:  connectorService,
+		cache:             cacheClient,
 	}
 
 	// Register task handlers
@@ -64,9 +74,15 @@ func NewWorker(cfg *config.Config, syncService *services.SyncService, reportServ
 func (w *Worker) registerHandlers() {
 	w.mux.HandleFunc(TypeSyncMetrica, w.handleSyncMetrica)
 	w.mux.HandleFunc(TypeSyncDirect, w.handleSyncDirect)
+	w.mux.HandleFunc(TypeSyncWebmaster, w.handleSyncWebmaster)
+	w.mux.HandleFunc(TypeSyncConnector, w.handleSyncConnector)
+	w.mux.HandleFunc(TypeSyncMetricaLogs, w.handleSyncMetricaLogs)
+	w.mux.HandleFunc(TypeSyncGoals, w.handleSyncGoals)
 	w.mux.HandleFunc(TypeSyncProject, w.handleSyncProject)
 	w.mux.HandleFunc(TypeAnalyzeMetrics, w.handleAnalyzeMetrics)
 	w.mux.HandleFunc(TypeGenerateReport, w.handleGenerateReport)
+	w.mux.HandleFunc(TypeRefreshOAuth, w.handleRefreshOAuth)
+	w.mux.HandleFunc(TypeFetchRates, w.handleFetchRates)
 }
 
 // handleSyncMetrica handles Metrica sync task
@@ -86,6 +102,7 @@ func (w *Worker) handleSyncMetrica(ctx context.Context, task *asynq.Task) error
 
 	// Call sync service method
 	err = w.syncService.SyncMetricaData(ctx, payload.ProjectID, payload.Year, payload.Month)
+	w.recordBackfillResult(ctx, payload.BackfillJobID, err)
 	if err != nil {
 		if logger.Log != nil {
 			logger.Log.Error("Failed to sync Metrica data",
@@ -105,6 +122,32 @@ func (w *Worker) handleSyncMetrica(ctx context.Context, task *asynq.Task) error
 	return nil
 }
 
+// recordBackfillResult counts a finished task in its backfill job
+// Failed attempts are counted only when asynq will not retry the task anymore
+func (w *Worker) recordBackfillResult(ctx context.Context, jobID uint, taskErr error) {
+	if jobID == 0 || w.backfillService == nil {
+		return
+	}
+
+	failed := taskErr != nil
+	if failed {
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried < maxRetry {
+			return
+		}
+	}
+
+	if err := w.backfillService.RecordTaskResult(ctx, jobID, failed); err != nil {
+		if logger.Log != nil {
+			logger.Log.Warn("Failed to record backfill progress",
+				zap.Uint("backfill_job_id", jobID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 // handleSyncDirect handles Direct sync task
 func (w *Worker) handleSyncDirect(ctx context.Context, task *asynq.Task) error {
 	payload, err := ParseSyncDirectPayload(task)
@@ -122,6 +165,7 @@ func (w *Worker) handleSyncDirect(ctx context.Context, task *asynq.Task) error {
 
 	// Call sync service method
 	err = w.syncService.SyncDirectData(ctx, payload.ProjectID, payload.Year, payload.Month)
+	w.recordBackfillResult(ctx, payload.BackfillJobID, err)
 	if err != nil {
 		if logger.Log != nil {
 			logger.Log.Error("Failed to sync Direct data",
@@ -141,6 +185,150 @@ func (w *Worker) handleSyncDirect(ctx context.Context, task *asynq.Task) error {
 	return nil
 }
 
+// handleSyncWebmaster handles Webmaster sync task
+func (w *Worker) handleSyncWebmaster(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseSyncWebmasterPayload(task)
+	if err != nil {
+		return fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Processing Webmaster sync task",
+			zap.Uint("project_id", payload.ProjectID),
+			zap.Int("year", payload.Year),
+			zap.Int("month", payload.Month),
+		)
+	}
+
+	// Call sync service method
+	err = w.syncService.SyncWebmasterData(ctx, payload.ProjectID, payload.Year, payload.Month)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to sync Webmaster data",
+				zap.Uint("project_id", payload.ProjectID),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Webmaster sync task completed",
+			zap.Uint("project_id", payload.ProjectID),
+		)
+	}
+
+	return nil
+}
+
+// handleSyncConnector handles sync task of a connector source
+// Sources are resolved in the connector registry, so new connectors need no handler of their own
+func (w *Worker) handleSyncConnector(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseSyncConnectorPayload(task)
+	if err != nil {
+		return fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Processing connector sync task",
+			zap.String("source", payload.Source),
+			zap.Uint("project_id", payload.ProjectID),
+			zap.Int("year", payload.Year),
+			zap.Int("month", payload.Month),
+		)
+	}
+
+	err = w.connectorService.SyncSource(ctx, payload.Source, payload.ProjectID, payload.Year, payload.Month)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to sync connector data",
+				zap.String("source", payload.Source),
+				zap.Uint("project_id", payload.ProjectID),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Connector sync task completed",
+			zap.String("source", payload.Source),
+			zap.Uint("project_id", payload.ProjectID),
+		)
+	}
+
+	return nil
+}
+
+// handleSyncMetricaLogs handles Metrica Logs API load task
+func (w *Worker) handleSyncMetricaLogs(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseSyncMetricaLogsPayload(task)
+	if err != nil {
+		return fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Processing Metrica logs task",
+			zap.Uint("project_id", payload.ProjectID),
+			zap.String("date_from", payload.DateFrom),
+			zap.String("date_to", payload.DateTo),
+			zap.Strings("fields", payload.Fields),
+		)
+	}
+
+	visits, err := w.logsService.LoadVisits(ctx, payload.ProjectID, payload.DateFrom, payload.DateTo, payload.Fields)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to load Metrica logs",
+				zap.Uint("project_id", payload.ProjectID),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Metrica logs task completed",
+			zap.Uint("project_id", payload.ProjectID),
+			zap.Int("visits", visits),
+		)
+	}
+
+	return nil
+}
+
+// handleSyncGoals handles counter goals sync task
+func (w *Worker) handleSyncGoals(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseSyncGoalsPayload(task)
+	if err != nil {
+		return fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Processing goals sync task",
+			zap.Uint("counter_id", payload.CounterID),
+		)
+	}
+
+	if err := w.syncService.SyncCounterGoals(ctx, payload.CounterID); err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to sync counter goals",
+				zap.Uint("counter_id", payload.CounterID),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Goals sync task completed",
+			zap.Uint("counter_id", payload.CounterID),
+		)
+	}
+
+	return nil
+}
+
 // handleSyncProject handles project sync task
 func (w *Worker) handleSyncProject(ctx context.Context, task *asynq.Task) error {
 	payload, err := ParseSyncProjectPayload(task)
@@ -190,7 +378,7 @@ func (w *Worker) handleAnalyzeMetrics(ctx context.Context, task *asynq.Task) err
 	}
 
 	// Get channel metrics data
-	metricsData, err := w.reportService.GetChannelMetrics(ctx, payload.ProjectID, payload.Periods)
+	metricsData, err := w.reportService.GetChannelMetrics(ctx, payload.ProjectID, payload.Periods, services.CurrencyNative)
 	if err != nil {
 		if logger.Log != nil {
 			logger.Log.Error("Failed to get channel metrics",
@@ -256,7 +444,7 @@ func (w *Worker) handleGenerateReport(ctx context.Context, task *asynq.Task) err
 		)
 	}
 
-	metricsData, err := w.reportService.GetChannelMetrics(ctx, payload.ProjectID, report.Periods)
+	metricsData, err := w.reportService.GetChannelMetrics(ctx, payload.ProjectID, report.Periods, services.CurrencyNative)
 	if err != nil {
 		if logger.Log != nil {
 			logger.Log.Warn("Failed to get channel metrics for AI analysis",
@@ -340,6 +528,73 @@ func (w *Worker) handleGenerateReport(ctx context.Context, task *asynq.Task) err
 	return nil
 }
 
+// handleRefreshOAuth refreshes expiring OAuth tokens and records token health
+func (w *Worker) handleRefreshOAuth(ctx context.Context, task *asynq.Task) error {
+	if logger.Log != nil {
+		logger.Log.Info("Processing OAuth token refresh task")
+	}
+
+	refreshed, err := w.credentialService.RefreshExpiringTokens(ctx)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to refresh OAuth tokens", zap.Error(err))
+		}
+		return err
+	}
+
+	// Check health after refresh so statuses reflect new tokens
+	if err := w.credentialService.CheckTokensHealth(ctx); err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to check OAuth tokens health", zap.Error(err))
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("OAuth token refresh task completed",
+			zap.Int("refreshed", refreshed),
+		)
+	}
+
+	return nil
+}
+
+// handleFetchRates loads exchange rates of a date from the configured provider
+func (w *Worker) handleFetchRates(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseFetchRatesPayload(task)
+	if err != nil {
+		return fmt.Errorf("failed to parse payload: %w", err)
+	}
+	date, err := time.Parse("2006-01-02", payload.Date)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", payload.Date, err)
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Processing exchange rates fetch task", zap.String("date", payload.Date))
+	}
+
+	saved, err := w.ratesService.FetchRates(ctx, date)
+	if err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to fetch exchange rates",
+				zap.String("date", payload.Date),
+				zap.Error(err),
+			)
+		}
+		return err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Exchange rates fetch task completed",
+			zap.String("date", payload.Date),
+			zap.Int("rates", saved),
+		)
+	}
+
+	return nil
+}
+
 // Start starts the worker server
 func (w *Worker) Start() error {
 	return w.server.Start(w.mux)
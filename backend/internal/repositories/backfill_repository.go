@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// BackfillRepository handles database operations for backfill jobs
+type BackfillRepository struct {
+	db *gorm.DB
+}
+
+// NewBackfillRepository creates a new backfill repository
+func NewBackfillRepository(db *gorm.DB) *BackfillRepository {
+	return &BackfillRepository{db: db}
+}
+
+// Create creates a new backfill job
+func (r *BackfillRepository) Create(ctx context.Context, job *models.BackfillJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID retrieves a backfill job by ID
+// Returns nil if job does not exist
+func (r *BackfillRepository) GetByID(ctx context.Context, id uint) (*models.BackfillJob, error) {
+	var job models.BackfillJob
+	err := r.db.WithContext(ctx).First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetByProjectID retrieves backfill jobs of a project, newest first
+func (r *BackfillRepository) GetByProjectID(ctx context.Context, projectID uint) ([]*models.BackfillJob, error) {
+	var jobs []*models.BackfillJob
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// AddProgress atomically adds finished tasks to a job and updates its status
+// Counters are incremented in SQL, so concurrent workers do not overwrite each other
+func (r *BackfillRepository) AddProgress(ctx context.Context, id uint, completed, failed int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.BackfillJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"completed_tasks": gorm.Expr("completed_tasks + ?", completed),
+			"failed_tasks":    gorm.Expr("failed_tasks + ?", failed),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BackfillJob{}).
+			Where("id = ? AND completed_tasks + failed_tasks >= total_tasks", id).
+			Update("status", gorm.Expr("CASE WHEN failed_tasks > 0 THEN ? ELSE ? END",
+				models.BackfillStatusFailed, models.BackfillStatusCompleted)).Error
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ChannelRepository handles database operations for campaign metrics of connectors
+type ChannelRepository struct {
+	db *gorm.DB
+}
+
+// NewChannelRepository creates a new channel repository
+func NewChannelRepository(db *gorm.DB) *ChannelRepository {
+	return &ChannelRepository{db: db}
+}
+
+// ReplaceCampaignMonthly replaces campaign metrics of a source for a project and month
+func (r *ChannelRepository) ReplaceCampaignMonthly(ctx context.Context, projectID uint, source string, year, month int, metrics []*models.ChannelCampaignMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND source = ? AND year = ? AND month = ?", projectID, source, year, month).
+			Delete(&models.ChannelCampaignMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(metrics, 100).Error
+	})
+}
+
+// GetCampaignMonthly retrieves campaign metrics of all sources for a project and month
+func (r *ChannelRepository) GetCampaignMonthly(ctx context.Context, projectID uint, year, month int) ([]*models.ChannelCampaignMonthly, error) {
+	var metrics []*models.ChannelCampaignMonthly
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("source, channel, cost DESC").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// SaveManualUpload records a manual upload and replaces manual metrics of its channels and months with the rows
+func (r *ChannelRepository) SaveManualUpload(ctx context.Context, upload *models.ManualUpload, metrics []*models.ChannelCampaignMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(upload).Error; err != nil {
+			return err
+		}
+		for _, metric := range metrics {
+			if err := tx.Where("project_id = ? AND source = ? AND channel = ? AND year = ? AND month = ?",
+				upload.ProjectID, models.ChannelSourceManual, metric.Channel, metric.Year, metric.Month).
+				Delete(&models.ChannelCampaignMonthly{}).Error; err != nil {
+				return err
+			}
+			metric.UploadID = &upload.ID
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(metrics, 100).Error
+	})
+}
+
+// GetManualUploads retrieves manual uploads of a project with users who made them, newest first
+func (r *ChannelRepository) GetManualUploads(ctx context.Context, projectID uint) ([]*models.ManualUpload, error) {
+	var uploads []*models.ManualUpload
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC").
+		Find(&uploads).Error
+	return uploads, err
+}
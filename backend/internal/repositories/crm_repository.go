@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CRMRepository handles database operations for CRM deals
+type CRMRepository struct {
+	db *gorm.DB
+}
+
+// NewCRMRepository creates a new CRM repository
+func NewCRMRepository(db *gorm.DB) *CRMRepository {
+	return &CRMRepository{db: db}
+}
+
+// SaveDeals inserts or updates deals (unique by project and deal ID)
+func (r *CRMRepository) SaveDeals(ctx context.Context, deals []*models.CRMDeal) error {
+	if len(deals) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "deal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"date", "status", "revenue", "utm_source", "utm_medium", "utm_campaign", "campaign_id", "traffic_source", "updated_at"}),
+	}).CreateInBatches(deals, 500).Error
+}
+
+// GetDeals retrieves deals of a project within a date range (inclusive)
+func (r *CRMRepository) GetDeals(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.CRMDeal, error) {
+	var deals []*models.CRMDeal
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND date BETWEEN ? AND ?", projectID, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")).
+		Order("date, id").
+		Find(&deals).Error
+	return deals, err
+}
+
+// SaveWebhook creates or replaces the webhook token of a project
+func (r *CRMRepository) SaveWebhook(ctx context.Context, webhook *models.CRMWebhook) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "updated_at"}),
+	}).Create(webhook).Error
+}
+
+// GetWebhookByTokenHash retrieves a webhook by hash of its token
+func (r *CRMRepository) GetWebhookByTokenHash(ctx context.Context, tokenHash string) (*models.CRMWebhook, error) {
+	var webhook models.CRMWebhook
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
@@ -2,11 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/cache"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DirectRepository handles database operations for Direct accounts
@@ -72,6 +75,31 @@ func (r *DirectRepository) GetAccountByClientLogin(ctx context.Context, projectI
 	return &account, nil
 }
 
+// GetAccountByID retrieves a Direct account by ID
+func (r *DirectRepository) GetAccountByID(ctx context.Context, id uint) (*models.DirectAccount, error) {
+	var account models.DirectAccount
+	err := r.db.WithContext(ctx).First(&account, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// UpdateAccount updates a Direct account
+func (r *DirectRepository) UpdateAccount(ctx context.Context, account *models.DirectAccount) error {
+	if err := r.db.WithContext(ctx).Save(account).Error; err != nil {
+		return err
+	}
+
+	// Invalidate cache for this project
+	if r.cache != nil {
+		cacheKey := cache.BuildKey(cache.KeyPrefixDirectAccounts, account.ProjectID)
+		_ = r.cache.Delete(cacheKey)
+	}
+
+	return nil
+}
+
 // GetCampaignMonthly retrieves monthly campaign metrics
 func (r *DirectRepository) GetCampaignMonthly(ctx context.Context, projectID uint, year int, month int) ([]*models.DirectCampaignMonthly, error) {
 	var metrics []*models.DirectCampaignMonthly
@@ -86,11 +114,15 @@ func (r *DirectRepository) SaveCampaignMonthly(metrics *models.DirectCampaignMon
 }
 
 // GetTotalsMonthly retrieves monthly totals for a project
+// Returns nil if there is no data for the month
 func (r *DirectRepository) GetTotalsMonthly(ctx context.Context, projectID uint, year int, month int) (*models.DirectTotalsMonthly, error) {
 	var totals models.DirectTotalsMonthly
 	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
 		First(&totals).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &totals, nil
@@ -139,6 +171,21 @@ func (r *DirectRepository) CreateCampaign(ctx context.Context, campaign *models.
 	return nil
 }
 
+// UpdateCampaign updates type, state, status and strategies of a Direct campaign
+func (r *DirectRepository) UpdateCampaign(ctx context.Context, campaign *models.DirectCampaign) error {
+	if err := r.db.WithContext(ctx).Save(campaign).Error; err != nil {
+		return err
+	}
+
+	// Invalidate cache for this account
+	if r.cache != nil {
+		cacheKey := cache.BuildKey(cache.KeyPrefixDirectCampaigns, campaign.DirectAccountID)
+		_ = r.cache.Delete(cacheKey)
+	}
+
+	return nil
+}
+
 // GetCampaignByID retrieves a Direct campaign by ID
 func (r *DirectRepository) GetCampaignByID(ctx context.Context, id uint) (*models.DirectCampaign, error) {
 	var campaign models.DirectCampaign
@@ -150,11 +197,15 @@ func (r *DirectRepository) GetCampaignByID(ctx context.Context, id uint) (*model
 }
 
 // GetCampaignMonthlyByCampaignID retrieves campaign monthly metrics by campaign ID
+// Returns nil if there is no data for the month
 func (r *DirectRepository) GetCampaignMonthlyByCampaignID(ctx context.Context, projectID uint, directCampaignID uint, year int, month int) (*models.DirectCampaignMonthly, error) {
 	var metrics models.DirectCampaignMonthly
 	err := r.db.WithContext(ctx).Where("project_id = ? AND direct_campaign_id = ? AND year = ? AND month = ?",
 		projectID, directCampaignID, year, month).First(&metrics).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &metrics, nil
@@ -172,3 +223,139 @@ func (r *DirectRepository) GetCampaignsByProjectID(ctx context.Context, projectI
 	}
 	return campaigns, nil
 }
+
+// SaveCampaignDaily inserts or updates daily campaign metrics (unique by campaign and date)
+func (r *DirectRepository) SaveCampaignDaily(ctx context.Context, metrics []*models.DirectCampaignDaily) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "direct_campaign_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"impressions", "clicks", "ctr_pct", "cpc", "conversions", "cpa", "cost",
+			"network_impressions", "network_clicks", "network_conversions", "network_cost", "updated_at"}),
+	}).Create(&metrics).Error
+}
+
+// GetCampaignDaily retrieves daily campaign metrics for a project within date range (inclusive), ordered by date
+func (r *DirectRepository) GetCampaignDaily(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error) {
+	var metrics []*models.DirectCampaignDaily
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND date BETWEEN ? AND ?", projectID, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")).
+		Order("date ASC, direct_campaign_id ASC").
+		Find(&metrics).Error
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// directStatsSortColumns maps sort fields of ad group and keyword statistics to columns
+// Nullable columns are ordered with NULLs last in both directions
+var directStatsSortColumns = map[string]struct {
+	column   string
+	nullable bool
+}{
+	models.DirectStatsSortCost:        {column: "cost"},
+	models.DirectStatsSortCTR:         {column: "ctr_pct"},
+	models.DirectStatsSortCPA:         {column: "cpa", nullable: true},
+	models.DirectStatsSortClicks:      {column: "clicks"},
+	models.DirectStatsSortImpressions: {column: "impressions"},
+	models.DirectStatsSortConversions: {column: "conversions", nullable: true},
+}
+
+// directStatsOrder builds ORDER BY clause for ad group and keyword statistics
+func directStatsOrder(filter models.DirectStatsFilter) string {
+	sort, ok := directStatsSortColumns[filter.Sort]
+	if !ok {
+		sort = directStatsSortColumns[models.DirectStatsSortCost]
+	}
+	order := "DESC"
+	if filter.Order == "ASC" {
+		order = "ASC"
+	}
+	if sort.nullable {
+		return sort.column + " IS NULL, " + sort.column + " " + order + ", id ASC"
+	}
+	return sort.column + " " + order + ", id ASC"
+}
+
+// GetAdGroupMonthly retrieves ad group metrics of a campaign for a month, sorted by filter
+func (r *DirectRepository) GetAdGroupMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.DirectAdGroupMonthly{}).
+		Where("direct_campaign_id = ? AND year = ? AND month = ?", filter.DirectCampaignID, filter.Year, filter.Month)
+	if filter.AdGroupID != 0 {
+		query = query.Where("ad_group_id = ?", filter.AdGroupID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var metrics []*models.DirectAdGroupMonthly
+	err := query.
+		Order(directStatsOrder(filter)).
+		Limit(pagination.PerPage).
+		Offset(pagination.Offset).
+		Find(&metrics).Error
+
+	return metrics, total, err
+}
+
+// ReplaceAdGroupMonthly replaces ad group metrics of campaigns for a month
+// Only the given campaigns are touched, so campaigns of other accounts keep their data
+func (r *DirectRepository) ReplaceAdGroupMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectAdGroupMonthly) error {
+	if len(directCampaignIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("direct_campaign_id IN ? AND year = ? AND month = ?", directCampaignIDs, year, month).
+			Delete(&models.DirectAdGroupMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(metrics, 100).Error
+	})
+}
+
+// GetKeywordMonthly retrieves keyword metrics of a campaign (optionally of one ad group) for a month, sorted by filter
+func (r *DirectRepository) GetKeywordMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.DirectKeywordMonthly{}).
+		Where("direct_campaign_id = ? AND year = ? AND month = ?", filter.DirectCampaignID, filter.Year, filter.Month)
+	if filter.AdGroupID != 0 {
+		query = query.Where("ad_group_id = ?", filter.AdGroupID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var metrics []*models.DirectKeywordMonthly
+	err := query.
+		Order(directStatsOrder(filter)).
+		Limit(pagination.PerPage).
+		Offset(pagination.Offset).
+		Find(&metrics).Error
+
+	return metrics, total, err
+}
+
+// ReplaceKeywordMonthly replaces keyword metrics of campaigns for a month
+func (r *DirectRepository) ReplaceKeywordMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectKeywordMonthly) error {
+	if len(directCampaignIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("direct_campaign_id IN ? AND year = ? AND month = ?", directCampaignIDs, year, month).
+			Delete(&models.DirectKeywordMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(metrics, 100).Error
+	})
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepository handles database operations for exchange rates
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// SaveRates inserts or updates rates (unique by currency pair and date)
+func (r *ExchangeRateRepository) SaveRates(ctx context.Context, rates []*models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).CreateInBatches(rates, 100).Error
+}
+
+// GetRate retrieves the latest rate of a currency pair on or before the date
+// Returns nil if the pair has no rate yet
+func (r *ExchangeRateRepository) GetRate(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("base = ? AND quote = ? AND date <= ?", base, quote, date.Format("2006-01-02")).
+		Order("date DESC").
+		First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetRatesByDate retrieves all rates of a date
+func (r *ExchangeRateRepository) GetRatesByDate(ctx context.Context, date time.Time) ([]*models.ExchangeRate, error) {
+	var rates []*models.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("date = ?", date.Format("2006-01-02")).
+		Find(&rates).Error
+	return rates, err
+}
+
+// List retrieves paginated rates matching the filter, newest first
+func (r *ExchangeRateRepository) List(ctx context.Context, filter models.ExchangeRateFilter, pagination *middleware.Pagination) ([]*models.ExchangeRate, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ExchangeRate{})
+	if filter.Base != "" {
+		query = query.Where("base = ?", filter.Base)
+	}
+	if filter.Quote != "" {
+		query = query.Where("quote = ?", filter.Quote)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("date >= ?", filter.DateFrom.Format("2006-01-02"))
+	}
+	if filter.DateTo != nil {
+		query = query.Where("date <= ?", filter.DateTo.Format("2006-01-02"))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rates []*models.ExchangeRate
+	err := query.
+		Order("date DESC, base ASC, quote ASC").
+		Limit(pagination.PerPage).
+		Offset(pagination.Offset).
+		Find(&rates).Error
+
+	return rates, total, err
+}
@@ -2,11 +2,13 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/cache"
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GoalRepository handles database operations for goals
@@ -38,6 +40,26 @@ func (r *GoalRepository) Create(ctx context.Context, goal *models.Goal) error {
 	return nil
 }
 
+// Upsert creates a goal or updates name, type and deleted flag of the existing goal of the counter
+// IsConversion chosen by managers is kept, concurrent syncs of a counter do not create duplicates
+func (r *GoalRepository) Upsert(ctx context.Context, goal *models.Goal) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "counter_id"}, {Name: "goal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "type", "is_deleted", "updated_at"}),
+	}).Create(goal).Error
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache for this counter
+	if r.cache != nil {
+		cacheKey := cache.BuildKey(cache.KeyPrefixGoals, goal.CounterID)
+		_ = r.cache.Delete(cacheKey)
+	}
+
+	return nil
+}
+
 // GetByID retrieves a goal by ID
 func (r *GoalRepository) GetByID(ctx context.Context, id uint) (*models.Goal, error) {
 	var goal models.Goal
@@ -72,10 +94,14 @@ func (r *GoalRepository) GetByCounterID(ctx context.Context, counterID uint) ([]
 }
 
 // GetByGoalID retrieves a goal by Yandex goal ID and counter ID
+// Returns nil if the goal does not exist
 func (r *GoalRepository) GetByGoalID(ctx context.Context, counterID uint, goalID int64) (*models.Goal, error) {
 	var goal models.Goal
 	err := r.db.WithContext(ctx).Where("counter_id = ? AND goal_id = ?", counterID, goalID).First(&goal).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &goal, nil
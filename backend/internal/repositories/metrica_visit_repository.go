@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MetricaVisitRepository handles database operations for raw Metrica visits
+type MetricaVisitRepository struct {
+	db *gorm.DB
+}
+
+// NewMetricaVisitRepository creates a new Metrica visit repository
+func NewMetricaVisitRepository(db *gorm.DB) *MetricaVisitRepository {
+	return &MetricaVisitRepository{db: db}
+}
+
+// ReplaceVisits replaces visits of a counter within date range (inclusive) with visits passed by load to save
+// Old visits are deleted before the first saved batch and the change is committed only if load succeeds,
+// so a failed load keeps previously loaded visits
+func (r *MetricaVisitRepository) ReplaceVisits(ctx context.Context, counterID int64, dateFrom, dateTo time.Time, load func(save func(visits []*models.MetricaVisit) error) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted := false
+		deleteOld := func() error {
+			if deleted {
+				return nil
+			}
+			deleted = true
+			return tx.Where("counter_id = ? AND date BETWEEN ? AND ?", counterID, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")).
+				Delete(&models.MetricaVisit{}).Error
+		}
+
+		err := load(func(visits []*models.MetricaVisit) error {
+			if err := deleteOld(); err != nil {
+				return err
+			}
+			if len(visits) == 0 {
+				return nil
+			}
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "counter_id"}, {Name: "visit_id"}},
+				UpdateAll: true,
+			}).CreateInBatches(visits, 500).Error
+		})
+		if err != nil {
+			return err
+		}
+		// Period without visits still replaces old ones
+		return deleteOld()
+	})
+}
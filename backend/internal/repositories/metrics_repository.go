@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MetricsRepository handles database operations for metrics
@@ -18,11 +21,15 @@ func NewMetricsRepository(db *gorm.DB) *MetricsRepository {
 }
 
 // GetMonthlyMetrics retrieves monthly metrics for a project
+// Returns nil if there is no data for the month
 func (r *MetricsRepository) GetMonthlyMetrics(ctx context.Context, projectID uint, year int, month int) (*models.MetricsMonthly, error) {
 	var metrics models.MetricsMonthly
 	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
 		First(&metrics).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &metrics, nil
@@ -46,12 +53,31 @@ func (r *MetricsRepository) SaveAgeMetrics(metrics *models.MetricsAgeMonthly) er
 	return r.db.Save(metrics).Error
 }
 
+// ReplaceAgeMetrics replaces age breakdown of a project for a month
+// Age groups missing in the new data are removed
+func (r *MetricsRepository) ReplaceAgeMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsAgeMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+			Delete(&models.MetricsAgeMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.Create(&metrics).Error
+	})
+}
+
 // GetAgeMetricsByGroup retrieves age metrics by project, year, month and age group
+// Returns nil if there is no data for the group
 func (r *MetricsRepository) GetAgeMetricsByGroup(ctx context.Context, projectID uint, year int, month int, ageGroup string) (*models.MetricsAgeMonthly, error) {
 	var metrics models.MetricsAgeMonthly
 	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ? AND age_group = ?",
 		projectID, year, month, ageGroup).First(&metrics).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &metrics, nil
@@ -69,3 +95,122 @@ func (r *MetricsRepository) GetAllMonthlyMetricsForProject(ctx context.Context,
 	}
 	return metrics, nil
 }
+
+// SaveDailyMetrics inserts or updates daily metrics (unique by project and date)
+func (r *MetricsRepository) SaveDailyMetrics(ctx context.Context, metrics []*models.MetricsDaily) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"visits", "users", "bounce_rate", "avg_session_duration_sec", "conversions", "updated_at"}),
+	}).Create(&metrics).Error
+}
+
+// GetDailyMetrics retrieves daily metrics for a project within date range (inclusive), ordered by date
+func (r *MetricsRepository) GetDailyMetrics(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error) {
+	var metrics []*models.MetricsDaily
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND date BETWEEN ? AND ?", projectID, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&metrics).Error
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GetTrafficSourceMetrics retrieves traffic source breakdown for a project
+func (r *MetricsRepository) GetTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsTrafficSourceMonthly, error) {
+	var metrics []*models.MetricsTrafficSourceMonthly
+	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("visits DESC").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// ReplaceTrafficSourceMetrics replaces traffic source breakdown of a project for a month
+// Sources missing in the new data are removed
+func (r *MetricsRepository) ReplaceTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsTrafficSourceMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+			Delete(&models.MetricsTrafficSourceMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.Create(&metrics).Error
+	})
+}
+
+// GetCampaignRevenue retrieves Metrica revenue of Direct campaigns of a project for a month
+func (r *MetricsRepository) GetCampaignRevenue(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsCampaignRevenueMonthly, error) {
+	var metrics []*models.MetricsCampaignRevenueMonthly
+	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("campaign_id").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// ReplaceCampaignRevenue replaces Metrica revenue of Direct campaigns of a project for a month
+func (r *MetricsRepository) ReplaceCampaignRevenue(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsCampaignRevenueMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+			Delete(&models.MetricsCampaignRevenueMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.Create(&metrics).Error
+	})
+}
+
+// GetBreakdownMetrics retrieves audience breakdowns (gender, device, regions) for a project, ordered by visits
+func (r *MetricsRepository) GetBreakdownMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsBreakdownMonthly, error) {
+	var metrics []*models.MetricsBreakdownMonthly
+	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("dimension ASC, visits DESC").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// ReplaceBreakdownMetrics replaces one breakdown dimension of a project for a month
+// Other dimensions are kept, so a failed request of one breakdown does not erase the rest
+func (r *MetricsRepository) ReplaceBreakdownMetrics(ctx context.Context, projectID uint, year int, month int, dimension string, metrics []*models.MetricsBreakdownMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ? AND dimension = ?", projectID, year, month, dimension).
+			Delete(&models.MetricsBreakdownMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.Create(&metrics).Error
+	})
+}
+
+// GetGoalMetrics retrieves per-goal metrics for a project, ordered by reaches
+func (r *MetricsRepository) GetGoalMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsGoalMonthly, error) {
+	var metrics []*models.MetricsGoalMonthly
+	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("reaches DESC, goal_id ASC").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// ReplaceGoalMetrics replaces per-goal metrics of a project for a month
+// Goals missing in the new data are removed
+func (r *MetricsRepository) ReplaceGoalMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsGoalMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+			Delete(&models.MetricsGoalMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.Create(&metrics).Error
+	})
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// OAuthCredentialRepository handles database operations for OAuth credentials
+// Tokens are stored encrypted; encryption is handled by the service layer
+type OAuthCredentialRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthCredentialRepository creates a new OAuth credential repository
+func NewOAuthCredentialRepository(db *gorm.DB) *OAuthCredentialRepository {
+	return &OAuthCredentialRepository{db: db}
+}
+
+// Create creates a new credential
+func (r *OAuthCredentialRepository) Create(ctx context.Context, credential *models.OAuthCredential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+// Update updates a credential
+func (r *OAuthCredentialRepository) Update(ctx context.Context, credential *models.OAuthCredential) error {
+	return r.db.WithContext(ctx).Save(credential).Error
+}
+
+// UpdateHealth writes status, last error and check time of a credential
+// updated_at is left as is, so health checks do not look like token changes
+func (r *OAuthCredentialRepository) UpdateHealth(ctx context.Context, credential *models.OAuthCredential) error {
+	return r.db.WithContext(ctx).Model(&models.OAuthCredential{ID: credential.ID}).UpdateColumns(map[string]interface{}{
+		"status":          credential.Status,
+		"last_error":      credential.LastError,
+		"last_checked_at": credential.LastCheckedAt,
+	}).Error
+}
+
+// GetByID retrieves a credential by ID. Returns nil if not found
+func (r *OAuthCredentialRepository) GetByID(ctx context.Context, id uint) (*models.OAuthCredential, error) {
+	var credential models.OAuthCredential
+	err := r.db.WithContext(ctx).First(&credential, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetByProjectID retrieves all credentials of a project
+func (r *OAuthCredentialRepository) GetByProjectID(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error) {
+	var credentials []*models.OAuthCredential
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
+		Order("updated_at DESC").
+		Find(&credentials).Error
+	return credentials, err
+}
+
+// GetByProjectAndLogin retrieves a credential by project and token owner login
+// projectID nil means agency-wide credential. Returns nil if not found
+func (r *OAuthCredentialRepository) GetByProjectAndLogin(ctx context.Context, projectID *uint, provider, login string) (*models.OAuthCredential, error) {
+	query := r.db.WithContext(ctx).Where("provider = ? AND login = ?", provider, login)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+
+	var credential models.OAuthCredential
+	if err := query.First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetDefaultForProject retrieves the first connected usable credential of a project
+// Ordered by ID, so token refreshes and health checks do not switch the default between logins
+// projectID nil returns the first agency-wide credential. Returns nil if not found
+func (r *OAuthCredentialRepository) GetDefaultForProject(ctx context.Context, projectID *uint, provider string) (*models.OAuthCredential, error) {
+	query := r.db.WithContext(ctx).Where("provider = ? AND status <> ?", provider, models.OAuthStatusRevoked)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+
+	var credential models.OAuthCredential
+	if err := query.Order("id").First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetAll retrieves all credentials
+func (r *OAuthCredentialRepository) GetAll(ctx context.Context) ([]*models.OAuthCredential, error) {
+	var credentials []*models.OAuthCredential
+	err := r.db.WithContext(ctx).Order("project_id, id").Find(&credentials).Error
+	return credentials, err
+}
+
+// GetRefreshable retrieves credentials with refresh token that expire before the given time
+// Revoked credentials are skipped since their refresh token was already rejected
+func (r *OAuthCredentialRepository) GetRefreshable(ctx context.Context, expiresBefore time.Time) ([]*models.OAuthCredential, error) {
+	var credentials []*models.OAuthCredential
+	err := r.db.WithContext(ctx).
+		Where("refresh_token <> '' AND expires_at IS NOT NULL AND expires_at < ? AND status <> ?", expiresBefore, models.OAuthStatusRevoked).
+		Find(&credentials).Error
+	return credentials, err
+}
+
+// Delete deletes a credential and unbinds Direct accounts, counters and Webmaster hosts from it
+// in one transaction, so they fall back to the project or agency credential
+func (r *OAuthCredentialRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.DirectAccount{}, &models.YandexCounter{}, &models.WebmasterHost{}} {
+			if err := tx.Model(model).Where("oauth_credential_id = ?", id).
+				UpdateColumn("oauth_credential_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.OAuthCredential{}, id).Error
+	})
+}
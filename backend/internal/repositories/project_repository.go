@@ -118,7 +118,7 @@ func (r *ProjectRepository) GetByUserIDPaginated(ctx context.Context, userID uin
 func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
 	return r.db.WithContext(ctx).
 		Model(project).
-		Select("name", "slug", "public_token", "timezone", "currency", "is_active", "updated_at").
+		Select("name", "slug", "public_token", "timezone", "currency", "reporting_currency", "is_active", "attribution_model", "counter_aggregation", "cost_include_vat", "cost_include_discount", "cost_markup_pct", "updated_at").
 		Updates(project).Error
 }
 
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"gorm.io/gorm"
@@ -21,6 +22,66 @@ func NewSEORepository(db *gorm.DB) *SEORepository {
 func (r *SEORepository) GetSEOQueries(ctx context.Context, projectID uint, year int, month int) ([]*models.SEOQueriesMonthly, error) {
 	var queries []*models.SEOQueriesMonthly
 	err := r.db.WithContext(ctx).Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+		Order("shows DESC, position ASC").
 		Find(&queries).Error
 	return queries, err
 }
+
+// ReplaceSEOQueries replaces SEO queries of a project for a month in a single transaction
+func (r *SEORepository) ReplaceSEOQueries(ctx context.Context, projectID uint, year int, month int, queries []*models.SEOQueriesMonthly) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND year = ? AND month = ?", projectID, year, month).
+			Delete(&models.SEOQueriesMonthly{}).Error; err != nil {
+			return err
+		}
+		if len(queries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(queries, 100).Error
+	})
+}
+
+// CreateHost binds a Webmaster host to a project
+func (r *SEORepository) CreateHost(ctx context.Context, host *models.WebmasterHost) error {
+	return r.db.WithContext(ctx).Create(host).Error
+}
+
+// GetHostsByProjectID retrieves Webmaster hosts bound to a project
+func (r *SEORepository) GetHostsByProjectID(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error) {
+	var hosts []*models.WebmasterHost
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id ASC").Find(&hosts).Error
+	return hosts, err
+}
+
+// GetHostByHostID retrieves a project's Webmaster host by Webmaster host id
+// Returns nil if the host is not bound to the project
+func (r *SEORepository) GetHostByHostID(ctx context.Context, projectID uint, hostID string) (*models.WebmasterHost, error) {
+	var host models.WebmasterHost
+	err := r.db.WithContext(ctx).Where("project_id = ? AND host_id = ?", projectID, hostID).First(&host).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &host, nil
+}
+
+// GetHostByID retrieves a Webmaster host binding by ID
+// Returns nil if the binding does not exist
+func (r *SEORepository) GetHostByID(ctx context.Context, id uint) (*models.WebmasterHost, error) {
+	var host models.WebmasterHost
+	err := r.db.WithContext(ctx).First(&host, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &host, nil
+}
+
+// DeleteHost unbinds a Webmaster host from a project
+func (r *SEORepository) DeleteHost(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WebmasterHost{}, id).Error
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncRunRepository handles database operations for sync run history
+type SyncRunRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRunRepository creates a new sync run repository
+func NewSyncRunRepository(db *gorm.DB) *SyncRunRepository {
+	return &SyncRunRepository{db: db}
+}
+
+// Create creates a new sync run
+func (r *SyncRunRepository) Create(ctx context.Context, run *models.SyncRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// Update saves result of a sync run
+func (r *SyncRunRepository) Update(ctx context.Context, run *models.SyncRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// List retrieves paginated sync runs matching the filter, newest first
+func (r *SyncRunRepository) List(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.SyncRun{})
+	if filter.ProjectID != 0 {
+		query = query.Where("project_id = ?", filter.ProjectID)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Since != nil {
+		query = query.Where("started_at >= ?", *filter.Since)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []*models.SyncRun
+	err := query.
+		Order("started_at DESC, id DESC").
+		Limit(pagination.PerPage).
+		Offset(pagination.Offset).
+		Find(&runs).Error
+
+	return runs, total, err
+}
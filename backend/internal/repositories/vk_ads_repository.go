@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// VKAdsRepository handles database operations for VK Ads cabinets
+type VKAdsRepository struct {
+	db *gorm.DB
+}
+
+// NewVKAdsRepository creates a new VK Ads repository
+func NewVKAdsRepository(db *gorm.DB) *VKAdsRepository {
+	return &VKAdsRepository{db: db}
+}
+
+// CreateAccount creates a new VK Ads cabinet of a project
+func (r *VKAdsRepository) CreateAccount(ctx context.Context, account *models.VKAdsAccount) error {
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+// GetAccountByProjectID retrieves VK Ads cabinet of a project
+func (r *VKAdsRepository) GetAccountByProjectID(ctx context.Context, projectID uint) (*models.VKAdsAccount, error) {
+	var account models.VKAdsAccount
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// DeleteAccount deletes a VK Ads cabinet
+func (r *VKAdsRepository) DeleteAccount(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.VKAdsAccount{}, id).Error
+}
@@ -36,6 +36,14 @@ func SetupRoutes(
 	marketingService handlers.MarketingServiceInterface,
 	authService handlers.AuthServiceInterface,
 	userService handlers.UserServiceInterface,
+	credentialService handlers.OAuthCredentialServiceInterface,
+	webmasterService handlers.WebmasterServiceInterface,
+	vkAdsService handlers.VKAdsServiceInterface,
+	manualCostService handlers.ManualCostServiceInterface,
+	crmService handlers.CRMServiceInterface,
+	backfillService handlers.BackfillServiceInterface,
+	syncRunService handlers.SyncRunServiceInterface,
+	rateService handlers.ExchangeRateServiceInterface,
 	userRepo services.UserRepositoryInterface,
 	cacheClient *cache.Cache,
 ) *Router {
@@ -78,14 +86,21 @@ func SetupRoutes(
 
 	// Initialize handlers with services
 	projectHandler := handlers.NewProjectHandler(projectService, userRepo)
-	countersHandler := handlers.NewCountersHandler(counterService)
+	countersHandler := handlers.NewCountersHandler(counterService, queueClient)
 	directHandler := handlers.NewDirectHandler(directService)
+	webmasterHandler := handlers.NewWebmasterHandler(webmasterService)
+	vkAdsHandler := handlers.NewVKAdsHandler(vkAdsService)
+	manualCostHandler := handlers.NewManualCostHandler(manualCostService)
+	crmHandler := handlers.NewCRMHandler(crmService)
 	goalsHandler := handlers.NewGoalsHandler(goalService)
 	metricsHandler := handlers.NewMetricsHandler(metricsService)
 	reportHandler := handlers.NewReportHandler(reportService, queueClient, cacheClient)
 	reportHandler.SetProjectService(projectService) // Set project service for public reports
 	syncHandler := handlers.NewSyncHandler(queueClient)
-	oauthHandler := handlers.NewOAuthHandler(cfg)
+	backfillHandler := handlers.NewBackfillHandler(backfillService, queueClient)
+	syncRunHandler := handlers.NewSyncRunHandler(syncRunService)
+	rateHandler := handlers.NewExchangeRateHandler(rateService, queueClient)
+	oauthHandler := handlers.NewOAuthHandler(cfg, credentialService, userRepo)
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService)
 	projectUserHandler := handlers.NewProjectUserHandler(userService)
@@ -108,13 +123,15 @@ func SetupRoutes(
 	api.POST("/auth/register", authHandler.Register, router.authLimiter.Middleware())
 	api.POST("/auth/login", authHandler.Login, router.authLimiter.Middleware())
 
-	// OAuth routes (public, but may require auth later)
-	api.GET("/oauth/yandex", oauthHandler.InitiateAuth)
+	// OAuth callback (public - Yandex redirects to it, authorized by the encrypted state)
 	api.GET("/oauth/yandex/callback", oauthHandler.HandleCallback)
 
 	// Public report route (no authentication required)
 	api.GET("/public/report/:token", reportHandler.GetPublicReport)
 
+	// CRM webhook (no authentication required, authorized by the webhook token)
+	api.POST("/webhooks/crm/:token", crmHandler.Webhook)
+
 	// Protected routes (require authentication)
 	protected := api.Group("")
 	protected.Use(AuthMiddleware(authService))
@@ -129,10 +146,21 @@ func SetupRoutes(
 	adminOnly.POST("/projects", projectHandler.CreateProject)
 	adminOnly.DELETE("/projects/:id", projectHandler.DeleteProject)
 	adminOnly.POST("/sync/:id", syncHandler.SyncProject)
+	adminOnly.POST("/sync/:id/metrica-logs", syncHandler.SyncMetricaLogs)
+	adminOnly.POST("/projects/:id/backfill", backfillHandler.StartBackfill)
+	adminOnly.GET("/projects/:id/backfill", backfillHandler.GetBackfills)
+	adminOnly.GET("/projects/:id/backfill/:jobId", backfillHandler.GetBackfill)
+	adminOnly.GET("/sync-runs", syncRunHandler.GetSyncRuns)
+	adminOnly.POST("/exchange-rates", rateHandler.SetRate)
+	adminOnly.POST("/exchange-rates/fetch", rateHandler.FetchRates)
+	adminOnly.GET("/oauth/yandex", oauthHandler.InitiateAuth)
 
 	// Get all projects (users see only their projects - handled in service)
 	protected.GET("/projects", projectHandler.GetAllProjects)
 
+	// Exchange rates are shared by all projects
+	protected.GET("/exchange-rates", rateHandler.GetRates)
+
 	// Project-specific routes (require project access)
 	projectRoutes := protected.Group("")
 	projectRoutes.Use(RequireProjectRole(userRepo, "admin", "manager", "client"))
@@ -141,7 +169,12 @@ func SetupRoutes(
 	projectRoutes.GET("/projects/:id/counters", countersHandler.GetCounters)
 	projectRoutes.GET("/projects/:id/direct-accounts", directHandler.GetDirectAccounts)
 	projectRoutes.GET("/projects/:id/campaigns", directHandler.GetCampaigns)
+	projectRoutes.GET("/projects/:id/campaigns/:campaignId/ad-groups", directHandler.GetCampaignAdGroups)
+	projectRoutes.GET("/projects/:id/campaigns/:campaignId/keywords", directHandler.GetCampaignKeywords)
+	projectRoutes.GET("/projects/:id/webmaster-hosts", webmasterHandler.GetHosts)
+	projectRoutes.GET("/projects/:id/vk-ads-account", vkAdsHandler.GetAccount)
 	projectRoutes.GET("/projects/:id/metrics", metricsHandler.GetMetrics)
+	projectRoutes.GET("/projects/:id/metrics/daily", metricsHandler.GetDailyMetrics)
 	projectRoutes.GET("/projects/:id/marketing", handlers.NewMarketingHandler(marketingService).GetMarketing)
 	projectRoutes.GET("/projects/:id/goals", goalsHandler.GetGoals)
 	projectRoutes.GET("/report/:id", reportHandler.GetReport)
@@ -155,6 +188,21 @@ func SetupRoutes(
 	managerRoutes.POST("/projects/:id/counters", countersHandler.AddCounter)
 	managerRoutes.POST("/projects/:id/direct-accounts", directHandler.AddDirectAccount)
 	managerRoutes.POST("/projects/:id/goals", goalsHandler.AddGoal)
+	managerRoutes.PATCH("/projects/:id/goals/:goalId", goalsHandler.UpdateGoal)
+	managerRoutes.GET("/projects/:id/webmaster-hosts/available", webmasterHandler.GetAvailableHosts)
+	managerRoutes.POST("/projects/:id/webmaster-hosts", webmasterHandler.AddHost)
+	managerRoutes.DELETE("/projects/:id/webmaster-hosts/:hostId", webmasterHandler.DeleteHost)
+	managerRoutes.POST("/projects/:id/vk-ads-account", vkAdsHandler.AddAccount)
+	managerRoutes.DELETE("/projects/:id/vk-ads-account", vkAdsHandler.DeleteAccount)
+	managerRoutes.POST("/projects/:id/manual-costs", manualCostHandler.Upload)
+	managerRoutes.GET("/projects/:id/manual-costs/uploads", manualCostHandler.GetUploads)
+	managerRoutes.POST("/projects/:id/crm/deals", crmHandler.ImportDeals)
+	managerRoutes.POST("/projects/:id/crm/deals/upload", crmHandler.UploadDeals)
+	managerRoutes.POST("/projects/:id/crm/webhook-token", crmHandler.CreateWebhookToken)
+	managerRoutes.GET("/projects/:id/oauth/yandex", oauthHandler.InitiateAuth)
+	managerRoutes.GET("/projects/:id/oauth-credentials", oauthHandler.GetProjectCredentials)
+	managerRoutes.DELETE("/projects/:id/oauth-credentials/:credentialId", oauthHandler.DeleteProjectCredential)
+	managerRoutes.GET("/projects/:id/sync-runs", syncRunHandler.GetProjectSyncRuns)
 
 	// Admin panel routes (require admin role)
 	// User management
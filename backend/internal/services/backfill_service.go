@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// Backfill sources
+const (
+	BackfillSourceMetrica = "metrica"
+	BackfillSourceDirect  = "direct"
+)
+
+// maxBackfillMonths limits the range of one backfill job
+// Metrica keeps data for several years, Direct reports are available for about 3 years
+const maxBackfillMonths = 36
+
+// BackfillMonth is a month to be synced by a backfill job
+type BackfillMonth struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+}
+
+// BackfillService handles creation and progress of historical sync jobs
+type BackfillService struct {
+	backfillRepo BackfillRepositoryInterface
+	projectRepo  ProjectRepositoryInterface
+}
+
+// NewBackfillService creates a new backfill service
+func NewBackfillService(backfillRepo BackfillRepositoryInterface, projectRepo ProjectRepositoryInterface) *BackfillService {
+	return &BackfillService{
+		backfillRepo: backfillRepo,
+		projectRepo:  projectRepo,
+	}
+}
+
+// CreateJob validates month range and sources and stores a new job
+// from and to are months in YYYY-MM format; empty sources mean all sources
+// Returns the job and months to enqueue tasks for
+func (s *BackfillService) CreateJob(ctx context.Context, projectID uint, from, to string, sources []string) (*models.BackfillJob, []BackfillMonth, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return nil, nil, errors.New("project not found")
+	}
+
+	months, err := BackfillMonths(from, to, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err = normalizeBackfillSources(sources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job := &models.BackfillJob{
+		ProjectID:  projectID,
+		FromMonth:  from,
+		ToMonth:    to,
+		Sources:    strings.Join(sources, ","),
+		TotalTasks: len(months) * len(sources),
+		Status:     models.BackfillStatusRunning,
+	}
+	if err := s.backfillRepo.Create(ctx, job); err != nil {
+		return nil, nil, fmt.Errorf("failed to create backfill job: %w", err)
+	}
+
+	return job, months, nil
+}
+
+// GetJob retrieves a job of a project
+func (s *BackfillService) GetJob(ctx context.Context, projectID uint, id uint) (*models.BackfillJob, error) {
+	job, err := s.backfillRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.ProjectID != projectID {
+		return nil, errors.New("backfill job not found")
+	}
+	return job, nil
+}
+
+// GetJobsByProject retrieves jobs of a project, newest first
+func (s *BackfillService) GetJobsByProject(ctx context.Context, projectID uint) ([]*models.BackfillJob, error) {
+	return s.backfillRepo.GetByProjectID(ctx, projectID)
+}
+
+// RecordTaskResult counts a finished task of a job
+// failed should be set only when a task will not be retried anymore
+func (s *BackfillService) RecordTaskResult(ctx context.Context, jobID uint, failed bool) error {
+	if failed {
+		return s.backfillRepo.AddProgress(ctx, jobID, 0, 1)
+	}
+	return s.backfillRepo.AddProgress(ctx, jobID, 1, 0)
+}
+
+// RecordNotEnqueued counts tasks that could not be enqueued as failed
+func (s *BackfillService) RecordNotEnqueued(ctx context.Context, jobID uint, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	return s.backfillRepo.AddProgress(ctx, jobID, 0, count)
+}
+
+// BackfillSources returns sources of a job
+func BackfillSources(job *models.BackfillJob) []string {
+	if job.Sources == "" {
+		return nil
+	}
+	return strings.Split(job.Sources, ",")
+}
+
+// BackfillMonths returns months from from to to inclusive
+// Months after the current one (relative to now) are rejected, the range is limited to 36 months
+func BackfillMonths(from, to string, now time.Time) ([]BackfillMonth, error) {
+	fromDate, err := time.Parse("2006-01", from)
+	if err != nil {
+		return nil, errors.New("from must be a month in YYYY-MM format")
+	}
+	toDate, err := time.Parse("2006-01", to)
+	if err != nil {
+		return nil, errors.New("to must be a month in YYYY-MM format")
+	}
+	if fromDate.After(toDate) {
+		return nil, errors.New("from must not be after to")
+	}
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if toDate.After(currentMonth) {
+		return nil, errors.New("to must not be in the future")
+	}
+
+	var months []BackfillMonth
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 1, 0) {
+		months = append(months, BackfillMonth{Year: date.Year(), Month: int(date.Month())})
+		if len(months) > maxBackfillMonths {
+			return nil, fmt.Errorf("backfill range is limited to %d months", maxBackfillMonths)
+		}
+	}
+	return months, nil
+}
+
+// normalizeBackfillSources validates sources, removes duplicates and applies default
+func normalizeBackfillSources(sources []string) ([]string, error) {
+	if len(sources) == 0 {
+		return []string{BackfillSourceMetrica, BackfillSourceDirect}, nil
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(sources))
+	for _, source := range sources {
+		source = strings.ToLower(strings.TrimSpace(source))
+		if source != BackfillSourceMetrica && source != BackfillSourceDirect {
+			return nil, fmt.Errorf("invalid source: %s", source)
+		}
+		if !seen[source] {
+			seen[source] = true
+			result = append(result, source)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// MockBackfillRepository implements BackfillRepositoryInterface for testing
+type MockBackfillRepository struct {
+	CreateFunc         func(ctx context.Context, job *models.BackfillJob) error
+	GetByIDFunc        func(ctx context.Context, id uint) (*models.BackfillJob, error)
+	GetByProjectIDFunc func(ctx context.Context, projectID uint) ([]*models.BackfillJob, error)
+	AddProgressFunc    func(ctx context.Context, id uint, completed, failed int) error
+}
+
+func (m *MockBackfillRepository) Create(ctx context.Context, job *models.BackfillJob) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, job)
+	}
+	return nil
+}
+
+func (m *MockBackfillRepository) GetByID(ctx context.Context, id uint) (*models.BackfillJob, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockBackfillRepository) GetByProjectID(ctx context.Context, projectID uint) ([]*models.BackfillJob, error) {
+	if m.GetByProjectIDFunc != nil {
+		return m.GetByProjectIDFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockBackfillRepository) AddProgress(ctx context.Context, id uint, completed, failed int) error {
+	if m.AddProgressFunc != nil {
+		return m.AddProgressFunc(ctx, id, completed, failed)
+	}
+	return nil
+}
+
+func TestBackfillMonths(t *testing.T) {
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		from        string
+		to          string
+		wantMonths  int
+		wantFirst   BackfillMonth
+		wantLast    BackfillMonth
+		wantErrText string
+	}{
+		{
+			name:       "переход через год",
+			from:       "2024-11",
+			to:         "2025-02",
+			wantMonths: 4,
+			wantFirst:  BackfillMonth{Year: 2024, Month: 11},
+			wantLast:   BackfillMonth{Year: 2025, Month: 2},
+		},
+		{
+			name:       "текущий месяц",
+			from:       "2025-03",
+			to:         "2025-03",
+			wantMonths: 1,
+			wantFirst:  BackfillMonth{Year: 2025, Month: 3},
+			wantLast:   BackfillMonth{Year: 2025, Month: 3},
+		},
+		{
+			name:        "неверный формат",
+			from:        "2024-1-01",
+			to:          "2025-02",
+			wantErrText: "from must be a month in YYYY-MM format",
+		},
+		{
+			name:        "начало позже конца",
+			from:        "2025-02",
+			to:          "2024-11",
+			wantErrText: "from must not be after to",
+		},
+		{
+			name:        "будущий месяц",
+			from:        "2025-01",
+			to:          "2025-04",
+			wantErrText: "to must not be in the future",
+		},
+		{
+			name:        "слишком большой диапазон",
+			from:        "2021-01",
+			to:          "2025-01",
+			wantErrText: "backfill range is limited to 36 months",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			months, err := BackfillMonths(tt.from, tt.to, now)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if len(months) != tt.wantMonths {
+				t.Fatalf("ожидалось %d месяцев, получили %d", tt.wantMonths, len(months))
+			}
+			if months[0] != tt.wantFirst || months[len(months)-1] != tt.wantLast {
+				t.Errorf("неверные границы: %v - %v", months[0], months[len(months)-1])
+			}
+		})
+	}
+}
+
+func TestBackfillService_CreateJob(t *testing.T) {
+	projectRepo := &MockProjectRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+			if id == 1 {
+				return &models.Project{ID: 1}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		projectID   uint
+		sources     []string
+		wantSources string
+		wantTasks   int
+		wantErrText string
+	}{
+		{
+			name:        "все источники по умолчанию",
+			projectID:   1,
+			wantSources: "metrica,direct",
+			wantTasks:   6,
+		},
+		{
+			name:        "один источник без дублей",
+			projectID:   1,
+			sources:     []string{"Direct", "direct"},
+			wantSources: "direct",
+			wantTasks:   3,
+		},
+		{
+			name:        "неизвестный источник",
+			projectID:   1,
+			sources:     []string{"metrica", "vk"},
+			wantErrText: "invalid source: vk",
+		},
+		{
+			name:        "проект не найден",
+			projectID:   2,
+			wantErrText: "project not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *models.BackfillJob
+			service := NewBackfillService(&MockBackfillRepository{
+				CreateFunc: func(ctx context.Context, job *models.BackfillJob) error {
+					job.ID = 7
+					created = job
+					return nil
+				},
+			}, projectRepo)
+
+			job, months, err := service.CreateJob(context.Background(), tt.projectID, "2024-01", "2024-03", tt.sources)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				if created != nil {
+					t.Errorf("задача не должна создаваться при ошибке")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if len(months) != 3 {
+				t.Errorf("ожидалось 3 месяца, получили %d", len(months))
+			}
+			if job.Sources != tt.wantSources || job.TotalTasks != tt.wantTasks || job.Status != models.BackfillStatusRunning {
+				t.Errorf("неверная задача: %+v", job)
+			}
+		})
+	}
+}
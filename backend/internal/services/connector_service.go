@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// ConnectorService syncs data sources registered as connectors
+// Every source is stored as monthly campaign metrics and recorded in sync run history under its name
+type ConnectorService struct {
+	registry    *connectors.Registry
+	channelRepo ChannelRepositoryInterface
+	projectRepo ProjectRepositoryInterface
+	syncRunRepo SyncRunRepositoryInterface
+	rates       *ExchangeRateService // Converts costs of accounts in another currency
+}
+
+// NewConnectorService creates a new connector service
+func NewConnectorService(
+	registry *connectors.Registry,
+	channelRepo ChannelRepositoryInterface,
+	projectRepo ProjectRepositoryInterface,
+	syncRunRepo SyncRunRepositoryInterface,
+	rates *ExchangeRateService,
+) *ConnectorService {
+	return &ConnectorService{
+		registry:    registry,
+		channelRepo: channelRepo,
+		projectRepo: projectRepo,
+		syncRunRepo: syncRunRepo,
+		rates:       rates,
+	}
+}
+
+// Sources returns sources of registered connectors
+func (s *ConnectorService) Sources() []string {
+	return s.registry.Sources()
+}
+
+// SyncSource loads a month of a source for a project and replaces its stored campaign metrics
+// Projects that do not use the source are skipped without a sync run
+func (s *ConnectorService) SyncSource(ctx context.Context, source string, projectID uint, year, month int) error {
+	connector, ok := s.registry.Get(source)
+	if !ok {
+		return fmt.Errorf("invalid source: %s", source)
+	}
+	period := connectors.Period{Year: year, Month: month}
+	if err := connector.Periods().Check(period, time.Now()); err != nil {
+		return err
+	}
+
+	credentials, credentialsErr := connector.Credentials(ctx, projectID)
+	if errors.Is(credentialsErr, connectors.ErrNotConfigured) {
+		return nil
+	}
+
+	return trackSyncRun(ctx, s.syncRunRepo, projectID, source, year, month, func(ctx context.Context, projectID uint, year, month int) error {
+		if credentialsErr != nil {
+			return fmt.Errorf("failed to resolve %s credentials: %w", source, credentialsErr)
+		}
+		return s.syncSource(ctx, connector, credentials, projectID, period)
+	})
+}
+
+// syncSource fetches, normalizes and stores a month of a source
+func (s *ConnectorService) syncSource(ctx context.Context, connector connectors.Connector, credentials *connectors.Credentials, projectID uint, period connectors.Period) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return fmt.Errorf("project not found")
+	}
+
+	costRate, err := s.costRate(ctx, credentials.Currency, project.Currency, period)
+	if err != nil {
+		return err
+	}
+
+	raw, err := connector.Fetch(ctx, credentials, period)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s data: %w", connector.Source(), err)
+	}
+	rows, err := connector.Normalize(raw)
+	if err != nil {
+		return fmt.Errorf("failed to normalize %s data: %w", connector.Source(), err)
+	}
+
+	monthly := channelCampaignMonthly(ctx, connector.Source(), projectID, period, rows, costRate)
+	if err := s.channelRepo.ReplaceCampaignMonthly(ctx, projectID, connector.Source(), period.Year, period.Month, monthly); err != nil {
+		return fmt.Errorf("failed to save %s campaign metrics: %w", connector.Source(), err)
+	}
+	syncRows(ctx, len(monthly))
+
+	return nil
+}
+
+// costRate returns the rate converting costs of a month from the account currency to the project currency
+func (s *ConnectorService) costRate(ctx context.Context, currency, projectCurrency string, period connectors.Period) (float64, error) {
+	if projectCurrency == "" {
+		projectCurrency = models.CurrencyRUB
+	}
+	if currency == "" || currency == projectCurrency {
+		return 1, nil
+	}
+	if s.rates == nil {
+		return 0, fmt.Errorf("no exchange rate %s/%s: rates are not configured", currency, projectCurrency)
+	}
+	return s.rates.Rate(ctx, currency, projectCurrency, rateDate(period.Year, period.Month, time.Now()))
+}
+
+// channelCampaignMonthly aggregates normalized rows of a month by channel and campaign
+// Rows outside the period are skipped with a warning
+func channelCampaignMonthly(ctx context.Context, source string, projectID uint, period connectors.Period, rows []connectors.MetricRow, costRate float64) []*models.ChannelCampaignMonthly {
+	type campaignKey struct {
+		channel    string
+		campaignID string
+	}
+
+	start, end := period.Range()
+	byCampaign := make(map[campaignKey]*directMetrics)
+	names := make(map[campaignKey]string)
+	var order []campaignKey
+	skipped := 0
+	for _, row := range rows {
+		if row.Date.Before(start) || row.Date.After(end) {
+			skipped++
+			continue
+		}
+
+		key := campaignKey{channel: row.Channel, campaignID: row.CampaignID}
+		if key.channel == "" {
+			key.channel = source
+		}
+		metrics, ok := byCampaign[key]
+		if !ok {
+			metrics = &directMetrics{}
+			byCampaign[key] = metrics
+			order = append(order, key)
+		}
+		metrics.addMetricRow(row)
+		if row.CampaignName != "" {
+			names[key] = row.CampaignName
+		}
+	}
+	if skipped > 0 {
+		syncWarn(ctx, "Connector returned rows outside of the period",
+			zap.String("source", source),
+			zap.String("period", period.String()),
+			zap.Int("rows", skipped),
+		)
+	}
+
+	monthly := make([]*models.ChannelCampaignMonthly, 0, len(order))
+	for _, key := range order {
+		metrics := byCampaign[key]
+		metrics.cost *= costRate
+		ctr, cpc, cpa := metrics.rates()
+		monthly = append(monthly, &models.ChannelCampaignMonthly{
+			ProjectID:    projectID,
+			Source:       source,
+			Channel:      key.channel,
+			CampaignID:   key.campaignID,
+			CampaignName: names[key],
+			Year:         period.Year,
+			Month:        period.Month,
+			Impressions:  metrics.impressions,
+			Clicks:       metrics.clicks,
+			CTRPct:       ctr,
+			CPC:          cpc,
+			Conversions:  metrics.conversionsPtr(),
+			CPA:          cpa,
+			Cost:         metrics.cost,
+		})
+	}
+	return monthly
+}
+
+// addMetricRow adds values of a normalized connector row
+func (m *directMetrics) addMetricRow(row connectors.MetricRow) {
+	m.impressions += row.Impressions
+	m.clicks += row.Clicks
+	m.cost += row.Cost
+	if row.Conversions != nil {
+		m.conversions += *row.Conversions
+		m.hasConversions = true
+	}
+}
+
+// addChannelMonthly adds stored monthly row values of a connector campaign
+func (m *directMetrics) addChannelMonthly(row *models.ChannelCampaignMonthly) {
+	m.impressions += row.Impressions
+	m.clicks += row.Clicks
+	m.cost += row.Cost
+	if row.Conversions != nil {
+		m.conversions += *row.Conversions
+		m.hasConversions = true
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// MockConnector implements connectors.Connector with fixed rows
+type MockConnector struct {
+	SourceName     string
+	Support        connectors.PeriodSupport
+	Creds          *connectors.Credentials
+	CredentialsErr error
+	Rows           []connectors.MetricRow
+	FetchErr       error
+	Fetched        []connectors.Period
+}
+
+func (m *MockConnector) Source() string {
+	return m.SourceName
+}
+
+func (m *MockConnector) Periods() connectors.PeriodSupport {
+	return m.Support
+}
+
+func (m *MockConnector) Credentials(ctx context.Context, projectID uint) (*connectors.Credentials, error) {
+	if m.CredentialsErr != nil {
+		return nil, m.CredentialsErr
+	}
+	if m.Creds == nil {
+		return &connectors.Credentials{}, nil
+	}
+	return m.Creds, nil
+}
+
+func (m *MockConnector) Fetch(ctx context.Context, credentials *connectors.Credentials, period connectors.Period) (connectors.RawData, error) {
+	m.Fetched = append(m.Fetched, period)
+	if m.FetchErr != nil {
+		return nil, m.FetchErr
+	}
+	return m.Rows, nil
+}
+
+func (m *MockConnector) Normalize(raw connectors.RawData) ([]connectors.MetricRow, error) {
+	rows, ok := raw.([]connectors.MetricRow)
+	if !ok {
+		return nil, errors.New("unexpected raw data")
+	}
+	return rows, nil
+}
+
+// MockChannelRepository implements ChannelRepositoryInterface over stored rows
+type MockChannelRepository struct {
+	Rows    []*models.ChannelCampaignMonthly
+	Saved   []*models.ChannelCampaignMonthly
+	Uploads []*models.ManualUpload
+}
+
+func (m *MockChannelRepository) ReplaceCampaignMonthly(ctx context.Context, projectID uint, source string, year, month int, metrics []*models.ChannelCampaignMonthly) error {
+	m.Saved = metrics
+	return nil
+}
+
+func (m *MockChannelRepository) GetCampaignMonthly(ctx context.Context, projectID uint, year, month int) ([]*models.ChannelCampaignMonthly, error) {
+	var rows []*models.ChannelCampaignMonthly
+	for _, row := range m.Rows {
+		if row.ProjectID == projectID && row.Year == year && row.Month == month {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (m *MockChannelRepository) SaveManualUpload(ctx context.Context, upload *models.ManualUpload, metrics []*models.ChannelCampaignMonthly) error {
+	upload.ID = uint(len(m.Uploads) + 1)
+	m.Uploads = append(m.Uploads, upload)
+	m.Saved = metrics
+	return nil
+}
+
+func (m *MockChannelRepository) GetManualUploads(ctx context.Context, projectID uint) ([]*models.ManualUpload, error) {
+	return m.Uploads, nil
+}
+
+func TestConnectorService_SyncSource(t *testing.T) {
+	prev := time.Now().AddDate(0, -1, 0)
+	year, month := prev.Year(), int(prev.Month())
+	day := func(d int) time.Time { return time.Date(year, time.Month(month), d, 0, 0, 0, 0, time.UTC) }
+
+	connector := &MockConnector{
+		SourceName: "test_ads",
+		Creds:      &connectors.Credentials{Token: "token", Currency: "USD"},
+		Rows: []connectors.MetricRow{
+			{Date: day(1), CampaignID: "1", CampaignName: "Brand", Impressions: 1000, Clicks: 10, Cost: 5, Conversions: intPtr(1)},
+			{Date: day(2), CampaignID: "1", CampaignName: "Brand", Impressions: 1000, Clicks: 30, Cost: 15},
+			{Date: day(2), Channel: "retargeting", CampaignID: "2", Impressions: 500, Clicks: 5, Cost: 10},
+			{Date: day(1).AddDate(0, 1, 0), CampaignID: "1", Impressions: 100, Clicks: 1, Cost: 1},
+		},
+	}
+	registry := connectors.NewRegistry()
+	if err := registry.Register(connector); err != nil {
+		t.Fatalf("не удалось зарегистрировать коннектор: %v", err)
+	}
+
+	channelRepo := &MockChannelRepository{}
+	var runs []*models.SyncRun
+	syncRunRepo := &MockSyncRunRepository{
+		UpdateFunc: func(ctx context.Context, run *models.SyncRun) error {
+			runs = append(runs, run)
+			return nil
+		},
+	}
+	projectRepo := &MockProjectRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+			return &models.Project{ID: id, Currency: models.CurrencyRUB}, nil
+		},
+	}
+	rates := NewExchangeRateService(&MockExchangeRateRepository{Rates: []*models.ExchangeRate{
+		{Base: "USD", Quote: "RUB", Date: day(1), Rate: 100},
+	}}, nil)
+	service := NewConnectorService(registry, channelRepo, projectRepo, syncRunRepo, rates)
+
+	if err := service.SyncSource(context.Background(), "test_ads", 1, year, month); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(channelRepo.Saved) != 2 {
+		t.Fatalf("ожидалось 2 кампании, получили %d", len(channelRepo.Saved))
+	}
+	brand := channelRepo.Saved[0]
+	if brand.Source != "test_ads" || brand.Channel != "test_ads" || brand.CampaignName != "Brand" ||
+		brand.Impressions != 2000 || brand.Clicks != 40 || math.Abs(brand.Cost-2000) > 1e-9 {
+		t.Errorf("неверные суммы кампании: %+v", brand)
+	}
+	if brand.CPA == nil || math.Abs(*brand.CPA-2000) > 1e-9 || math.Abs(brand.CPC-50) > 1e-9 {
+		t.Errorf("ожидались CPC 50 и CPA 2000 в рублях, получили %v/%v", brand.CPC, brand.CPA)
+	}
+	if channelRepo.Saved[1].Channel != "retargeting" || channelRepo.Saved[1].Conversions != nil {
+		t.Errorf("неверная вторая кампания: %+v", channelRepo.Saved[1])
+	}
+
+	if len(runs) != 1 || runs[0].Source != "test_ads" || runs[0].Status != models.SyncStatusPartial || runs[0].RowsSynced != 2 {
+		t.Errorf("ожидался частичный запуск с предупреждением о строках вне периода: %+v", runs)
+	}
+}
+
+func TestConnectorService_SyncSource_Errors(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		connector   *MockConnector
+		source      string
+		year, month int
+		wantErrText string
+		wantRun     bool
+	}{
+		{
+			name:        "неизвестный источник",
+			connector:   &MockConnector{SourceName: "test_ads"},
+			source:      "unknown",
+			year:        now.Year(),
+			month:       int(now.Month()),
+			wantErrText: "invalid source: unknown",
+		},
+		{
+			name:        "текущий месяц не поддерживается",
+			connector:   &MockConnector{SourceName: "test_ads"},
+			source:      "test_ads",
+			year:        now.Year(),
+			month:       int(now.Month()),
+			wantErrText: "invalid period: " + now.Format("2006-01") + " is not finished yet",
+		},
+		{
+			name:      "источник не настроен в проекте",
+			connector: &MockConnector{SourceName: "test_ads", Support: connectors.PeriodSupport{CurrentMonth: true}, CredentialsErr: connectors.ErrNotConfigured},
+			source:    "test_ads",
+			year:      now.Year(),
+			month:     int(now.Month()),
+		},
+		{
+			name:        "ошибка загрузки попадает в историю",
+			connector:   &MockConnector{SourceName: "test_ads", Support: connectors.PeriodSupport{CurrentMonth: true}, FetchErr: errors.New("api unavailable")},
+			source:      "test_ads",
+			year:        now.Year(),
+			month:       int(now.Month()),
+			wantErrText: "failed to fetch test_ads data: api unavailable",
+			wantRun:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := connectors.NewRegistry()
+			if err := registry.Register(tt.connector); err != nil {
+				t.Fatalf("не удалось зарегистрировать коннектор: %v", err)
+			}
+			var runs []*models.SyncRun
+			syncRunRepo := &MockSyncRunRepository{
+				UpdateFunc: func(ctx context.Context, run *models.SyncRun) error {
+					runs = append(runs, run)
+					return nil
+				},
+			}
+			projectRepo := &MockProjectRepository{
+				GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+					return &models.Project{ID: id}, nil
+				},
+			}
+			service := NewConnectorService(registry, &MockChannelRepository{}, projectRepo, syncRunRepo, nil)
+
+			err := service.SyncSource(context.Background(), tt.source, 1, tt.year, tt.month)
+
+			if tt.wantErrText == "" {
+				if err != nil {
+					t.Errorf("не ожидалась ошибка, но получили: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErrText {
+				t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+			}
+			if tt.wantRun != (len(runs) == 1) {
+				t.Errorf("ожидалась запись в истории: %v, получили %d", tt.wantRun, len(runs))
+			}
+			if tt.wantRun && runs[0].Status != models.SyncStatusFailed {
+				t.Errorf("ожидался статус failed, получили %s", runs[0].Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+)
+
+// crmMaxDeals limits deals of one request or file
+const crmMaxDeals = 5000
+
+// crmDealColumns maps header names of deal files to fields, English and Russian names are accepted
+var crmDealColumns = map[string]string{
+	"deal_id":      "deal_id",
+	"id":           "deal_id",
+	"сделка":       "deal_id",
+	"date":         "date",
+	"дата":         "date",
+	"status":       "status",
+	"статус":       "status",
+	"revenue":      "revenue",
+	"выручка":      "revenue",
+	"source":       "utm_source",
+	"utm_source":   "utm_source",
+	"источник":     "utm_source",
+	"utm_medium":   "utm_medium",
+	"utm_campaign": "utm_campaign",
+	"campaign_id":  "campaign_id",
+}
+
+// crmDealRequiredColumns must be present in the header of a deal file
+var crmDealRequiredColumns = []string{"deal_id", "date", "status"}
+
+// crmMediumSources maps utm_medium values to Metrica traffic sources
+var crmMediumSources = map[string]string{
+	"cpc":         models.TrafficSourceAd,
+	"ppc":         models.TrafficSourceAd,
+	"cpm":         models.TrafficSourceAd,
+	"cpa":         models.TrafficSourceAd,
+	"cpv":         models.TrafficSourceAd,
+	"paid":        models.TrafficSourceAd,
+	"display":     models.TrafficSourceAd,
+	"banner":      models.TrafficSourceAd,
+	"retargeting": models.TrafficSourceAd,
+	"organic":     models.TrafficSourceOrganic,
+	"seo":         models.TrafficSourceOrganic,
+	"social":      models.TrafficSourceSocial,
+	"smm":         models.TrafficSourceSocial,
+	"referral":    models.TrafficSourceReferral,
+	"email":       "email",
+	"newsletter":  "email",
+	"messenger":   "messenger",
+}
+
+// CRMDealInput is a deal sent by a CRM as JSON or read from a row of a CSV/XLSX file
+// Revenue is in the native currency of the project
+type CRMDealInput struct {
+	DealID      string  `json:"deal_id" validate:"required,max=100"`
+	Date        string  `json:"date" validate:"required"` // YYYY-MM-DD, DD.MM.YYYY or RFC 3339
+	Status      string  `json:"status" validate:"required,oneof=open won lost"`
+	Revenue     float64 `json:"revenue" validate:"gte=0"`
+	UTMSource   string  `json:"utm_source" validate:"max=255"`
+	UTMMedium   string  `json:"utm_medium" validate:"max=255"`
+	UTMCampaign string  `json:"utm_campaign" validate:"max=255"`
+	CampaignID  *int64  `json:"campaign_id,omitempty"` // Direct campaign ID if known to the CRM, otherwise matched by utm_campaign
+}
+
+// CRMImportResult represents the result of a deals import
+type CRMImportResult struct {
+	Imported        int `json:"imported"`         // Deals created or updated
+	MatchedCampaign int `json:"matched_campaign"` // Deals matched to Direct campaigns
+	MatchedSource   int `json:"matched_source"`   // Deals matched to Metrica traffic sources
+}
+
+// CRMService imports CRM deals and matches them to Direct campaigns and Metrica traffic sources
+// Deals are unique by CRM deal ID, so a repeated import updates status and revenue
+type CRMService struct {
+	crmRepo    CRMRepositoryInterface
+	directRepo DirectRepositoryInterface
+}
+
+// NewCRMService creates a new CRM service
+func NewCRMService(crmRepo CRMRepositoryInterface, directRepo DirectRepositoryInterface) *CRMService {
+	return &CRMService{
+		crmRepo:    crmRepo,
+		directRepo: directRepo,
+	}
+}
+
+// ImportDeals validates and saves deals of a project, errors of all deals are returned at once as *ImportError
+func (s *CRMService) ImportDeals(ctx context.Context, projectID uint, inputs []CRMDealInput) (*CRMImportResult, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("invalid deals: no deals")
+	}
+	if len(inputs) > crmMaxDeals {
+		return nil, fmt.Errorf("invalid deals: too many deals, max %d", crmMaxDeals)
+	}
+
+	rows := make([]int, len(inputs))
+	for i := range inputs {
+		rows[i] = i + 1
+	}
+	return s.importDeals(ctx, projectID, inputs, rows)
+}
+
+// ImportDealsFile imports deals from a CSV or XLSX file with columns deal_id, date, status, revenue, utm_source,
+// utm_medium, utm_campaign and campaign_id; file errors start with "invalid file"
+func (s *CRMService) ImportDealsFile(ctx context.Context, projectID uint, fileName string, file io.Reader) (*CRMImportResult, error) {
+	table, err := utils.ReadTable(fileName, file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file: %w", err)
+	}
+	if len(table) == 0 {
+		return nil, errors.New("invalid file: file is empty")
+	}
+	columns, err := tableColumns(table[0], crmDealColumns, crmDealRequiredColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(table)-1 > crmMaxDeals {
+		return nil, fmt.Errorf("invalid file: too many rows, max %d", crmMaxDeals)
+	}
+
+	var inputs []CRMDealInput
+	var rows []int
+	var rowErrors []ImportRowError
+	for i, record := range table[1:] {
+		line := i + 2
+		if isEmptyRecord(record) {
+			continue
+		}
+		value := tableValue(columns, record)
+
+		input := CRMDealInput{
+			DealID:      value("deal_id"),
+			Date:        value("date"),
+			Status:      value("status"),
+			UTMSource:   value("utm_source"),
+			UTMMedium:   value("utm_medium"),
+			UTMCampaign: value("utm_campaign"),
+		}
+		if revenue := value("revenue"); revenue != "" {
+			if input.Revenue, err = parseImportNumber(revenue); err != nil {
+				rowErrors = append(rowErrors, ImportRowError{Row: line, Message: "Field 'Revenue' must be a number"})
+				continue
+			}
+		}
+		if campaignID := value("campaign_id"); campaignID != "" {
+			id, err := strconv.ParseInt(campaignID, 10, 64)
+			if err != nil {
+				rowErrors = append(rowErrors, ImportRowError{Row: line, Message: "Field 'CampaignID' must be a whole number"})
+				continue
+			}
+			input.CampaignID = &id
+		}
+		inputs = append(inputs, input)
+		rows = append(rows, line)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &ImportError{Rows: rowErrors}
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("invalid file: no rows")
+	}
+	return s.importDeals(ctx, projectID, inputs, rows)
+}
+
+// CreateWebhookToken generates a new webhook token of a project, the previous token stops working
+// The token is returned once, only its hash is stored
+func (s *CRMService) CreateWebhookToken(ctx context.Context, projectID uint) (string, error) {
+	bytes := make([]byte, 32) // 64 hex characters
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(bytes)
+
+	if err := s.crmRepo.SaveWebhook(ctx, &models.CRMWebhook{ProjectID: projectID, TokenHash: crmTokenHash(token)}); err != nil {
+		return "", fmt.Errorf("failed to save webhook token: %w", err)
+	}
+	return token, nil
+}
+
+// ImportWebhook imports deals sent to the webhook of the project the token belongs to
+func (s *CRMService) ImportWebhook(ctx context.Context, token string, inputs []CRMDealInput) (*CRMImportResult, error) {
+	if token == "" {
+		return nil, errors.New("invalid webhook token")
+	}
+	webhook, err := s.crmRepo.GetWebhookByTokenHash(ctx, crmTokenHash(token))
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, errors.New("invalid webhook token")
+	}
+	return s.ImportDeals(ctx, webhook.ProjectID, inputs)
+}
+
+// importDeals validates deals, matches them to campaigns and sources and saves them
+// rows are numbers of deals used in errors
+func (s *CRMService) importDeals(ctx context.Context, projectID uint, inputs []CRMDealInput, rows []int) (*CRMImportResult, error) {
+	campaigns, err := s.directRepo.GetCampaignsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct campaigns: %w", err)
+	}
+	matcher := newCRMMatcher(campaigns)
+
+	result := &CRMImportResult{}
+	deals := make([]*models.CRMDeal, 0, len(inputs))
+	var rowErrors []ImportRowError
+	seen := make(map[string]int)
+	for i, input := range inputs {
+		deal, err := crmDeal(projectID, input)
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: rows[i], Message: err.Error()})
+			continue
+		}
+		if first, ok := seen[deal.DealID]; ok {
+			rowErrors = append(rowErrors, ImportRowError{
+				Row:     rows[i],
+				Message: fmt.Sprintf("Deal '%s' is already in row %d", deal.DealID, first),
+			})
+			continue
+		}
+		seen[deal.DealID] = rows[i]
+
+		matcher.match(deal)
+		if deal.CampaignID != nil {
+			result.MatchedCampaign++
+		}
+		if deal.TrafficSource != "" {
+			result.MatchedSource++
+		}
+		deals = append(deals, deal)
+	}
+	if len(rowErrors) > 0 {
+		return nil, &ImportError{Rows: rowErrors}
+	}
+
+	if err := s.crmRepo.SaveDeals(ctx, deals); err != nil {
+		return nil, fmt.Errorf("failed to save deals: %w", err)
+	}
+	result.Imported = len(deals)
+	return result, nil
+}
+
+// crmDeal validates a deal input and converts it to a model
+func crmDeal(projectID uint, input CRMDealInput) (*models.CRMDeal, error) {
+	input.DealID = strings.TrimSpace(input.DealID)
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+	input.Date = strings.TrimSpace(input.Date)
+	if err := middleware.Validate.Struct(input); err != nil {
+		return nil, errors.New(middleware.ValidationMessage(err))
+	}
+
+	date, err := parseImportDate(input.Date)
+	if err != nil {
+		return nil, errors.New("Field 'Date' must be in YYYY-MM-DD format")
+	}
+
+	return &models.CRMDeal{
+		ProjectID:   projectID,
+		DealID:      input.DealID,
+		Date:        time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Status:      input.Status,
+		Revenue:     input.Revenue,
+		UTMSource:   strings.TrimSpace(input.UTMSource),
+		UTMMedium:   strings.TrimSpace(input.UTMMedium),
+		UTMCampaign: strings.TrimSpace(input.UTMCampaign),
+		CampaignID:  input.CampaignID,
+	}, nil
+}
+
+// crmTokenHash returns SHA-256 hash of a webhook token
+func crmTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// crmMatcher matches deals to Direct campaigns of a project and to Metrica traffic sources
+type crmMatcher struct {
+	campaignIDs   map[int64]bool
+	campaignNames map[string]int64 // Lowercase name -> Yandex campaign ID
+}
+
+// newCRMMatcher creates a matcher of the project campaigns
+func newCRMMatcher(campaigns []*models.DirectCampaign) *crmMatcher {
+	m := &crmMatcher{
+		campaignIDs:   make(map[int64]bool, len(campaigns)),
+		campaignNames: make(map[string]int64, len(campaigns)),
+	}
+	for _, campaign := range campaigns {
+		m.campaignIDs[campaign.CampaignID] = true
+		if campaign.Name != "" {
+			m.campaignNames[strings.ToLower(campaign.Name)] = campaign.CampaignID
+		}
+	}
+	return m
+}
+
+// match sets Direct campaign and traffic source of a deal
+// Deals with a Yandex (or empty) utm_source are matched to campaigns by utm_campaign, which may be the campaign ID
+// ({campaign_id} in Direct UTM templates) or its name; deals of campaigns are ad traffic, others are matched by utm_medium
+func (m *crmMatcher) match(deal *models.CRMDeal) {
+	source := strings.ToLower(deal.UTMSource)
+	if deal.CampaignID == nil && deal.UTMCampaign != "" && (source == "" || source == "ya" || strings.Contains(source, "yandex")) {
+		if id, err := strconv.ParseInt(deal.UTMCampaign, 10, 64); err == nil {
+			if m.campaignIDs[id] {
+				deal.CampaignID = &id
+			}
+		} else if id, ok := m.campaignNames[strings.ToLower(deal.UTMCampaign)]; ok {
+			deal.CampaignID = &id
+		}
+	}
+
+	if deal.CampaignID != nil {
+		deal.TrafficSource = models.TrafficSourceAd
+		return
+	}
+	deal.TrafficSource = crmMediumSources[strings.ToLower(deal.UTMMedium)]
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// MockCRMRepository is an in-memory CRMRepositoryInterface
+type MockCRMRepository struct {
+	Deals    []*models.CRMDeal
+	Webhooks []*models.CRMWebhook
+}
+
+func (m *MockCRMRepository) SaveDeals(ctx context.Context, deals []*models.CRMDeal) error {
+	m.Deals = append(m.Deals, deals...)
+	return nil
+}
+
+func (m *MockCRMRepository) GetDeals(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.CRMDeal, error) {
+	var deals []*models.CRMDeal
+	for _, deal := range m.Deals {
+		if deal.ProjectID == projectID && !deal.Date.Before(dateFrom) && !deal.Date.After(dateTo) {
+			deals = append(deals, deal)
+		}
+	}
+	return deals, nil
+}
+
+func (m *MockCRMRepository) SaveWebhook(ctx context.Context, webhook *models.CRMWebhook) error {
+	for i, existing := range m.Webhooks {
+		if existing.ProjectID == webhook.ProjectID {
+			m.Webhooks[i] = webhook
+			return nil
+		}
+	}
+	m.Webhooks = append(m.Webhooks, webhook)
+	return nil
+}
+
+func (m *MockCRMRepository) GetWebhookByTokenHash(ctx context.Context, tokenHash string) (*models.CRMWebhook, error) {
+	for _, webhook := range m.Webhooks {
+		if webhook.TokenHash == tokenHash {
+			return webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+// newTestCRMService creates a CRM service of a project with two Direct campaigns
+func newTestCRMService(crmRepo *MockCRMRepository) *CRMService {
+	directRepo := &MockDirectRepositoryForDirectService{
+		GetCampaignsByProjectIDFunc: func(ctx context.Context, projectID uint) ([]*models.DirectCampaign, error) {
+			return []*models.DirectCampaign{
+				{ID: 1, CampaignID: 101, Name: "Поиск | Бренд"},
+				{ID: 2, CampaignID: 102, Name: "РСЯ | Ретаргетинг"},
+			}, nil
+		},
+	}
+	return NewCRMService(crmRepo, directRepo)
+}
+
+func TestCRMService_ImportDeals(t *testing.T) {
+	middleware.InitValidator()
+	repo := &MockCRMRepository{}
+	service := newTestCRMService(repo)
+	explicitID := int64(555)
+
+	result, err := service.ImportDeals(context.Background(), 1, []CRMDealInput{
+		{DealID: "1", Date: "2025-02-10", Status: "WON", Revenue: 30000, UTMSource: "yandex", UTMMedium: "cpc", UTMCampaign: "101"},
+		{DealID: "2", Date: "11.02.2025", Status: "lost", UTMSource: "ya", UTMCampaign: "рся | ретаргетинг"},
+		{DealID: "3", Date: "2025-02-12T15:04:05+03:00", Status: "won", Revenue: 15000, UTMSource: "google", UTMMedium: "organic", UTMCampaign: "101"},
+		{DealID: "4", Date: "2025-02-13", Status: "open", UTMSource: "vk", UTMMedium: "social"},
+		{DealID: "5", Date: "2025-02-14", Status: "open", CampaignID: &explicitID},
+		{DealID: "6", Date: "2025-02-15", Status: "open", UTMSource: "partner", UTMMedium: "offline"},
+	})
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if *result != (CRMImportResult{Imported: 6, MatchedCampaign: 3, MatchedSource: 5}) {
+		t.Errorf("неверный результат импорта: %+v", result)
+	}
+	if len(repo.Deals) != 6 {
+		t.Fatalf("ожидалось 6 сделок, получили %d", len(repo.Deals))
+	}
+
+	tests := []struct {
+		dealID     string
+		campaignID int64
+		source     string
+	}{
+		{dealID: "1", campaignID: 101, source: models.TrafficSourceAd},
+		{dealID: "2", campaignID: 102, source: models.TrafficSourceAd},
+		{dealID: "3", source: models.TrafficSourceOrganic}, // utm_campaign of other sources is not a Direct campaign
+		{dealID: "4", source: models.TrafficSourceSocial},
+		{dealID: "5", campaignID: 555, source: models.TrafficSourceAd},
+		{dealID: "6"},
+	}
+	for i, tt := range tests {
+		deal := repo.Deals[i]
+		var campaignID int64
+		if deal.CampaignID != nil {
+			campaignID = *deal.CampaignID
+		}
+		if deal.DealID != tt.dealID || campaignID != tt.campaignID || deal.TrafficSource != tt.source {
+			t.Errorf("сделка %s: ожидались кампания %d и источник '%s', получили %d и '%s'",
+				tt.dealID, tt.campaignID, tt.source, campaignID, deal.TrafficSource)
+		}
+	}
+
+	first := repo.Deals[0]
+	if first.ProjectID != 1 || first.Status != models.CRMDealStatusWon || !first.Date.Equal(time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("неверная сделка: %+v", first)
+	}
+	if !repo.Deals[2].Date.Equal(time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("дата со временем должна сохраняться как день: %v", repo.Deals[2].Date)
+	}
+}
+
+func TestCRMService_ImportDeals_Errors(t *testing.T) {
+	middleware.InitValidator()
+
+	tests := []struct {
+		name        string
+		inputs      []CRMDealInput
+		wantErrText string
+		wantRows    []ImportRowError
+	}{
+		{
+			name:        "нет сделок",
+			inputs:      nil,
+			wantErrText: "invalid deals: no deals",
+		},
+		{
+			name:        "слишком много сделок",
+			inputs:      make([]CRMDealInput, crmMaxDeals+1),
+			wantErrText: "invalid deals: too many deals, max 5000",
+		},
+		{
+			name: "ошибки в сделках",
+			inputs: []CRMDealInput{
+				{DealID: "1", Date: "2025-02-10", Status: "won", Revenue: 100},
+				{Date: "2025-02-10", Status: "won"},
+				{DealID: "3", Date: "февраль", Status: "won"},
+				{DealID: "4", Date: "2025-02-10", Status: "paid"},
+				{DealID: "5", Date: "2025-02-10", Status: "won", Revenue: -1},
+				{DealID: " 1 ", Date: "2025-02-11", Status: "lost"},
+			},
+			wantRows: []ImportRowError{
+				{Row: 2, Message: "Field 'DealID' is required"},
+				{Row: 3, Message: "Field 'Date' must be in YYYY-MM-DD format"},
+				{Row: 4, Message: "Field 'Status' must be one of: open won lost"},
+				{Row: 5, Message: "Field 'Revenue' must be greater than or equal to 0"},
+				{Row: 6, Message: "Deal '1' is already in row 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockCRMRepository{}
+			service := newTestCRMService(repo)
+
+			_, err := service.ImportDeals(context.Background(), 1, tt.inputs)
+			if err == nil {
+				t.Fatal("ожидалась ошибка, но получили nil")
+			}
+			if len(repo.Deals) != 0 {
+				t.Errorf("при ошибке ничего не должно сохраняться")
+			}
+
+			if tt.wantErrText != "" {
+				if err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили '%s'", tt.wantErrText, err.Error())
+				}
+				return
+			}
+			var importErr *ImportError
+			if !errors.As(err, &importErr) {
+				t.Fatalf("ожидалась ошибка строк, но получили %v", err)
+			}
+			if !reflect.DeepEqual(importErr.Rows, tt.wantRows) {
+				t.Errorf("ожидались ошибки строк\n%+v\nполучили\n%+v", tt.wantRows, importErr.Rows)
+			}
+		})
+	}
+}
+
+func TestCRMService_ImportDealsFile(t *testing.T) {
+	middleware.InitValidator()
+
+	t.Run("CSV", func(t *testing.T) {
+		repo := &MockCRMRepository{}
+		service := newTestCRMService(repo)
+
+		file := "Сделка;Дата;Статус;Выручка;utm_source;utm_medium;utm_campaign\n" +
+			"A-1;10.02.2025;Won;45 000,50;yandex;cpc;Поиск | Бренд\n" +
+			"\n" +
+			"A-2;2025-02-11;open;;;email;\n"
+		result, err := service.ImportDealsFile(context.Background(), 1, "deals.csv", strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+		}
+
+		if result.Imported != 2 || result.MatchedCampaign != 1 || len(repo.Deals) != 2 {
+			t.Fatalf("неверный результат импорта: %+v", result)
+		}
+		if deal := repo.Deals[0]; deal.Revenue != 45000.5 || deal.CampaignID == nil || *deal.CampaignID != 101 {
+			t.Errorf("неверная сделка: %+v", deal)
+		}
+		if repo.Deals[1].TrafficSource != "email" {
+			t.Errorf("ожидался источник email, получили '%s'", repo.Deals[1].TrafficSource)
+		}
+	})
+
+	t.Run("ошибки в строках файла", func(t *testing.T) {
+		repo := &MockCRMRepository{}
+		service := newTestCRMService(repo)
+
+		file := "deal_id,date,status,revenue,campaign_id\n" +
+			"1,2025-02-10,won,много,\n" +
+			"2,2025-02-10,won,100,abc\n" +
+			"3,2025-02-10,,100,\n"
+		_, err := service.ImportDealsFile(context.Background(), 1, "deals.csv", strings.NewReader(file))
+
+		var importErr *ImportError
+		if !errors.As(err, &importErr) {
+			t.Fatalf("ожидалась ошибка строк, но получили %v", err)
+		}
+		want := []ImportRowError{
+			{Row: 2, Message: "Field 'Revenue' must be a number"},
+			{Row: 3, Message: "Field 'CampaignID' must be a whole number"},
+		}
+		if !reflect.DeepEqual(importErr.Rows, want) {
+			t.Errorf("ожидались ошибки строк\n%+v\nполучили\n%+v", want, importErr.Rows)
+		}
+	})
+
+	t.Run("нет обязательной колонки", func(t *testing.T) {
+		service := newTestCRMService(&MockCRMRepository{})
+
+		_, err := service.ImportDealsFile(context.Background(), 1, "deals.csv", strings.NewReader("deal_id,date\n1,2025-02-10\n"))
+		if err == nil || err.Error() != "invalid file: missing column status" {
+			t.Errorf("ожидалась ошибка колонки, но получили %v", err)
+		}
+	})
+}
+
+func TestCRMService_Webhook(t *testing.T) {
+	middleware.InitValidator()
+	repo := &MockCRMRepository{}
+	service := newTestCRMService(repo)
+
+	token, err := service.CreateWebhookToken(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if len(token) != 64 || len(repo.Webhooks) != 1 || repo.Webhooks[0].TokenHash == token {
+		t.Fatalf("должен храниться только хеш токена: %s, %+v", token, repo.Webhooks)
+	}
+
+	deals := []CRMDealInput{{DealID: "1", Date: "2025-02-10", Status: "won", Revenue: 100}}
+	if _, err := service.ImportWebhook(context.Background(), token, deals); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if len(repo.Deals) != 1 || repo.Deals[0].ProjectID != 3 {
+		t.Errorf("сделка должна сохраняться в проект токена: %+v", repo.Deals)
+	}
+
+	newToken, err := service.CreateWebhookToken(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if newToken == token {
+		t.Errorf("новый токен должен отличаться от старого")
+	}
+	for _, oldToken := range []string{token, ""} {
+		if _, err := service.ImportWebhook(context.Background(), oldToken, deals); err == nil || err.Error() != "invalid webhook token" {
+			t.Errorf("ожидалась ошибка токена, но получили %v", err)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 	"github.com/suprt/planica_bi/backend/pkg/utils"
 )
@@ -137,3 +140,66 @@ func (s *DirectService) GetCampaignsWithMetrics(ctx context.Context, projectID u
 
 	return result, nil
 }
+
+// GetCampaignAdGroups retrieves ad group metrics of a project campaign for a period (YYYY-MM, current month if empty)
+// campaignID is the Yandex campaign ID as returned by GetCampaignsWithMetrics
+func (s *DirectService) GetCampaignAdGroups(ctx context.Context, projectID uint, campaignID int64, period string, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error) {
+	filter, err := s.campaignStatsFilter(ctx, projectID, campaignID, period, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.directRepo.GetAdGroupMonthly(ctx, filter, pagination)
+}
+
+// GetCampaignKeywords retrieves keyword metrics of a project campaign for a period, optionally of one ad group
+func (s *DirectService) GetCampaignKeywords(ctx context.Context, projectID uint, campaignID int64, period string, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error) {
+	filter, err := s.campaignStatsFilter(ctx, projectID, campaignID, period, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.directRepo.GetKeywordMonthly(ctx, filter, pagination)
+}
+
+// campaignStatsFilter validates sorting and period and resolves the campaign of the project
+func (s *DirectService) campaignStatsFilter(ctx context.Context, projectID uint, campaignID int64, period string, filter models.DirectStatsFilter) (models.DirectStatsFilter, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.DirectStatsSortCost
+	case models.DirectStatsSortCost, models.DirectStatsSortCTR, models.DirectStatsSortCPA,
+		models.DirectStatsSortClicks, models.DirectStatsSortImpressions, models.DirectStatsSortConversions:
+	default:
+		return filter, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+
+	switch strings.ToUpper(filter.Order) {
+	case "", "DESC":
+		filter.Order = "DESC"
+	case "ASC":
+		filter.Order = "ASC"
+	default:
+		return filter, fmt.Errorf("invalid order: %s", filter.Order)
+	}
+
+	if period == "" {
+		now := time.Now()
+		filter.Year, filter.Month = now.Year(), int(now.Month())
+	} else {
+		year, month, err := parsePeriod(period)
+		if err != nil {
+			return filter, fmt.Errorf("invalid period: %w", err)
+		}
+		filter.Year, filter.Month = year, month
+	}
+
+	campaigns, err := s.directRepo.GetCampaignsByProjectID(ctx, projectID)
+	if err != nil {
+		return filter, err
+	}
+	for _, campaign := range campaigns {
+		if campaign.CampaignID == campaignID {
+			filter.DirectCampaignID = campaign.ID
+			return filter, nil
+		}
+	}
+	return filter, errors.New("campaign not found")
+}
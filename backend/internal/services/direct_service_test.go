@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 )
 
@@ -18,12 +20,19 @@ type MockDirectRepositoryForDirectService struct {
 	GetCampaignsByProjectIDFunc        func(ctx context.Context, projectID uint) ([]*models.DirectCampaign, error)
 	GetCampaignsByAccountIDFunc        func(ctx context.Context, accountID uint) ([]*models.DirectCampaign, error)
 	CreateCampaignFunc                 func(ctx context.Context, campaign *models.DirectCampaign) error
+	UpdateCampaignFunc                 func(ctx context.Context, campaign *models.DirectCampaign) error
 	GetCampaignByIDFunc                func(ctx context.Context, id uint) (*models.DirectCampaign, error)
 	GetCampaignMonthlyFunc             func(ctx context.Context, projectID uint, year int, month int) ([]*models.DirectCampaignMonthly, error)
 	GetCampaignMonthlyByCampaignIDFunc func(ctx context.Context, projectID uint, campaignID uint, year int, month int) (*models.DirectCampaignMonthly, error)
 	SaveCampaignMonthlyFunc            func(campaign *models.DirectCampaignMonthly) error
 	GetTotalsMonthlyFunc               func(ctx context.Context, projectID uint, year int, month int) (*models.DirectTotalsMonthly, error)
 	SaveTotalsMonthlyFunc              func(totals *models.DirectTotalsMonthly) error
+	SaveCampaignDailyFunc              func(ctx context.Context, metrics []*models.DirectCampaignDaily) error
+	GetCampaignDailyFunc               func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error)
+	GetAdGroupMonthlyFunc              func(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error)
+	ReplaceAdGroupMonthlyFunc          func(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectAdGroupMonthly) error
+	GetKeywordMonthlyFunc              func(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error)
+	ReplaceKeywordMonthlyFunc          func(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectKeywordMonthly) error
 }
 
 func (m *MockDirectRepositoryForDirectService) CreateAccount(ctx context.Context, account *models.DirectAccount) error {
@@ -89,6 +98,13 @@ func (m *MockDirectRepositoryForDirectService) CreateCampaign(ctx context.Contex
 	return nil
 }
 
+func (m *MockDirectRepositoryForDirectService) UpdateCampaign(ctx context.Context, campaign *models.DirectCampaign) error {
+	if m.UpdateCampaignFunc != nil {
+		return m.UpdateCampaignFunc(ctx, campaign)
+	}
+	return nil
+}
+
 func (m *MockDirectRepositoryForDirectService) GetCampaignByID(ctx context.Context, id uint) (*models.DirectCampaign, error) {
 	if m.GetCampaignByIDFunc != nil {
 		return m.GetCampaignByIDFunc(ctx, id)
@@ -131,6 +147,48 @@ func (m *MockDirectRepositoryForDirectService) SaveTotalsMonthly(totals *models.
 	return nil
 }
 
+func (m *MockDirectRepositoryForDirectService) SaveCampaignDaily(ctx context.Context, metrics []*models.DirectCampaignDaily) error {
+	if m.SaveCampaignDailyFunc != nil {
+		return m.SaveCampaignDailyFunc(ctx, metrics)
+	}
+	return nil
+}
+
+func (m *MockDirectRepositoryForDirectService) GetCampaignDaily(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error) {
+	if m.GetCampaignDailyFunc != nil {
+		return m.GetCampaignDailyFunc(ctx, projectID, dateFrom, dateTo)
+	}
+	return nil, nil
+}
+
+func (m *MockDirectRepositoryForDirectService) GetAdGroupMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error) {
+	if m.GetAdGroupMonthlyFunc != nil {
+		return m.GetAdGroupMonthlyFunc(ctx, filter, pagination)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockDirectRepositoryForDirectService) ReplaceAdGroupMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectAdGroupMonthly) error {
+	if m.ReplaceAdGroupMonthlyFunc != nil {
+		return m.ReplaceAdGroupMonthlyFunc(ctx, directCampaignIDs, year, month, metrics)
+	}
+	return nil
+}
+
+func (m *MockDirectRepositoryForDirectService) GetKeywordMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error) {
+	if m.GetKeywordMonthlyFunc != nil {
+		return m.GetKeywordMonthlyFunc(ctx, filter, pagination)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockDirectRepositoryForDirectService) ReplaceKeywordMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectKeywordMonthly) error {
+	if m.ReplaceKeywordMonthlyFunc != nil {
+		return m.ReplaceKeywordMonthlyFunc(ctx, directCampaignIDs, year, month, metrics)
+	}
+	return nil
+}
+
 func TestDirectService_CreateAccount(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -284,3 +342,81 @@ func TestDirectService_GetAccountsByProject(t *testing.T) {
 		})
 	}
 }
+
+func TestDirectService_GetCampaignKeywords(t *testing.T) {
+	tests := []struct {
+		name        string
+		campaignID  int64
+		period      string
+		filter      models.DirectStatsFilter
+		wantFilter  models.DirectStatsFilter
+		wantErrText string
+	}{
+		{
+			name:       "сортировка по CPA по возрастанию",
+			campaignID: 111,
+			period:     "2025-03",
+			filter:     models.DirectStatsFilter{Sort: models.DirectStatsSortCPA, Order: "asc", AdGroupID: 501},
+			wantFilter: models.DirectStatsFilter{DirectCampaignID: 10, AdGroupID: 501, Year: 2025, Month: 3, Sort: models.DirectStatsSortCPA, Order: "ASC"},
+		},
+		{
+			name:       "сортировка по умолчанию",
+			campaignID: 111,
+			period:     "2025-03",
+			wantFilter: models.DirectStatsFilter{DirectCampaignID: 10, Year: 2025, Month: 3, Sort: models.DirectStatsSortCost, Order: "DESC"},
+		},
+		{
+			name:        "неизвестная сортировка",
+			campaignID:  111,
+			period:      "2025-03",
+			filter:      models.DirectStatsFilter{Sort: "name"},
+			wantErrText: "invalid sort: name",
+		},
+		{
+			name:        "неверный период",
+			campaignID:  111,
+			period:      "март",
+			wantErrText: "invalid period: invalid period format, expected YYYY-MM",
+		},
+		{
+			name:        "кампания другого проекта",
+			campaignID:  999,
+			period:      "2025-03",
+			wantErrText: "campaign not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter *models.DirectStatsFilter
+			mockRepo := &MockDirectRepositoryForDirectService{
+				GetCampaignsByProjectIDFunc: func(ctx context.Context, projectID uint) ([]*models.DirectCampaign, error) {
+					return []*models.DirectCampaign{{ID: 10, CampaignID: 111}, {ID: 11, CampaignID: 222}}, nil
+				},
+				GetKeywordMonthlyFunc: func(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error) {
+					gotFilter = &filter
+					return []*models.DirectKeywordMonthly{{ID: 1}}, 1, nil
+				},
+			}
+			service := NewDirectService(mockRepo)
+
+			_, total, err := service.GetCampaignKeywords(context.Background(), 1, tt.campaignID, tt.period, tt.filter, middleware.DefaultPagination())
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				if gotFilter != nil {
+					t.Error("запрос к репозиторию не должен выполняться при ошибке")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if total != 1 || gotFilter == nil || *gotFilter != tt.wantFilter {
+				t.Errorf("ожидался фильтр %+v, получили %+v", tt.wantFilter, gotFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// ExchangeRateProvider loads exchange rates from an external source, e.g. the Bank of Russia
+type ExchangeRateProvider interface {
+	Name() string
+	GetRates(ctx context.Context, date time.Time) ([]integrations.CurrencyRate, error)
+}
+
+// ExchangeRateService handles exchange rates and money conversion between currencies
+type ExchangeRateService struct {
+	rateRepo ExchangeRateRepositoryInterface
+	provider ExchangeRateProvider // Optional: without provider only manual rates are used
+}
+
+// NewExchangeRateService creates a new exchange rate service
+func NewExchangeRateService(rateRepo ExchangeRateRepositoryInterface, provider ExchangeRateProvider) *ExchangeRateService {
+	return &ExchangeRateService{
+		rateRepo: rateRepo,
+		provider: provider,
+	}
+}
+
+// GetRates retrieves paginated exchange rates matching the filter
+func (s *ExchangeRateService) GetRates(ctx context.Context, filter models.ExchangeRateFilter, pagination *middleware.Pagination) ([]*models.ExchangeRate, int64, error) {
+	filter.Base = strings.ToUpper(filter.Base)
+	filter.Quote = strings.ToUpper(filter.Quote)
+	if filter.Base != "" && !models.IsValidCurrency(filter.Base) {
+		return nil, 0, fmt.Errorf("invalid base: %s", filter.Base)
+	}
+	if filter.Quote != "" && !models.IsValidCurrency(filter.Quote) {
+		return nil, 0, fmt.Errorf("invalid quote: %s", filter.Quote)
+	}
+	return s.rateRepo.List(ctx, filter, pagination)
+}
+
+// SetRate saves a manual rate of a currency pair on a date: 1 base = rate quote
+// Manual rates replace provider rates of the same date and are never overwritten by the provider
+func (s *ExchangeRateService) SetRate(ctx context.Context, base, quote string, date time.Time, rate float64) (*models.ExchangeRate, error) {
+	base = strings.ToUpper(base)
+	quote = strings.ToUpper(quote)
+	if !models.IsValidCurrency(base) {
+		return nil, fmt.Errorf("invalid base: %s", base)
+	}
+	if !models.IsValidCurrency(quote) {
+		return nil, fmt.Errorf("invalid quote: %s", quote)
+	}
+	if base == quote {
+		return nil, errors.New("invalid pair: base and quote are the same")
+	}
+	if rate <= 0 {
+		return nil, errors.New("invalid rate: must be positive")
+	}
+
+	exchangeRate := &models.ExchangeRate{
+		Base:   base,
+		Quote:  quote,
+		Date:   time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Rate:   rate,
+		Source: models.ExchangeRateSourceManual,
+	}
+	if err := s.rateRepo.SaveRates(ctx, []*models.ExchangeRate{exchangeRate}); err != nil {
+		return nil, fmt.Errorf("failed to save rate: %w", err)
+	}
+	return exchangeRate, nil
+}
+
+// FetchRates loads rates of a date from the provider and saves rates of supported currencies
+// Returns number of saved rates; pairs with manual rates on the same date are kept
+func (s *ExchangeRateService) FetchRates(ctx context.Context, date time.Time) (int, error) {
+	if s.provider == nil {
+		return 0, errors.New("exchange rate provider is not configured")
+	}
+
+	providerRates, err := s.provider.GetRates(ctx, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rates from %s: %w", s.provider.Name(), err)
+	}
+
+	// Provider may return rates of another date, e.g. the last working day
+	manual := make(map[string]bool)
+	checkedDates := make(map[string]bool)
+	rates := make([]*models.ExchangeRate, 0, len(providerRates))
+	for _, providerRate := range providerRates {
+		if !models.IsValidCurrency(providerRate.Base) || !models.IsValidCurrency(providerRate.Quote) || providerRate.Rate <= 0 {
+			continue
+		}
+
+		day := providerRate.Date.Format("2006-01-02")
+		if !checkedDates[day] {
+			existing, err := s.rateRepo.GetRatesByDate(ctx, providerRate.Date)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get existing rates: %w", err)
+			}
+			for _, rate := range existing {
+				if rate.Source == models.ExchangeRateSourceManual {
+					manual[exchangeRateKey(rate.Base, rate.Quote, day)] = true
+				}
+			}
+			checkedDates[day] = true
+		}
+		if manual[exchangeRateKey(providerRate.Base, providerRate.Quote, day)] {
+			continue
+		}
+
+		rates = append(rates, &models.ExchangeRate{
+			Base:   providerRate.Base,
+			Quote:  providerRate.Quote,
+			Date:   providerRate.Date,
+			Rate:   providerRate.Rate,
+			Source: s.provider.Name(),
+		})
+	}
+
+	if err := s.rateRepo.SaveRates(ctx, rates); err != nil {
+		return 0, fmt.Errorf("failed to save rates: %w", err)
+	}
+	return len(rates), nil
+}
+
+// Rate returns how many units of currency to one unit of currency from is worth on a date
+// The latest known rate on or before the date is used: direct pair, inverse pair or cross rate via RUB
+func (s *ExchangeRateService) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	rate, ok, err := s.pairRate(ctx, from, to, date)
+	if err != nil || ok {
+		return rate, err
+	}
+
+	if from != models.CurrencyRUB && to != models.CurrencyRUB {
+		fromRUB, fromOK, err := s.pairRate(ctx, from, models.CurrencyRUB, date)
+		if err != nil {
+			return 0, err
+		}
+		toRUB, toOK, err := s.pairRate(ctx, models.CurrencyRUB, to, date)
+		if err != nil {
+			return 0, err
+		}
+		if fromOK && toOK {
+			return fromRUB * toRUB, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no exchange rate %s/%s on %s", from, to, date.Format("2006-01-02"))
+}
+
+// Convert converts an amount between currencies at the rate of a date
+func (s *ExchangeRateService) Convert(ctx context.Context, amount float64, from, to string, date time.Time) (float64, error) {
+	rate, err := s.Rate(ctx, from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// pairRate looks up a stored rate of the pair or of the inverse pair
+func (s *ExchangeRateService) pairRate(ctx context.Context, from, to string, date time.Time) (float64, bool, error) {
+	rate, err := s.rateRepo.GetRate(ctx, from, to, date)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	if rate != nil && rate.Rate > 0 {
+		return rate.Rate, true, nil
+	}
+
+	inverse, err := s.rateRepo.GetRate(ctx, to, from, date)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	if inverse != nil && inverse.Rate > 0 {
+		return 1 / inverse.Rate, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// exchangeRateKey identifies a rate of a currency pair on a day
+func exchangeRateKey(base, quote, day string) string {
+	return base + "/" + quote + "/" + day
+}
+
+// rateDate returns the date whose rates convert money of a month: its last day, or today for the current month
+func rateDate(year, month int, now time.Time) time.Time {
+	_, end := monthRange(year, month)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if end.After(today) {
+		return today
+	}
+	return end
+}
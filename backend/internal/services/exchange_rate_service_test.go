@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// MockExchangeRateRepository implements ExchangeRateRepositoryInterface over stored rates
+type MockExchangeRateRepository struct {
+	Rates []*models.ExchangeRate
+	Saved []*models.ExchangeRate
+}
+
+func (m *MockExchangeRateRepository) SaveRates(ctx context.Context, rates []*models.ExchangeRate) error {
+	m.Saved = append(m.Saved, rates...)
+	return nil
+}
+
+func (m *MockExchangeRateRepository) GetRate(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error) {
+	var latest *models.ExchangeRate
+	for _, rate := range m.Rates {
+		if rate.Base != base || rate.Quote != quote || rate.Date.After(date) {
+			continue
+		}
+		if latest == nil || rate.Date.After(latest.Date) {
+			latest = rate
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockExchangeRateRepository) GetRatesByDate(ctx context.Context, date time.Time) ([]*models.ExchangeRate, error) {
+	var rates []*models.ExchangeRate
+	for _, rate := range m.Rates {
+		if rate.Date.Equal(date) {
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+func (m *MockExchangeRateRepository) List(ctx context.Context, filter models.ExchangeRateFilter, pagination *middleware.Pagination) ([]*models.ExchangeRate, int64, error) {
+	return m.Rates, int64(len(m.Rates)), nil
+}
+
+// MockExchangeRateProvider implements ExchangeRateProvider with fixed rates
+type MockExchangeRateProvider struct {
+	Rates []integrations.CurrencyRate
+}
+
+func (m *MockExchangeRateProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockExchangeRateProvider) GetRates(ctx context.Context, date time.Time) ([]integrations.CurrencyRate, error) {
+	return m.Rates, nil
+}
+
+func TestExchangeRateService_Rate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	repo := &MockExchangeRateRepository{Rates: []*models.ExchangeRate{
+		{Base: "USD", Quote: "RUB", Date: day(1), Rate: 90},
+		{Base: "USD", Quote: "RUB", Date: day(10), Rate: 100},
+		{Base: "KZT", Quote: "RUB", Date: day(1), Rate: 0.2},
+		{Base: "RUB", Quote: "BYN", Date: day(1), Rate: 0.04},
+	}}
+	service := NewExchangeRateService(repo, nil)
+
+	tests := []struct {
+		name        string
+		from, to    string
+		date        time.Time
+		wantRate    float64
+		wantErrText string
+	}{
+		{name: "одинаковые валюты", from: "KZT", to: "KZT", date: day(5), wantRate: 1},
+		{name: "прямой курс на последнюю известную дату", from: "USD", to: "RUB", date: day(5), wantRate: 90},
+		{name: "более свежий курс", from: "USD", to: "RUB", date: day(15), wantRate: 100},
+		{name: "обратный курс", from: "RUB", to: "USD", date: day(15), wantRate: 0.01},
+		{name: "кросс-курс через рубль", from: "KZT", to: "USD", date: day(15), wantRate: 0.002},
+		{name: "кросс-курс с обратной парой", from: "USD", to: "BYN", date: day(15), wantRate: 4},
+		{name: "курса ещё нет", from: "USD", to: "RUB", date: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), wantErrText: "no exchange rate USD/RUB on 2025-02-28"},
+		{name: "неизвестная пара", from: "EUR", to: "USD", date: day(15), wantErrText: "no exchange rate EUR/USD on 2025-03-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := service.Rate(context.Background(), tt.from, tt.to, tt.date)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if math.Abs(rate-tt.wantRate) > 1e-9 {
+				t.Errorf("ожидался курс %v, получили %v", tt.wantRate, rate)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_SetRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		base, quote string
+		rate        float64
+		wantErrText string
+	}{
+		{name: "ручной курс", base: "usd", quote: "KZT", rate: 480.5},
+		{name: "неизвестная валюта", base: "XXX", quote: "RUB", rate: 1, wantErrText: "invalid base: XXX"},
+		{name: "одинаковые валюты", base: "RUB", quote: "RUB", rate: 1, wantErrText: "invalid pair: base and quote are the same"},
+		{name: "нулевой курс", base: "USD", quote: "RUB", rate: 0, wantErrText: "invalid rate: must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockExchangeRateRepository{}
+			service := NewExchangeRateService(repo, nil)
+
+			rate, err := service.SetRate(context.Background(), tt.base, tt.quote, time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC), tt.rate)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				if len(repo.Saved) != 0 {
+					t.Error("курс не должен сохраняться при ошибке")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if rate.Base != "USD" || rate.Source != models.ExchangeRateSourceManual || rate.Date.Hour() != 0 || len(repo.Saved) != 1 {
+				t.Errorf("неверный сохранённый курс: %+v", rate)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_FetchRates(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := &MockExchangeRateRepository{Rates: []*models.ExchangeRate{
+		{Base: "KZT", Quote: "RUB", Date: date, Rate: 0.19, Source: models.ExchangeRateSourceManual},
+		{Base: "USD", Quote: "RUB", Date: date, Rate: 88, Source: "mock"},
+	}}
+	provider := &MockExchangeRateProvider{Rates: []integrations.CurrencyRate{
+		{Base: "USD", Quote: "RUB", Date: date, Rate: 88.8},
+		{Base: "KZT", Quote: "RUB", Date: date, Rate: 0.18},
+		{Base: "XDR", Quote: "RUB", Date: date, Rate: 118},
+	}}
+	service := NewExchangeRateService(repo, provider)
+
+	saved, err := service.FetchRates(context.Background(), date)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if saved != 1 || len(repo.Saved) != 1 {
+		t.Fatalf("ожидался 1 сохранённый курс, получили %d", saved)
+	}
+	if repo.Saved[0].Base != "USD" || repo.Saved[0].Rate != 88.8 || repo.Saved[0].Source != "mock" {
+		t.Errorf("курс провайдера должен обновить прежний курс провайдера: %+v", repo.Saved[0])
+	}
+
+	if _, err := NewExchangeRateService(repo, nil).FetchRates(context.Background(), date); err == nil {
+		t.Error("ожидалась ошибка без провайдера")
+	}
+}
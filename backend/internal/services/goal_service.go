@@ -105,6 +105,27 @@ func (s *GoalService) UpdateGoal(ctx context.Context, goal *models.Goal) error {
 	return s.goalRepo.Update(ctx, goal)
 }
 
+// SetConversion marks a goal of a project as conversion goal or unmarks it
+// Goals are discovered from Metrica automatically, so this is how managers choose conversions
+func (s *GoalService) SetConversion(ctx context.Context, projectID uint, id uint, isConversion bool) (*models.Goal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil || goal == nil {
+		return nil, errors.New("goal not found")
+	}
+
+	// Goal must belong to a counter of the project
+	counter, err := s.counterRepo.GetByID(ctx, goal.CounterID)
+	if err != nil || counter == nil || counter.ProjectID != projectID {
+		return nil, errors.New("goal not found")
+	}
+
+	goal.IsConversion = isConversion
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
 // DeleteGoal deletes a goal
 func (s *GoalService) DeleteGoal(ctx context.Context, id uint) error {
 	return s.goalRepo.Delete(ctx, id)
@@ -130,7 +151,7 @@ func (s *GoalService) GetConversionGoals(ctx context.Context, counterID uint) ([
 
 	var conversionGoals []*models.Goal
 	for _, goal := range goals {
-		if goal.IsConversion {
+		if goal.IsConversion && !goal.IsDeleted {
 			conversionGoals = append(conversionGoals, goal)
 		}
 	}
@@ -10,6 +10,7 @@ import (
 // MockGoalRepository implements GoalRepositoryInterface for goal service testing
 type MockGoalRepository struct {
 	CreateFunc            func(ctx context.Context, goal *models.Goal) error
+	UpsertFunc            func(ctx context.Context, goal *models.Goal) error
 	GetByProjectIDFunc    func(ctx context.Context, projectID uint) ([]*models.Goal, error)
 	GetByIDFunc           func(ctx context.Context, id uint) (*models.Goal, error)
 	GetByGoalIDFunc       func(ctx context.Context, counterID uint, goalID int64) (*models.Goal, error)
@@ -27,6 +28,13 @@ func (m *MockGoalRepository) Create(ctx context.Context, goal *models.Goal) erro
 	return nil
 }
 
+func (m *MockGoalRepository) Upsert(ctx context.Context, goal *models.Goal) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, goal)
+	}
+	return nil
+}
+
 func (m *MockGoalRepository) GetByProjectID(ctx context.Context, projectID uint) ([]*models.Goal, error) {
 	if m.GetByProjectIDFunc != nil {
 		return m.GetByProjectIDFunc(ctx, projectID)
@@ -406,3 +414,63 @@ func TestGoalService_DeleteGoal(t *testing.T) {
 		})
 	}
 }
+
+func TestGoalService_SetConversion(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectID      uint
+		counterProject uint
+		wantErrText    string
+	}{
+		{
+			name:           "цель проекта",
+			projectID:      1,
+			counterProject: 1,
+		},
+		{
+			name:           "цель другого проекта",
+			projectID:      2,
+			counterProject: 1,
+			wantErrText:    "goal not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *models.Goal
+			goalRepo := &MockGoalRepository{
+				GetByIDFunc: func(ctx context.Context, id uint) (*models.Goal, error) {
+					return &models.Goal{ID: id, CounterID: 10, GoalID: 555, Name: "Заявка"}, nil
+				},
+				UpdateFunc: func(ctx context.Context, goal *models.Goal) error {
+					updated = goal
+					return nil
+				},
+			}
+			counterRepo := &MockCounterRepositoryForGoalService{
+				GetByIDFunc: func(ctx context.Context, id uint) (*models.YandexCounter, error) {
+					return &models.YandexCounter{ID: id, ProjectID: tt.counterProject}, nil
+				},
+			}
+			service := NewGoalService(goalRepo, counterRepo)
+
+			goal, err := service.SetConversion(context.Background(), tt.projectID, 3, true)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили '%v'", tt.wantErrText, err)
+				}
+				if updated != nil {
+					t.Errorf("цель не должна обновляться")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if !goal.IsConversion || updated == nil || !updated.IsConversion {
+				t.Errorf("цель должна стать конверсионной: %+v", goal)
+			}
+		})
+	}
+}
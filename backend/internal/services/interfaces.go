@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
@@ -58,8 +59,11 @@ type DirectRepositoryInterface interface {
 	CreateAccount(ctx context.Context, account *models.DirectAccount) error
 	GetAccountsByProjectID(ctx context.Context, projectID uint) ([]*models.DirectAccount, error)
 	GetAccountByClientLogin(ctx context.Context, projectID uint, clientLogin string) (*models.DirectAccount, error)
+	GetAccountByID(ctx context.Context, id uint) (*models.DirectAccount, error)
+	UpdateAccount(ctx context.Context, account *models.DirectAccount) error
 	GetCampaignsByAccountID(ctx context.Context, accountID uint) ([]*models.DirectCampaign, error)
 	CreateCampaign(ctx context.Context, campaign *models.DirectCampaign) error
+	UpdateCampaign(ctx context.Context, campaign *models.DirectCampaign) error
 	GetCampaignByID(ctx context.Context, id uint) (*models.DirectCampaign, error)
 	GetCampaignsByProjectID(ctx context.Context, projectID uint) ([]*models.DirectCampaign, error)
 	GetCampaignMonthly(ctx context.Context, projectID uint, year int, month int) ([]*models.DirectCampaignMonthly, error)
@@ -67,11 +71,18 @@ type DirectRepositoryInterface interface {
 	SaveCampaignMonthly(metrics *models.DirectCampaignMonthly) error
 	GetTotalsMonthly(ctx context.Context, projectID uint, year int, month int) (*models.DirectTotalsMonthly, error)
 	SaveTotalsMonthly(totals *models.DirectTotalsMonthly) error
+	SaveCampaignDaily(ctx context.Context, metrics []*models.DirectCampaignDaily) error
+	GetCampaignDaily(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error)
+	GetAdGroupMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error)
+	ReplaceAdGroupMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectAdGroupMonthly) error
+	GetKeywordMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error)
+	ReplaceKeywordMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectKeywordMonthly) error
 }
 
 // GoalRepositoryInterface defines methods for goal data access
 type GoalRepositoryInterface interface {
 	Create(ctx context.Context, goal *models.Goal) error
+	Upsert(ctx context.Context, goal *models.Goal) error
 	GetByCounterID(ctx context.Context, counterID uint) ([]*models.Goal, error)
 	GetByCounterIDs(ctx context.Context, counterIDs []uint) ([]*models.Goal, error)
 	GetByID(ctx context.Context, id uint) (*models.Goal, error)
@@ -88,10 +99,92 @@ type MetricsRepositoryInterface interface {
 	GetAgeMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsAgeMonthly, error)
 	SaveAgeMetrics(metrics *models.MetricsAgeMonthly) error
 	GetAgeMetricsByGroup(ctx context.Context, projectID uint, year int, month int, ageGroup string) (*models.MetricsAgeMonthly, error)
+	ReplaceAgeMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsAgeMonthly) error
+	GetBreakdownMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsBreakdownMonthly, error)
+	ReplaceBreakdownMetrics(ctx context.Context, projectID uint, year int, month int, dimension string, metrics []*models.MetricsBreakdownMonthly) error
 	GetAllMonthlyMetricsForProject(ctx context.Context, projectID uint) ([]*models.MetricsMonthly, error)
+	SaveDailyMetrics(ctx context.Context, metrics []*models.MetricsDaily) error
+	GetDailyMetrics(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error)
+	GetTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsTrafficSourceMonthly, error)
+	ReplaceTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsTrafficSourceMonthly) error
+	GetGoalMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsGoalMonthly, error)
+	ReplaceGoalMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsGoalMonthly) error
+	GetCampaignRevenue(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsCampaignRevenueMonthly, error)
+	ReplaceCampaignRevenue(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsCampaignRevenueMonthly) error
+}
+
+// OAuthCredentialRepositoryInterface defines methods for OAuth credential data access
+type OAuthCredentialRepositoryInterface interface {
+	Create(ctx context.Context, credential *models.OAuthCredential) error
+	Update(ctx context.Context, credential *models.OAuthCredential) error
+	UpdateHealth(ctx context.Context, credential *models.OAuthCredential) error
+	GetByID(ctx context.Context, id uint) (*models.OAuthCredential, error)
+	GetByProjectID(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error)
+	GetByProjectAndLogin(ctx context.Context, projectID *uint, provider, login string) (*models.OAuthCredential, error)
+	GetDefaultForProject(ctx context.Context, projectID *uint, provider string) (*models.OAuthCredential, error)
+	GetAll(ctx context.Context) ([]*models.OAuthCredential, error)
+	GetRefreshable(ctx context.Context, expiresBefore time.Time) ([]*models.OAuthCredential, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// MetricaVisitRepositoryInterface defines methods for raw Metrica visits data access
+type MetricaVisitRepositoryInterface interface {
+	ReplaceVisits(ctx context.Context, counterID int64, dateFrom, dateTo time.Time, load func(save func(visits []*models.MetricaVisit) error) error) error
+}
+
+// ExchangeRateRepositoryInterface defines methods for exchange rates data access
+type ExchangeRateRepositoryInterface interface {
+	SaveRates(ctx context.Context, rates []*models.ExchangeRate) error
+	GetRate(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error)
+	GetRatesByDate(ctx context.Context, date time.Time) ([]*models.ExchangeRate, error)
+	List(ctx context.Context, filter models.ExchangeRateFilter, pagination *middleware.Pagination) ([]*models.ExchangeRate, int64, error)
 }
 
 // SEORepositoryInterface defines methods for SEO data access
 type SEORepositoryInterface interface {
 	GetSEOQueries(ctx context.Context, projectID uint, year int, month int) ([]*models.SEOQueriesMonthly, error)
+	ReplaceSEOQueries(ctx context.Context, projectID uint, year int, month int, queries []*models.SEOQueriesMonthly) error
+	CreateHost(ctx context.Context, host *models.WebmasterHost) error
+	GetHostsByProjectID(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error)
+	GetHostByHostID(ctx context.Context, projectID uint, hostID string) (*models.WebmasterHost, error)
+	GetHostByID(ctx context.Context, id uint) (*models.WebmasterHost, error)
+	DeleteHost(ctx context.Context, id uint) error
+}
+
+// BackfillRepositoryInterface defines methods for backfill job data access
+type BackfillRepositoryInterface interface {
+	Create(ctx context.Context, job *models.BackfillJob) error
+	GetByID(ctx context.Context, id uint) (*models.BackfillJob, error)
+	GetByProjectID(ctx context.Context, projectID uint) ([]*models.BackfillJob, error)
+	AddProgress(ctx context.Context, id uint, completed, failed int) error
+}
+
+// SyncRunRepositoryInterface defines methods for sync run history data access
+type SyncRunRepositoryInterface interface {
+	Create(ctx context.Context, run *models.SyncRun) error
+	Update(ctx context.Context, run *models.SyncRun) error
+	List(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error)
+}
+
+// ChannelRepositoryInterface defines methods for campaign metrics of connectors and manual uploads
+type ChannelRepositoryInterface interface {
+	ReplaceCampaignMonthly(ctx context.Context, projectID uint, source string, year, month int, metrics []*models.ChannelCampaignMonthly) error
+	GetCampaignMonthly(ctx context.Context, projectID uint, year, month int) ([]*models.ChannelCampaignMonthly, error)
+	SaveManualUpload(ctx context.Context, upload *models.ManualUpload, metrics []*models.ChannelCampaignMonthly) error
+	GetManualUploads(ctx context.Context, projectID uint) ([]*models.ManualUpload, error)
+}
+
+// VKAdsRepositoryInterface defines methods for VK Ads cabinets data access
+type VKAdsRepositoryInterface interface {
+	CreateAccount(ctx context.Context, account *models.VKAdsAccount) error
+	GetAccountByProjectID(ctx context.Context, projectID uint) (*models.VKAdsAccount, error)
+	DeleteAccount(ctx context.Context, id uint) error
+}
+
+// CRMRepositoryInterface defines methods for CRM deals data access
+type CRMRepositoryInterface interface {
+	SaveDeals(ctx context.Context, deals []*models.CRMDeal) error
+	GetDeals(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.CRMDeal, error)
+	SaveWebhook(ctx context.Context, webhook *models.CRMWebhook) error
+	GetWebhookByTokenHash(ctx context.Context, tokenHash string) (*models.CRMWebhook, error)
 }
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+)
+
+// manualCostMaxRows limits rows of an uploaded file
+const manualCostMaxRows = 5000
+
+// manualCostColumns maps header names of uploaded files to columns, English and Russian names are accepted
+var manualCostColumns = map[string]string{
+	"channel":     "channel",
+	"канал":       "channel",
+	"month":       "month",
+	"месяц":       "month",
+	"period":      "month",
+	"период":      "month",
+	"cost":        "cost",
+	"расход":      "cost",
+	"impressions": "impressions",
+	"показы":      "impressions",
+	"clicks":      "clicks",
+	"клики":       "clicks",
+	"conversions": "conversions",
+	"конверсии":   "conversions",
+}
+
+// manualCostRequiredColumns must be present in the header of an uploaded file
+var manualCostRequiredColumns = []string{"channel", "month", "cost"}
+
+// ManualCostRow is a row of an uploaded file: metrics of an offline channel for a month
+// Cost is in the native currency of the project
+type ManualCostRow struct {
+	Channel     string  `json:"channel" validate:"required,max=50"`
+	Month       string  `json:"month" validate:"required"` // YYYY-MM
+	Cost        float64 `json:"cost" validate:"gte=0"`
+	Impressions int     `json:"impressions" validate:"gte=0"`
+	Clicks      int     `json:"clicks" validate:"gte=0"`
+	Conversions *int    `json:"conversions,omitempty" validate:"omitempty,gte=0"`
+}
+
+// ManualCostService imports costs of channels that have no API from CSV/XLSX files
+// Rows are stored as the manual channel source and shown in reports next to connector channels
+type ManualCostService struct {
+	channelRepo ChannelRepositoryInterface
+}
+
+// NewManualCostService creates a new manual cost service
+func NewManualCostService(channelRepo ChannelRepositoryInterface) *ManualCostService {
+	return &ManualCostService{
+		channelRepo: channelRepo,
+	}
+}
+
+// Import validates rows of an uploaded file and replaces manual metrics of their channels and months
+// Errors of all rows are returned at once as *ImportError; file errors start with "invalid file"
+func (s *ManualCostService) Import(ctx context.Context, projectID, userID uint, fileName string, file io.Reader) (*models.ManualUpload, error) {
+	table, err := utils.ReadTable(fileName, file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file: %w", err)
+	}
+
+	rows, err := parseManualCostRows(table, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &models.ManualUpload{
+		ProjectID: projectID,
+		UserID:    userID,
+		FileName:  fileName,
+		RowsCount: len(rows),
+	}
+	channels := make(map[string]bool)
+	periods := make(map[string]bool)
+	metrics := make([]*models.ChannelCampaignMonthly, 0, len(rows))
+	for _, row := range rows {
+		year, month, _ := parsePeriod(row.Month)
+
+		values := directMetrics{impressions: row.Impressions, clicks: row.Clicks, cost: row.Cost}
+		if row.Conversions != nil {
+			values.conversions = *row.Conversions
+			values.hasConversions = true
+		}
+		ctr, cpc, cpa := values.rates()
+
+		metrics = append(metrics, &models.ChannelCampaignMonthly{
+			ProjectID:   projectID,
+			Source:      models.ChannelSourceManual,
+			Channel:     row.Channel,
+			Year:        year,
+			Month:       month,
+			Impressions: row.Impressions,
+			Clicks:      row.Clicks,
+			CTRPct:      ctr,
+			CPC:         cpc,
+			Conversions: row.Conversions,
+			CPA:         cpa,
+			Cost:        row.Cost,
+		})
+
+		upload.TotalCost += row.Cost
+		if !channels[row.Channel] {
+			channels[row.Channel] = true
+			upload.Channels = append(upload.Channels, row.Channel)
+		}
+		periods[row.Month] = true
+	}
+	for period := range periods {
+		upload.Periods = append(upload.Periods, period)
+	}
+	sort.Strings(upload.Periods)
+
+	if err := s.channelRepo.SaveManualUpload(ctx, upload, metrics); err != nil {
+		return nil, fmt.Errorf("failed to save manual upload: %w", err)
+	}
+	return upload, nil
+}
+
+// GetUploads retrieves manual uploads of a project, newest first
+func (s *ManualCostService) GetUploads(ctx context.Context, projectID uint) ([]*models.ManualUpload, error) {
+	return s.channelRepo.GetManualUploads(ctx, projectID)
+}
+
+// parseManualCostRows converts table rows into validated manual cost rows, the first row is the header
+// Empty lines are skipped; a channel may occur once per month
+func parseManualCostRows(table [][]string, now time.Time) ([]ManualCostRow, error) {
+	if len(table) == 0 {
+		return nil, errors.New("invalid file: file is empty")
+	}
+
+	columns, err := tableColumns(table[0], manualCostColumns, manualCostRequiredColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(table)-1 > manualCostMaxRows {
+		return nil, fmt.Errorf("invalid file: too many rows, max %d", manualCostMaxRows)
+	}
+
+	var rows []ManualCostRow
+	var rowErrors []ImportRowError
+	seen := make(map[string]int)
+	for i, record := range table[1:] {
+		line := i + 2
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		row, err := parseManualCostRow(tableValue(columns, record), now)
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		key := strings.ToLower(row.Channel) + "|" + row.Month
+		if first, ok := seen[key]; ok {
+			rowErrors = append(rowErrors, ImportRowError{
+				Row:     line,
+				Message: fmt.Sprintf("Channel '%s' for %s is already in row %d", row.Channel, row.Month, first),
+			})
+			continue
+		}
+		seen[key] = line
+		rows = append(rows, row)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &ImportError{Rows: rowErrors}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("invalid file: no rows")
+	}
+	return rows, nil
+}
+
+// parseManualCostRow parses and validates values of a row
+func parseManualCostRow(value func(column string) string, now time.Time) (ManualCostRow, error) {
+	row := ManualCostRow{Channel: value("channel")}
+
+	var err error
+	if row.Month, err = parseManualMonth(value("month"), now); err != nil {
+		return row, err
+	}
+	if value("cost") == "" {
+		return row, errors.New("Field 'Cost' is required")
+	}
+	if row.Cost, err = parseImportNumber(value("cost")); err != nil {
+		return row, errors.New("Field 'Cost' must be a number")
+	}
+	if row.Impressions, err = parseImportCount(value("impressions")); err != nil {
+		return row, errors.New("Field 'Impressions' must be a whole number")
+	}
+	if row.Clicks, err = parseImportCount(value("clicks")); err != nil {
+		return row, errors.New("Field 'Clicks' must be a whole number")
+	}
+	if conversions := value("conversions"); conversions != "" {
+		count, err := parseImportCount(conversions)
+		if err != nil {
+			return row, errors.New("Field 'Conversions' must be a whole number")
+		}
+		row.Conversions = &count
+	}
+
+	if err := middleware.Validate.Struct(row); err != nil {
+		return row, errors.New(middleware.ValidationMessage(err))
+	}
+	if isReservedChannel(row.Channel) {
+		return row, fmt.Errorf("Channel '%s' is reserved for built-in channels", row.Channel)
+	}
+	return row, nil
+}
+
+// isReservedChannel reports whether a manual channel name would merge with Direct or connector channels in reports
+func isReservedChannel(name string) bool {
+	for _, channel := range directChannelNames {
+		if strings.EqualFold(name, channel.name) {
+			return true
+		}
+	}
+	for channel, channelName := range channelNames {
+		if strings.EqualFold(name, channel) || strings.EqualFold(name, channelName) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseManualMonth parses a month as YYYY-MM, YYYY-MM-DD, MM.YYYY, DD.MM.YYYY or an Excel date serial number
+// Months in the future are rejected
+func parseManualMonth(value string, now time.Time) (string, error) {
+	if value == "" {
+		return "", errors.New("Field 'Month' is required")
+	}
+
+	date, err := parseImportDate(value, "2006-01", "01.2006")
+	if err != nil {
+		return "", errors.New("Field 'Month' must be in YYYY-MM format")
+	}
+
+	if date.Year() > now.Year() || (date.Year() == now.Year() && date.Month() > now.Month()) {
+		return "", errors.New("Field 'Month' must not be in the future")
+	}
+	return utils.FormatPeriod(date.Year(), int(date.Month())), nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// newTestXLSX builds an XLSX file with a sheet of shared and inline strings
+func newTestXLSX(t *testing.T) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Расходы" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>Канал</t></si><si><t>Месяц</t></si><si><t>Расход</t></si><si><r><t>Радио</t></r><r><t> Европа</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="s"><v>2</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2"><v>45658</v></c><c r="D2"><v>15000.5</v></c></row>
+			<row r="3"><c r="A3" t="inlineStr"><is><t>Наружная реклама</t></is></c><c r="B3" t="str"><v>2025-01</v></c><c r="D3"><v>40000</v></c></row>
+		</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("не удалось создать XLSX: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("не удалось создать XLSX: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestManualCostService_Import(t *testing.T) {
+	middleware.InitValidator()
+
+	t.Run("CSV с точкой с запятой", func(t *testing.T) {
+		repo := &MockChannelRepository{}
+		service := NewManualCostService(repo)
+
+		file := "\xef\xbb\xbfchannel;month;cost;impressions;clicks;conversions\n" +
+			"Радио;2025-01;10 000,50;50000;;4\n" +
+			"\n" +
+			"Инфлюенсеры;02.2025;5000;20000;400;\n"
+		upload, err := service.Import(context.Background(), 1, 7, "costs.csv", strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+		}
+
+		if upload.UserID != 7 || upload.FileName != "costs.csv" || upload.RowsCount != 2 || upload.TotalCost != 15000.5 {
+			t.Errorf("неверный аудит загрузки: %+v", upload)
+		}
+		if !reflect.DeepEqual(upload.Periods, []string{"2025-01", "2025-02"}) || !reflect.DeepEqual(upload.Channels, []string{"Радио", "Инфлюенсеры"}) {
+			t.Errorf("неверные периоды или каналы загрузки: %v, %v", upload.Periods, upload.Channels)
+		}
+		if len(repo.Uploads) != 1 || len(repo.Saved) != 2 {
+			t.Fatalf("ожидались 1 загрузка и 2 строки, получили %d и %d", len(repo.Uploads), len(repo.Saved))
+		}
+
+		radio := repo.Saved[0]
+		if radio.Source != models.ChannelSourceManual || radio.Channel != "Радио" || radio.Year != 2025 || radio.Month != 1 ||
+			radio.Cost != 10000.5 || radio.Clicks != 0 || radio.Conversions == nil || *radio.Conversions != 4 || *radio.CPA != 2500.125 {
+			t.Errorf("неверная строка радио: %+v", radio)
+		}
+		influencers := repo.Saved[1]
+		if influencers.Month != 2 || influencers.CPC != 12.5 || influencers.CTRPct != 2 || influencers.Conversions != nil {
+			t.Errorf("неверная строка инфлюенсеров: %+v", influencers)
+		}
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		repo := &MockChannelRepository{}
+		service := NewManualCostService(repo)
+
+		_, err := service.Import(context.Background(), 1, 7, "costs.XLSX", bytes.NewReader(newTestXLSX(t)))
+		if err != nil {
+			t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+		}
+
+		if len(repo.Saved) != 2 {
+			t.Fatalf("ожидалось 2 строки, получили %d", len(repo.Saved))
+		}
+		// 45658 is the Excel serial number of 2025-01-01
+		if repo.Saved[0].Channel != "Радио Европа" || repo.Saved[0].Year != 2025 || repo.Saved[0].Month != 1 || repo.Saved[0].Cost != 15000.5 {
+			t.Errorf("неверная строка из общих строк: %+v", repo.Saved[0])
+		}
+		if repo.Saved[1].Channel != "Наружная реклама" || repo.Saved[1].Cost != 40000 {
+			t.Errorf("неверная строка из встроенных строк: %+v", repo.Saved[1])
+		}
+	})
+}
+
+func TestManualCostService_Import_Errors(t *testing.T) {
+	middleware.InitValidator()
+	nextMonth := time.Now().AddDate(0, 1, 0).Format("2006-01")
+
+	tests := []struct {
+		name        string
+		fileName    string
+		file        string
+		wantErrText string
+		wantRows    []ImportRowError
+	}{
+		{
+			name:        "неподдерживаемый формат",
+			fileName:    "costs.xls",
+			file:        "channel,month,cost\n",
+			wantErrText: "invalid file: unsupported file format, expected .csv or .xlsx",
+		},
+		{
+			name:        "нет обязательной колонки",
+			fileName:    "costs.csv",
+			file:        "channel,month,impressions\nРадио,2025-01,100\n",
+			wantErrText: "invalid file: missing column cost",
+		},
+		{
+			name:        "нет строк",
+			fileName:    "costs.csv",
+			file:        "channel,month,cost\n",
+			wantErrText: "invalid file: no rows",
+		},
+		{
+			name:     "ошибки в строках",
+			fileName: "costs.csv",
+			file: "channel,month,cost,clicks\n" +
+				"Радио,2025-01,1000,10\n" +
+				",2025-01,1000,\n" +
+				"ТВ,январь,1000,\n" +
+				"ТВ,2025-01,-5,\n" +
+				"ТВ," + nextMonth + ",100,\n" +
+				"ТВ,2025-02,100,1.5\n" +
+				"радио,2025-01-15,500,\n" +
+				"ТВ,2025-03,,\n" +
+				"рся,2025-01,100,\n" +
+				"vk_ads,2025-01,100,\n" +
+				"VK Реклама,2025-01,100,\n",
+			wantRows: []ImportRowError{
+				{Row: 3, Message: "Field 'Channel' is required"},
+				{Row: 4, Message: "Field 'Month' must be in YYYY-MM format"},
+				{Row: 5, Message: "Field 'Cost' must be greater than or equal to 0"},
+				{Row: 6, Message: "Field 'Month' must not be in the future"},
+				{Row: 7, Message: "Field 'Clicks' must be a whole number"},
+				{Row: 8, Message: "Channel 'радио' for 2025-01 is already in row 2"},
+				{Row: 9, Message: "Field 'Cost' is required"},
+				{Row: 10, Message: "Channel 'рся' is reserved for built-in channels"},
+				{Row: 11, Message: "Channel 'vk_ads' is reserved for built-in channels"},
+				{Row: 12, Message: "Channel 'VK Реклама' is reserved for built-in channels"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockChannelRepository{}
+			service := NewManualCostService(repo)
+
+			_, err := service.Import(context.Background(), 1, 7, tt.fileName, strings.NewReader(tt.file))
+			if err == nil {
+				t.Fatal("ожидалась ошибка, но получили nil")
+			}
+			if len(repo.Uploads) != 0 {
+				t.Errorf("при ошибке ничего не должно сохраняться")
+			}
+
+			if tt.wantErrText != "" {
+				if err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили '%s'", tt.wantErrText, err.Error())
+				}
+				return
+			}
+			var importErr *ImportError
+			if !errors.As(err, &importErr) {
+				t.Fatalf("ожидалась ошибка строк, но получили %v", err)
+			}
+			if !reflect.DeepEqual(importErr.Rows, tt.wantRows) {
+				t.Errorf("ожидались ошибки строк\n%+v\nполучили\n%+v", tt.wantRows, importErr.Rows)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ func NewMarketingService(directRepo DirectRepositoryInterface) *MarketingService
 type MarketingData struct {
 	Clicks      MarketingSection `json:"clicks"`
 	Conversions MarketingSection `json:"conversions"`
+	// CostBasis of the latest synced month, costs of all months are in the basis they were synced with
+	CostBasis *models.CostBasis `json:"costBasis,omitempty"`
 }
 
 // MarketingSection represents a section (clicks or conversions) with summary and metrics
@@ -98,11 +100,22 @@ func (s *MarketingService) GetMarketingData(ctx context.Context, projectID uint)
 	data := &MarketingData{
 		Clicks:      s.buildClicksSection(october, september, august),
 		Conversions: s.buildConversionsSection(october, september, august),
+		CostBasis:   latestCostBasis(october, september, august),
 	}
 
 	return data, nil
 }
 
+// latestCostBasis returns the cost basis of the first month that has one, months go from the latest
+func latestCostBasis(months ...*models.DirectTotalsMonthly) *models.CostBasis {
+	for _, totals := range months {
+		if totals != nil && totals.CostBasis != nil {
+			return totals.CostBasis
+		}
+	}
+	return nil
+}
+
 // buildClicksSection builds clicks section with summary and metrics
 func (s *MarketingService) buildClicksSection(oct, sept, aug *models.DirectTotalsMonthly) MarketingSection {
 	var summary []SummaryItem
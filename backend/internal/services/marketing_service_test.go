@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 )
 
@@ -48,6 +50,10 @@ func (m *MockDirectRepositoryForMarketing) CreateCampaign(ctx context.Context, c
 	return nil
 }
 
+func (m *MockDirectRepositoryForMarketing) UpdateCampaign(ctx context.Context, campaign *models.DirectCampaign) error {
+	return nil
+}
+
 func (m *MockDirectRepositoryForMarketing) GetCampaignByID(ctx context.Context, id uint) (*models.DirectCampaign, error) {
 	return nil, nil
 }
@@ -75,6 +81,30 @@ func (m *MockDirectRepositoryForMarketing) SaveTotalsMonthly(totals *models.Dire
 	return nil
 }
 
+func (m *MockDirectRepositoryForMarketing) SaveCampaignDaily(ctx context.Context, metrics []*models.DirectCampaignDaily) error {
+	return nil
+}
+
+func (m *MockDirectRepositoryForMarketing) GetCampaignDaily(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error) {
+	return nil, nil
+}
+
+func (m *MockDirectRepositoryForMarketing) GetAdGroupMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectAdGroupMonthly, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDirectRepositoryForMarketing) ReplaceAdGroupMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectAdGroupMonthly) error {
+	return nil
+}
+
+func (m *MockDirectRepositoryForMarketing) GetKeywordMonthly(ctx context.Context, filter models.DirectStatsFilter, pagination *middleware.Pagination) ([]*models.DirectKeywordMonthly, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockDirectRepositoryForMarketing) ReplaceKeywordMonthly(ctx context.Context, directCampaignIDs []uint, year int, month int, metrics []*models.DirectKeywordMonthly) error {
+	return nil
+}
+
 func TestMarketingService_GetMarketingData(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -135,3 +165,29 @@ func TestMarketingService_GetMarketingData(t *testing.T) {
 		})
 	}
 }
+
+func TestMarketingService_GetMarketingData_CostBasis(t *testing.T) {
+	now := time.Now()
+	mockRepo := &MockDirectRepositoryForMarketing{
+		GetTotalsMonthlyFunc: func(ctx context.Context, projectID uint, year int, month int) (*models.DirectTotalsMonthly, error) {
+			// Current month is not synced yet, previous one was synced with VAT and markup
+			if year == now.Year() && month == int(now.Month()) {
+				return nil, nil
+			}
+			return &models.DirectTotalsMonthly{
+				Clicks:    100,
+				Cost:      1000.0,
+				CostBasis: &models.CostBasis{IncludeVAT: true, MarkupPct: 15},
+			}, nil
+		},
+	}
+	service := NewMarketingService(mockRepo)
+
+	data, err := service.GetMarketingData(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if data.CostBasis == nil || !data.CostBasis.IncludeVAT || data.CostBasis.MarkupPct != 15 {
+		t.Errorf("ожидалась база расходов последнего синхронизированного месяца, получили %+v", data.CostBasis)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// metricaRegionsLimit is the number of top regions requested from every counter
+const metricaRegionsLimit = 50
+
+// metricaBreakdownDimension describes an audience breakdown stored in metrics_breakdown_monthly
+type metricaBreakdownDimension struct {
+	dimension        string // models.Breakdown* value
+	metricaDimension string // Metrica API dimension
+	limit            int    // Rows per counter, 0 - API default
+}
+
+// metricaBreakdownDimensions lists breakdowns synced in addition to age
+var metricaBreakdownDimensions = []metricaBreakdownDimension{
+	{dimension: models.BreakdownGender, metricaDimension: integrations.DimensionGender},
+	{dimension: models.BreakdownDevice, metricaDimension: integrations.DimensionDeviceCategory},
+	{dimension: models.BreakdownCity, metricaDimension: integrations.DimensionRegionCity, limit: metricaRegionsLimit},
+	{dimension: models.BreakdownCountry, metricaDimension: integrations.DimensionRegionCountry, limit: metricaRegionsLimit},
+}
+
+// countersForAggregation returns counters whose data forms project metrics
+//
+// In sum mode all counters are added up. Counters are expected to track different sites,
+// so their audiences are treated as disjoint and users are summed: Metrica has no way to
+// deduplicate visitors across counters. Projects whose counters track the same audience
+// (a site and its mirror, a test counter) should use primary mode, otherwise users and visits
+// are counted twice.
+//
+// In primary mode only the primary counter is used; the first counter is taken when none is marked primary.
+func countersForAggregation(mode string, counters []*models.YandexCounter) []*models.YandexCounter {
+	if mode != models.CounterAggregationPrimary || len(counters) == 0 {
+		return counters
+	}
+	for _, counter := range counters {
+		if counter.IsPrimary {
+			return []*models.YandexCounter{counter}
+		}
+	}
+	return counters[:1]
+}
+
+// metricaBreakdown accumulates breakdown rows (age groups, devices, regions, etc.) of several counters
+// Visits and users are summed, bounce rate and duration are weighted by visits
+type metricaBreakdown struct {
+	values map[string]*metricaDay
+	names  map[string]string
+	order  []string
+}
+
+// newMetricaBreakdown creates an empty breakdown accumulator
+func newMetricaBreakdown() *metricaBreakdown {
+	return &metricaBreakdown{
+		values: make(map[string]*metricaDay),
+		names:  make(map[string]string),
+	}
+}
+
+// add adds values of one counter for a dimension value
+func (b *metricaBreakdown) add(key, name string, visits, users int, bounceRate float64, durationSec int) {
+	value, ok := b.values[key]
+	if !ok {
+		value = &metricaDay{}
+		b.values[key] = value
+		b.order = append(b.order, key)
+	}
+	if b.names[key] == "" {
+		b.names[key] = name
+	}
+	value.addValues(visits, users, bounceRate, durationSec, nil)
+}
+
+// breakdownMetrics converts accumulated values of a dimension to monthly rows
+func (b *metricaBreakdown) breakdownMetrics(projectID uint, year, month int, dimension string) []*models.MetricsBreakdownMonthly {
+	rows := make([]*models.MetricsBreakdownMonthly, 0, len(b.order))
+	for _, key := range b.order {
+		value := b.values[key]
+		bounceRate, duration := value.rates()
+		rows = append(rows, &models.MetricsBreakdownMonthly{
+			ProjectID:             projectID,
+			Year:                  year,
+			Month:                 month,
+			Dimension:             dimension,
+			Value:                 key,
+			Name:                  b.names[key],
+			Visits:                value.visits,
+			Users:                 value.users,
+			BounceRate:            bounceRate,
+			AvgSessionDurationSec: duration,
+		})
+	}
+	return rows
+}
+
+// addBreakdownMetrics adds breakdown of one counter to the accumulator
+// Values are keyed by id (name if API returned no id); undefined values are collected into BreakdownValueUnknown
+func addBreakdownMetrics(breakdown *metricaBreakdown, results []integrations.BreakdownResult) {
+	for _, result := range results {
+		key := result.ID
+		if key == "" {
+			key = result.Name
+		}
+		if key == "" {
+			key = models.BreakdownValueUnknown
+		}
+		breakdown.add(key, result.Name, int(result.Visits), int(result.Users), result.BounceRate, result.AvgSessionDurationSec)
+	}
+}
+
+// ageMetrics converts accumulated age groups to monthly rows
+func (b *metricaBreakdown) ageMetrics(projectID uint, year, month int) []*models.MetricsAgeMonthly {
+	rows := make([]*models.MetricsAgeMonthly, 0, len(b.order))
+	for _, key := range b.order {
+		value := b.values[key]
+		bounceRate, duration := value.rates()
+		rows = append(rows, &models.MetricsAgeMonthly{
+			ProjectID:             projectID,
+			Year:                  year,
+			Month:                 month,
+			AgeGroup:              models.AgeGroup(key),
+			Visits:                value.visits,
+			Users:                 value.users,
+			BounceRate:            bounceRate,
+			AvgSessionDurationSec: duration,
+		})
+	}
+	return rows
+}
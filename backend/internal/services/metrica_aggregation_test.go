@@ -0,0 +1,124 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+func TestCountersForAggregation(t *testing.T) {
+	first := &models.YandexCounter{ID: 1, CounterID: 1001}
+	primary := &models.YandexCounter{ID: 2, CounterID: 1002, IsPrimary: true}
+
+	tests := []struct {
+		name     string
+		mode     string
+		counters []*models.YandexCounter
+		wantIDs  []uint
+	}{
+		{
+			name:     "сумма всех счётчиков",
+			mode:     models.CounterAggregationSum,
+			counters: []*models.YandexCounter{first, primary},
+			wantIDs:  []uint{1, 2},
+		},
+		{
+			name:     "только основной счётчик",
+			mode:     models.CounterAggregationPrimary,
+			counters: []*models.YandexCounter{first, primary},
+			wantIDs:  []uint{2},
+		},
+		{
+			name:     "основной счётчик не выбран",
+			mode:     models.CounterAggregationPrimary,
+			counters: []*models.YandexCounter{first, {ID: 3, CounterID: 1003}},
+			wantIDs:  []uint{1},
+		},
+		{
+			name:     "режим не задан",
+			mode:     "",
+			counters: []*models.YandexCounter{first, primary},
+			wantIDs:  []uint{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countersForAggregation(tt.mode, tt.counters)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("ожидалось %d счётчиков, получили %d", len(tt.wantIDs), len(got))
+			}
+			for i, counter := range got {
+				if counter.ID != tt.wantIDs[i] {
+					t.Errorf("счётчик %d: ожидался ID %d, получили %d", i, tt.wantIDs[i], counter.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricaBreakdown_AgeMetrics(t *testing.T) {
+	ages := newMetricaBreakdown()
+	addAgeMetrics(ages, []integrations.AgeMetricsResult{
+		{AgeGroup: "25-34", Visits: 100, Users: 80, BounceRate: 20, AvgSessionDurationSec: 120},
+		{AgeGroup: "до 18", Visits: 10, Users: 10, BounceRate: 50, AvgSessionDurationSec: 30},
+	})
+	addAgeMetrics(ages, []integrations.AgeMetricsResult{
+		{AgeGroup: "25-34", Visits: 300, Users: 200, BounceRate: 40, AvgSessionDurationSec: 40},
+		{AgeGroup: "unknown", Visits: 30, Users: 25, BounceRate: 30, AvgSessionDurationSec: 70},
+	})
+
+	rows := ages.ageMetrics(5, 2025, 3)
+	if len(rows) != 2 {
+		t.Fatalf("ожидалось 2 возрастные группы, получили %d", len(rows))
+	}
+
+	group := rows[0]
+	if group.AgeGroup != models.AgeGroup2534 || group.ProjectID != 5 || group.Year != 2025 || group.Month != 3 {
+		t.Errorf("неверная строка группы: %+v", group)
+	}
+	if group.Visits != 400 || group.Users != 280 {
+		t.Errorf("ожидались суммы 400/280, получили %d/%d", group.Visits, group.Users)
+	}
+	// Duration is weighted by visits instead of being summed across counters
+	if math.Abs(group.BounceRate-35) > 0.0001 || group.AvgSessionDurationSec != 60 {
+		t.Errorf("ожидались взвешенные показатели 35/60, получили %v/%d", group.BounceRate, group.AvgSessionDurationSec)
+	}
+
+	unknown := rows[1]
+	if unknown.AgeGroup != models.AgeGroupUnknown || unknown.Visits != 40 {
+		t.Errorf("неизвестные интервалы должны попасть в unknown: %+v", unknown)
+	}
+	if math.Abs(unknown.BounceRate-35) > 0.0001 || unknown.AvgSessionDurationSec != 60 {
+		t.Errorf("ожидались взвешенные показатели 35/60, получили %v/%d", unknown.BounceRate, unknown.AvgSessionDurationSec)
+	}
+}
+
+func TestMetricaBreakdown_BreakdownMetrics(t *testing.T) {
+	devices := newMetricaBreakdown()
+	addBreakdownMetrics(devices, []integrations.BreakdownResult{
+		{ID: "mobile", Name: "Смартфоны", Visits: 100, Users: 90, BounceRate: 20, AvgSessionDurationSec: 60},
+		{ID: "", Name: "", Visits: 10, Users: 10},
+	})
+	addBreakdownMetrics(devices, []integrations.BreakdownResult{
+		{ID: "mobile", Name: "Смартфоны", Visits: 100, Users: 50, BounceRate: 40, AvgSessionDurationSec: 120},
+	})
+
+	rows := devices.breakdownMetrics(5, 2025, 3, models.BreakdownDevice)
+	if len(rows) != 2 {
+		t.Fatalf("ожидалось 2 значения, получили %d", len(rows))
+	}
+
+	mobile := rows[0]
+	if mobile.Dimension != models.BreakdownDevice || mobile.Value != "mobile" || mobile.Name != "Смартфоны" || mobile.ProjectID != 5 {
+		t.Errorf("неверная строка разреза: %+v", mobile)
+	}
+	if mobile.Visits != 200 || mobile.Users != 140 || math.Abs(mobile.BounceRate-30) > 0.0001 || mobile.AvgSessionDurationSec != 90 {
+		t.Errorf("ожидались суммы и взвешенные показатели 200/140/30/90, получили %+v", mobile)
+	}
+	if rows[1].Value != models.BreakdownValueUnknown {
+		t.Errorf("значение без id должно попасть в unknown: %+v", rows[1])
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// maxMetricaLogDays limits the period of one Logs API load
+const maxMetricaLogDays = 366
+
+// metricaLogRequiredFields are always requested, they identify and date a visit
+var metricaLogRequiredFields = []string{"ym:s:visitID", "ym:s:date", "ym:s:dateTime"}
+
+// metricaLogFields maps configurable field names to Logs API visit fields
+// Documentation: https://yandex.ru/dev/metrika/doc/api2/logs/fields/visits.html
+var metricaLogFields = map[string][]string{
+	"client_id":      {"ym:s:clientID"},
+	"new_user":       {"ym:s:isNewUser"},
+	"start_url":      {"ym:s:startURL"},
+	"end_url":        {"ym:s:endURL"},
+	"page_views":     {"ym:s:pageViews"},
+	"duration":       {"ym:s:visitDuration"},
+	"bounce":         {"ym:s:bounce"},
+	"traffic_source": {"ym:s:lastTrafficSource", "ym:s:lastSearchEngine", "ym:s:lastAdvEngine"},
+	"utm":            {"ym:s:UTMSource", "ym:s:UTMMedium", "ym:s:UTMCampaign"},
+	"goals":          {"ym:s:goalsID"},
+	"device":         {"ym:s:deviceCategory"},
+	"region":         {"ym:s:regionCity"},
+}
+
+// DefaultMetricaLogFields are loaded when neither config nor task specify fields
+var DefaultMetricaLogFields = []string{"client_id", "new_user", "start_url", "traffic_source", "goals", "page_views", "duration", "bounce"}
+
+// MetricaLogFields converts configurable field names to Logs API fields including required ones
+func MetricaLogFields(names []string) ([]string, error) {
+	fields := append([]string{}, metricaLogRequiredFields...)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		apiFields, ok := metricaLogFields[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid log field: %s", name)
+		}
+		seen[name] = true
+		fields = append(fields, apiFields...)
+	}
+	return fields, nil
+}
+
+// MetricaLogsService loads raw visits from Metrica Logs API into local storage
+// Stored visits allow custom aggregations such as path analysis or new vs returning users
+type MetricaLogsService struct {
+	counterRepo CounterRepositoryInterface
+	visitRepo   MetricaVisitRepositoryInterface
+	credentials *OAuthCredentialService
+	fields      []string // Default field names, see metricaLogFields
+}
+
+// NewMetricaLogsService creates a new Metrica logs service
+// fields are field names used when a load does not specify its own, DefaultMetricaLogFields if empty
+func NewMetricaLogsService(counterRepo CounterRepositoryInterface, visitRepo MetricaVisitRepositoryInterface, credentials *OAuthCredentialService, fields []string) *MetricaLogsService {
+	if len(fields) == 0 {
+		fields = DefaultMetricaLogFields
+	}
+	return &MetricaLogsService{
+		counterRepo: counterRepo,
+		visitRepo:   visitRepo,
+		credentials: credentials,
+		fields:      fields,
+	}
+}
+
+// LoadVisits loads raw visits of all project counters for a period (YYYY-MM-DD, inclusive)
+// Previously loaded visits of the period are replaced. Returns number of saved visits
+func (s *MetricaLogsService) LoadVisits(ctx context.Context, projectID uint, dateFrom, dateTo string, fieldNames []string) (int, error) {
+	from, to, err := parseMetricaLogPeriod(dateFrom, dateTo, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(fieldNames) == 0 {
+		fieldNames = s.fields
+	}
+	fields, err := MetricaLogFields(fieldNames)
+	if err != nil {
+		return 0, err
+	}
+
+	counters, err := s.counterRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get counters: %w", err)
+	}
+	if len(counters) == 0 {
+		return 0, nil
+	}
+
+	saved := 0
+	loaded := 0
+	var lastErr error
+	for _, counter := range counters {
+		count, err := s.loadCounterVisits(ctx, projectID, counter, fields, from, to)
+		saved += count
+		if err != nil {
+			if logger.Log != nil {
+				logger.Log.Warn("Failed to load Metrica logs",
+					zap.Uint("project_id", projectID),
+					zap.Int64("counter_id", counter.CounterID),
+					zap.Error(err),
+				)
+			}
+			lastErr = err
+			continue
+		}
+		loaded++
+	}
+
+	if loaded == 0 {
+		return saved, fmt.Errorf("failed to load logs of any counter: %w", lastErr)
+	}
+	return saved, nil
+}
+
+// loadCounterVisits replaces visits of one counter with rows downloaded from Logs API
+// The period is loaded month by month, each month is a separate log request and transaction,
+// so a failed month keeps its old visits and months loaded before it are kept
+func (s *MetricaLogsService) loadCounterVisits(ctx context.Context, projectID uint, counter *models.YandexCounter, fields []string, from, to time.Time) (int, error) {
+	token, err := s.credentials.ResolveToken(ctx, projectID, counter.OAuthCredentialID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve OAuth token: %w", err)
+	}
+	metricaClient := integrations.NewYandexMetricaClient(token)
+
+	saved := 0
+	for _, period := range metricaLogMonths(from, to) {
+		count, err := s.loadCounterMonth(ctx, metricaClient, projectID, counter.CounterID, fields, period[0], period[1])
+		if err != nil {
+			return saved, err
+		}
+		saved += count
+	}
+	return saved, nil
+}
+
+// loadCounterMonth replaces visits of a counter within a month with rows of one log request
+// The request is prepared before the transaction is opened, so waiting for it does not hold a connection
+func (s *MetricaLogsService) loadCounterMonth(ctx context.Context, metricaClient *integrations.YandexMetricaClient, projectID uint, counterID int64, fields []string, from, to time.Time) (int, error) {
+	request, err := metricaClient.PrepareLogs(ctx, counterID, integrations.LogSourceVisits, fields,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// Clean with a fresh context so that a cancelled task does not leave logs in the quota
+		if err := metricaClient.CleanLogRequest(context.WithoutCancel(ctx), counterID, request.RequestID); err != nil && logger.Log != nil {
+			logger.Log.Warn("Failed to clean Metrica log request",
+				zap.Int64("counter_id", counterID),
+				zap.Int64("request_id", request.RequestID),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	saved := 0
+	err = s.visitRepo.ReplaceVisits(ctx, counterID, from, to, func(save func(visits []*models.MetricaVisit) error) error {
+		return metricaClient.DownloadLogParts(ctx, counterID, request, func(rows []map[string]string) error {
+			visits := make([]*models.MetricaVisit, 0, len(rows))
+			for _, row := range rows {
+				visit, err := metricaVisitFromLog(projectID, counterID, row)
+				if err != nil {
+					return err
+				}
+				visits = append(visits, visit)
+			}
+			if err := save(visits); err != nil {
+				return fmt.Errorf("failed to save visits: %w", err)
+			}
+			saved += len(visits)
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return saved, nil
+}
+
+// metricaLogMonths splits a period into calendar months, the first and the last are cut to the period
+func metricaLogMonths(from, to time.Time) [][2]time.Time {
+	var months [][2]time.Time
+	for start := from; !start.After(to); {
+		end := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		if end.After(to) {
+			end = to
+		}
+		months = append(months, [2]time.Time{start, end})
+		start = end.AddDate(0, 0, 1)
+	}
+	return months
+}
+
+// parseMetricaLogPeriod validates period of a Logs API load
+// Logs API does not return the current day, so the period must end before today
+func parseMetricaLogPeriod(dateFrom, dateTo string, now time.Time) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", dateFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date_from, expected YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", dateTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date_to, expected YYYY-MM-DD")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: date_from is after date_to")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !to.Before(today) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: date_to must be before today")
+	}
+	if to.Sub(from) >= maxMetricaLogDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: more than %d days", maxMetricaLogDays)
+	}
+	return from, to, nil
+}
+
+// metricaVisitFromLog converts a Logs API row to visit model
+func metricaVisitFromLog(projectID uint, counterID int64, row map[string]string) (*models.MetricaVisit, error) {
+	visitID, err := strconv.ParseUint(row["ym:s:visitID"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid visit ID %q: %w", row["ym:s:visitID"], err)
+	}
+	date, err := time.Parse("2006-01-02", row["ym:s:date"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid visit date %q: %w", row["ym:s:date"], err)
+	}
+	dateTime, err := time.Parse("2006-01-02 15:04:05", row["ym:s:dateTime"])
+	if err != nil {
+		dateTime = date
+	}
+
+	visit := &models.MetricaVisit{
+		ProjectID:      projectID,
+		CounterID:      counterID,
+		VisitID:        visitID,
+		Date:           date,
+		DateTime:       dateTime,
+		ClientID:       row["ym:s:clientID"],
+		IsNewUser:      row["ym:s:isNewUser"] == "1",
+		StartURL:       row["ym:s:startURL"],
+		EndURL:         row["ym:s:endURL"],
+		Bounce:         row["ym:s:bounce"] == "1",
+		TrafficSource:  row["ym:s:lastTrafficSource"],
+		SearchEngine:   row["ym:s:lastSearchEngine"],
+		AdvEngine:      row["ym:s:lastAdvEngine"],
+		UTMSource:      row["ym:s:UTMSource"],
+		UTMMedium:      row["ym:s:UTMMedium"],
+		UTMCampaign:    row["ym:s:UTMCampaign"],
+		DeviceCategory: row["ym:s:deviceCategory"],
+		RegionCity:     row["ym:s:regionCity"],
+		GoalIDs:        parseLogIDs(row["ym:s:goalsID"]),
+	}
+	visit.PageViews, _ = strconv.Atoi(row["ym:s:pageViews"])
+	visit.VisitDuration, _ = strconv.Atoi(row["ym:s:visitDuration"])
+
+	return visit, nil
+}
+
+// parseLogIDs parses Logs API array value like "[123,456]"
+func parseLogIDs(value string) []int64 {
+	value = strings.Trim(value, "[]")
+	if value == "" {
+		return nil
+	}
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMetricaLogFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		names       []string
+		wantFields  []string
+		wantErrText string
+	}{
+		{
+			name:       "обязательные поля всегда запрашиваются",
+			names:      nil,
+			wantFields: []string{"ym:s:visitID", "ym:s:date", "ym:s:dateTime"},
+		},
+		{
+			name:  "источник трафика и цели",
+			names: []string{"client_id", "traffic_source", "goals", "client_id"},
+			wantFields: []string{"ym:s:visitID", "ym:s:date", "ym:s:dateTime", "ym:s:clientID",
+				"ym:s:lastTrafficSource", "ym:s:lastSearchEngine", "ym:s:lastAdvEngine", "ym:s:goalsID"},
+		},
+		{
+			name:        "неизвестное поле",
+			names:       []string{"start_url", "ym:s:browser"},
+			wantErrText: "invalid log field: ym:s:browser",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := MetricaLogFields(tt.names)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("ожидалось %v, получили %v", tt.wantFields, fields)
+			}
+			for i := range fields {
+				if fields[i] != tt.wantFields[i] {
+					t.Errorf("ожидалось поле %s на позиции %d, получили %s", tt.wantFields[i], i, fields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseMetricaLogPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		dateFrom    string
+		dateTo      string
+		wantErrText string
+	}{
+		{name: "период до вчерашнего дня", dateFrom: "2025-03-01", dateTo: "2025-03-14"},
+		{name: "сегодняшний день недоступен", dateFrom: "2025-03-01", dateTo: "2025-03-15", wantErrText: "invalid period: date_to must be before today"},
+		{name: "начало позже конца", dateFrom: "2025-03-10", dateTo: "2025-03-01", wantErrText: "invalid period: date_from is after date_to"},
+		{name: "неверный формат даты", dateFrom: "01.03.2025", dateTo: "2025-03-14", wantErrText: "invalid date_from, expected YYYY-MM-DD"},
+		{name: "слишком длинный период", dateFrom: "2023-01-01", dateTo: "2025-03-14", wantErrText: "invalid period: more than 366 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseMetricaLogPeriod(tt.dateFrom, tt.dateTo, now)
+
+			if tt.wantErrText == "" && err != nil {
+				t.Errorf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if tt.wantErrText != "" && (err == nil || err.Error() != tt.wantErrText) {
+				t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+			}
+		})
+	}
+}
+
+func TestMetricaLogMonths(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", value)
+		return parsed
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     [][2]string
+	}{
+		{name: "внутри месяца", from: "2025-03-05", to: "2025-03-14", want: [][2]string{{"2025-03-05", "2025-03-14"}}},
+		{name: "один день", from: "2025-03-31", to: "2025-03-31", want: [][2]string{{"2025-03-31", "2025-03-31"}}},
+		{
+			name: "через границу года",
+			from: "2024-11-20", to: "2025-02-10",
+			want: [][2]string{
+				{"2024-11-20", "2024-11-30"},
+				{"2024-12-01", "2024-12-31"},
+				{"2025-01-01", "2025-01-31"},
+				{"2025-02-01", "2025-02-10"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			months := metricaLogMonths(date(tt.from), date(tt.to))
+
+			if len(months) != len(tt.want) {
+				t.Fatalf("ожидалось %d месяцев, получили %v", len(tt.want), months)
+			}
+			for i, month := range months {
+				got := [2]string{month[0].Format("2006-01-02"), month[1].Format("2006-01-02")}
+				if got != tt.want[i] {
+					t.Errorf("месяц %d: ожидалось %v, получили %v", i, tt.want[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricaVisitFromLog(t *testing.T) {
+	visit, err := metricaVisitFromLog(5, 12345, map[string]string{
+		"ym:s:visitID":           "9007199254740993",
+		"ym:s:date":              "2025-03-02",
+		"ym:s:dateTime":          "2025-03-02 13:45:10",
+		"ym:s:clientID":          "1700000000123456789",
+		"ym:s:isNewUser":         "1",
+		"ym:s:startURL":          "https://example.com/catalog",
+		"ym:s:pageViews":         "4",
+		"ym:s:visitDuration":     "125",
+		"ym:s:bounce":            "0",
+		"ym:s:lastTrafficSource": "ad",
+		"ym:s:goalsID":           "[111,222]",
+	})
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if visit.ProjectID != 5 || visit.CounterID != 12345 || visit.VisitID != 9007199254740993 {
+		t.Errorf("неверные идентификаторы визита: %+v", visit)
+	}
+	if visit.DateTime.Hour() != 13 || visit.Date.Day() != 2 {
+		t.Errorf("неверная дата визита: %v, %v", visit.Date, visit.DateTime)
+	}
+	if !visit.IsNewUser || visit.Bounce || visit.PageViews != 4 || visit.VisitDuration != 125 || visit.TrafficSource != "ad" {
+		t.Errorf("неверные поля визита: %+v", visit)
+	}
+	if len(visit.GoalIDs) != 2 || visit.GoalIDs[0] != 111 || visit.GoalIDs[1] != 222 {
+		t.Errorf("ожидались цели [111 222], получили %v", visit.GoalIDs)
+	}
+
+	if _, err := metricaVisitFromLog(5, 12345, map[string]string{"ym:s:visitID": "", "ym:s:date": "2025-03-02"}); err == nil {
+		t.Error("ожидалась ошибка для визита без ID")
+	}
+}
+
+func TestMetricaLogsService_LoadVisits_InvalidFields(t *testing.T) {
+	service := NewMetricaLogsService(nil, nil, nil, []string{"unknown"})
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	_, err := service.LoadVisits(context.Background(), 1, yesterday, yesterday, nil)
+	if err == nil || err.Error() != "invalid log field: unknown" {
+		t.Errorf("ожидалась ошибка настроенных полей, получили %v", err)
+	}
+}
@@ -10,12 +10,14 @@ import (
 // MetricsService handles business logic for Yandex.Metrica metrics
 type MetricsService struct {
 	metricsRepo MetricsRepositoryInterface
+	directRepo  DirectRepositoryInterface
 }
 
 // NewMetricsService creates a new Metrics service
-func NewMetricsService(metricsRepo MetricsRepositoryInterface) *MetricsService {
+func NewMetricsService(metricsRepo MetricsRepositoryInterface, directRepo DirectRepositoryInterface) *MetricsService {
 	return &MetricsService{
 		metricsRepo: metricsRepo,
+		directRepo:  directRepo,
 	}
 }
 
@@ -83,3 +85,100 @@ func (s *MetricsService) GetMetricsWithData(ctx context.Context, projectID uint)
 
 	return result, nil
 }
+
+// MetricaDailyRow represents Metrica metrics for a day
+type MetricaDailyRow struct {
+	Date        string  `json:"date"`
+	Visits      int     `json:"visits"`
+	Users       int     `json:"users"`
+	BounceRate  float64 `json:"bounce_rate"`
+	AvgSec      int     `json:"avg_sec"`
+	Conversions *int    `json:"conversions,omitempty"`
+}
+
+// DirectDailyRow represents Direct metrics for a day summed over all campaigns of a project
+type DirectDailyRow struct {
+	Date        string   `json:"date"`
+	Impressions int      `json:"impressions"`
+	Clicks      int      `json:"clicks"`
+	CTR         float64  `json:"ctr"`
+	CPC         float64  `json:"cpc"`
+	Cost        float64  `json:"cost"`
+	Conversions *int     `json:"conversions,omitempty"`
+	CPA         *float64 `json:"cpa,omitempty"`
+}
+
+// DailySeries represents daily metrics of a project for a date range
+type DailySeries struct {
+	ProjectID uint              `json:"projectId"`
+	DateFrom  string            `json:"dateFrom"`
+	DateTo    string            `json:"dateTo"`
+	Metrica   []MetricaDailyRow `json:"metrica"`
+	Direct    []DirectDailyRow  `json:"direct"`
+}
+
+// GetDailySeries retrieves daily Metrica and Direct metrics for a date range (inclusive)
+func (s *MetricsService) GetDailySeries(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) (*DailySeries, error) {
+	result := &DailySeries{
+		ProjectID: projectID,
+		DateFrom:  dateFrom.Format("2006-01-02"),
+		DateTo:    dateTo.Format("2006-01-02"),
+		Metrica:   []MetricaDailyRow{},
+		Direct:    []DirectDailyRow{},
+	}
+
+	metricaRows, err := s.metricsRepo.GetDailyMetrics(ctx, projectID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range metricaRows {
+		result.Metrica = append(result.Metrica, MetricaDailyRow{
+			Date:        row.Date.Format("2006-01-02"),
+			Visits:      row.Visits,
+			Users:       row.Users,
+			BounceRate:  row.BounceRate,
+			AvgSec:      row.AvgSessionDurationSec,
+			Conversions: row.Conversions,
+		})
+	}
+
+	campaignRows, err := s.directRepo.GetCampaignDaily(ctx, projectID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are ordered by date, sum campaigns of the same day
+	for _, row := range campaignRows {
+		date := row.Date.Format("2006-01-02")
+		if len(result.Direct) == 0 || result.Direct[len(result.Direct)-1].Date != date {
+			result.Direct = append(result.Direct, DirectDailyRow{Date: date})
+		}
+		day := &result.Direct[len(result.Direct)-1]
+		day.Impressions += row.Impressions
+		day.Clicks += row.Clicks
+		day.Cost += row.Cost
+		if row.Conversions != nil {
+			conversions := *row.Conversions
+			if day.Conversions != nil {
+				conversions += *day.Conversions
+			}
+			day.Conversions = &conversions
+		}
+	}
+
+	for i := range result.Direct {
+		day := &result.Direct[i]
+		if day.Impressions > 0 {
+			day.CTR = float64(day.Clicks) / float64(day.Impressions) * 100
+		}
+		if day.Clicks > 0 {
+			day.CPC = day.Cost / float64(day.Clicks)
+		}
+		if day.Conversions != nil && *day.Conversions > 0 {
+			cpa := day.Cost / float64(*day.Conversions)
+			day.CPA = &cpa
+		}
+	}
+
+	return result, nil
+}
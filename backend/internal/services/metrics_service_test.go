@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/models"
 )
@@ -16,10 +17,21 @@ type MockMetricsRepository struct {
 	GetAgeMetricsFunc                  func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsAgeMonthly, error)
 	GetAgeMetricsByGroupFunc           func(ctx context.Context, projectID uint, year int, month int, ageGroup string) (*models.MetricsAgeMonthly, error)
 	SaveAgeMetricsFunc                 func(metrics *models.MetricsAgeMonthly) error
+	ReplaceAgeMetricsFunc              func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsAgeMonthly) error
 	GetByProjectIDAndAgeFunc           func(ctx context.Context, projectID uint, month string) ([]*models.MetricsAgeMonthly, error)
+	GetBreakdownMetricsFunc            func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsBreakdownMonthly, error)
+	ReplaceBreakdownMetricsFunc        func(ctx context.Context, projectID uint, year int, month int, dimension string, metrics []*models.MetricsBreakdownMonthly) error
 	UpdateFunc                         func(ctx context.Context, metrics *models.MetricsMonthly) error
 	DeleteFunc                         func(ctx context.Context, id uint) error
 	GetAllMonthlyMetricsForProjectFunc func(ctx context.Context, projectID uint) ([]*models.MetricsMonthly, error)
+	SaveDailyMetricsFunc               func(ctx context.Context, metrics []*models.MetricsDaily) error
+	GetDailyMetricsFunc                func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error)
+	GetTrafficSourceMetricsFunc        func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsTrafficSourceMonthly, error)
+	ReplaceTrafficSourceMetricsFunc    func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsTrafficSourceMonthly) error
+	GetGoalMetricsFunc                 func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsGoalMonthly, error)
+	ReplaceGoalMetricsFunc             func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsGoalMonthly) error
+	GetCampaignRevenueFunc             func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsCampaignRevenueMonthly, error)
+	ReplaceCampaignRevenueFunc         func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsCampaignRevenueMonthly) error
 }
 
 func (m *MockMetricsRepository) Create(ctx context.Context, metrics *models.MetricsMonthly) error {
@@ -68,6 +80,13 @@ func (m *MockMetricsRepository) SaveAgeMetrics(metrics *models.MetricsAgeMonthly
 	return nil
 }
 
+func (m *MockMetricsRepository) ReplaceAgeMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsAgeMonthly) error {
+	if m.ReplaceAgeMetricsFunc != nil {
+		return m.ReplaceAgeMetricsFunc(ctx, projectID, year, month, metrics)
+	}
+	return nil
+}
+
 func (m *MockMetricsRepository) GetByProjectIDAndAge(ctx context.Context, projectID uint, month string) ([]*models.MetricsAgeMonthly, error) {
 	if m.GetByProjectIDAndAgeFunc != nil {
 		return m.GetByProjectIDAndAgeFunc(ctx, projectID, month)
@@ -75,6 +94,20 @@ func (m *MockMetricsRepository) GetByProjectIDAndAge(ctx context.Context, projec
 	return nil, nil
 }
 
+func (m *MockMetricsRepository) GetBreakdownMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsBreakdownMonthly, error) {
+	if m.GetBreakdownMetricsFunc != nil {
+		return m.GetBreakdownMetricsFunc(ctx, projectID, year, month)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) ReplaceBreakdownMetrics(ctx context.Context, projectID uint, year int, month int, dimension string, metrics []*models.MetricsBreakdownMonthly) error {
+	if m.ReplaceBreakdownMetricsFunc != nil {
+		return m.ReplaceBreakdownMetricsFunc(ctx, projectID, year, month, dimension, metrics)
+	}
+	return nil
+}
+
 func (m *MockMetricsRepository) Update(ctx context.Context, metrics *models.MetricsMonthly) error {
 	return nil
 }
@@ -90,6 +123,62 @@ func (m *MockMetricsRepository) GetAllMonthlyMetricsForProject(ctx context.Conte
 	return nil, nil
 }
 
+func (m *MockMetricsRepository) SaveDailyMetrics(ctx context.Context, metrics []*models.MetricsDaily) error {
+	if m.SaveDailyMetricsFunc != nil {
+		return m.SaveDailyMetricsFunc(ctx, metrics)
+	}
+	return nil
+}
+
+func (m *MockMetricsRepository) GetDailyMetrics(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error) {
+	if m.GetDailyMetricsFunc != nil {
+		return m.GetDailyMetricsFunc(ctx, projectID, dateFrom, dateTo)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) GetTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsTrafficSourceMonthly, error) {
+	if m.GetTrafficSourceMetricsFunc != nil {
+		return m.GetTrafficSourceMetricsFunc(ctx, projectID, year, month)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) ReplaceTrafficSourceMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsTrafficSourceMonthly) error {
+	if m.ReplaceTrafficSourceMetricsFunc != nil {
+		return m.ReplaceTrafficSourceMetricsFunc(ctx, projectID, year, month, metrics)
+	}
+	return nil
+}
+
+func (m *MockMetricsRepository) GetGoalMetrics(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsGoalMonthly, error) {
+	if m.GetGoalMetricsFunc != nil {
+		return m.GetGoalMetricsFunc(ctx, projectID, year, month)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) ReplaceGoalMetrics(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsGoalMonthly) error {
+	if m.ReplaceGoalMetricsFunc != nil {
+		return m.ReplaceGoalMetricsFunc(ctx, projectID, year, month, metrics)
+	}
+	return nil
+}
+
+func (m *MockMetricsRepository) GetCampaignRevenue(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsCampaignRevenueMonthly, error) {
+	if m.GetCampaignRevenueFunc != nil {
+		return m.GetCampaignRevenueFunc(ctx, projectID, year, month)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) ReplaceCampaignRevenue(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsCampaignRevenueMonthly) error {
+	if m.ReplaceCampaignRevenueFunc != nil {
+		return m.ReplaceCampaignRevenueFunc(ctx, projectID, year, month, metrics)
+	}
+	return nil
+}
+
 func TestMetricsService_GetMetricsWithData(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -130,7 +219,7 @@ func TestMetricsService_GetMetricsWithData(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := tt.mockSetup()
-			service := NewMetricsService(mockRepo)
+			service := NewMetricsService(mockRepo, &MockDirectRepositoryForDirectService{})
 
 			metrics, err := service.GetMetricsWithData(context.Background(), tt.projectID)
 
@@ -149,3 +238,54 @@ func TestMetricsService_GetMetricsWithData(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsService_GetDailySeries(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	conversions := 4
+
+	metricsRepo := &MockMetricsRepository{
+		GetDailyMetricsFunc: func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error) {
+			return []*models.MetricsDaily{
+				{ProjectID: projectID, Date: day1, Visits: 100, Users: 80},
+				{ProjectID: projectID, Date: day2, Visits: 120, Users: 90},
+			}, nil
+		},
+	}
+	directRepo := &MockDirectRepositoryForDirectService{
+		GetCampaignDailyFunc: func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error) {
+			return []*models.DirectCampaignDaily{
+				{DirectCampaignID: 1, Date: day1, Impressions: 1000, Clicks: 10, Cost: 100, Conversions: &conversions},
+				{DirectCampaignID: 2, Date: day1, Impressions: 1000, Clicks: 30, Cost: 300},
+				{DirectCampaignID: 1, Date: day2, Impressions: 500, Clicks: 5, Cost: 50},
+			}, nil
+		},
+	}
+
+	service := NewMetricsService(metricsRepo, directRepo)
+	series, err := service.GetDailySeries(context.Background(), 1, day1, day2)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(series.Metrica) != 2 {
+		t.Fatalf("ожидалось 2 дня Метрики, получили %d", len(series.Metrica))
+	}
+	if len(series.Direct) != 2 {
+		t.Fatalf("ожидалось 2 дня Директа, получили %d", len(series.Direct))
+	}
+
+	first := series.Direct[0]
+	if first.Date != "2025-03-01" || first.Impressions != 2000 || first.Clicks != 40 || first.Cost != 400 {
+		t.Errorf("неверные суммы за первый день: %+v", first)
+	}
+	if first.CTR != 2 || first.CPC != 10 {
+		t.Errorf("неверные CTR/CPC за первый день: %v/%v", first.CTR, first.CPC)
+	}
+	if first.Conversions == nil || *first.Conversions != 4 || first.CPA == nil || *first.CPA != 100 {
+		t.Errorf("неверные конверсии за первый день: %v/%v", first.Conversions, first.CPA)
+	}
+	if series.Direct[1].Conversions != nil {
+		t.Errorf("конверсии второго дня должны отсутствовать")
+	}
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// ErrNoOAuthToken is returned when no token is configured for a project
+var ErrNoOAuthToken = errors.New("no Yandex OAuth token configured for project")
+
+// tokenExpiringWindow is how long before expiry a token is refreshed and reported as expiring
+const tokenExpiringWindow = 14 * 24 * time.Hour
+
+// YandexOAuthClientInterface defines Yandex OAuth operations used by the service
+type YandexOAuthClientInterface interface {
+	RefreshToken(ctx context.Context, refreshToken string) (*integrations.OAuthToken, error)
+	GetUserInfo(ctx context.Context, accessToken string) (*integrations.YandexUserInfo, error)
+}
+
+// OAuthCredentialService handles storage and resolution of OAuth tokens
+type OAuthCredentialService struct {
+	credentialRepo OAuthCredentialRepositoryInterface
+	directRepo     DirectRepositoryInterface
+	oauthClient    YandexOAuthClientInterface
+	appKey         string
+	fallbackToken  string
+}
+
+// NewOAuthCredentialService creates a new OAuth credential service
+// fallbackToken is the legacy YANDEX_OAUTH_TOKEN used when nothing is stored in DB
+func NewOAuthCredentialService(
+	credentialRepo OAuthCredentialRepositoryInterface,
+	directRepo DirectRepositoryInterface,
+	oauthClient YandexOAuthClientInterface,
+	appKey string,
+	fallbackToken string,
+) *OAuthCredentialService {
+	return &OAuthCredentialService{
+		credentialRepo: credentialRepo,
+		directRepo:     directRepo,
+		oauthClient:    oauthClient,
+		appKey:         appKey,
+		fallbackToken:  fallbackToken,
+	}
+}
+
+// SaveToken encrypts and stores a token for a project (nil for agency-wide)
+// Token owner login is requested from Yandex; existing credential with the same login is updated
+func (s *OAuthCredentialService) SaveToken(ctx context.Context, projectID *uint, token *integrations.OAuthToken) (*models.OAuthCredential, error) {
+	if token == nil || token.AccessToken == "" {
+		return nil, errors.New("access token is required")
+	}
+
+	info, err := s.oauthClient.GetUserInfo(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token owner: %w", err)
+	}
+
+	credential, err := s.credentialRepo.GetByProjectAndLogin(ctx, projectID, models.OAuthProviderYandex, info.Login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential: %w", err)
+	}
+
+	isNew := credential == nil
+	if isNew {
+		credential = &models.OAuthCredential{
+			ProjectID: projectID,
+			Provider:  models.OAuthProviderYandex,
+			Login:     info.Login,
+		}
+	}
+
+	if err := s.applyToken(credential, token, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		if err := s.credentialRepo.Create(ctx, credential); err != nil {
+			return nil, fmt.Errorf("failed to create credential: %w", err)
+		}
+		return credential, nil
+	}
+
+	if err := s.credentialRepo.Update(ctx, credential); err != nil {
+		return nil, fmt.Errorf("failed to update credential: %w", err)
+	}
+	return credential, nil
+}
+
+// applyToken encrypts token fields into credential and resets its health status
+// Refresh token is kept when Yandex does not issue a new one
+func (s *OAuthCredentialService) applyToken(credential *models.OAuthCredential, token *integrations.OAuthToken, now time.Time) error {
+	accessToken, err := utils.EncryptString(s.appKey, token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token: %w", err)
+	}
+	credential.AccessToken = accessToken
+
+	if token.RefreshToken != "" {
+		refreshToken, err := utils.EncryptString(s.appKey, token.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt refresh token: %w", err)
+		}
+		credential.RefreshToken = refreshToken
+	}
+
+	credential.ExpiresAt = token.ExpiresAt(now)
+	credential.Status = credentialStatus(credential, now)
+	credential.LastError = ""
+	credential.LastCheckedAt = &now
+	return nil
+}
+
+// RefreshExpiringTokens refreshes tokens that expire within tokenExpiringWindow
+// Returns number of refreshed credentials; failures are recorded on the credential
+func (s *OAuthCredentialService) RefreshExpiringTokens(ctx context.Context) (int, error) {
+	now := time.Now()
+	credentials, err := s.credentialRepo.GetRefreshable(ctx, now.Add(tokenExpiringWindow))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get refreshable credentials: %w", err)
+	}
+
+	refreshed := 0
+	for _, credential := range credentials {
+		if err := s.refreshCredential(ctx, credential, now); err != nil {
+			if logger.Log != nil {
+				logger.Log.Warn("Failed to refresh OAuth token",
+					zap.Uint("credential_id", credential.ID),
+					zap.String("login", credential.Login),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// refreshCredential exchanges refresh token of a credential and stores the new token
+func (s *OAuthCredentialService) refreshCredential(ctx context.Context, credential *models.OAuthCredential, now time.Time) error {
+	refreshToken, err := utils.DecryptString(s.appKey, credential.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt refresh token: %w", err)
+	}
+
+	token, refreshErr := s.oauthClient.RefreshToken(ctx, refreshToken)
+	if refreshErr != nil {
+		credential.LastError = refreshErr.Error()
+		credential.LastCheckedAt = &now
+		if errors.Is(refreshErr, integrations.ErrRefreshTokenInvalid) {
+			credential.Status = models.OAuthStatusRevoked
+		} else {
+			credential.Status = credentialStatus(credential, now)
+		}
+		if err := s.credentialRepo.Update(ctx, credential); err != nil {
+			return fmt.Errorf("failed to update credential: %w", err)
+		}
+		return refreshErr
+	}
+
+	if err := s.applyToken(credential, token, now); err != nil {
+		return err
+	}
+	return s.credentialRepo.Update(ctx, credential)
+}
+
+// CheckTokensHealth validates every stored token with Yandex and records its status
+func (s *OAuthCredentialService) CheckTokensHealth(ctx context.Context) error {
+	credentials, err := s.credentialRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	for _, credential := range credentials {
+		now := time.Now()
+		credential.LastCheckedAt = &now
+
+		accessToken, err := utils.DecryptString(s.appKey, credential.AccessToken)
+		if err == nil {
+			_, err = s.oauthClient.GetUserInfo(ctx, accessToken)
+		}
+
+		switch {
+		case err == nil:
+			credential.Status = credentialStatus(credential, now)
+			credential.LastError = ""
+		case errors.Is(err, integrations.ErrTokenRevoked):
+			credential.Status = models.OAuthStatusRevoked
+			if credential.ExpiresAt != nil && credential.ExpiresAt.Before(now) {
+				credential.Status = models.OAuthStatusExpired
+			}
+			credential.LastError = err.Error()
+		default:
+			// Network or decryption problem - keep previous status
+			credential.LastError = err.Error()
+		}
+
+		if err := s.credentialRepo.UpdateHealth(ctx, credential); err != nil {
+			return fmt.Errorf("failed to update credential %d: %w", credential.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCredentialsHealth returns stored credentials with status adjusted for expiry
+// projectID nil returns credentials of all projects
+func (s *OAuthCredentialService) GetCredentialsHealth(ctx context.Context, projectID *uint) ([]*models.OAuthCredential, error) {
+	var credentials []*models.OAuthCredential
+	var err error
+	if projectID != nil {
+		credentials, err = s.credentialRepo.GetByProjectID(ctx, *projectID)
+	} else {
+		credentials, err = s.credentialRepo.GetAll(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	now := time.Now()
+	for _, credential := range credentials {
+		if credential.Status != models.OAuthStatusRevoked {
+			credential.Status = credentialStatus(credential, now)
+		}
+	}
+
+	return credentials, nil
+}
+
+// credentialStatus derives status of a working token from its expiry time
+func credentialStatus(credential *models.OAuthCredential, now time.Time) string {
+	if credential.ExpiresAt == nil {
+		return models.OAuthStatusValid
+	}
+	if credential.ExpiresAt.Before(now) {
+		return models.OAuthStatusExpired
+	}
+	if credential.ExpiresAt.Before(now.Add(tokenExpiringWindow)) {
+		return models.OAuthStatusExpiring
+	}
+	return models.OAuthStatusValid
+}
+
+// BindDirectAccount makes a Direct account use a specific credential
+func (s *OAuthCredentialService) BindDirectAccount(ctx context.Context, accountID uint, credentialID uint) error {
+	account, err := s.directRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get Direct account: %w", err)
+	}
+
+	credential, err := s.credentialRepo.GetByID(ctx, credentialID)
+	if err != nil {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+	if credential == nil {
+		return errors.New("credential not found")
+	}
+	if credential.ProjectID != nil && *credential.ProjectID != account.ProjectID {
+		return errors.New("credential belongs to another project")
+	}
+
+	account.OAuthCredentialID = &credential.ID
+	return s.directRepo.UpdateAccount(ctx, account)
+}
+
+// GetProjectCredentials retrieves all credentials of a project (tokens are not exposed)
+func (s *OAuthCredentialService) GetProjectCredentials(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error) {
+	return s.credentialRepo.GetByProjectID(ctx, projectID)
+}
+
+// DeleteCredential deletes a credential of a project
+// Sources bound to it are unbound and fall back to the project or agency credential
+func (s *OAuthCredentialService) DeleteCredential(ctx context.Context, projectID uint, id uint) error {
+	credential, err := s.credentialRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+	if credential == nil || credential.ProjectID == nil || *credential.ProjectID != projectID {
+		return errors.New("credential not found")
+	}
+	return s.credentialRepo.Delete(ctx, id)
+}
+
+// ResolveToken returns decrypted token for a project (0 resolves agency-wide token)
+// Order: explicit credential, project credential, agency-wide credential, YANDEX_OAUTH_TOKEN
+// An explicit credential that no longer exists is skipped, as sources are unbound when it is deleted
+func (s *OAuthCredentialService) ResolveToken(ctx context.Context, projectID uint, credentialID *uint) (string, error) {
+	if credentialID != nil {
+		credential, err := s.credentialRepo.GetByID(ctx, *credentialID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get credential %d: %w", *credentialID, err)
+		}
+		if credential != nil {
+			return s.decrypt(credential)
+		}
+		if logger.Log != nil {
+			logger.Log.Warn("Bound OAuth credential not found, using project credential",
+				zap.Uint("project_id", projectID),
+				zap.Uint("credential_id", *credentialID),
+			)
+		}
+	}
+
+	credential, err := s.credentialRepo.GetDefaultForProject(ctx, &projectID, models.OAuthProviderYandex)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project credential: %w", err)
+	}
+	if credential != nil {
+		return s.decrypt(credential)
+	}
+
+	credential, err = s.credentialRepo.GetDefaultForProject(ctx, nil, models.OAuthProviderYandex)
+	if err != nil {
+		return "", fmt.Errorf("failed to get agency credential: %w", err)
+	}
+	if credential != nil {
+		return s.decrypt(credential)
+	}
+
+	if s.fallbackToken != "" {
+		return s.fallbackToken, nil
+	}
+
+	return "", ErrNoOAuthToken
+}
+
+// decrypt returns plaintext access token of a credential
+func (s *OAuthCredentialService) decrypt(credential *models.OAuthCredential) (string, error) {
+	token, err := utils.DecryptString(s.appKey, credential.AccessToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt credential %d: %w", credential.ID, err)
+	}
+	return token, nil
+}
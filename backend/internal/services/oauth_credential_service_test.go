@@ -0,0 +1,473 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+)
+
+const testAppKey = "test-app-key"
+
+// MockOAuthCredentialRepository implements OAuthCredentialRepositoryInterface for testing
+type MockOAuthCredentialRepository struct {
+	CreateFunc               func(ctx context.Context, credential *models.OAuthCredential) error
+	UpdateFunc               func(ctx context.Context, credential *models.OAuthCredential) error
+	UpdateHealthFunc         func(ctx context.Context, credential *models.OAuthCredential) error
+	GetByIDFunc              func(ctx context.Context, id uint) (*models.OAuthCredential, error)
+	GetByProjectIDFunc       func(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error)
+	GetByProjectAndLoginFunc func(ctx context.Context, projectID *uint, provider, login string) (*models.OAuthCredential, error)
+	GetDefaultForProjectFunc func(ctx context.Context, projectID *uint, provider string) (*models.OAuthCredential, error)
+	GetAllFunc               func(ctx context.Context) ([]*models.OAuthCredential, error)
+	GetRefreshableFunc       func(ctx context.Context, expiresBefore time.Time) ([]*models.OAuthCredential, error)
+	DeleteFunc               func(ctx context.Context, id uint) error
+}
+
+func (m *MockOAuthCredentialRepository) Create(ctx context.Context, credential *models.OAuthCredential) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, credential)
+	}
+	return nil
+}
+
+func (m *MockOAuthCredentialRepository) Update(ctx context.Context, credential *models.OAuthCredential) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, credential)
+	}
+	return nil
+}
+
+func (m *MockOAuthCredentialRepository) UpdateHealth(ctx context.Context, credential *models.OAuthCredential) error {
+	if m.UpdateHealthFunc != nil {
+		return m.UpdateHealthFunc(ctx, credential)
+	}
+	return nil
+}
+
+func (m *MockOAuthCredentialRepository) GetByID(ctx context.Context, id uint) (*models.OAuthCredential, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) GetByProjectID(ctx context.Context, projectID uint) ([]*models.OAuthCredential, error) {
+	if m.GetByProjectIDFunc != nil {
+		return m.GetByProjectIDFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) GetByProjectAndLogin(ctx context.Context, projectID *uint, provider, login string) (*models.OAuthCredential, error) {
+	if m.GetByProjectAndLoginFunc != nil {
+		return m.GetByProjectAndLoginFunc(ctx, projectID, provider, login)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) GetDefaultForProject(ctx context.Context, projectID *uint, provider string) (*models.OAuthCredential, error) {
+	if m.GetDefaultForProjectFunc != nil {
+		return m.GetDefaultForProjectFunc(ctx, projectID, provider)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) GetAll(ctx context.Context) ([]*models.OAuthCredential, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) GetRefreshable(ctx context.Context, expiresBefore time.Time) ([]*models.OAuthCredential, error) {
+	if m.GetRefreshableFunc != nil {
+		return m.GetRefreshableFunc(ctx, expiresBefore)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuthCredentialRepository) Delete(ctx context.Context, id uint) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+// MockYandexOAuthClient implements YandexOAuthClientInterface for testing
+type MockYandexOAuthClient struct {
+	RefreshTokenFunc func(ctx context.Context, refreshToken string) (*integrations.OAuthToken, error)
+	GetUserInfoFunc  func(ctx context.Context, accessToken string) (*integrations.YandexUserInfo, error)
+}
+
+func (m *MockYandexOAuthClient) RefreshToken(ctx context.Context, refreshToken string) (*integrations.OAuthToken, error) {
+	if m.RefreshTokenFunc != nil {
+		return m.RefreshTokenFunc(ctx, refreshToken)
+	}
+	return nil, nil
+}
+
+func (m *MockYandexOAuthClient) GetUserInfo(ctx context.Context, accessToken string) (*integrations.YandexUserInfo, error) {
+	if m.GetUserInfoFunc != nil {
+		return m.GetUserInfoFunc(ctx, accessToken)
+	}
+	return &integrations.YandexUserInfo{Login: "user"}, nil
+}
+
+func encryptedCredential(t *testing.T, id uint, projectID *uint, token string) *models.OAuthCredential {
+	t.Helper()
+	encrypted, err := utils.EncryptString(testAppKey, token)
+	if err != nil {
+		t.Fatalf("EncryptString() error = %v", err)
+	}
+	return &models.OAuthCredential{ID: id, ProjectID: projectID, Provider: models.OAuthProviderYandex, AccessToken: encrypted}
+}
+
+func TestOAuthCredentialService_ResolveToken(t *testing.T) {
+	projectID := uint(1)
+	credentialID := uint(7)
+
+	tests := []struct {
+		name          string
+		credentialID  *uint
+		fallbackToken string
+		mockSetup     func(t *testing.T) *MockOAuthCredentialRepository
+		wantToken     string
+		wantErr       bool
+	}{
+		{
+			name:         "явно указанный credential",
+			credentialID: &credentialID,
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{
+					GetByIDFunc: func(ctx context.Context, id uint) (*models.OAuthCredential, error) {
+						return encryptedCredential(t, id, &projectID, "explicit-token"), nil
+					},
+				}
+			},
+			wantToken: "explicit-token",
+		},
+		{
+			name:         "удалённый credential заменяется токеном проекта",
+			credentialID: &credentialID,
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{
+					GetDefaultForProjectFunc: func(ctx context.Context, pid *uint, provider string) (*models.OAuthCredential, error) {
+						return encryptedCredential(t, 1, pid, "project-token"), nil
+					},
+				}
+			},
+			wantToken: "project-token",
+		},
+		{
+			name: "токен проекта",
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{
+					GetDefaultForProjectFunc: func(ctx context.Context, pid *uint, provider string) (*models.OAuthCredential, error) {
+						if pid == nil {
+							return encryptedCredential(t, 2, nil, "agency-token"), nil
+						}
+						return encryptedCredential(t, 1, pid, "project-token"), nil
+					},
+				}
+			},
+			wantToken: "project-token",
+		},
+		{
+			name: "токен агентства при отсутствии токена проекта",
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{
+					GetDefaultForProjectFunc: func(ctx context.Context, pid *uint, provider string) (*models.OAuthCredential, error) {
+						if pid == nil {
+							return encryptedCredential(t, 2, nil, "agency-token"), nil
+						}
+						return nil, nil
+					},
+				}
+			},
+			wantToken: "agency-token",
+		},
+		{
+			name:          "fallback на YANDEX_OAUTH_TOKEN",
+			fallbackToken: "env-token",
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{}
+			},
+			wantToken: "env-token",
+		},
+		{
+			name: "токен не настроен",
+			mockSetup: func(t *testing.T) *MockOAuthCredentialRepository {
+				return &MockOAuthCredentialRepository{}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOAuthCredentialService(tt.mockSetup(t), &MockDirectRepositoryForDirectService{}, &MockYandexOAuthClient{}, testAppKey, tt.fallbackToken)
+
+			token, err := service.ResolveToken(context.Background(), projectID, tt.credentialID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if token != tt.wantToken {
+				t.Errorf("ResolveToken() = %q, want %q", token, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestOAuthCredentialService_CheckTokensHealth(t *testing.T) {
+	projectID := uint(1)
+	first := encryptedCredential(t, 1, &projectID, "first-token")
+	second := encryptedCredential(t, 2, &projectID, "second-token")
+	credentials := []*models.OAuthCredential{first, second}
+
+	checked := make(map[uint]*models.OAuthCredential)
+	repo := &MockOAuthCredentialRepository{
+		GetAllFunc: func(ctx context.Context) ([]*models.OAuthCredential, error) {
+			return credentials, nil
+		},
+		// Like the repository: the first connected credential that is not revoked
+		GetDefaultForProjectFunc: func(ctx context.Context, pid *uint, provider string) (*models.OAuthCredential, error) {
+			for _, credential := range credentials {
+				if pid != nil && credential.Status != models.OAuthStatusRevoked {
+					return credential, nil
+				}
+			}
+			return nil, nil
+		},
+		UpdateFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+			t.Errorf("проверка токенов не должна обновлять credential %d целиком", credential.ID)
+			return nil
+		},
+		UpdateHealthFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+			checked[credential.ID] = credential
+			return nil
+		},
+	}
+	oauthClient := &MockYandexOAuthClient{
+		GetUserInfoFunc: func(ctx context.Context, accessToken string) (*integrations.YandexUserInfo, error) {
+			if accessToken == "first-token" {
+				return nil, errors.New("timeout")
+			}
+			return &integrations.YandexUserInfo{Login: "user"}, nil
+		},
+	}
+	service := NewOAuthCredentialService(repo, &MockDirectRepositoryForDirectService{}, oauthClient, testAppKey, "")
+
+	before, err := service.ResolveToken(context.Background(), projectID, nil)
+	if err != nil {
+		t.Fatalf("ResolveToken() error = %v", err)
+	}
+	if err := service.CheckTokensHealth(context.Background()); err != nil {
+		t.Fatalf("CheckTokensHealth() error = %v", err)
+	}
+	after, err := service.ResolveToken(context.Background(), projectID, nil)
+	if err != nil {
+		t.Fatalf("ResolveToken() error = %v", err)
+	}
+
+	if before != "first-token" || after != before {
+		t.Errorf("токен проекта не должен меняться после проверки: было %q, стало %q", before, after)
+	}
+	if len(checked) != 2 || checked[1].LastError != "timeout" || checked[1].LastCheckedAt == nil {
+		t.Errorf("результаты проверки должны сохраняться для обоих credentials: %+v", checked)
+	}
+	if checked[2] == nil || checked[2].Status != models.OAuthStatusValid {
+		t.Errorf("ожидался статус valid второго credential: %+v", checked[2])
+	}
+}
+
+func TestOAuthCredentialService_DeleteCredential(t *testing.T) {
+	projectID := uint(1)
+	otherProjectID := uint(2)
+
+	tests := []struct {
+		name        string
+		credential  *models.OAuthCredential
+		repoErr     error
+		wantErrText string
+		wantDeleted bool
+	}{
+		{
+			name:        "credential проекта",
+			credential:  &models.OAuthCredential{ID: 7, ProjectID: &projectID},
+			wantDeleted: true,
+		},
+		{
+			name:        "credential не найден",
+			wantErrText: "credential not found",
+		},
+		{
+			name:        "credential другого проекта",
+			credential:  &models.OAuthCredential{ID: 7, ProjectID: &otherProjectID},
+			wantErrText: "credential not found",
+		},
+		{
+			name:        "credential агентства",
+			credential:  &models.OAuthCredential{ID: 7},
+			wantErrText: "credential not found",
+		},
+		{
+			name:        "ошибка БД",
+			repoErr:     errors.New("connection refused"),
+			wantErrText: "failed to get credential: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			repo := &MockOAuthCredentialRepository{
+				GetByIDFunc: func(ctx context.Context, id uint) (*models.OAuthCredential, error) {
+					return tt.credential, tt.repoErr
+				},
+				DeleteFunc: func(ctx context.Context, id uint) error {
+					deleted = true
+					return nil
+				},
+			}
+			service := NewOAuthCredentialService(repo, &MockDirectRepositoryForDirectService{}, &MockYandexOAuthClient{}, testAppKey, "")
+
+			err := service.DeleteCredential(context.Background(), projectID, 7)
+			if tt.wantErrText == "" && err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if tt.wantErrText != "" && (err == nil || err.Error() != tt.wantErrText) {
+				t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("ожидалось удаление %v, получили %v", tt.wantDeleted, deleted)
+			}
+		})
+	}
+}
+
+func TestOAuthCredentialService_SaveToken(t *testing.T) {
+	projectID := uint(1)
+
+	t.Run("новый токен сохраняется зашифрованным", func(t *testing.T) {
+		var created *models.OAuthCredential
+		repo := &MockOAuthCredentialRepository{
+			CreateFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+				created = credential
+				return nil
+			},
+		}
+		service := NewOAuthCredentialService(repo, &MockDirectRepositoryForDirectService{}, &MockYandexOAuthClient{}, testAppKey, "")
+
+		if _, err := service.SaveToken(context.Background(), &projectID, &integrations.OAuthToken{AccessToken: "secret", RefreshToken: "refresh", ExpiresIn: 3600 * 24 * 365}); err != nil {
+			t.Fatalf("SaveToken() error = %v", err)
+		}
+		if created == nil {
+			t.Fatal("expected credential to be created")
+		}
+		if created.AccessToken == "secret" {
+			t.Error("token must not be stored in plaintext")
+		}
+		decrypted, err := utils.DecryptString(testAppKey, created.AccessToken)
+		if err != nil || decrypted != "secret" {
+			t.Errorf("DecryptString() = %q, %v", decrypted, err)
+		}
+		if created.Login != "user" {
+			t.Errorf("Login = %q, want user", created.Login)
+		}
+		if created.RefreshToken == "" || created.ExpiresAt == nil {
+			t.Error("expected refresh token and expiry to be stored")
+		}
+		if created.Status != models.OAuthStatusValid {
+			t.Errorf("Status = %q, want %q", created.Status, models.OAuthStatusValid)
+		}
+	})
+
+	t.Run("существующий токен обновляется", func(t *testing.T) {
+		updated := false
+		repo := &MockOAuthCredentialRepository{
+			GetByProjectAndLoginFunc: func(ctx context.Context, pid *uint, provider, login string) (*models.OAuthCredential, error) {
+				return encryptedCredential(t, 5, pid, "old"), nil
+			},
+			UpdateFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+				updated = credential.ID == 5
+				return nil
+			},
+			CreateFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+				t.Error("Create should not be called")
+				return nil
+			},
+		}
+		service := NewOAuthCredentialService(repo, &MockDirectRepositoryForDirectService{}, &MockYandexOAuthClient{}, testAppKey, "")
+
+		if _, err := service.SaveToken(context.Background(), &projectID, &integrations.OAuthToken{AccessToken: "new"}); err != nil {
+			t.Fatalf("SaveToken() error = %v", err)
+		}
+		if !updated {
+			t.Error("expected existing credential to be updated")
+		}
+	})
+}
+
+func TestOAuthCredentialService_RefreshExpiringTokens(t *testing.T) {
+	projectID := uint(1)
+	soon := time.Now().Add(24 * time.Hour)
+
+	newCredential := func(t *testing.T, id uint, refreshToken string) *models.OAuthCredential {
+		credential := encryptedCredential(t, id, &projectID, "old-access")
+		encrypted, err := utils.EncryptString(testAppKey, refreshToken)
+		if err != nil {
+			t.Fatalf("EncryptString() error = %v", err)
+		}
+		credential.RefreshToken = encrypted
+		credential.ExpiresAt = &soon
+		credential.Status = models.OAuthStatusExpiring
+		return credential
+	}
+
+	good := newCredential(t, 1, "good-refresh")
+	revoked := newCredential(t, 2, "revoked-refresh")
+
+	updated := make(map[uint]*models.OAuthCredential)
+	repo := &MockOAuthCredentialRepository{
+		GetRefreshableFunc: func(ctx context.Context, expiresBefore time.Time) ([]*models.OAuthCredential, error) {
+			return []*models.OAuthCredential{good, revoked}, nil
+		},
+		UpdateFunc: func(ctx context.Context, credential *models.OAuthCredential) error {
+			updated[credential.ID] = credential
+			return nil
+		},
+	}
+	oauthClient := &MockYandexOAuthClient{
+		RefreshTokenFunc: func(ctx context.Context, refreshToken string) (*integrations.OAuthToken, error) {
+			if refreshToken == "revoked-refresh" {
+				return nil, integrations.ErrRefreshTokenInvalid
+			}
+			return &integrations.OAuthToken{AccessToken: "new-access", ExpiresIn: 3600 * 24 * 365}, nil
+		},
+	}
+	service := NewOAuthCredentialService(repo, &MockDirectRepositoryForDirectService{}, oauthClient, testAppKey, "")
+
+	refreshed, err := service.RefreshExpiringTokens(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshExpiringTokens() error = %v", err)
+	}
+	if refreshed != 1 {
+		t.Errorf("refreshed = %d, want 1", refreshed)
+	}
+
+	if got := updated[1]; got == nil || got.Status != models.OAuthStatusValid {
+		t.Errorf("refreshed credential status = %+v, want valid", got)
+	} else if token, _ := utils.DecryptString(testAppKey, got.AccessToken); token != "new-access" {
+		t.Errorf("access token = %q, want new-access", token)
+	} else if token, _ := utils.DecryptString(testAppKey, got.RefreshToken); token != "good-refresh" {
+		t.Errorf("refresh token should be kept when not reissued, got %q", token)
+	}
+
+	if got := updated[2]; got == nil || got.Status != models.OAuthStatusRevoked || got.LastError == "" {
+		t.Errorf("revoked credential = %+v, want revoked with error", got)
+	}
+}
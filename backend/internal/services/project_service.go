@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/suprt/planica_bi/backend/internal/integrations"
 	"github.com/suprt/planica_bi/backend/internal/middleware"
 	"github.com/suprt/planica_bi/backend/internal/models"
 )
@@ -41,6 +42,9 @@ func (s *ProjectService) CreateProject(ctx context.Context, project *models.Proj
 	if project.Slug == "" {
 		return errors.New("slug is required")
 	}
+	if err := applyProjectSettings(project, nil); err != nil {
+		return err
+	}
 
 	// Generate public token if not provided
 	if project.PublicToken == "" {
@@ -99,14 +103,73 @@ func (s *ProjectService) UpdateProject(ctx context.Context, project *models.Proj
 	}
 
 	// Check if project exists
-	_, err := s.projectRepo.GetByID(ctx, project.ID)
+	existing, err := s.projectRepo.GetByID(ctx, project.ID)
 	if err != nil {
 		return fmt.Errorf("project not found: %w", err)
 	}
 
+	if err := applyProjectSettings(project, existing); err != nil {
+		return err
+	}
+
 	return s.projectRepo.Update(ctx, project)
 }
 
+// applyProjectSettings fills sync settings that are not passed and validates them
+// Empty values are taken from existing project (on update) or set to defaults
+func applyProjectSettings(project, existing *models.Project) error {
+	if existing != nil {
+		if project.AttributionModel == "" {
+			project.AttributionModel = existing.AttributionModel
+		}
+		if project.CounterAggregation == "" {
+			project.CounterAggregation = existing.CounterAggregation
+		}
+		if project.Currency == "" {
+			project.Currency = existing.Currency
+		}
+		if project.ReportingCurrency == nil {
+			project.ReportingCurrency = existing.ReportingCurrency
+		}
+	}
+
+	if project.AttributionModel == "" {
+		project.AttributionModel = integrations.AttributionModelAuto
+	}
+	if !integrations.IsValidAttributionModel(project.AttributionModel) {
+		return errors.New("invalid attribution_model")
+	}
+
+	if project.CounterAggregation == "" {
+		project.CounterAggregation = models.CounterAggregationSum
+	}
+	if project.CounterAggregation != models.CounterAggregationSum && project.CounterAggregation != models.CounterAggregationPrimary {
+		return errors.New("invalid counter_aggregation")
+	}
+
+	if project.Currency == "" {
+		project.Currency = models.CurrencyRUB
+	}
+	if !models.IsValidCurrency(project.Currency) {
+		return errors.New("invalid currency")
+	}
+
+	// Reporting currency is optional, empty value resets it to the native currency
+	if project.ReportingCurrency != nil {
+		if *project.ReportingCurrency == "" || *project.ReportingCurrency == project.Currency {
+			project.ReportingCurrency = nil
+		} else if !models.IsValidCurrency(*project.ReportingCurrency) {
+			return errors.New("invalid reporting_currency")
+		}
+	}
+
+	if project.CostMarkupPct < 0 || project.CostMarkupPct > 100 {
+		return errors.New("invalid cost_markup_pct")
+	}
+
+	return nil
+}
+
 // DeleteProject deletes a project
 func (s *ProjectService) DeleteProject(ctx context.Context, id uint) error {
 	return s.projectRepo.Delete(ctx, id)
@@ -131,6 +131,112 @@ func TestProjectService_CreateProject(t *testing.T) {
 			wantErr:     true,
 			wantErrText: "slug is required",
 		},
+		{
+			name: "модель атрибуции по умолчанию",
+			project: &models.Project{
+				Name: "Test Project",
+				Slug: "test-project",
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{
+					CreateFunc: func(ctx context.Context, project *models.Project) error {
+						if project.AttributionModel != "AUTO" {
+							t.Errorf("ожидалась модель атрибуции AUTO, но получили '%s'", project.AttributionModel)
+						}
+						return nil
+					},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "неизвестная модель атрибуции",
+			project: &models.Project{
+				Name:             "Test Project",
+				Slug:             "test-project",
+				AttributionModel: "LAST",
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{}
+			},
+			wantErr:     true,
+			wantErrText: "invalid attribution_model",
+		},
+		{
+			name: "неизвестный режим объединения счётчиков",
+			project: &models.Project{
+				Name:               "Test Project",
+				Slug:               "test-project",
+				CounterAggregation: "avg",
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{}
+			},
+			wantErr:     true,
+			wantErrText: "invalid counter_aggregation",
+		},
+		{
+			name: "валюта проекта по умолчанию",
+			project: &models.Project{
+				Name:              "Test Project",
+				Slug:              "test-project",
+				ReportingCurrency: stringPtr("RUB"),
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{
+					CreateFunc: func(ctx context.Context, project *models.Project) error {
+						if project.Currency != "RUB" {
+							t.Errorf("ожидалась валюта RUB, но получили '%s'", project.Currency)
+						}
+						if project.ReportingCurrency != nil {
+							t.Errorf("валюта отчётов, совпадающая с валютой проекта, должна сбрасываться")
+						}
+						return nil
+					},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "неизвестная валюта",
+			project: &models.Project{
+				Name:     "Test Project",
+				Slug:     "test-project",
+				Currency: "RUR",
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{}
+			},
+			wantErr:     true,
+			wantErrText: "invalid currency",
+		},
+		{
+			name: "неизвестная валюта отчётов",
+			project: &models.Project{
+				Name:              "Test Project",
+				Slug:              "test-project",
+				Currency:          "KZT",
+				ReportingCurrency: stringPtr("XXX"),
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{}
+			},
+			wantErr:     true,
+			wantErrText: "invalid reporting_currency",
+		},
+		{
+			name: "наценка агентства вне диапазона",
+			project: &models.Project{
+				Name:          "Test Project",
+				Slug:          "test-project",
+				CostMarkupPct: 150,
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{}
+			},
+			wantErr:     true,
+			wantErrText: "invalid cost_markup_pct",
+		},
 		{
 			name: "сгенерировать public token если не предоставлен",
 			project: &models.Project{
@@ -323,6 +429,28 @@ func TestProjectService_UpdateProject(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "сохранить текущую модель атрибуции",
+			project: &models.Project{
+				ID:   1,
+				Name: "Updated Project",
+				Slug: "updated-project",
+			},
+			mockSetup: func() *MockProjectRepository {
+				return &MockProjectRepository{
+					GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+						return &models.Project{ID: id, AttributionModel: "LSC"}, nil
+					},
+					UpdateFunc: func(ctx context.Context, project *models.Project) error {
+						if project.AttributionModel != "LSC" {
+							t.Errorf("ожидалась модель атрибуции LSC, но получили '%s'", project.AttributionModel)
+						}
+						return nil
+					},
+				}
+			},
+			wantErr: false,
+		},
 		{
 			name: "пустое название при обновлении",
 			project: &models.Project{
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// CurrencyNative requests money of a report in the native currency of the project
+const CurrencyNative = "native"
+
+// ConvertReport converts money of a report in place to the requested currency
+// Empty currency selects the reporting currency of the project, "native" keeps the project currency
+// Each month is converted at the rate of its last day (or today for the current month)
+func (s *ReportService) ConvertReport(ctx context.Context, report *Report, currency string) (*Report, error) {
+	project, err := s.projectRepo.GetByID(ctx, report.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	// Reports cached before currencies were introduced are in RUB
+	if report.Currency == "" {
+		report.Currency = models.CurrencyRUB
+	}
+	target, err := reportCurrency(project, currency)
+	if err != nil {
+		return nil, err
+	}
+	if target == report.Currency {
+		return report, nil
+	}
+
+	rates, err := s.periodRates(ctx, report.Currency, target, report.Periods)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Metrica.Summary {
+		row := &report.Metrica.Summary[i]
+		row.Revenue = convertAmount(rates[row.Month], row.Revenue)
+		row.AvgOrderValue = convertAmount(rates[row.Month], row.AvgOrderValue)
+		row.GoalRevenue = convertAmount(rates[row.Month], row.GoalRevenue)
+	}
+	for i := range report.Direct.Totals {
+		row := &report.Direct.Totals[i]
+		row.Cpc, row.Cpa, row.Cost = convertMoney(rates[row.Month], row.Cpc, row.Cpa, row.Cost)
+		row.Sales.convert(rates[row.Month])
+		row.Revenue = convertAmount(rates[row.Month], row.Revenue)
+	}
+	for i := range report.Direct.Campaigns {
+		for j := range report.Direct.Campaigns[i].Rows {
+			row := &report.Direct.Campaigns[i].Rows[j]
+			row.Cpc, row.Cpa, row.Cost = convertMoney(rates[row.Month], row.Cpc, row.Cpa, row.Cost)
+			row.Sales.convert(rates[row.Month])
+			row.Revenue = convertAmount(rates[row.Month], row.Revenue)
+		}
+	}
+	for i := range report.Channels.Totals {
+		row := &report.Channels.Totals[i]
+		row.Cpc, row.Cpa, row.Cost = convertMoney(rates[row.Month], row.Cpc, row.Cpa, row.Cost)
+	}
+	for i := range report.Channels.Campaigns {
+		for j := range report.Channels.Campaigns[i].Rows {
+			row := &report.Channels.Campaigns[i].Rows[j]
+			row.Cpc, row.Cpa, row.Cost = convertMoney(rates[row.Month], row.Cpc, row.Cpa, row.Cost)
+		}
+	}
+	for i := range report.TrafficSources {
+		row := &report.TrafficSources[i]
+		row.Sales.convert(rates[row.Month])
+	}
+	report.Currency = target
+
+	return report, nil
+}
+
+// reportCurrency resolves the currency money of a project is shown in
+func reportCurrency(project *models.Project, requested string) (string, error) {
+	native := project.Currency
+	if native == "" {
+		native = models.CurrencyRUB
+	}
+
+	requested = strings.TrimSpace(requested)
+	switch {
+	case requested == "":
+		if project.ReportingCurrency != nil && *project.ReportingCurrency != "" {
+			return *project.ReportingCurrency, nil
+		}
+		return native, nil
+	case strings.EqualFold(requested, CurrencyNative):
+		return native, nil
+	}
+
+	requested = strings.ToUpper(requested)
+	if !models.IsValidCurrency(requested) {
+		return "", fmt.Errorf("invalid currency: %s", requested)
+	}
+	return requested, nil
+}
+
+// periodRates returns conversion rates of periods ("YYYY-MM")
+func (s *ReportService) periodRates(ctx context.Context, from, to string, periods []string) (map[string]float64, error) {
+	if s.rates == nil {
+		return nil, fmt.Errorf("no exchange rate %s/%s: rates are not configured", from, to)
+	}
+
+	now := time.Now()
+	rates := make(map[string]float64, len(periods))
+	for _, period := range periods {
+		year, month, err := parsePeriod(period)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period format %s: %w", period, err)
+		}
+		rate, err := s.rates.Rate(ctx, from, to, rateDate(year, month, now))
+		if err != nil {
+			return nil, err
+		}
+		rates[period] = rate
+	}
+	return rates, nil
+}
+
+// convertAmount converts an optional amount at the rate
+func convertAmount(rate float64, amount *float64) *float64 {
+	if amount == nil {
+		return nil
+	}
+	value := *amount * rate
+	return &value
+}
+
+// convertMoney converts CPC, CPA and cost at the rate
+func convertMoney(rate, cpc float64, cpa *float64, cost float64) (float64, *float64, float64) {
+	if cpa != nil {
+		value := *cpa * rate
+		cpa = &value
+	}
+	return cpc * rate, cpa, cost * rate
+}
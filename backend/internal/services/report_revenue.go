@@ -0,0 +1,44 @@
+package services
+
+import (
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// averageOrderValue returns ecommerce revenue per purchase, nil without purchases
+func averageOrderValue(metrics *models.MetricsMonthly) *float64 {
+	if metrics.Purchases == nil || *metrics.Purchases == 0 || metrics.Revenue == nil {
+		return nil
+	}
+	value := *metrics.Revenue / float64(*metrics.Purchases)
+	return &value
+}
+
+// campaignRevenue sums ecommerce and goal value revenue of Direct campaigns by Yandex campaign ID
+func campaignRevenue(rows []*models.MetricsCampaignRevenueMonthly) map[int64]float64 {
+	revenue := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		revenue[row.CampaignID] += row.Revenue + row.GoalRevenue
+	}
+	return revenue
+}
+
+// revenueWithROAS returns revenue and ROAS (% of cost) of a report row, both nil if revenue was not synced
+// ROAS is nil without cost
+func revenueWithROAS(revenue float64, ok bool, cost float64) (*float64, *float64) {
+	if !ok {
+		return nil, nil
+	}
+	if cost <= 0 {
+		return &revenue, nil
+	}
+	roas := revenue / cost * 100
+	return &revenue, &roas
+}
+
+// roasValue returns ROAS (% of cost) for channel metrics, 0 without cost
+func roasValue(revenue, cost float64) float64 {
+	if cost <= 0 {
+		return 0
+	}
+	return revenue / cost * 100
+}
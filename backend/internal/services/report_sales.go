@@ -0,0 +1,118 @@
+package services
+
+import (
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// SalesMetrics represents CRM deals attributed to a report row and the return on its cost
+// Revenue is the sum of won deals; ROAS and ROMI are percents of cost and are absent when there is no cost
+type SalesMetrics struct {
+	Deals       int      `json:"deals"` // All deals of the month, including open and lost
+	Sales       int      `json:"sales"` // Won deals
+	Revenue     float64  `json:"revenue"`
+	CostPerSale *float64 `json:"costPerSale,omitempty"`
+	ROAS        *float64 `json:"roas,omitempty"` // Revenue / cost * 100
+	ROMI        *float64 `json:"romi,omitempty"` // (Revenue - cost) / cost * 100
+}
+
+// add counts a deal
+func (m *SalesMetrics) add(deal *models.CRMDeal) {
+	m.Deals++
+	if deal.Status == models.CRMDealStatusWon {
+		m.Sales++
+		m.Revenue += deal.Revenue
+	}
+}
+
+// withCost returns a copy of the metrics with cost per sale, ROAS and ROMI of the cost
+func (m *SalesMetrics) withCost(cost float64) *SalesMetrics {
+	sales := *m
+	if cost <= 0 {
+		return &sales
+	}
+	if sales.Sales > 0 {
+		costPerSale := cost / float64(sales.Sales)
+		sales.CostPerSale = &costPerSale
+	}
+	roas := sales.Revenue / cost * 100
+	romi := (sales.Revenue - cost) / cost * 100
+	sales.ROAS = &roas
+	sales.ROMI = &romi
+	return &sales
+}
+
+// convert converts revenue and cost per sale at the rate, ROAS and ROMI do not depend on currency
+func (m *SalesMetrics) convert(rate float64) {
+	if m == nil {
+		return
+	}
+	m.Revenue *= rate
+	if m.CostPerSale != nil {
+		value := *m.CostPerSale * rate
+		m.CostPerSale = &value
+	}
+}
+
+// monthSales aggregates CRM deals of a month by Direct campaign and Metrica traffic source
+type monthSales struct {
+	direct    *SalesMetrics            // Deals matched to any Direct campaign, nil if the month has no deals
+	campaigns map[int64]*SalesMetrics  // By Yandex campaign ID
+	sources   map[string]*SalesMetrics // By traffic source
+}
+
+// newMonthSales aggregates deals of a month
+func newMonthSales(deals []*models.CRMDeal) *monthSales {
+	sales := &monthSales{
+		campaigns: make(map[int64]*SalesMetrics),
+		sources:   make(map[string]*SalesMetrics),
+	}
+	if len(deals) == 0 {
+		return sales
+	}
+
+	sales.direct = &SalesMetrics{}
+	for _, deal := range deals {
+		if deal.CampaignID != nil {
+			sales.direct.add(deal)
+			if sales.campaigns[*deal.CampaignID] == nil {
+				sales.campaigns[*deal.CampaignID] = &SalesMetrics{}
+			}
+			sales.campaigns[*deal.CampaignID].add(deal)
+		}
+		if deal.TrafficSource != "" {
+			if sales.sources[deal.TrafficSource] == nil {
+				sales.sources[deal.TrafficSource] = &SalesMetrics{}
+			}
+			sales.sources[deal.TrafficSource].add(deal)
+		}
+	}
+	return sales
+}
+
+// directTotals returns sales of Direct for the cost of the month, nil if the month has no deals
+// A month with deals but no sales of Direct campaigns still gets ROMI of -100%
+func (s *monthSales) directTotals(cost float64) *SalesMetrics {
+	if s.direct == nil {
+		return nil
+	}
+	return s.direct.withCost(cost)
+}
+
+// campaign returns sales of a Direct campaign for its cost, nil if the campaign has no deals
+func (s *monthSales) campaign(campaignID int64, cost float64) *SalesMetrics {
+	sales, ok := s.campaigns[campaignID]
+	if !ok {
+		return nil
+	}
+	return sales.withCost(cost)
+}
+
+// source returns sales of a traffic source, nil if the source has no deals
+// Traffic sources have no cost, so only deals and revenue are set
+func (s *monthSales) source(source string) *SalesMetrics {
+	sales, ok := s.sources[source]
+	if !ok {
+		return nil
+	}
+	return sales.withCost(0)
+}
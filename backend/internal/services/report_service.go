@@ -10,6 +10,7 @@ import (
 	"github.com/suprt/planica_bi/backend/internal/ai"
 	"github.com/suprt/planica_bi/backend/internal/config"
 	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/models"
 	"github.com/suprt/planica_bi/backend/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -20,6 +21,9 @@ type ReportService struct {
 	directRepo  DirectRepositoryInterface
 	seoRepo     SEORepositoryInterface
 	projectRepo ProjectRepositoryInterface
+	channelRepo ChannelRepositoryInterface // Campaigns of additional channels loaded by connectors
+	crmRepo     CRMRepositoryInterface     // CRM deals for sales, ROMI and ROAS
+	rates       *ExchangeRateService       // Optional: converts money to a reporting currency
 	cfg         *config.Config
 }
 
@@ -29,6 +33,9 @@ func NewReportService(
 	directRepo DirectRepositoryInterface,
 	seoRepo SEORepositoryInterface,
 	projectRepo ProjectRepositoryInterface,
+	channelRepo ChannelRepositoryInterface,
+	crmRepo CRMRepositoryInterface,
+	rates *ExchangeRateService,
 	cfg *config.Config,
 ) *ReportService {
 	return &ReportService{
@@ -36,6 +43,9 @@ func NewReportService(
 		directRepo:  directRepo,
 		seoRepo:     seoRepo,
 		projectRepo: projectRepo,
+		channelRepo: channelRepo,
+		crmRepo:     crmRepo,
+		rates:       rates,
 		cfg:         cfg,
 	}
 }
@@ -58,16 +68,45 @@ type MetricaSummaryRow struct {
 	AvgSec   int       `json:"avgSec"`
 	Conv     *int      `json:"conv,omitempty"`
 	Dynamics *Dynamics `json:"dynamics,omitempty"`
+	// Ecommerce and goal value revenue, absent for months synced before revenue was introduced
+	Purchases     *int     `json:"purchases,omitempty"`
+	Revenue       *float64 `json:"revenue,omitempty"` // Ecommerce revenue
+	AvgOrderValue *float64 `json:"avgOrderValue,omitempty"`
+	GoalRevenue   *float64 `json:"goalRevenue,omitempty"` // Sum of values of conversion goals
 }
 
 // MetricaAgeRow represents a single row in metrica age breakdown
 type MetricaAgeRow struct {
-	Month  string  `json:"month"`
-	Age    string  `json:"age"`
-	Visits int     `json:"visits"`
-	Users  int     `json:"users"`
-	Bounce float64 `json:"bounce"`
-	AvgSec int     `json:"avgSec"`
+	Month    string    `json:"month"`
+	Age      string    `json:"age"`
+	Visits   int       `json:"visits"`
+	Users    int       `json:"users"`
+	Bounce   float64   `json:"bounce"`
+	AvgSec   int       `json:"avgSec"`
+	Dynamics *Dynamics `json:"dynamics,omitempty"`
+}
+
+// MetricaBreakdownRow represents a single row in metrica gender, device or region breakdown
+type MetricaBreakdownRow struct {
+	Month    string    `json:"month"`
+	Value    string    `json:"value"` // Value id, e.g. male, mobile, 213
+	Name     string    `json:"name"`
+	Visits   int       `json:"visits"`
+	Users    int       `json:"users"`
+	Bounce   float64   `json:"bounce"`
+	AvgSec   int       `json:"avgSec"`
+	SharePct float64   `json:"sharePct"` // Share of visits of the month within the breakdown
+	Dynamics *Dynamics `json:"dynamics,omitempty"`
+}
+
+// MetricaGoalRow represents metrics of a conversion goal in a month
+type MetricaGoalRow struct {
+	Month          string  `json:"month"`
+	GoalID         int64   `json:"goalId"`
+	Name           string  `json:"name"`
+	Reaches        int     `json:"reaches"`
+	Visits         int     `json:"visits"`
+	ConversionRate float64 `json:"conversionRate"` // Goal visits share of all visits, %
 }
 
 // DirectTotalsRow represents a single row in direct totals
@@ -80,11 +119,43 @@ type DirectTotalsRow struct {
 	Conv        *int     `json:"conv,omitempty"`
 	Cpa         *float64 `json:"cpa,omitempty"`
 	Cost        float64  `json:"cost"`
+	// CostBasis the month was synced with, absent for months synced before cost bases were introduced
+	CostBasis *models.CostBasis `json:"costBasis,omitempty"`
+	// Sales of CRM deals matched to Direct campaigns, absent if the month has no deals
+	Sales *SalesMetrics `json:"sales,omitempty"`
+	// Metrica ecommerce and goal value revenue of visits from Direct clicks, absent if not synced
+	Revenue *float64 `json:"revenue,omitempty"`
+	Roas    *float64 `json:"roas,omitempty"` // Revenue / cost * 100
 }
 
 // DirectCampaignRow represents a single row for a campaign in a month
 type DirectCampaignRow struct {
+	Month       string        `json:"month"`
+	Impressions int           `json:"impressions"`
+	Clicks      int           `json:"clicks"`
+	Ctr         float64       `json:"ctr"`
+	Cpc         float64       `json:"cpc"`
+	Conv        *int          `json:"conv,omitempty"`
+	Cpa         *float64      `json:"cpa,omitempty"`
+	Cost        float64       `json:"cost"`
+	Sales       *SalesMetrics `json:"sales,omitempty"`   // CRM deals of a Direct campaign
+	Revenue     *float64      `json:"revenue,omitempty"` // Metrica revenue of the campaign's clicks
+	Roas        *float64      `json:"roas,omitempty"`
+}
+
+// DirectCampaignData represents campaign data with rows for all months
+type DirectCampaignData struct {
+	CampaignID int64               `json:"campaignId"`
+	Name       string              `json:"name"`
+	Rows       []DirectCampaignRow `json:"rows"`
+}
+
+// ChannelTotalsRow represents totals of an additional channel in a month
+type ChannelTotalsRow struct {
 	Month       string   `json:"month"`
+	Source      string   `json:"source"`  // Connector that loaded the channel, e.g. vk_ads
+	Channel     string   `json:"channel"` // Channel id
+	Name        string   `json:"name"`    // Channel name for display
 	Impressions int      `json:"impressions"`
 	Clicks      int      `json:"clicks"`
 	Ctr         float64  `json:"ctr"`
@@ -94,9 +165,11 @@ type DirectCampaignRow struct {
 	Cost        float64  `json:"cost"`
 }
 
-// DirectCampaignData represents campaign data with rows for all months
-type DirectCampaignData struct {
-	CampaignID int64               `json:"campaignId"`
+// ChannelCampaignData represents a campaign of an additional channel with rows for all months
+type ChannelCampaignData struct {
+	Source     string              `json:"source"`
+	Channel    string              `json:"channel"`
+	CampaignID string              `json:"campaignId"` // Campaign ID in the source
 	Name       string              `json:"name"`
 	Rows       []DirectCampaignRow `json:"rows"`
 }
@@ -108,26 +181,52 @@ type SEOSummaryRow struct {
 	Conv     int    `json:"conv"`
 }
 
+// TrafficSourceRow represents metrics of a traffic source in a month
+type TrafficSourceRow struct {
+	Month    string        `json:"month"`
+	Source   string        `json:"source"`
+	Name     string        `json:"name"`
+	Visits   int           `json:"visits"`
+	Users    int           `json:"users"`
+	Conv     *int          `json:"conv,omitempty"`
+	SharePct float64       `json:"sharePct"`        // Share of all visits of the month
+	Sales    *SalesMetrics `json:"sales,omitempty"` // CRM deals of the source
+}
+
 // SEOQueryRow represents a single SEO query row
 type SEOQueryRow struct {
 	Month    string  `json:"month"`
 	Query    string  `json:"query"`
 	Position int     `json:"position"`
+	Clicks   int     `json:"clicks"`
+	Shows    int     `json:"shows"`
 	URL      *string `json:"url,omitempty"`
 }
 
 // MetricaData represents metrica section of the report
 type MetricaData struct {
-	Summary []MetricaSummaryRow `json:"summary"`
-	Age     []MetricaAgeRow     `json:"age"`
+	Summary   []MetricaSummaryRow   `json:"summary"`
+	Age       []MetricaAgeRow       `json:"age"`
+	Gender    []MetricaBreakdownRow `json:"gender"`
+	Devices   []MetricaBreakdownRow `json:"devices"`
+	Cities    []MetricaBreakdownRow `json:"cities"`
+	Countries []MetricaBreakdownRow `json:"countries"`
+	Goals     []MetricaGoalRow      `json:"goals"`
 }
 
 // DirectData represents direct section of the report
 type DirectData struct {
+	CostBasis models.CostBasis     `json:"costBasis"` // Current cost basis of the project
 	Totals    []DirectTotalsRow    `json:"totals"`
 	Campaigns []DirectCampaignData `json:"campaigns"`
 }
 
+// ChannelsData represents paid channels other than Direct, loaded by connectors
+type ChannelsData struct {
+	Totals    []ChannelTotalsRow    `json:"totals"`
+	Campaigns []ChannelCampaignData `json:"campaigns"`
+}
+
 // SEOData represents SEO section of the report
 type SEOData struct {
 	Summary []SEOSummaryRow `json:"summary"`
@@ -142,18 +241,35 @@ type AiInsights struct {
 
 // Report represents a full report according to TZ format
 type Report struct {
-	ProjectID  uint        `json:"projectId"`
-	Periods    []string    `json:"periods"`
-	Metrica    MetricaData `json:"metrica"`
-	Direct     DirectData  `json:"direct"`
-	SEO        SEOData     `json:"seo"`
-	AiInsights *AiInsights `json:"ai_insights,omitempty"`
+	ProjectID      uint               `json:"projectId"`
+	Periods        []string           `json:"periods"`
+	Currency       string             `json:"currency"` // Currency of money values, see ConvertReport
+	Metrica        MetricaData        `json:"metrica"`
+	Direct         DirectData         `json:"direct"`
+	Channels       ChannelsData       `json:"channels"`
+	SEO            SEOData            `json:"seo"`
+	TrafficSources []TrafficSourceRow `json:"trafficSources"`
+	AiInsights     *AiInsights        `json:"ai_insights,omitempty"`
 }
 
 // GetReport generates a report for a project for the last 3 months
+// Money is in the native currency of the project, ConvertReport shows it in another one
 func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report, error) {
 	now := time.Now()
 
+	currency := models.CurrencyRUB
+	var costBasis models.CostBasis
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project != nil {
+		if project.Currency != "" {
+			currency = project.Currency
+		}
+		costBasis = project.CostBasis()
+	}
+
 	// Prepare periods array: M (current), M-1, M-2
 	periods := make([]string, 3)
 	periodData := make([]struct {
@@ -178,22 +294,36 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 	report := &Report{
 		ProjectID: projectID,
 		Periods:   periods,
+		Currency:  currency,
 		Metrica: MetricaData{
-			Summary: []MetricaSummaryRow{},
-			Age:     []MetricaAgeRow{},
+			Summary:   []MetricaSummaryRow{},
+			Age:       []MetricaAgeRow{},
+			Gender:    []MetricaBreakdownRow{},
+			Devices:   []MetricaBreakdownRow{},
+			Cities:    []MetricaBreakdownRow{},
+			Countries: []MetricaBreakdownRow{},
+			Goals:     []MetricaGoalRow{},
 		},
 		Direct: DirectData{
+			CostBasis: costBasis,
 			Totals:    []DirectTotalsRow{},
 			Campaigns: []DirectCampaignData{},
 		},
+		Channels: ChannelsData{
+			Totals:    []ChannelTotalsRow{},
+			Campaigns: []ChannelCampaignData{},
+		},
 		SEO: SEOData{
 			Summary: []SEOSummaryRow{},
 			Queries: []SEOQueryRow{},
 		},
+		TrafficSources: []TrafficSourceRow{},
 	}
 
 	// Map to group campaigns by CampaignID (Yandex ID)
 	campaignMap := make(map[int64]*DirectCampaignData)
+	// Campaigns of additional channels by index in report.Channels.Campaigns
+	channelCampaigns := make(map[channelCampaignKey]int)
 
 	// Process each period
 	for _, pd := range periodData {
@@ -210,6 +340,11 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 				Bounce: metrics.BounceRate,
 				AvgSec: metrics.AvgSessionDurationSec,
 				Conv:   metrics.Conversions,
+
+				Purchases:     metrics.Purchases,
+				Revenue:       metrics.Revenue,
+				AvgOrderValue: averageOrderValue(metrics),
+				GoalRevenue:   metrics.GoalRevenue,
 			})
 		}
 
@@ -229,12 +364,56 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 			})
 		}
 
+		// Get gender, device and region breakdowns
+		breakdownMetrics, err := s.metricsRepo.GetBreakdownMetrics(ctx, projectID, pd.year, pd.month)
+		if err != nil {
+			return nil, err
+		}
+		report.Metrica.addBreakdowns(pd.period, breakdownMetrics)
+
+		// Get per-goal breakdown
+		goalMetrics, err := s.metricsRepo.GetGoalMetrics(ctx, projectID, pd.year, pd.month)
+		if err != nil {
+			return nil, err
+		}
+		for _, goal := range goalMetrics {
+			report.Metrica.Goals = append(report.Metrica.Goals, MetricaGoalRow{
+				Month:          pd.period,
+				GoalID:         goal.GoalID,
+				Name:           goal.GoalName,
+				Reaches:        goal.Reaches,
+				Visits:         goal.Visits,
+				ConversionRate: goal.ConversionRate,
+			})
+		}
+
+		// Get CRM deals of the month
+		dateFrom, dateTo := monthRange(pd.year, pd.month)
+		deals, err := s.crmRepo.GetDeals(ctx, projectID, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+		sales := newMonthSales(deals)
+
+		// Get Metrica revenue of Direct campaigns
+		revenueRows, err := s.metricsRepo.GetCampaignRevenue(ctx, projectID, pd.year, pd.month)
+		if err != nil {
+			return nil, err
+		}
+		revenueSynced := len(revenueRows) > 0
+		revenueByCampaign := campaignRevenue(revenueRows)
+
 		// Get Direct totals
 		directTotals, err := s.directRepo.GetTotalsMonthly(ctx, projectID, pd.year, pd.month)
 		if err != nil {
 			return nil, err
 		}
 		if directTotals != nil {
+			var totalRevenue float64
+			for _, revenue := range revenueByCampaign {
+				totalRevenue += revenue
+			}
+			revenue, roas := revenueWithROAS(totalRevenue, revenueSynced, directTotals.Cost)
 			report.Direct.Totals = append(report.Direct.Totals, DirectTotalsRow{
 				Month:       pd.period,
 				Impressions: directTotals.Impressions,
@@ -244,6 +423,10 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 				Conv:        directTotals.Conversions,
 				Cpa:         directTotals.CPA,
 				Cost:        directTotals.Cost,
+				CostBasis:   directTotals.CostBasis,
+				Sales:       sales.directTotals(directTotals.Cost),
+				Revenue:     revenue,
+				Roas:        roas,
 			})
 		}
 
@@ -269,6 +452,7 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 			}
 
 			// Add row for this month
+			revenue, roas := revenueWithROAS(revenueByCampaign[campaign.CampaignID], revenueSynced, campaignMonthly.Cost)
 			campaignMap[campaign.CampaignID].Rows = append(campaignMap[campaign.CampaignID].Rows, DirectCampaignRow{
 				Month:       pd.period,
 				Impressions: campaignMonthly.Impressions,
@@ -278,9 +462,19 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 				Conv:        campaignMonthly.Conversions,
 				Cpa:         campaignMonthly.CPA,
 				Cost:        campaignMonthly.Cost,
+				Sales:       sales.campaign(campaign.CampaignID, campaignMonthly.Cost),
+				Revenue:     revenue,
+				Roas:        roas,
 			})
 		}
 
+		// Get campaigns of additional channels
+		channelRows, err := s.channelRepo.GetCampaignMonthly(ctx, projectID, pd.year, pd.month)
+		if err != nil {
+			return nil, err
+		}
+		report.Channels.addMonth(pd.period, channelRows, channelCampaigns)
+
 		// Get SEO queries
 		seoQueries, err := s.seoRepo.GetSEOQueries(ctx, projectID, pd.year, pd.month)
 		if err != nil {
@@ -291,28 +485,48 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 				Month:    pd.period,
 				Query:    query.Query,
 				Position: query.Position,
+				Clicks:   query.Clicks,
+				Shows:    query.Shows,
 				URL:      query.URL,
 			})
 		}
 
-		// Get SEO summary (organic visitors and conversions)
-		// For now, we can use Metrica metrics as a base, but ideally should filter by organic traffic
-		// TODO: Add proper organic traffic filtering via Metrica API with source segment
-		// Reuse metrics from earlier in the loop (already fetched at line 207)
-		if metrics != nil {
-			// Calculate organic visitors as a percentage of total (rough estimate: 30-50% is typical for organic)
-			// In production, this should come from Metrica API with organic segment filter
-			organicVisitors := int(float64(metrics.Users) * 0.4) // Rough estimate: 40% organic
-			organicConversions := 0
-			if metrics.Conversions != nil {
-				organicConversions = int(float64(*metrics.Conversions) * 0.4) // Same percentage for conversions
+		// Get traffic sources; SEO summary is the organic source
+		trafficSources, err := s.metricsRepo.GetTrafficSourceMetrics(ctx, projectID, pd.year, pd.month)
+		if err != nil {
+			return nil, err
+		}
+		var totalVisits int
+		for _, source := range trafficSources {
+			totalVisits += source.Visits
+		}
+		for _, source := range trafficSources {
+			var share float64
+			if totalVisits > 0 {
+				share = float64(source.Visits) / float64(totalVisits) * 100
 			}
-
-			report.SEO.Summary = append(report.SEO.Summary, SEOSummaryRow{
+			report.TrafficSources = append(report.TrafficSources, TrafficSourceRow{
 				Month:    pd.period,
-				Visitors: organicVisitors,
-				Conv:     organicConversions,
+				Source:   source.Source,
+				Name:     source.SourceName,
+				Visits:   source.Visits,
+				Users:    source.Users,
+				Conv:     source.Conversions,
+				SharePct: share,
+				Sales:    sales.source(source.Source),
 			})
+
+			if source.Source == models.TrafficSourceOrganic {
+				organicConversions := 0
+				if source.Conversions != nil {
+					organicConversions = *source.Conversions
+				}
+				report.SEO.Summary = append(report.SEO.Summary, SEOSummaryRow{
+					Month:    pd.period,
+					Visitors: source.Users,
+					Conv:     organicConversions,
+				})
+			}
 		}
 	}
 
@@ -326,12 +540,7 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 		m0 := &report.Metrica.Summary[0] // Current month
 		m1 := &report.Metrica.Summary[1] // Previous month
 
-		m0.Dynamics = &Dynamics{
-			Visits: utils.CalculateDynamics(float64(m0.Visits), float64(m1.Visits)),
-			Users:  utils.CalculateDynamics(float64(m0.Users), float64(m1.Users)),
-			Bounce: utils.CalculateDynamics(m0.Bounce, m1.Bounce),
-			AvgSec: utils.CalculateDynamics(float64(m0.AvgSec), float64(m1.AvgSec)),
-		}
+		m0.Dynamics = visitDynamics(m0.Visits, m1.Visits, m0.Users, m1.Users, m0.Bounce, m1.Bounce, m0.AvgSec, m1.AvgSec)
 
 		// Calculate dynamics for conversions if both have values
 		var conv0, conv1 float64
@@ -346,9 +555,165 @@ func (s *ReportService) GetReport(ctx context.Context, projectID uint) (*Report,
 		}
 	}
 
+	// Breakdown rows of the current month are compared with the same value of the previous month
+	report.Metrica.calculateBreakdownDynamics(periods[0], periods[1])
+
 	return report, nil
 }
 
+// channelCampaignKey identifies a campaign of an additional channel
+type channelCampaignKey struct {
+	source     string
+	channel    string
+	campaignID string
+}
+
+// addMonth adds channel totals and campaign rows of a month
+// campaigns indexes already added campaigns, so rows of one campaign are grouped across months
+func (d *ChannelsData) addMonth(period string, rows []*models.ChannelCampaignMonthly, campaigns map[channelCampaignKey]int) {
+	type totalsKey struct {
+		source  string
+		channel string
+	}
+	totals := make(map[totalsKey]*directMetrics)
+	var order []totalsKey
+
+	for _, row := range rows {
+		tk := totalsKey{source: row.Source, channel: row.Channel}
+		metrics, ok := totals[tk]
+		if !ok {
+			metrics = &directMetrics{}
+			totals[tk] = metrics
+			order = append(order, tk)
+		}
+		metrics.addChannelMonthly(row)
+
+		// Manual uploads are channel totals without campaigns
+		if row.CampaignID == "" {
+			continue
+		}
+		key := channelCampaignKey{source: row.Source, channel: row.Channel, campaignID: row.CampaignID}
+		index, ok := campaigns[key]
+		if !ok {
+			index = len(d.Campaigns)
+			campaigns[key] = index
+			d.Campaigns = append(d.Campaigns, ChannelCampaignData{
+				Source:     row.Source,
+				Channel:    row.Channel,
+				CampaignID: row.CampaignID,
+				Name:       row.CampaignName,
+				Rows:       []DirectCampaignRow{},
+			})
+		}
+		d.Campaigns[index].Rows = append(d.Campaigns[index].Rows, DirectCampaignRow{
+			Month:       period,
+			Impressions: row.Impressions,
+			Clicks:      row.Clicks,
+			Ctr:         row.CTRPct,
+			Cpc:         row.CPC,
+			Conv:        row.Conversions,
+			Cpa:         row.CPA,
+			Cost:        row.Cost,
+		})
+	}
+
+	for _, tk := range order {
+		metrics := totals[tk]
+		ctr, cpc, cpa := metrics.rates()
+		d.Totals = append(d.Totals, ChannelTotalsRow{
+			Month:       period,
+			Source:      tk.source,
+			Channel:     tk.channel,
+			Name:        channelName(tk.channel),
+			Impressions: metrics.impressions,
+			Clicks:      metrics.clicks,
+			Ctr:         ctr,
+			Cpc:         cpc,
+			Conv:        metrics.conversionsPtr(),
+			Cpa:         cpa,
+			Cost:        metrics.cost,
+		})
+	}
+}
+
+// addBreakdowns adds gender, device and region rows of a month to the report sections
+func (d *MetricaData) addBreakdowns(period string, metrics []*models.MetricsBreakdownMonthly) {
+	totals := make(map[string]int)
+	for _, row := range metrics {
+		totals[row.Dimension] += row.Visits
+	}
+
+	for _, row := range metrics {
+		var share float64
+		if totals[row.Dimension] > 0 {
+			share = float64(row.Visits) / float64(totals[row.Dimension]) * 100
+		}
+		breakdownRow := MetricaBreakdownRow{
+			Month:    period,
+			Value:    row.Value,
+			Name:     row.Name,
+			Visits:   row.Visits,
+			Users:    row.Users,
+			Bounce:   row.BounceRate,
+			AvgSec:   row.AvgSessionDurationSec,
+			SharePct: share,
+		}
+
+		switch row.Dimension {
+		case models.BreakdownGender:
+			d.Gender = append(d.Gender, breakdownRow)
+		case models.BreakdownDevice:
+			d.Devices = append(d.Devices, breakdownRow)
+		case models.BreakdownCity:
+			d.Cities = append(d.Cities, breakdownRow)
+		case models.BreakdownCountry:
+			d.Countries = append(d.Countries, breakdownRow)
+		}
+	}
+}
+
+// calculateBreakdownDynamics sets dynamics of age, gender, device and region rows of the current month
+// Values missing in the previous month get no dynamics
+func (d *MetricaData) calculateBreakdownDynamics(current, previous string) {
+	previousAges := make(map[string]MetricaAgeRow)
+	for _, row := range d.Age {
+		if row.Month == previous {
+			previousAges[row.Age] = row
+		}
+	}
+	for i := range d.Age {
+		row := &d.Age[i]
+		if prev, ok := previousAges[row.Age]; ok && row.Month == current {
+			row.Dynamics = visitDynamics(row.Visits, prev.Visits, row.Users, prev.Users, row.Bounce, prev.Bounce, row.AvgSec, prev.AvgSec)
+		}
+	}
+
+	for _, rows := range [][]MetricaBreakdownRow{d.Gender, d.Devices, d.Cities, d.Countries} {
+		previousRows := make(map[string]MetricaBreakdownRow)
+		for _, row := range rows {
+			if row.Month == previous {
+				previousRows[row.Value] = row
+			}
+		}
+		for i := range rows {
+			row := &rows[i]
+			if prev, ok := previousRows[row.Value]; ok && row.Month == current {
+				row.Dynamics = visitDynamics(row.Visits, prev.Visits, row.Users, prev.Users, row.Bounce, prev.Bounce, row.AvgSec, prev.AvgSec)
+			}
+		}
+	}
+}
+
+// visitDynamics calculates dynamics of visit metrics compared to previous period
+func visitDynamics(visits, prevVisits, users, prevUsers int, bounce, prevBounce float64, avgSec, prevAvgSec int) *Dynamics {
+	return &Dynamics{
+		Visits: utils.CalculateDynamics(float64(visits), float64(prevVisits)),
+		Users:  utils.CalculateDynamics(float64(users), float64(prevUsers)),
+		Bounce: utils.CalculateDynamics(bounce, prevBounce),
+		AvgSec: utils.CalculateDynamics(float64(avgSec), float64(prevAvgSec)),
+	}
+}
+
 // CalculateDynamics calculates percentage change between two values
 func (s *ReportService) CalculateDynamics(current, previous float64) float64 {
 	return utils.CalculateDynamics(current, previous)
@@ -362,18 +727,33 @@ type ChannelMetrics struct {
 	CTR         []float64 `json:"ctr"`
 	Conversions []int     `json:"conversions"`
 	CPA         []float64 `json:"cpa"`
-	Cost        []float64 `json:"cost"` // 💰 бюджет в рублях
+	Cost        []float64 `json:"cost"`    // 💰 бюджет в валюте отчёта
+	Revenue     []float64 `json:"revenue"` // Metrica ecommerce and goal value revenue, 0 for channels without attribution
+	ROAS        []float64 `json:"roas"`    // Revenue / cost * 100
+}
+
+// GoalMetrics represents metrics of a conversion goal, values are aligned with periods
+type GoalMetrics struct {
+	GoalID         int64     `json:"goal_id"`
+	Name           string    `json:"name"`
+	Reaches        []int     `json:"reaches"`
+	Visits         []int     `json:"visits"`
+	ConversionRate []float64 `json:"conversion_rate"` // Goal visits share of all visits, %
 }
 
 // ChannelMetricsOutput represents the output format for channel metrics
 type ChannelMetricsOutput struct {
-	Project string                     `json:"project"`
-	Periods []string                   `json:"periods"`
-	Metrics map[string]*ChannelMetrics `json:"metrics"`
+	Project   string                     `json:"project"`
+	Periods   []string                   `json:"periods"`
+	Currency  string                     `json:"currency"`
+	CostBasis models.CostBasis           `json:"cost_basis"` // Basis of Direct costs, see models.CostBasis
+	Metrics   map[string]*ChannelMetrics `json:"metrics"`
+	Goals     []*GoalMetrics             `json:"goals"`
 }
 
 // GetChannelMetrics retrieves channel metrics from database for specified periods
-func (s *ReportService) GetChannelMetrics(ctx context.Context, projectID uint, periods []string) (*ChannelMetricsOutput, error) {
+// currency selects money currency the same way as in ConvertReport
+func (s *ReportService) GetChannelMetrics(ctx context.Context, projectID uint, periods []string, currency string) (*ChannelMetricsOutput, error) {
 	// Get project to get project name
 	project, err := s.projectRepo.GetByID(ctx, projectID)
 	if err != nil {
@@ -381,127 +761,256 @@ func (s *ReportService) GetChannelMetrics(ctx context.Context, projectID uint, p
 	}
 
 	output := &ChannelMetricsOutput{
-		Project: project.Name,
-		Periods: periods,
-		Metrics: make(map[string]*ChannelMetrics),
+		Project:   project.Name,
+		Periods:   periods,
+		Currency:  project.Currency,
+		CostBasis: project.CostBasis(),
+		Metrics:   make(map[string]*ChannelMetrics),
+		Goals:     []*GoalMetrics{},
+	}
+	if output.Currency == "" {
+		output.Currency = models.CurrencyRUB
+	}
+
+	target, err := reportCurrency(project, currency)
+	if err != nil {
+		return nil, err
+	}
+	var rates map[string]float64
+	if target != output.Currency {
+		if rates, err = s.periodRates(ctx, output.Currency, target, periods); err != nil {
+			return nil, err
+		}
+		output.Currency = target
 	}
+	goalsByKey := make(map[goalKey]*GoalMetrics)
 
-	// Initialize channel metrics
-	simpleMetrics := &ChannelMetrics{}
-	mkMetrics := &ChannelMetrics{}
-	rsyaMetrics := &ChannelMetrics{}
+	// Campaigns are classified into channels by type and bidding strategy,
+	// stats of text campaigns shown both on search and in networks are split by ad network type
+	campaigns, err := s.directRepo.GetCampaignsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct campaigns: %w", err)
+	}
+	campaignChannels := make(map[uint]string, len(campaigns))
+	splitCampaigns := make(map[uint]bool, len(campaigns))
+	yandexCampaignIDs := make(map[int64]uint, len(campaigns)) // Metrica revenue is by Yandex campaign ID
+	for _, campaign := range campaigns {
+		campaignChannels[campaign.ID] = campaign.Channel()
+		splitCampaigns[campaign.ID] = campaign.ServesBothNetworks()
+		yandexCampaignIDs[campaign.CampaignID] = campaign.ID
+	}
+
+	channels := make(map[string]*ChannelMetrics, len(directChannelNames))
+	for _, channel := range directChannelNames {
+		channels[channel.channel] = &ChannelMetrics{}
+	}
+	// Channels of connectors are summed per period and added once all periods are loaded
+	connectorMetrics := make([]map[string]*directMetrics, len(periods))
+	var connectorChannels []string
+	connectorSeen := make(map[string]bool)
 
 	// Process each period
-	for _, period := range periods {
+	for i, period := range periods {
 		year, month, err := parsePeriod(period)
 		if err != nil {
 			return nil, fmt.Errorf("invalid period format %s: %w", period, err)
 		}
 
-		// Get "simple" channel data (Direct totals)
-		directTotals, err := s.directRepo.GetTotalsMonthly(ctx, projectID, year, month)
+		// Get per-goal metrics; goals missing in a period get zeros
+		goalMetrics, err := s.metricsRepo.GetGoalMetrics(ctx, projectID, year, month)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get direct totals: %w", err)
+			return nil, fmt.Errorf("failed to get goal metrics: %w", err)
 		}
-		if directTotals != nil {
-			simpleMetrics.CPC = append(simpleMetrics.CPC, directTotals.CPC)
-			simpleMetrics.Impressions = append(simpleMetrics.Impressions, directTotals.Impressions)
-			simpleMetrics.Clicks = append(simpleMetrics.Clicks, directTotals.Clicks)
-			simpleMetrics.CTR = append(simpleMetrics.CTR, directTotals.CTRPct)
-			if directTotals.Conversions != nil {
-				simpleMetrics.Conversions = append(simpleMetrics.Conversions, *directTotals.Conversions)
-			} else {
-				simpleMetrics.Conversions = append(simpleMetrics.Conversions, 0)
-			}
-			if directTotals.CPA != nil {
-				simpleMetrics.CPA = append(simpleMetrics.CPA, *directTotals.CPA)
-			} else {
-				simpleMetrics.CPA = append(simpleMetrics.CPA, 0)
+		for _, goal := range goalMetrics {
+			key := goalKey{counterID: goal.CounterID, goalID: goal.GoalID}
+			metrics, ok := goalsByKey[key]
+			if !ok {
+				metrics = &GoalMetrics{
+					GoalID:         goal.GoalID,
+					Name:           goal.GoalName,
+					Reaches:        make([]int, len(periods)),
+					Visits:         make([]int, len(periods)),
+					ConversionRate: make([]float64, len(periods)),
+				}
+				goalsByKey[key] = metrics
+				output.Goals = append(output.Goals, metrics)
 			}
-			simpleMetrics.Cost = append(simpleMetrics.Cost, directTotals.Cost)
-		} else {
-			// Add zeros if no data
-			simpleMetrics.CPC = append(simpleMetrics.CPC, 0)
-			simpleMetrics.Impressions = append(simpleMetrics.Impressions, 0)
-			simpleMetrics.Clicks = append(simpleMetrics.Clicks, 0)
-			simpleMetrics.CTR = append(simpleMetrics.CTR, 0)
-			simpleMetrics.Conversions = append(simpleMetrics.Conversions, 0)
-			simpleMetrics.CPA = append(simpleMetrics.CPA, 0)
-			simpleMetrics.Cost = append(simpleMetrics.Cost, 0)
-		}
-
-		// Get "МК" channel data (Metrica metrics)
-		metrics, err := s.metricsRepo.GetMonthlyMetrics(ctx, projectID, year, month)
+			metrics.Reaches[i] = goal.Reaches
+			metrics.Visits[i] = goal.Visits
+			metrics.ConversionRate[i] = goal.ConversionRate
+		}
+
+		// Sum campaigns of the month by channel
+		directCampaigns, err := s.directRepo.GetCampaignMonthly(ctx, projectID, year, month)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get metrica metrics: %w", err)
+			return nil, fmt.Errorf("failed to get direct campaigns: %w", err)
 		}
-		if metrics != nil {
-			// For Metrica, we don't have CPC/CPA directly, so we'll use 0 or calculate from other data
-			// Metrica doesn't have impressions/clicks in the same way, so we'll use visits/users
-			mkMetrics.CPC = append(mkMetrics.CPC, 0)                              // Metrica doesn't have CPC
-			mkMetrics.Impressions = append(mkMetrics.Impressions, metrics.Visits) // Using visits as approximation
-			mkMetrics.Clicks = append(mkMetrics.Clicks, metrics.Users)            // Using users as approximation
-			mkMetrics.CTR = append(mkMetrics.CTR, 0)                              // Metrica doesn't have CTR in this context
-			if metrics.Conversions != nil {
-				mkMetrics.Conversions = append(mkMetrics.Conversions, *metrics.Conversions)
-			} else {
-				mkMetrics.Conversions = append(mkMetrics.Conversions, 0)
+		periodMetrics := make(map[string]*directMetrics, len(channels))
+		addMetrics := func(channel string, values directMetrics) {
+			metrics, ok := periodMetrics[channel]
+			if !ok {
+				metrics = &directMetrics{}
+				periodMetrics[channel] = metrics
 			}
-			mkMetrics.CPA = append(mkMetrics.CPA, 0)   // Metrica doesn't have CPA
-			mkMetrics.Cost = append(mkMetrics.Cost, 0) // Metrica doesn't track cost
-		} else {
-			// Add zeros if no data
-			mkMetrics.CPC = append(mkMetrics.CPC, 0)
-			mkMetrics.Impressions = append(mkMetrics.Impressions, 0)
-			mkMetrics.Clicks = append(mkMetrics.Clicks, 0)
-			mkMetrics.CTR = append(mkMetrics.CTR, 0)
-			mkMetrics.Conversions = append(mkMetrics.Conversions, 0)
-			mkMetrics.CPA = append(mkMetrics.CPA, 0)
-			mkMetrics.Cost = append(mkMetrics.Cost, 0)
+			metrics.add(values)
 		}
+		networkShares := make(map[uint]float64) // Share of clicks in networks of split campaigns
+		for _, campaignMonthly := range directCampaigns {
+			var values directMetrics
+			values.addMonthly(campaignMonthly)
+			if splitCampaigns[campaignMonthly.DirectCampaignID] {
+				search, network := values.splitByNetwork()
+				addMetrics(models.DirectChannelSearch, search)
+				addMetrics(models.DirectChannelNetwork, network)
+				if values.clicks > 0 {
+					networkShares[campaignMonthly.DirectCampaignID] = float64(network.clicks) / float64(values.clicks)
+				}
+				continue
+			}
 
-		// Get "РСЯ" channel data (sum of all Direct campaigns)
-		directCampaigns, err := s.directRepo.GetCampaignMonthly(ctx, projectID, year, month)
+			channel, ok := campaignChannels[campaignMonthly.DirectCampaignID]
+			if !ok {
+				channel = models.DirectChannelOther
+			}
+			addMetrics(channel, values)
+		}
+
+		// Sum Metrica revenue of campaigns of the month by channel
+		// Revenue is not split by ad network type, split campaigns share it by clicks
+		revenueRows, err := s.metricsRepo.GetCampaignRevenue(ctx, projectID, year, month)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get direct campaigns: %w", err)
+			return nil, fmt.Errorf("failed to get campaign revenue: %w", err)
+		}
+		periodRevenue := make(map[string]float64, len(channels))
+		for campaignID, revenue := range campaignRevenue(revenueRows) {
+			directCampaignID, ok := yandexCampaignIDs[campaignID]
+			if !ok {
+				periodRevenue[models.DirectChannelOther] += revenue
+				continue
+			}
+			if share, ok := networkShares[directCampaignID]; ok {
+				periodRevenue[models.DirectChannelNetwork] += revenue * share
+				periodRevenue[models.DirectChannelSearch] += revenue * (1 - share)
+				continue
+			}
+			periodRevenue[campaignChannels[directCampaignID]] += revenue
 		}
 
-		var rsyaImpressions, rsyaClicks, rsyaConversions int
-		var rsyaCost, rsyaCPC, rsyaCTR, rsyaCPA float64
+		for channel, channelMetrics := range channels {
+			metrics, ok := periodMetrics[channel]
+			if !ok {
+				metrics = &directMetrics{}
+			}
+			revenue := periodRevenue[channel]
+			if rates != nil {
+				metrics.cost *= rates[period]
+				revenue *= rates[period]
+			}
+			channelMetrics.append(metrics, revenue)
+		}
 
-		for _, campaign := range directCampaigns {
-			rsyaImpressions += campaign.Impressions
-			rsyaClicks += campaign.Clicks
-			rsyaCost += campaign.Cost
-			if campaign.Conversions != nil {
-				rsyaConversions += *campaign.Conversions
+		channelRows, err := s.channelRepo.GetCampaignMonthly(ctx, projectID, year, month)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channel campaigns: %w", err)
+		}
+		connectorMetrics[i] = make(map[string]*directMetrics)
+		for _, row := range channelRows {
+			metrics, ok := connectorMetrics[i][row.Channel]
+			if !ok {
+				metrics = &directMetrics{}
+				connectorMetrics[i][row.Channel] = metrics
 			}
+			if !connectorSeen[row.Channel] {
+				connectorSeen[row.Channel] = true
+				connectorChannels = append(connectorChannels, row.Channel)
+			}
+			metrics.addChannelMonthly(row)
 		}
+	}
 
-		// Calculate aggregated metrics for РСЯ
-		if rsyaClicks > 0 {
-			rsyaCTR = (float64(rsyaClicks) / float64(rsyaImpressions)) * 100
-			rsyaCPC = rsyaCost / float64(rsyaClicks)
+	// Add metrics to output; campaigns of other types are shown only if present
+	for _, channel := range directChannelNames {
+		channelMetrics := channels[channel.channel]
+		if channel.channel == models.DirectChannelOther && channelMetrics.isEmpty() {
+			continue
 		}
-		if rsyaConversions > 0 {
-			rsyaCPA = rsyaCost / float64(rsyaConversions)
+		output.Metrics[channel.name] = channelMetrics
+	}
+	for _, channel := range connectorChannels {
+		channelMetrics := &ChannelMetrics{}
+		for i, period := range periods {
+			metrics, ok := connectorMetrics[i][channel]
+			if !ok {
+				metrics = &directMetrics{}
+			}
+			if rates != nil {
+				metrics.cost *= rates[period]
+			}
+			channelMetrics.append(metrics, 0)
 		}
+		output.Metrics[channelName(channel)] = channelMetrics
+	}
+
+	return output, nil
+}
+
+// channelNames maps channels of connectors to names used in reports
+var channelNames = map[string]string{
+	models.ChannelVKAds: "VK Реклама",
+}
 
-		rsyaMetrics.CPC = append(rsyaMetrics.CPC, rsyaCPC)
-		rsyaMetrics.Impressions = append(rsyaMetrics.Impressions, rsyaImpressions)
-		rsyaMetrics.Clicks = append(rsyaMetrics.Clicks, rsyaClicks)
-		rsyaMetrics.CTR = append(rsyaMetrics.CTR, rsyaCTR)
-		rsyaMetrics.Conversions = append(rsyaMetrics.Conversions, rsyaConversions)
-		rsyaMetrics.CPA = append(rsyaMetrics.CPA, rsyaCPA)
-		rsyaMetrics.Cost = append(rsyaMetrics.Cost, rsyaCost)
+// channelName returns the display name of a connector channel, the channel itself if unknown
+func channelName(channel string) string {
+	if name, ok := channelNames[channel]; ok {
+		return name
 	}
+	return channel
+}
 
-	// Add metrics to output
-	output.Metrics["simple"] = simpleMetrics
-	output.Metrics["МК"] = mkMetrics
-	output.Metrics["РСЯ"] = rsyaMetrics
+// directChannelNames maps Direct channels to names used in channel metrics
+var directChannelNames = []struct {
+	channel string
+	name    string
+}{
+	{channel: models.DirectChannelSearch, name: "Поиск"},
+	{channel: models.DirectChannelNetwork, name: "РСЯ"},
+	{channel: models.DirectChannelMaster, name: "МК"},
+	{channel: models.DirectChannelSmart, name: "Смарт-баннеры"},
+	{channel: models.DirectChannelOther, name: "Прочее"},
+}
 
-	return output, nil
+// append adds values and revenue of a period to the channel
+func (c *ChannelMetrics) append(metrics *directMetrics, revenue float64) {
+	ctr, cpc, cpa := metrics.rates()
+	var cpaValue float64
+	if cpa != nil {
+		cpaValue = *cpa
+	}
+	c.CPC = append(c.CPC, cpc)
+	c.Impressions = append(c.Impressions, metrics.impressions)
+	c.Clicks = append(c.Clicks, metrics.clicks)
+	c.CTR = append(c.CTR, ctr)
+	c.Conversions = append(c.Conversions, metrics.conversions)
+	c.CPA = append(c.CPA, cpaValue)
+	c.Cost = append(c.Cost, metrics.cost)
+	c.Revenue = append(c.Revenue, revenue)
+	c.ROAS = append(c.ROAS, roasValue(revenue, metrics.cost))
+}
+
+// isEmpty checks whether the channel has no impressions and cost in all periods
+func (c *ChannelMetrics) isEmpty() bool {
+	for i := range c.Impressions {
+		if c.Impressions[i] > 0 || c.Cost[i] > 0 || c.Revenue[i] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// goalKey identifies a goal of a counter
+type goalKey struct {
+	counterID uint
+	goalID    int64
 }
 
 // parsePeriod parses a period string "YYYY-MM" into year and month
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+func TestReportService_GetChannelMetrics_Goals(t *testing.T) {
+	metricsRepo := &MockMetricsRepository{
+		GetGoalMetricsFunc: func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsGoalMonthly, error) {
+			switch month {
+			case 2:
+				return []*models.MetricsGoalMonthly{
+					{CounterID: 1, GoalID: 10, GoalName: "Заявка", Reaches: 30, Visits: 25, ConversionRate: 2.5},
+					{CounterID: 1, GoalID: 20, GoalName: "Звонок", Reaches: 8, Visits: 8, ConversionRate: 0.8},
+				}, nil
+			case 1:
+				return []*models.MetricsGoalMonthly{
+					{CounterID: 1, GoalID: 10, GoalName: "Заявка", Reaches: 20, Visits: 18, ConversionRate: 2},
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+	projectRepo := &MockProjectRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+			return &models.Project{ID: id, Name: "Тест"}, nil
+		},
+	}
+	service := NewReportService(metricsRepo, &MockDirectRepositoryForDirectService{}, &MockSEORepository{}, projectRepo, &MockChannelRepository{}, &MockCRMRepository{}, nil, nil)
+
+	output, err := service.GetChannelMetrics(context.Background(), 1, []string{"2025-02", "2025-01"}, "")
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(output.Goals) != 2 {
+		t.Fatalf("ожидалось 2 цели, получили %d", len(output.Goals))
+	}
+	lead := output.Goals[0]
+	if lead.GoalID != 10 || lead.Reaches[0] != 30 || lead.Reaches[1] != 20 || lead.ConversionRate[1] != 2 {
+		t.Errorf("неверные данные цели 'Заявка': %+v", lead)
+	}
+	call := output.Goals[1]
+	if call.Name != "Звонок" || call.Reaches[0] != 8 || call.Reaches[1] != 0 || call.Visits[1] != 0 {
+		t.Errorf("цель без данных в периоде должна получить нули: %+v", call)
+	}
+}
+
+func TestMetricaData_BreakdownDynamics(t *testing.T) {
+	data := &MetricaData{}
+	data.addBreakdowns("2025-02", []*models.MetricsBreakdownMonthly{
+		{Dimension: models.BreakdownDevice, Value: "mobile", Name: "Смартфоны", Visits: 300, Users: 200, BounceRate: 30, AvgSessionDurationSec: 60},
+		{Dimension: models.BreakdownDevice, Value: "desktop", Name: "ПК", Visits: 100, Users: 90, BounceRate: 20, AvgSessionDurationSec: 120},
+		{Dimension: models.BreakdownCity, Value: "213", Name: "Москва", Visits: 50, Users: 40},
+	})
+	data.addBreakdowns("2025-01", []*models.MetricsBreakdownMonthly{
+		{Dimension: models.BreakdownDevice, Value: "mobile", Name: "Смартфоны", Visits: 200, Users: 100, BounceRate: 40, AvgSessionDurationSec: 60},
+	})
+	data.Age = []MetricaAgeRow{
+		{Month: "2025-02", Age: "25-34", Visits: 110, Users: 100},
+		{Month: "2025-01", Age: "25-34", Visits: 100, Users: 100},
+	}
+
+	data.calculateBreakdownDynamics("2025-02", "2025-01")
+
+	if len(data.Devices) != 3 || len(data.Cities) != 1 || len(data.Gender) != 0 {
+		t.Fatalf("неверное распределение по разделам: %d устройств, %d городов, %d полов", len(data.Devices), len(data.Cities), len(data.Gender))
+	}
+
+	mobile := data.Devices[0]
+	if mobile.SharePct != 75 || data.Cities[0].SharePct != 100 {
+		t.Errorf("доля считается внутри разреза: %v, %v", mobile.SharePct, data.Cities[0].SharePct)
+	}
+	if mobile.Dynamics == nil || mobile.Dynamics.Visits != 50 || mobile.Dynamics.Users != 100 || mobile.Dynamics.Bounce != -25 {
+		t.Errorf("неверная динамика смартфонов: %+v", mobile.Dynamics)
+	}
+	if data.Devices[1].Dynamics != nil {
+		t.Errorf("значение без данных за прошлый месяц не должно получать динамику: %+v", data.Devices[1].Dynamics)
+	}
+	if data.Devices[2].Dynamics != nil {
+		t.Errorf("строки прошлого месяца не должны получать динамику")
+	}
+	if data.Age[0].Dynamics == nil || data.Age[0].Dynamics.Visits != 10 {
+		t.Errorf("неверная динамика возрастной группы: %+v", data.Age[0].Dynamics)
+	}
+}
+
+func TestReportService_GetChannelMetrics_Channels(t *testing.T) {
+	intValue := func(v int) *int { return &v }
+	directRepo := &MockDirectRepositoryForDirectService{
+		GetCampaignsByProjectIDFunc: func(ctx context.Context, projectID uint) ([]*models.DirectCampaign, error) {
+			return []*models.DirectCampaign{
+				{ID: 1, CampaignID: 101, Type: models.DirectCampaignTypeText, SearchStrategy: "HIGHEST_POSITION", NetworkStrategy: models.DirectStrategyServingOff},
+				{ID: 2, CampaignID: 102, Type: models.DirectCampaignTypeText, SearchStrategy: models.DirectStrategyServingOff, NetworkStrategy: "WB_MAXIMUM_CLICKS"},
+				{ID: 3, Type: models.DirectCampaignTypeUnified},
+				{ID: 4, Type: models.DirectCampaignTypeSmart},
+				{ID: 5, Type: models.DirectCampaignTypeText, SearchStrategy: models.DirectStrategyServingOff},
+				{ID: 6, CampaignID: 106, Type: models.DirectCampaignTypeText, SearchStrategy: "HIGHEST_POSITION", NetworkStrategy: "MAXIMUM_COVERAGE"},
+			}, nil
+		},
+		GetCampaignMonthlyFunc: func(ctx context.Context, projectID uint, year int, month int) ([]*models.DirectCampaignMonthly, error) {
+			if month != 2 {
+				return nil, nil
+			}
+			return []*models.DirectCampaignMonthly{
+				{DirectCampaignID: 1, Impressions: 1000, Clicks: 100, Cost: 5000, Conversions: intValue(10)},
+				{DirectCampaignID: 2, Impressions: 10000, Clicks: 50, Cost: 1000},
+				{DirectCampaignID: 5, Impressions: 10000, Clicks: 150, Cost: 2000, Conversions: intValue(5)},
+				{DirectCampaignID: 3, Impressions: 500, Clicks: 20, Cost: 800},
+				// Shown both on search and in networks, split by ad network type
+				{DirectCampaignID: 6, Impressions: 3000, Clicks: 40, Cost: 1300, Conversions: intValue(3),
+					NetworkImpressions: 2000, NetworkClicks: 20, NetworkCost: 300, NetworkConversions: 1},
+			}, nil
+		},
+	}
+	projectRepo := &MockProjectRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+			return &models.Project{ID: id, Name: "Тест"}, nil
+		},
+	}
+	channelRepo := &MockChannelRepository{Rows: []*models.ChannelCampaignMonthly{
+		{ProjectID: 1, Source: models.ChannelVKAds, Channel: models.ChannelVKAds, CampaignID: "101", Year: 2025, Month: 1, Impressions: 4000, Clicks: 40, Cost: 1200, Conversions: intValue(3)},
+		{ProjectID: 1, Source: models.ChannelVKAds, Channel: models.ChannelVKAds, CampaignID: "102", Year: 2025, Month: 1, Impressions: 1000, Clicks: 20, Cost: 600},
+	}}
+	metricsRepo := &MockMetricsRepository{
+		GetCampaignRevenueFunc: func(ctx context.Context, projectID uint, year int, month int) ([]*models.MetricsCampaignRevenueMonthly, error) {
+			if month != 2 {
+				return nil, nil
+			}
+			return []*models.MetricsCampaignRevenueMonthly{
+				{CampaignID: 101, Revenue: 10000, GoalRevenue: 5000},
+				{CampaignID: 102, GoalRevenue: 1500},
+				{CampaignID: 106, Revenue: 6000}, // Split by clicks in half
+			}, nil
+		},
+	}
+	service := NewReportService(metricsRepo, directRepo, &MockSEORepository{}, projectRepo, channelRepo, &MockCRMRepository{}, nil, nil)
+
+	output, err := service.GetChannelMetrics(context.Background(), 1, []string{"2025-02", "2025-01"}, "")
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	for _, name := range []string{"Поиск", "РСЯ", "МК", "Смарт-баннеры"} {
+		if _, ok := output.Metrics[name]; !ok {
+			t.Errorf("ожидался канал %s", name)
+		}
+	}
+	if _, ok := output.Metrics["Прочее"]; ok {
+		t.Errorf("пустой канал 'Прочее' не должен выводиться")
+	}
+
+	search := output.Metrics["Поиск"]
+	if search.Clicks[0] != 120 || search.Conversions[0] != 12 || search.CPA[0] != 500 || search.Clicks[1] != 0 {
+		t.Errorf("неверные данные поиска: %+v", search)
+	}
+	if search.Revenue[0] != 18000 || search.ROAS[0] != 300 || search.Revenue[1] != 0 || search.ROAS[1] != 0 {
+		t.Errorf("неверная выручка поиска: %v, ROAS %v", search.Revenue, search.ROAS)
+	}
+	network := output.Metrics["РСЯ"]
+	if network.Impressions[0] != 22000 || network.Clicks[0] != 220 || network.Cost[0] != 3300 || network.CPC[0] != 15 || network.CTR[0] != 1 ||
+		network.Conversions[0] != 6 || network.Revenue[0] != 4500 {
+		t.Errorf("неверные данные РСЯ: %+v", network)
+	}
+	if output.Metrics["МК"].Cost[0] != 800 || output.Metrics["Смарт-баннеры"].Impressions[0] != 0 {
+		t.Errorf("неверные данные МК и смарт-баннеров")
+	}
+
+	vk, ok := output.Metrics["VK Реклама"]
+	if !ok {
+		t.Fatalf("ожидался канал VK Реклама")
+	}
+	if len(vk.Cost) != 2 || vk.Cost[0] != 0 || vk.Cost[1] != 1800 || vk.Clicks[1] != 60 || vk.Conversions[1] != 3 || vk.CPA[1] != 600 {
+		t.Errorf("неверные данные VK Рекламы: %+v", vk)
+	}
+}
+
+func TestReportService_ConvertReport(t *testing.T) {
+	rub := "RUB"
+	projectRepo := &MockProjectRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+			return &models.Project{ID: id, Currency: "KZT", ReportingCurrency: &rub}, nil
+		},
+	}
+	rates := NewExchangeRateService(&MockExchangeRateRepository{Rates: []*models.ExchangeRate{
+		{Base: "KZT", Quote: "RUB", Date: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), Rate: 0.18},
+		{Base: "KZT", Quote: "RUB", Date: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), Rate: 0.2},
+	}}, nil)
+	service := NewReportService(&MockMetricsRepository{}, &MockDirectRepositoryForDirectService{}, &MockSEORepository{}, projectRepo, &MockChannelRepository{}, &MockCRMRepository{}, rates, nil)
+
+	newReport := func() *Report {
+		cpa := 500.0
+		revenue, roas := revenueWithROAS(30000, true, 10000)
+		aov, goalRevenue := 3000.0, 5000.0
+		return &Report{
+			ProjectID: 1,
+			Periods:   []string{"2025-02", "2025-01"},
+			Currency:  "KZT",
+			Metrica: MetricaData{
+				Summary: []MetricaSummaryRow{{Month: "2025-02", Revenue: revenue, AvgOrderValue: &aov, GoalRevenue: &goalRevenue}},
+			},
+			Direct: DirectData{
+				Totals: []DirectTotalsRow{
+					{Month: "2025-02", Cpc: 100, Cpa: &cpa, Cost: 10000, Sales: (&SalesMetrics{Sales: 2, Revenue: 50000}).withCost(10000), Revenue: revenue, Roas: roas},
+					{Month: "2025-01", Cpc: 100, Cost: 10000},
+				},
+				Campaigns: []DirectCampaignData{{CampaignID: 7, Rows: []DirectCampaignRow{{Month: "2025-01", Cpc: 50, Cost: 1000}}}},
+			},
+			Channels: ChannelsData{
+				Totals:    []ChannelTotalsRow{{Month: "2025-02", Channel: models.ChannelVKAds, Cpc: 10, Cost: 5000}},
+				Campaigns: []ChannelCampaignData{{CampaignID: "101", Rows: []DirectCampaignRow{{Month: "2025-02", Cost: 5000}}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		currency     string
+		wantCurrency string
+		wantCost     []float64
+		wantErrText  string
+	}{
+		{name: "валюта отчётов проекта по умолчанию", currency: "", wantCurrency: "RUB", wantCost: []float64{2000, 1800}},
+		{name: "собственная валюта проекта", currency: "native", wantCurrency: "KZT", wantCost: []float64{10000, 10000}},
+		{name: "неизвестная валюта", currency: "xxx", wantErrText: "invalid currency: XXX"},
+		{name: "нет курса", currency: "USD", wantErrText: "no exchange rate KZT/USD on 2025-02-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := service.ConvertReport(context.Background(), newReport(), tt.currency)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if report.Currency != tt.wantCurrency {
+				t.Errorf("ожидалась валюта %s, получили %s", tt.wantCurrency, report.Currency)
+			}
+			for i, cost := range tt.wantCost {
+				if math.Abs(report.Direct.Totals[i].Cost-cost) > 1e-6 {
+					t.Errorf("ожидался расход %v за %s, получили %v", cost, report.Direct.Totals[i].Month, report.Direct.Totals[i].Cost)
+				}
+			}
+		})
+	}
+
+	report, err := service.ConvertReport(context.Background(), newReport(), "RUB")
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if *report.Direct.Totals[0].Cpa != 100 || report.Direct.Campaigns[0].Rows[0].Cost != 180 {
+		t.Errorf("CPA и расходы кампаний должны конвертироваться: %+v", report.Direct)
+	}
+	if report.Channels.Totals[0].Cost != 1000 || report.Channels.Totals[0].Cpc != 2 || report.Channels.Campaigns[0].Rows[0].Cost != 1000 {
+		t.Errorf("расходы дополнительных каналов должны конвертироваться: %+v", report.Channels)
+	}
+	if sales := report.Direct.Totals[0].Sales; sales.Revenue != 10000 || *sales.CostPerSale != 1000 || *sales.ROMI != 400 {
+		t.Errorf("выручка и стоимость продажи должны конвертироваться, ROMI - нет: %+v", sales)
+	}
+	if totals := report.Direct.Totals[0]; *totals.Revenue != 6000 || *totals.Roas != 300 {
+		t.Errorf("выручка Метрики должна конвертироваться, ROAS - нет: %v, %v", *totals.Revenue, *totals.Roas)
+	}
+	if summary := report.Metrica.Summary[0]; *summary.Revenue != 6000 || *summary.AvgOrderValue != 600 || *summary.GoalRevenue != 1000 {
+		t.Errorf("выручка сводки должна конвертироваться: %+v", summary)
+	}
+}
+
+func TestMonthSales(t *testing.T) {
+	campaignID := int64(101)
+	otherCampaignID := int64(102)
+	sales := newMonthSales([]*models.CRMDeal{
+		{DealID: "1", Status: models.CRMDealStatusWon, Revenue: 30000, CampaignID: &campaignID, TrafficSource: models.TrafficSourceAd},
+		{DealID: "2", Status: models.CRMDealStatusWon, Revenue: 20000, CampaignID: &campaignID, TrafficSource: models.TrafficSourceAd},
+		{DealID: "3", Status: models.CRMDealStatusLost, Revenue: 5000, CampaignID: &otherCampaignID, TrafficSource: models.TrafficSourceAd},
+		{DealID: "4", Status: models.CRMDealStatusWon, Revenue: 15000, TrafficSource: models.TrafficSourceOrganic},
+		{DealID: "5", Status: models.CRMDealStatusOpen},
+	})
+
+	direct := sales.directTotals(25000)
+	if direct.Deals != 3 || direct.Sales != 2 || direct.Revenue != 50000 {
+		t.Fatalf("неверные продажи Директа: %+v", direct)
+	}
+	if *direct.CostPerSale != 12500 || *direct.ROAS != 200 || *direct.ROMI != 100 {
+		t.Errorf("неверные стоимость продажи, ROAS и ROMI: %v, %v, %v", *direct.CostPerSale, *direct.ROAS, *direct.ROMI)
+	}
+
+	lost := sales.campaign(otherCampaignID, 5000)
+	if lost.Sales != 0 || lost.CostPerSale != nil || *lost.ROMI != -100 {
+		t.Errorf("кампания без продаж должна иметь ROMI -100%% и не иметь стоимости продажи: %+v", lost)
+	}
+	if sales.campaign(103, 1000) != nil {
+		t.Errorf("кампания без сделок не должна получать продажи")
+	}
+	if direct := sales.directTotals(0); direct.ROAS != nil || direct.ROMI != nil {
+		t.Errorf("без расхода ROAS и ROMI не считаются: %+v", direct)
+	}
+
+	organic := sales.source(models.TrafficSourceOrganic)
+	if organic.Sales != 1 || organic.Revenue != 15000 || organic.ROMI != nil {
+		t.Errorf("неверные продажи органики: %+v", organic)
+	}
+	if sales.source(models.TrafficSourceSocial) != nil {
+		t.Errorf("источник без сделок не должен получать продажи")
+	}
+	if newMonthSales(nil).directTotals(1000) != nil {
+		t.Errorf("месяц без сделок не должен получать продажи")
+	}
+}
+
+func TestChannelsData_AddMonth(t *testing.T) {
+	conversions := 4
+	data := &ChannelsData{}
+	campaigns := make(map[channelCampaignKey]int)
+
+	data.addMonth("2025-02", []*models.ChannelCampaignMonthly{
+		{Source: models.ChannelVKAds, Channel: models.ChannelVKAds, CampaignID: "101", CampaignName: "Бренд", Impressions: 1000, Clicks: 10, Cost: 400, Conversions: &conversions},
+		{Source: models.ChannelVKAds, Channel: models.ChannelVKAds, CampaignID: "102", CampaignName: "Ретаргетинг", Impressions: 1000, Clicks: 30, Cost: 400},
+		{Source: models.ChannelSourceManual, Channel: "Радио", Cost: 10000},
+	}, campaigns)
+	data.addMonth("2025-01", []*models.ChannelCampaignMonthly{
+		{Source: models.ChannelVKAds, Channel: models.ChannelVKAds, CampaignID: "101", CampaignName: "Бренд", Impressions: 500, Clicks: 5, Cost: 100},
+	}, campaigns)
+
+	if len(data.Totals) != 3 {
+		t.Fatalf("ожидалось 3 строки итогов, получили %d", len(data.Totals))
+	}
+	if radio := data.Totals[1]; radio.Source != models.ChannelSourceManual || radio.Name != "Радио" || radio.Cost != 10000 {
+		t.Errorf("неверные итоги ручного канала: %+v", radio)
+	}
+	totals := data.Totals[0]
+	if totals.Name != "VK Реклама" || totals.Clicks != 40 || totals.Ctr != 2 || totals.Cpc != 20 || totals.Cost != 800 ||
+		totals.Conv == nil || *totals.Conv != 4 || totals.Cpa == nil || *totals.Cpa != 200 {
+		t.Errorf("неверные итоги канала: %+v", totals)
+	}
+	if data.Totals[2].Conv != nil {
+		t.Errorf("месяц без конверсий не должен получать конверсии: %+v", data.Totals[2])
+	}
+
+	if len(data.Campaigns) != 2 || len(data.Campaigns[0].Rows) != 2 || data.Campaigns[0].Rows[1].Month != "2025-01" {
+		t.Errorf("строки кампании должны группироваться по месяцам, ручные каналы не имеют кампаний: %+v", data.Campaigns)
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/logger"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxSyncRunWarnings limits warnings stored with one run
+const maxSyncRunWarnings = 50
+
+// SyncRunService handles business logic for sync run history
+type SyncRunService struct {
+	syncRunRepo SyncRunRepositoryInterface
+	registry    *connectors.Registry // Sources of connectors are valid filter values too
+}
+
+// NewSyncRunService creates a new sync run service
+func NewSyncRunService(syncRunRepo SyncRunRepositoryInterface, registry *connectors.Registry) *SyncRunService {
+	return &SyncRunService{
+		syncRunRepo: syncRunRepo,
+		registry:    registry,
+	}
+}
+
+// GetRuns retrieves sync runs matching the filter, newest first
+func (s *SyncRunService) GetRuns(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error) {
+	if err := validateSyncRunFilter(filter, s.registry); err != nil {
+		return nil, 0, err
+	}
+	return s.syncRunRepo.List(ctx, filter, pagination)
+}
+
+// validateSyncRunFilter checks source and statuses of the filter
+func validateSyncRunFilter(filter models.SyncRunFilter, registry *connectors.Registry) error {
+	switch filter.Source {
+	case "", models.SyncSourceMetrica, models.SyncSourceDirect, models.SyncSourceWebmaster:
+	default:
+		if _, ok := registry.Get(filter.Source); !ok {
+			return fmt.Errorf("invalid source: %s", filter.Source)
+		}
+	}
+	for _, status := range filter.Statuses {
+		switch status {
+		case models.SyncStatusRunning, models.SyncStatusSuccess, models.SyncStatusPartial, models.SyncStatusFailed:
+		default:
+			return fmt.Errorf("invalid status: %s", status)
+		}
+	}
+	return nil
+}
+
+// syncRunReport collects rows and warnings of a running sync
+type syncRunReport struct {
+	mu       sync.Mutex
+	rows     int
+	warnings []string
+}
+
+type syncRunReportKey struct{}
+
+// syncWarn logs a warning of a sync and records it with the current run
+func syncWarn(ctx context.Context, message string, fields ...zap.Field) {
+	if logger.Log != nil {
+		logger.Log.Warn(message, fields...)
+	}
+
+	report, ok := ctx.Value(syncRunReportKey{}).(*syncRunReport)
+	if !ok {
+		return
+	}
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	if len(report.warnings) < maxSyncRunWarnings {
+		report.warnings = append(report.warnings, formatSyncWarning(message, fields))
+	}
+}
+
+// syncRows adds saved rows to the current run
+func syncRows(ctx context.Context, rows int) {
+	report, ok := ctx.Value(syncRunReportKey{}).(*syncRunReport)
+	if !ok {
+		return
+	}
+	report.mu.Lock()
+	report.rows += rows
+	report.mu.Unlock()
+}
+
+// formatSyncWarning renders warning as "message (key=value, ...): error"
+func formatSyncWarning(message string, fields []zap.Field) string {
+	encoder := zapcore.NewMapObjectEncoder()
+	var details []string
+	var errText string
+	for _, field := range fields {
+		if field.Type == zapcore.ErrorType {
+			if err, ok := field.Interface.(error); ok {
+				errText = err.Error()
+			}
+			continue
+		}
+		field.AddTo(encoder)
+		details = append(details, fmt.Sprintf("%s=%v", field.Key, encoder.Fields[field.Key]))
+	}
+
+	text := message
+	if len(details) > 0 {
+		text += " (" + strings.Join(details, ", ") + ")"
+	}
+	if errText != "" {
+		text += ": " + errText
+	}
+	return text
+}
+
+// trackRun records a sync attempt of a source in sync run history
+func (s *SyncService) trackRun(ctx context.Context, projectID uint, source string, year, month int, syncFn func(ctx context.Context, projectID uint, year, month int) error) error {
+	return trackSyncRun(ctx, s.syncRunRepo, projectID, source, year, month, syncFn)
+}
+
+// trackSyncRun runs syncFn and records the attempt in sync run history
+// Failure to write history is logged and does not affect the sync itself
+func trackSyncRun(ctx context.Context, syncRunRepo SyncRunRepositoryInterface, projectID uint, source string, year, month int, syncFn func(ctx context.Context, projectID uint, year, month int) error) error {
+	if syncRunRepo == nil {
+		return syncFn(ctx, projectID, year, month)
+	}
+
+	run := &models.SyncRun{
+		ProjectID: projectID,
+		Source:    source,
+		Year:      year,
+		Month:     month,
+		Status:    models.SyncStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := syncRunRepo.Create(ctx, run); err != nil {
+		if logger.Log != nil {
+			logger.Log.Error("Failed to record sync run",
+				zap.Uint("project_id", projectID),
+				zap.String("source", source),
+				zap.Error(err),
+			)
+		}
+		return syncFn(ctx, projectID, year, month)
+	}
+
+	report := &syncRunReport{}
+	syncErr := syncFn(context.WithValue(ctx, syncRunReportKey{}, report), projectID, year, month)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.RowsSynced = report.rows
+	run.Warnings = report.warnings
+	switch {
+	case syncErr != nil:
+		run.Status = models.SyncStatusFailed
+		run.Error = syncErr.Error()
+	case len(report.warnings) > 0:
+		run.Status = models.SyncStatusPartial
+	default:
+		run.Status = models.SyncStatusSuccess
+	}
+
+	// Result is saved even if the task context was cancelled
+	if err := syncRunRepo.Update(context.WithoutCancel(ctx), run); err != nil && logger.Log != nil {
+		logger.Log.Error("Failed to save sync run result",
+			zap.Uint("sync_run_id", run.ID),
+			zap.Error(err),
+		)
+	}
+
+	return syncErr
+}
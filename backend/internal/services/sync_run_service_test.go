@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/middleware"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// MockSyncRunRepository implements SyncRunRepositoryInterface for testing
+type MockSyncRunRepository struct {
+	CreateFunc func(ctx context.Context, run *models.SyncRun) error
+	UpdateFunc func(ctx context.Context, run *models.SyncRun) error
+	ListFunc   func(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error)
+}
+
+func (m *MockSyncRunRepository) Create(ctx context.Context, run *models.SyncRun) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, run)
+	}
+	return nil
+}
+
+func (m *MockSyncRunRepository) Update(ctx context.Context, run *models.SyncRun) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, run)
+	}
+	return nil
+}
+
+func (m *MockSyncRunRepository) List(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter, pagination)
+	}
+	return nil, 0, nil
+}
+
+func TestSyncService_TrackRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		syncFn       func(ctx context.Context, projectID uint, year, month int) error
+		wantStatus   string
+		wantRows     int
+		wantWarnings []string
+		wantError    string
+	}{
+		{
+			name: "успешная синхронизация",
+			syncFn: func(ctx context.Context, projectID uint, year, month int) error {
+				syncRows(ctx, 30)
+				return nil
+			},
+			wantStatus: models.SyncStatusSuccess,
+			wantRows:   30,
+		},
+		{
+			name: "синхронизация с предупреждениями",
+			syncFn: func(ctx context.Context, projectID uint, year, month int) error {
+				syncWarn(ctx, "Failed to sync Direct account",
+					zap.Uint("account_id", 7),
+					zap.String("client_login", "client"),
+					zap.Error(errors.New("token expired")),
+				)
+				syncRows(ctx, 10)
+				return nil
+			},
+			wantStatus:   models.SyncStatusPartial,
+			wantRows:     10,
+			wantWarnings: []string{"Failed to sync Direct account (account_id=7, client_login=client): token expired"},
+		},
+		{
+			name: "ошибка синхронизации",
+			syncFn: func(ctx context.Context, projectID uint, year, month int) error {
+				return errors.New("failed to sync any Direct account")
+			},
+			wantStatus: models.SyncStatusFailed,
+			wantError:  "failed to sync any Direct account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.SyncRun
+			repo := &MockSyncRunRepository{
+				CreateFunc: func(ctx context.Context, run *models.SyncRun) error {
+					if run.Status != models.SyncStatusRunning {
+						t.Errorf("ожидался статус running при создании, получили %s", run.Status)
+					}
+					run.ID = 1
+					return nil
+				},
+				UpdateFunc: func(ctx context.Context, run *models.SyncRun) error {
+					saved = run
+					return nil
+				},
+			}
+			service := &SyncService{syncRunRepo: repo}
+
+			err := service.trackRun(context.Background(), 5, models.SyncSourceDirect, 2025, 3, tt.syncFn)
+
+			if (err != nil) != (tt.wantError != "") {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if saved == nil {
+				t.Fatal("результат запуска не сохранён")
+			}
+			if saved.Status != tt.wantStatus || saved.RowsSynced != tt.wantRows || saved.Error != tt.wantError {
+				t.Errorf("неверный запуск: %+v", saved)
+			}
+			if saved.ProjectID != 5 || saved.Source != models.SyncSourceDirect || saved.Year != 2025 || saved.Month != 3 || saved.FinishedAt == nil {
+				t.Errorf("неверные параметры запуска: %+v", saved)
+			}
+			if len(saved.Warnings) != len(tt.wantWarnings) {
+				t.Fatalf("ожидалось %d предупреждений, получили %v", len(tt.wantWarnings), saved.Warnings)
+			}
+			for i, warning := range tt.wantWarnings {
+				if saved.Warnings[i] != warning {
+					t.Errorf("ожидалось предупреждение '%s', получили '%s'", warning, saved.Warnings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSyncService_TrackRun_HistoryUnavailable(t *testing.T) {
+	repo := &MockSyncRunRepository{
+		CreateFunc: func(ctx context.Context, run *models.SyncRun) error {
+			return errors.New("database is down")
+		},
+		UpdateFunc: func(ctx context.Context, run *models.SyncRun) error {
+			t.Error("результат не должен сохраняться без созданного запуска")
+			return nil
+		},
+	}
+	service := &SyncService{syncRunRepo: repo}
+
+	called := false
+	err := service.trackRun(context.Background(), 5, models.SyncSourceMetrica, 2025, 3, func(ctx context.Context, projectID uint, year, month int) error {
+		called = true
+		return nil
+	})
+
+	if err != nil || !called {
+		t.Errorf("синхронизация должна выполняться без истории: called=%v, err=%v", called, err)
+	}
+}
+
+func TestSyncRunService_GetRuns(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      models.SyncRunFilter
+		wantErrText string
+	}{
+		{
+			name:   "фильтр по ошибкам",
+			filter: models.SyncRunFilter{Source: models.SyncSourceDirect, Statuses: []string{models.SyncStatusFailed, models.SyncStatusPartial}},
+		},
+		{
+			name:        "неизвестный источник",
+			filter:      models.SyncRunFilter{Source: "vk"},
+			wantErrText: "invalid source: vk",
+		},
+		{
+			name:   "источник коннектора",
+			filter: models.SyncRunFilter{Source: "test_ads"},
+		},
+		{
+			name:        "неизвестный статус",
+			filter:      models.SyncRunFilter{Statuses: []string{"broken"}},
+			wantErrText: "invalid status: broken",
+		},
+	}
+
+	registry := connectors.NewRegistry()
+	if err := registry.Register(&MockConnector{SourceName: "test_ads"}); err != nil {
+		t.Fatalf("не удалось зарегистрировать коннектор: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed := false
+			service := NewSyncRunService(&MockSyncRunRepository{
+				ListFunc: func(ctx context.Context, filter models.SyncRunFilter, pagination *middleware.Pagination) ([]*models.SyncRun, int64, error) {
+					listed = true
+					return nil, 0, nil
+				},
+			}, registry)
+
+			_, _, err := service.GetRuns(context.Background(), tt.filter, middleware.DefaultPagination())
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				if listed {
+					t.Error("запрос к репозиторию не должен выполняться при ошибке")
+				}
+				return
+			}
+			if err != nil || !listed {
+				t.Errorf("ожидался запрос к репозиторию, err=%v", err)
+			}
+		})
+	}
+}
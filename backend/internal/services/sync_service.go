@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/suprt/planica_bi/backend/internal/integrations"
@@ -11,6 +14,12 @@ import (
 	"go.uber.org/zap"
 )
 
+// minDirectUnits is the Units balance below which Direct accounts are not synced
+const minDirectUnits = 100
+
+// errNotEnoughDirectUnits marks Direct accounts skipped because their Units are spent
+var errNotEnoughDirectUnits = errors.New("not enough Direct units")
+
 // SyncService handles data synchronization with Yandex APIs
 type SyncService struct {
 	projectRepo   ProjectRepositoryInterface
@@ -18,8 +27,13 @@ type SyncService struct {
 	directRepo    DirectRepositoryInterface
 	counterRepo   CounterRepositoryInterface
 	goalRepo      GoalRepositoryInterface
-	metricaClient *integrations.YandexMetricaClient
-	directClient  *integrations.YandexDirectClient
+	seoRepo       SEORepositoryInterface
+	syncRunRepo   SyncRunRepositoryInterface
+	credentials   *OAuthCredentialService
+	rates         *ExchangeRateService // Converts Direct costs from account to project currency
+	directSandbox bool
+	// defaultCurrency is assumed for Direct accounts whose currency is not known yet
+	defaultCurrency string
 }
 
 // NewSyncService creates a new sync service
@@ -29,18 +43,54 @@ func NewSyncService(
 	directRepo DirectRepositoryInterface,
 	counterRepo CounterRepositoryInterface,
 	goalRepo GoalRepositoryInterface,
-	metricaClient *integrations.YandexMetricaClient,
-	directClient *integrations.YandexDirectClient,
+	seoRepo SEORepositoryInterface,
+	syncRunRepo SyncRunRepositoryInterface,
+	credentials *OAuthCredentialService,
+	rates *ExchangeRateService,
+	directSandbox bool,
+	defaultCurrency string,
 ) *SyncService {
 	return &SyncService{
-		projectRepo:   projectRepo,
-		metricsRepo:   metricsRepo,
-		directRepo:    directRepo,
-		counterRepo:   counterRepo,
-		goalRepo:      goalRepo,
-		metricaClient: metricaClient,
-		directClient:  directClient,
+		projectRepo:     projectRepo,
+		metricsRepo:     metricsRepo,
+		directRepo:      directRepo,
+		counterRepo:     counterRepo,
+		goalRepo:        goalRepo,
+		seoRepo:         seoRepo,
+		syncRunRepo:     syncRunRepo,
+		credentials:     credentials,
+		rates:           rates,
+		directSandbox:   directSandbox,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+// metricaClientFor creates Metrica client with the token resolved for a counter
+func (s *SyncService) metricaClientFor(ctx context.Context, projectID uint, counter *models.YandexCounter) (*integrations.YandexMetricaClient, error) {
+	token, err := s.credentials.ResolveToken(ctx, projectID, counter.OAuthCredentialID)
+	if err != nil {
+		return nil, err
+	}
+	return integrations.NewYandexMetricaClient(token), nil
+}
+
+// directClientFor creates Direct client with the token resolved for an account
+// Requests are made on behalf of the account via Client-Login (required for agency tokens)
+func (s *SyncService) directClientFor(ctx context.Context, projectID uint, account *models.DirectAccount) (*integrations.YandexDirectClient, error) {
+	token, err := s.credentials.ResolveToken(ctx, projectID, account.OAuthCredentialID)
+	if err != nil {
+		return nil, err
+	}
+	return integrations.NewYandexDirectClient(token, account.ClientLogin, s.directSandbox), nil
+}
+
+// webmasterClientFor creates Webmaster client with the token resolved for a host
+func (s *SyncService) webmasterClientFor(ctx context.Context, projectID uint, host *models.WebmasterHost) (*integrations.YandexWebmasterClient, error) {
+	token, err := s.credentials.ResolveToken(ctx, projectID, host.OAuthCredentialID)
+	if err != nil {
+		return nil, err
 	}
+	return integrations.NewYandexWebmasterClient(token), nil
 }
 
 // SyncProject synchronizes data for a specific project
@@ -69,12 +119,23 @@ func (s *SyncService) SyncProject(ctx context.Context, projectID uint) error {
 		return fmt.Errorf("failed to sync Direct data: %w", err)
 	}
 
+	// Sync Yandex.Webmaster search queries
+	if err := s.SyncWebmasterData(ctx, projectID, currentYear, currentMonth); err != nil {
+		return fmt.Errorf("failed to sync Webmaster data: %w", err)
+	}
+
 	return nil
 }
 
 // SyncMetricaData synchronizes Yandex.Metrica data for a project
+// Daily metrics are loaded and stored first, monthly row is recomputed from them
 // Made public for use by queue workers
 func (s *SyncService) SyncMetricaData(ctx context.Context, projectID uint, year, month int) error {
+	return s.trackRun(ctx, projectID, models.SyncSourceMetrica, year, month, s.syncMetricaData)
+}
+
+// syncMetricaData performs Metrica sync recorded by SyncMetricaData
+func (s *SyncService) syncMetricaData(ctx context.Context, projectID uint, year, month int) error {
 	// Get all counters for the project
 	counters, err := s.counterRepo.GetByProjectID(ctx, projectID)
 	if err != nil {
@@ -85,665 +146,1575 @@ func (s *SyncService) SyncMetricaData(ctx context.Context, projectID uint, year,
 		return nil // No counters to sync
 	}
 
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	aggregationMode := models.CounterAggregationSum
+	if project != nil && project.CounterAggregation != "" {
+		aggregationMode = project.CounterAggregation
+	}
+	metricCounters := countersForAggregation(aggregationMode, counters)
+	aggregated := make(map[uint]bool, len(metricCounters))
+	for _, counter := range metricCounters {
+		aggregated[counter.ID] = true
+	}
+
 	// Calculate date range for the month
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0).AddDate(0, 0, -1) // Last day of month
+	startDate, endDate := monthRange(year, month)
 	dateFrom := startDate.Format("2006-01-02")
 	dateTo := endDate.Format("2006-01-02")
 
-	// Aggregate metrics from all counters
-	var totalVisits, totalUsers int
-	var totalBounceRate float64
-	var totalDurationSec int
-	var bounceRateSum float64
-	counterCount := 0
+	// Aggregate daily metrics from counters selected by project aggregation mode
+	days := make(map[string]*metricaDay)
+	var totalUsers int
+	usersSynced := false
+	ages := newMetricaBreakdown()
+	agesSynced := false
+	breakdowns := make(map[string]*metricaBreakdown, len(metricaBreakdownDimensions))
+	for _, dimension := range metricaBreakdownDimensions {
+		breakdowns[dimension.dimension] = newMetricaBreakdown()
+	}
+	breakdownsSynced := make(map[string]bool, len(metricaBreakdownDimensions))
+	metricaClients := make(map[uint]*integrations.YandexMetricaClient)
+	counterVisits := make(map[uint]int)
+	synced := 0
+	var lastErr error
 
 	for _, counter := range counters {
-		// Get metrics from API
-		metricsData, err := s.metricaClient.GetMetrics(ctx, counter.CounterID, dateFrom, dateTo)
+		metricaClient, err := s.metricaClientFor(ctx, projectID, counter)
+		if err != nil {
+			syncWarn(ctx, "Failed to resolve OAuth token for counter",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			lastErr = err
+			continue
+		}
+		metricaClients[counter.ID] = metricaClient
+
+		// Keep goals in sync with Metrica; failure does not affect metrics
+		if err := s.syncGoals(ctx, counter, metricaClient); err != nil {
+			syncWarn(ctx, "Failed to sync goals from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+		}
+
+		// Counters left out by aggregation mode only keep their goals in sync
+		if !aggregated[counter.ID] {
+			continue
+		}
+
+		// Get daily metrics from API
+		dailyMetrics, err := metricaClient.GetDailyMetrics(ctx, counter.CounterID, dateFrom, dateTo)
 		if err != nil {
 			// Log error but continue with other counters
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to get metrics from Metrica API",
-					zap.Int64("counter_id", counter.CounterID),
-					zap.Error(err),
-				)
-			}
+			syncWarn(ctx, "Failed to get daily metrics from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			lastErr = err
 			continue
 		}
+		synced++
 
-		// Parse metricsData and aggregate
-		visits, users, bounceRate, duration := s.parseMetricaMetrics(metricsData)
-		totalVisits += visits
-		totalUsers += users
-		if bounceRate > 0 {
-			bounceRateSum += bounceRate
-			counterCount++
+		for _, result := range dailyMetrics {
+			dayFor(days, result.Date).add(result.MetricsResult)
+		}
+
+		// Unique users are not additive over days, so they are requested for the whole month
+		monthMetrics, err := metricaClient.GetMetrics(ctx, counter.CounterID, dateFrom, dateTo)
+		if err != nil {
+			syncWarn(ctx, "Failed to get metrics from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+		} else {
+			totalUsers += int(monthMetrics.Users)
+			usersSynced = true
+			counterVisits[counter.ID] = int(monthMetrics.Visits)
 		}
-		totalDurationSec += duration
 
 		// Get age breakdown
-		ageData, err := s.metricaClient.GetMetricsByAge(ctx, counter.CounterID, dateFrom, dateTo)
+		ageData, err := metricaClient.GetMetricsByAge(ctx, counter.CounterID, dateFrom, dateTo)
 		if err != nil {
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to get age metrics from Metrica API",
-					zap.Int64("counter_id", counter.CounterID),
-					zap.Error(err),
-				)
-			}
-			continue
+			syncWarn(ctx, "Failed to get age metrics from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+		} else {
+			addAgeMetrics(ages, ageData)
+			agesSynced = true
 		}
 
-		// Parse ageData and save
-		if err := s.parseAndSaveAgeMetrics(ctx, ageData, projectID, counter.ID, year, month); err != nil {
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to save age metrics",
+		// Get gender, device and region breakdowns
+		for _, dimension := range metricaBreakdownDimensions {
+			results, err := metricaClient.GetMetricsByDimension(ctx, counter.CounterID, dimension.metricaDimension, dimension.limit, dateFrom, dateTo)
+			if err != nil {
+				syncWarn(ctx, "Failed to get breakdown metrics from Metrica API",
 					zap.Int64("counter_id", counter.CounterID),
+					zap.String("dimension", dimension.dimension),
 					zap.Error(err),
 				)
+				continue
 			}
+			addBreakdownMetrics(breakdowns[dimension.dimension], results)
+			breakdownsSynced[dimension.dimension] = true
 		}
 	}
 
-	// Calculate average bounce rate
-	if counterCount > 0 {
-		totalBounceRate = bounceRateSum / float64(counterCount)
+	// Nothing is saved when no counter could be queried, so the task is retried and stored metrics are kept
+	if synced == 0 {
+		return fmt.Errorf("failed to sync any Metrica counter: %w", lastErr)
 	}
 
-	// Initialize monthly metrics
-	monthlyMetrics := &models.MetricsMonthly{
-		ProjectID:             projectID,
-		Year:                  year,
-		Month:                 month,
-		Visits:                totalVisits,
-		Users:                 totalUsers,
-		BounceRate:            totalBounceRate,
-		AvgSessionDurationSec: totalDurationSec,
+	if agesSynced {
+		if err := s.metricsRepo.ReplaceAgeMetrics(ctx, projectID, year, month, ages.ageMetrics(projectID, year, month)); err != nil {
+			syncWarn(ctx, "Failed to save age metrics",
+				zap.Uint("project_id", projectID),
+				zap.Error(err),
+			)
+		}
 	}
 
-	// Get goals for conversions
-	var counterIDs []uint
-	for _, counter := range counters {
-		counterIDs = append(counterIDs, counter.ID)
+	// Dimensions that failed for every counter keep previously stored rows
+	for _, dimension := range metricaBreakdownDimensions {
+		if !breakdownsSynced[dimension.dimension] {
+			continue
+		}
+		rows := breakdowns[dimension.dimension].breakdownMetrics(projectID, year, month, dimension.dimension)
+		if err := s.metricsRepo.ReplaceBreakdownMetrics(ctx, projectID, year, month, dimension.dimension, rows); err != nil {
+			syncWarn(ctx, "Failed to save breakdown metrics",
+				zap.Uint("project_id", projectID),
+				zap.String("dimension", dimension.dimension),
+				zap.Error(err),
+			)
+		}
 	}
 
-	if len(counterIDs) > 0 {
-		goals, err := s.goalRepo.GetByCounterIDs(ctx, counterIDs)
-		if err == nil {
-			goalIDs := make([]int64, 0, len(goals))
-			for _, goal := range goals {
-				if goal.IsConversion {
-					goalIDs = append(goalIDs, goal.GoalID)
-				}
-			}
+	// Conversions are stored with daily rows, failure leaves them empty
+	if err := s.addMetricaConversions(ctx, metricCounters, metricaClients, days, dateFrom, dateTo); err != nil {
+		syncWarn(ctx, "Failed to sync conversions",
+			zap.Uint("project_id", projectID),
+			zap.Error(err),
+		)
+	}
 
-			if len(goalIDs) > 0 {
-				// Get conversions for primary counter
-				for _, counter := range counters {
-					if counter.IsPrimary {
-						conversionsData, err := s.metricaClient.GetConversions(ctx, counter.CounterID, goalIDs, dateFrom, dateTo)
-						if err == nil {
-							conversions := s.parseConversions(conversionsData)
-							if conversions != nil {
-								monthlyMetrics.Conversions = conversions
-							}
-						} else if logger.Log != nil {
-							logger.Log.Warn("Failed to get conversions from Metrica API",
-								zap.Int64("counter_id", counter.CounterID),
-								zap.Error(err),
-							)
-						}
-						break
-					}
-				}
-			}
-		}
+	// Traffic source breakdown is not critical for the rest of the sync
+	if err := s.syncTrafficSources(ctx, projectID, metricCounters, metricaClients, year, month); err != nil {
+		syncWarn(ctx, "Failed to sync traffic sources",
+			zap.Uint("project_id", projectID),
+			zap.Error(err),
+		)
 	}
 
-	// Check if record exists
-	existing, err := s.metricsRepo.GetMonthlyMetrics(ctx, projectID, year, month)
+	// Per-goal breakdown is not critical for the rest of the sync either
+	if err := s.syncGoalMetrics(ctx, projectID, metricCounters, metricaClients, counterVisits, year, month); err != nil {
+		syncWarn(ctx, "Failed to sync goal metrics",
+			zap.Uint("project_id", projectID),
+			zap.Error(err),
+		)
+	}
+
+	// Revenue is not critical either, previously stored values are kept if no counter could be queried
+	// It is requested in the project currency to be comparable with Direct costs
+	currency := models.CurrencyRUB
+	if project != nil && project.Currency != "" {
+		currency = project.Currency
+	}
+	revenue, err := s.syncRevenue(ctx, projectID, metricCounters, metricaClients, currency, year, month)
 	if err != nil {
-		return err
+		syncWarn(ctx, "Failed to sync revenue",
+			zap.Uint("project_id", projectID),
+			zap.Error(err),
+		)
 	}
 
-	if existing != nil {
-		monthlyMetrics.ID = existing.ID
+	// Save daily rows
+	dailyRows := make([]*models.MetricsDaily, 0, len(days))
+	for date, day := range days {
+		parsedDate, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q in Metrica response: %w", date, err)
+		}
+		dailyRows = append(dailyRows, day.toModel(projectID, parsedDate))
+	}
+	if err := s.metricsRepo.SaveDailyMetrics(ctx, dailyRows); err != nil {
+		return fmt.Errorf("failed to save daily metrics: %w", err)
 	}
+	syncRows(ctx, len(dailyRows))
 
-	return s.metricsRepo.SaveMonthlyMetrics(monthlyMetrics)
+	var users *int
+	if usersSynced {
+		users = &totalUsers
+	}
+	return s.rollupMetricsMonthly(ctx, projectID, year, month, users, revenue)
 }
 
-// SyncDirectData synchronizes Yandex.Direct data for a project
-// Made public for use by queue workers
-func (s *SyncService) SyncDirectData(ctx context.Context, projectID uint, year, month int) error {
-	// Get all Direct accounts for the project
-	accounts, err := s.directRepo.GetAccountsByProjectID(ctx, projectID)
+// conversionGoalIDs returns IDs of conversion goals by counter
+func (s *SyncService) conversionGoalIDs(ctx context.Context, counters []*models.YandexCounter) (map[uint][]int64, error) {
+	counterIDs := make([]uint, 0, len(counters))
+	for _, counter := range counters {
+		counterIDs = append(counterIDs, counter.ID)
+	}
+	goals, err := s.goalRepo.GetByCounterIDs(ctx, counterIDs)
 	if err != nil {
-		return fmt.Errorf("failed to get Direct accounts: %w", err)
+		return nil, fmt.Errorf("failed to get goals: %w", err)
 	}
-
-	if len(accounts) == 0 {
-		return nil // No accounts to sync
+	goalIDs := make(map[uint][]int64)
+	for _, goal := range goals {
+		if goal.IsConversion && !goal.IsDeleted {
+			goalIDs[goal.CounterID] = append(goalIDs[goal.CounterID], goal.GoalID)
+		}
 	}
+	return goalIDs, nil
+}
 
-	// Calculate date range for the month
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0).AddDate(0, 0, -1) // Last day of month
-	dateFrom := startDate.Format("2006-01-02")
-	dateTo := endDate.Format("2006-01-02")
-
-	// Aggregate totals from all accounts
-	var totalImpressions, totalClicks int
-	var totalCost float64
-	var totalCTR float64
-	var totalCPC float64
-
-	for _, account := range accounts {
-		// Use shared client for now
-		// Note: In future, token should be retrieved from account settings or config
-		directClient := s.directClient
+// addMetricaConversions adds daily conversions of counters to day accumulators
+// Every counter is requested for its own conversion goals and results are summed
+func (s *SyncService) addMetricaConversions(ctx context.Context, counters []*models.YandexCounter, metricaClients map[uint]*integrations.YandexMetricaClient, days map[string]*metricaDay, dateFrom, dateTo string) error {
+	goalIDs, err := s.conversionGoalIDs(ctx, counters)
+	if err != nil {
+		return err
+	}
 
-		// Get campaign report
-		reportData, err := directClient.GetCampaignReport(ctx, dateFrom, dateTo)
-		if err != nil {
-			// Log error but continue with other accounts
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to get campaign report from Direct API",
-					zap.Uint("account_id", account.ID),
-					zap.String("client_login", account.ClientLogin),
-					zap.Error(err),
-				)
-			}
+	for _, counter := range counters {
+		metricaClient, ok := metricaClients[counter.ID]
+		if !ok || len(goalIDs[counter.ID]) == 0 {
 			continue
 		}
-
-		// Parse reportData and aggregate
-		impressions, clicks, cost := s.parseDirectReport(reportData)
-		totalImpressions += impressions
-		totalClicks += clicks
-		totalCost += cost
-
-		// Get campaigns list
-		campaignsData, err := directClient.GetCampaigns(ctx)
+		conversions, err := metricaClient.GetDailyConversions(ctx, counter.CounterID, goalIDs[counter.ID], dateFrom, dateTo)
 		if err != nil {
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to get campaigns from Direct API",
-					zap.Uint("account_id", account.ID),
-					zap.Error(err),
-				)
-			}
+			syncWarn(ctx, "Failed to get conversions from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
 			continue
 		}
-
-		// Parse campaignsData and save individual campaign metrics
-		if err := s.parseAndSaveCampaignMetrics(ctx, campaignsData, account.ID, projectID, year, month); err != nil {
-			if logger.Log != nil {
-				logger.Log.Warn("Failed to save campaign metrics",
-					zap.Uint("account_id", account.ID),
-					zap.Error(err),
-				)
-			}
+		for _, result := range conversions {
+			dayFor(days, result.Date).addConversions(int(result.Conversions))
 		}
 	}
 
-	// Calculate aggregated metrics
-	if totalClicks > 0 {
-		totalCTR = (float64(totalClicks) / float64(totalImpressions)) * 100
-		totalCPC = totalCost / float64(totalClicks)
-	}
-
-	// Save aggregated monthly totals
-	totals := &models.DirectTotalsMonthly{
-		ProjectID:   projectID,
-		Year:        year,
-		Month:       month,
-		Impressions: totalImpressions,
-		Clicks:      totalClicks,
-		CTRPct:      totalCTR,
-		CPC:         totalCPC,
-		Cost:        totalCost,
-	}
+	return nil
+}
 
-	// Check if record exists
-	existing, err := s.directRepo.GetTotalsMonthly(ctx, projectID, year, month)
+// SyncCounterGoals loads goals of a counter from Management API and stores them
+// Made public for use by queue workers (goal discovery after a counter is added)
+func (s *SyncService) SyncCounterGoals(ctx context.Context, counterID uint) error {
+	counter, err := s.counterRepo.GetByID(ctx, counterID)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get counter: %w", err)
+	}
+	if counter == nil {
+		return fmt.Errorf("counter %d not found", counterID)
 	}
 
-	if existing != nil {
-		totals.ID = existing.ID
+	metricaClient, err := s.metricaClientFor(ctx, counter.ProjectID, counter)
+	if err != nil {
+		return fmt.Errorf("failed to resolve OAuth token: %w", err)
 	}
 
-	return s.directRepo.SaveTotalsMonthly(totals)
+	return s.syncGoals(ctx, counter, metricaClient)
 }
 
-// parseMetricaMetrics parses metrics data from Yandex.Metrica API
-// Returns: visits, users, bounceRate, avgDurationSec
-func (s *SyncService) parseMetricaMetrics(data interface{}) (int, int, float64, int) {
-	if data == nil {
-		return 0, 0, 0, 0
+// syncGoals upserts goals of a counter from Management API
+// Name and type are refreshed, IsConversion chosen by managers is kept,
+// goals missing in Metrica are flagged as deleted (and restored if they reappear)
+func (s *SyncService) syncGoals(ctx context.Context, counter *models.YandexCounter, metricaClient *integrations.YandexMetricaClient) error {
+	apiGoals, err := metricaClient.GetGoals(ctx, counter.CounterID)
+	if err != nil {
+		return err
 	}
 
-	// Try to parse as map[string]interface{}
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return 0, 0, 0, 0
+	existing, err := s.goalRepo.GetByCounterID(ctx, counter.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get goals: %w", err)
 	}
-
-	var visits, users int
-	var bounceRate float64
-	var durationSec int
-
-	// Parse data array if exists
-	if dataArray, ok := dataMap["data"].([]interface{}); ok && len(dataArray) > 0 {
-		if firstRow, ok := dataArray[0].([]interface{}); ok && len(firstRow) >= 4 {
-			// Typical structure: [visits, users, bounceRate, avgDuration]
-			if v, ok := firstRow[0].(float64); ok {
-				visits = int(v)
-			}
-			if v, ok := firstRow[1].(float64); ok {
-				users = int(v)
-			}
-			if v, ok := firstRow[2].(float64); ok {
-				bounceRate = v
-			}
-			if v, ok := firstRow[3].(float64); ok {
-				durationSec = int(v)
-			}
-		}
+	byGoalID := make(map[int64]*models.Goal, len(existing))
+	for _, goal := range existing {
+		byGoalID[goal.GoalID] = goal
 	}
 
-	// Alternative: try direct fields
-	if visits == 0 {
-		if v, ok := dataMap["visits"].(float64); ok {
-			visits = int(v)
+	// Goals are upserted by (counter, goal ID): a goal created by a concurrent sync is updated, not duplicated,
+	// and IsConversion is never overwritten with a stale value
+	seen := make(map[int64]bool, len(apiGoals))
+	for _, apiGoal := range apiGoals {
+		seen[apiGoal.ID] = true
+
+		goal, ok := byGoalID[apiGoal.ID]
+		if ok && goal.Name == apiGoal.Name && goal.Type == apiGoal.Type && !goal.IsDeleted {
+			continue
 		}
-	}
-	if users == 0 {
-		if v, ok := dataMap["users"].(float64); ok {
-			users = int(v)
+		if err := s.goalRepo.Upsert(ctx, &models.Goal{
+			CounterID: counter.ID,
+			GoalID:    apiGoal.ID,
+			Name:      apiGoal.Name,
+			Type:      apiGoal.Type,
+		}); err != nil {
+			return fmt.Errorf("failed to save goal %d: %w", apiGoal.ID, err)
 		}
 	}
-	if bounceRate == 0 {
-		if v, ok := dataMap["bounceRate"].(float64); ok {
-			bounceRate = v
+
+	for _, goal := range existing {
+		if seen[goal.GoalID] || goal.IsDeleted {
+			continue
 		}
-	}
-	if durationSec == 0 {
-		if v, ok := dataMap["avgVisitDurationSeconds"].(float64); ok {
-			durationSec = int(v)
+		if err := s.goalRepo.Upsert(ctx, &models.Goal{
+			CounterID: counter.ID,
+			GoalID:    goal.GoalID,
+			Name:      goal.Name,
+			Type:      goal.Type,
+			IsDeleted: true,
+		}); err != nil {
+			return fmt.Errorf("failed to update goal %d: %w", goal.GoalID, err)
 		}
 	}
 
-	return visits, users, bounceRate, durationSec
+	return nil
 }
 
-// parseAndSaveAgeMetrics parses age breakdown data and saves to database
-func (s *SyncService) parseAndSaveAgeMetrics(ctx context.Context, data interface{}, projectID uint, counterID uint, year, month int) error {
-	if data == nil {
-		return nil
-	}
-
-	// Try to cast to []AgeMetricsResult first (new format)
-	if ageResults, ok := data.([]integrations.AgeMetricsResult); ok {
-		return s.parseAndSaveAgeMetricsTyped(ctx, ageResults, projectID, counterID, year, month)
-	}
+// syncGoalMetrics loads goal visits and reaches of every conversion goal of each counter
+// Conversion rate is the share of counter visits in which the goal was reached
+func (s *SyncService) syncGoalMetrics(ctx context.Context, projectID uint, counters []*models.YandexCounter, metricaClients map[uint]*integrations.YandexMetricaClient, counterVisits map[uint]int, year, month int) error {
+	startDate, endDate := monthRange(year, month)
+	dateFrom := startDate.Format("2006-01-02")
+	dateTo := endDate.Format("2006-01-02")
 
-	// Fallback to old format (map[string]interface{}) for backward compatibility
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return nil
+	counterIDs := make([]uint, 0, len(counters))
+	for _, counter := range counters {
+		counterIDs = append(counterIDs, counter.ID)
 	}
-
-	// Parse data array
-	dataArray, ok := dataMap["data"].([]interface{})
-	if !ok {
-		return nil
+	goals, err := s.goalRepo.GetByCounterIDs(ctx, counterIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get goals: %w", err)
 	}
-
-	// Map age intervals to our AgeGroup enum
-	ageGroupMap := map[string]models.AgeGroup{
-		"18-24":   models.AgeGroup1824,
-		"25-34":   models.AgeGroup2534,
-		"35-44":   models.AgeGroup3544,
-		"45-54":   models.AgeGroup4554,
-		"55+":     models.AgeGroup55Plus,
-		"unknown": models.AgeGroupUnknown,
+	counterGoals := make(map[uint]map[int64]*models.Goal)
+	for _, goal := range goals {
+		if !goal.IsConversion || goal.IsDeleted {
+			continue
+		}
+		if counterGoals[goal.CounterID] == nil {
+			counterGoals[goal.CounterID] = make(map[int64]*models.Goal)
+		}
+		counterGoals[goal.CounterID][goal.GoalID] = goal
 	}
 
-	for _, row := range dataArray {
-		rowArray, ok := row.([]interface{})
-		if !ok || len(rowArray) < 5 {
+	var rows []*models.MetricsGoalMonthly
+	requested, synced := 0, 0
+	for _, counter := range counters {
+		byGoalID := counterGoals[counter.ID]
+		if len(byGoalID) == 0 {
 			continue
 		}
+		requested++
 
-		// Parse age group
-		ageInterval, ok := rowArray[0].(string)
+		metricaClient, ok := metricaClients[counter.ID]
 		if !ok {
 			continue
 		}
-		ageGroup, exists := ageGroupMap[ageInterval]
-		if !exists {
-			ageGroup = models.AgeGroupUnknown
-		}
-
-		// Parse metrics
-		var visits, users int
-		var bounceRate float64
-		var durationSec int
 
-		if v, ok := rowArray[1].(float64); ok {
-			visits = int(v)
-		}
-		if v, ok := rowArray[2].(float64); ok {
-			users = int(v)
-		}
-		if v, ok := rowArray[3].(float64); ok {
-			bounceRate = v
-		}
-		if v, ok := rowArray[4].(float64); ok {
-			durationSec = int(v)
+		goalIDs := make([]int64, 0, len(byGoalID))
+		for _, goal := range goals {
+			if byGoalID[goal.GoalID] == goal {
+				goalIDs = append(goalIDs, goal.GoalID)
+			}
 		}
 
-		// Check if record exists
-		existing, err := s.metricsRepo.GetAgeMetricsByGroup(ctx, projectID, year, month, string(ageGroup))
-
-		ageMetrics := &models.MetricsAgeMonthly{
-			ProjectID:             projectID,
-			Year:                  year,
-			Month:                 month,
-			AgeGroup:              ageGroup,
-			Visits:                visits,
-			Users:                 users,
-			BounceRate:            bounceRate,
-			AvgSessionDurationSec: durationSec,
+		results, err := metricaClient.GetConversions(ctx, counter.CounterID, goalIDs, dateFrom, dateTo)
+		if err != nil {
+			syncWarn(ctx, "Failed to get goal conversions from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			continue
 		}
+		synced++
 
-		if err == nil && existing != nil {
-			ageMetrics.ID = existing.ID
-		} else if err != nil {
-			return err
+		for _, result := range results {
+			var rate float64
+			if visits := counterVisits[counter.ID]; visits > 0 {
+				rate = float64(result.Visits) / float64(visits) * 100
+			}
+			rows = append(rows, &models.MetricsGoalMonthly{
+				ProjectID:      projectID,
+				Year:           year,
+				Month:          month,
+				CounterID:      counter.ID,
+				GoalID:         result.GoalID,
+				GoalName:       byGoalID[result.GoalID].Name,
+				Reaches:        int(result.Conversions),
+				Visits:         int(result.Visits),
+				ConversionRate: rate,
+			})
 		}
+	}
 
-		if err := s.metricsRepo.SaveAgeMetrics(ageMetrics); err != nil {
-			return err
-		}
+	// Keep previously stored data if goals exist but nothing could be loaded
+	if requested > 0 && synced == 0 {
+		return fmt.Errorf("failed to get goal conversions for any counter")
 	}
 
-	return nil
+	return s.metricsRepo.ReplaceGoalMetrics(ctx, projectID, year, month, rows)
 }
 
-// parseAndSaveAgeMetricsTyped parses typed age breakdown data and saves to database
-func (s *SyncService) parseAndSaveAgeMetricsTyped(ctx context.Context, ageResults []integrations.AgeMetricsResult, projectID uint, counterID uint, year, month int) error {
-	// Map age intervals to our AgeGroup enum
-	ageGroupMap := map[string]models.AgeGroup{
-		"18-24":   models.AgeGroup1824,
-		"25-34":   models.AgeGroup2534,
-		"35-44":   models.AgeGroup3544,
-		"45-54":   models.AgeGroup4554,
-		"55+":     models.AgeGroup55Plus,
-		"unknown": models.AgeGroupUnknown,
+// syncTrafficSources loads visits, users and conversion goal reaches by last traffic source
+// Each counter is queried with its own conversion goals; results are summed by source
+func (s *SyncService) syncTrafficSources(ctx context.Context, projectID uint, counters []*models.YandexCounter, metricaClients map[uint]*integrations.YandexMetricaClient, year, month int) error {
+	startDate, endDate := monthRange(year, month)
+	dateFrom := startDate.Format("2006-01-02")
+	dateTo := endDate.Format("2006-01-02")
+
+	goalIDs, err := s.conversionGoalIDs(ctx, counters)
+	if err != nil {
+		return err
 	}
 
-	for _, result := range ageResults {
-		ageGroup, exists := ageGroupMap[result.AgeGroup]
-		if !exists {
-			ageGroup = models.AgeGroupUnknown
+	sources := make(map[string]*models.MetricsTrafficSourceMonthly)
+	var order []string
+	synced := 0
+	for _, counter := range counters {
+		metricaClient, ok := metricaClients[counter.ID]
+		if !ok {
+			continue
 		}
 
-		// Check if record exists
-		existing, err := s.metricsRepo.GetAgeMetricsByGroup(ctx, projectID, year, month, string(ageGroup))
-
-		ageMetrics := &models.MetricsAgeMonthly{
-			ProjectID:             projectID,
-			Year:                  year,
-			Month:                 month,
-			AgeGroup:              ageGroup,
-			Visits:                int(result.Visits),
-			Users:                 int(result.Users),
-			BounceRate:            result.BounceRate,
-			AvgSessionDurationSec: result.AvgSessionDurationSec,
+		results, err := metricaClient.GetMetricsByTrafficSource(ctx, counter.CounterID, goalIDs[counter.ID], dateFrom, dateTo)
+		if err != nil {
+			syncWarn(ctx, "Failed to get traffic sources from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			continue
 		}
-
-		if err == nil && existing != nil {
-			ageMetrics.ID = existing.ID
-		} else if err != nil {
-			return err
+		synced++
+
+		for _, result := range results {
+			source, ok := sources[result.Source]
+			if !ok {
+				source = &models.MetricsTrafficSourceMonthly{
+					ProjectID:  projectID,
+					Year:       year,
+					Month:      month,
+					Source:     result.Source,
+					SourceName: result.Name,
+				}
+				sources[result.Source] = source
+				order = append(order, result.Source)
+			}
+			source.Visits += int(result.Visits)
+			source.Users += int(result.Users)
+			if result.Conversions != nil {
+				conversions := int(*result.Conversions)
+				if source.Conversions != nil {
+					conversions += *source.Conversions
+				}
+				source.Conversions = &conversions
+			}
 		}
+	}
 
-		if err := s.metricsRepo.SaveAgeMetrics(ageMetrics); err != nil {
-			return err
-		}
+	// Keep previously stored breakdown when no counter could be queried
+	if synced == 0 {
+		return nil
 	}
 
-	return nil
+	rows := make([]*models.MetricsTrafficSourceMonthly, 0, len(order))
+	for _, source := range order {
+		rows = append(rows, sources[source])
+	}
+	return s.metricsRepo.ReplaceTrafficSourceMetrics(ctx, projectID, year, month, rows)
 }
 
-// parseConversions parses conversions data from Yandex.Metrica API
-func (s *SyncService) parseConversions(data interface{}) *int {
-	if data == nil {
-		return nil
-	}
+// syncRevenue loads ecommerce and goal value revenue of counters and of Direct campaigns
+// Each counter is queried with its own conversion goals; revenue is nil when no counter could be queried
+// Metrica converts revenue from currencies of counters to the currency
+func (s *SyncService) syncRevenue(ctx context.Context, projectID uint, counters []*models.YandexCounter, metricaClients map[uint]*integrations.YandexMetricaClient, currency string, year, month int) (*metricaRevenue, error) {
+	startDate, endDate := monthRange(year, month)
+	dateFrom := startDate.Format("2006-01-02")
+	dateTo := endDate.Format("2006-01-02")
 
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return nil
+	goalIDs, err := s.conversionGoalIDs(ctx, counters)
+	if err != nil {
+		return nil, err
 	}
 
-	var totalConversions int
+	total := &metricaRevenue{}
+	campaigns := make(map[int64]*models.MetricsCampaignRevenueMonthly)
+	var order []int64
+	synced, campaignsSynced := 0, 0
+	for _, counter := range counters {
+		metricaClient, ok := metricaClients[counter.ID]
+		if !ok {
+			continue
+		}
 
-	// Parse data array
-	if dataArray, ok := dataMap["data"].([]interface{}); ok {
-		for _, row := range dataArray {
-			rowArray, ok := row.([]interface{})
-			if !ok || len(rowArray) < 2 {
-				continue
+		result, err := metricaClient.GetRevenueMetrics(ctx, counter.CounterID, goalIDs[counter.ID], currency, dateFrom, dateTo)
+		if err != nil {
+			syncWarn(ctx, "Failed to get revenue from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			continue
+		}
+		synced++
+		total.purchases += int(result.Purchases)
+		total.revenue += result.Revenue
+		total.goalRevenue += result.GoalRevenue
+
+		results, err := metricaClient.GetRevenueByDirectCampaign(ctx, counter.CounterID, goalIDs[counter.ID], currency, dateFrom, dateTo)
+		if err != nil {
+			syncWarn(ctx, "Failed to get Direct campaign revenue from Metrica API",
+				zap.Int64("counter_id", counter.CounterID),
+				zap.Error(err),
+			)
+			continue
+		}
+		campaignsSynced++
+
+		for _, result := range results {
+			campaignID, err := strconv.ParseInt(result.ID, 10, 64)
+			if err != nil {
+				continue // Clicks without a known campaign
 			}
-			// Sum all conversions
-			if v, ok := rowArray[1].(float64); ok {
-				totalConversions += int(v)
+			campaign, ok := campaigns[campaignID]
+			if !ok {
+				campaign = &models.MetricsCampaignRevenueMonthly{
+					ProjectID:    projectID,
+					Year:         year,
+					Month:        month,
+					CampaignID:   campaignID,
+					CampaignName: result.Name,
+				}
+				campaigns[campaignID] = campaign
+				order = append(order, campaignID)
 			}
+			campaign.Purchases += int(result.Purchases)
+			campaign.Revenue += result.Revenue
+			campaign.GoalRevenue += result.GoalRevenue
 		}
 	}
 
-	// Alternative: direct field
-	if totalConversions == 0 {
-		if v, ok := dataMap["conversions"].(float64); ok {
-			totalConversions = int(v)
-		}
+	if synced == 0 {
+		return nil, nil
+	}
+	// Keep previously stored campaign revenue when no counter could be queried
+	if campaignsSynced == 0 {
+		return total, nil
 	}
 
-	if totalConversions == 0 {
-		return nil
+	rows := make([]*models.MetricsCampaignRevenueMonthly, 0, len(order))
+	for _, campaignID := range order {
+		rows = append(rows, campaigns[campaignID])
 	}
+	if err := s.metricsRepo.ReplaceCampaignRevenue(ctx, projectID, year, month, rows); err != nil {
+		return total, fmt.Errorf("failed to save campaign revenue: %w", err)
+	}
+	return total, nil
+}
 
-	return &totalConversions
+// metricaRevenue holds revenue of a month summed over counters
+type metricaRevenue struct {
+	purchases   int
+	revenue     float64
+	goalRevenue float64
 }
 
-// parseDirectReport parses campaign report data from Yandex.Direct API
-// Returns: impressions, clicks, cost
-func (s *SyncService) parseDirectReport(data interface{}) (int, int, float64) {
-	if data == nil {
-		return 0, 0, 0
+// rollupMetricsMonthly recomputes monthly metrics of a project from stored daily rows
+// Bounce rate and session duration are weighted by visits; users and revenue nil keep previously stored values
+func (s *SyncService) rollupMetricsMonthly(ctx context.Context, projectID uint, year, month int, users *int, revenue *metricaRevenue) error {
+	startDate, endDate := monthRange(year, month)
+	dailyRows, err := s.metricsRepo.GetDailyMetrics(ctx, projectID, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get daily metrics: %w", err)
 	}
 
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return 0, 0, 0
+	var total metricaDay
+	for _, row := range dailyRows {
+		total.addDaily(row)
 	}
 
-	var impressions, clicks int
-	var cost float64
-
-	// Parse report structure
-	if report, ok := dataMap["report"].(map[string]interface{}); ok {
-		if rows, ok := report["rows"].([]interface{}); ok {
-			for _, row := range rows {
-				rowMap, ok := row.(map[string]interface{})
-				if !ok {
-					continue
-				}
+	monthlyMetrics := total.toMonthly(projectID, year, month)
 
-				if v, ok := rowMap["Impressions"].(float64); ok {
-					impressions += int(v)
-				}
-				if v, ok := rowMap["Clicks"].(float64); ok {
-					clicks += int(v)
-				}
-				if v, ok := rowMap["Cost"].(float64); ok {
-					cost += v
-				}
-			}
-		}
+	// Check if record exists
+	existing, err := s.metricsRepo.GetMonthlyMetrics(ctx, projectID, year, month)
+	if err != nil {
+		return err
 	}
 
-	// Alternative: direct totals
-	if impressions == 0 {
-		if v, ok := dataMap["Impressions"].(float64); ok {
-			impressions = int(v)
-		}
+	if existing != nil {
+		monthlyMetrics.ID = existing.ID
+		monthlyMetrics.Users = existing.Users
+		monthlyMetrics.Purchases = existing.Purchases
+		monthlyMetrics.Revenue = existing.Revenue
+		monthlyMetrics.GoalRevenue = existing.GoalRevenue
 	}
-	if clicks == 0 {
-		if v, ok := dataMap["Clicks"].(float64); ok {
-			clicks = int(v)
-		}
+	if users != nil {
+		monthlyMetrics.Users = *users
 	}
-	if cost == 0 {
-		if v, ok := dataMap["Cost"].(float64); ok {
-			cost = v
-		}
+	if revenue != nil {
+		monthlyMetrics.Purchases = &revenue.purchases
+		monthlyMetrics.Revenue = &revenue.revenue
+		monthlyMetrics.GoalRevenue = &revenue.goalRevenue
 	}
 
-	return impressions, clicks, cost
+	return s.metricsRepo.SaveMonthlyMetrics(monthlyMetrics)
 }
 
-// parseAndSaveCampaignMetrics parses campaigns data and saves individual campaign metrics
-func (s *SyncService) parseAndSaveCampaignMetrics(ctx context.Context, data interface{}, accountID uint, projectID uint, year, month int) error {
-	if data == nil {
-		return nil
-	}
+// metricaDay accumulates Metrica values of one day (or of a period for rollups)
+// Bounce rate and duration are accumulated as visit-weighted sums
+type metricaDay struct {
+	visits           int
+	users            int
+	bounceWeighted   float64
+	durationWeighted float64
+	conversions      *int
+}
 
-	dataMap, ok := data.(map[string]interface{})
+// dayFor returns accumulator for a date, creating it if needed
+func dayFor(days map[string]*metricaDay, date string) *metricaDay {
+	day, ok := days[date]
 	if !ok {
-		return nil
+		day = &metricaDay{}
+		days[date] = day
 	}
+	return day
+}
+
+// add adds metrics of one counter
+func (d *metricaDay) add(result integrations.MetricsResult) {
+	d.addValues(int(result.Visits), int(result.Users), result.BounceRate, result.AvgVisitDurationSec, nil)
+}
+
+// addDaily adds stored daily row
+func (d *metricaDay) addDaily(row *models.MetricsDaily) {
+	d.addValues(row.Visits, row.Users, row.BounceRate, row.AvgSessionDurationSec, row.Conversions)
+}
+
+// addValues adds raw values to the accumulator
+func (d *metricaDay) addValues(visits, users int, bounceRate float64, durationSec int, conversions *int) {
+	d.visits += visits
+	d.users += users
+	d.bounceWeighted += bounceRate * float64(visits)
+	d.durationWeighted += float64(durationSec) * float64(visits)
+	if conversions != nil {
+		d.addConversions(*conversions)
+	}
+}
 
-	// Get campaigns array
-	var campaigns []interface{}
-	if result, ok := dataMap["result"].(map[string]interface{}); ok {
-		if campaignsArray, ok := result["Campaigns"].([]interface{}); ok {
-			campaigns = campaignsArray
+// addConversions adds conversions to the accumulator
+func (d *metricaDay) addConversions(conversions int) {
+	total := d.conversionsValue() + conversions
+	d.conversions = &total
+}
+
+// conversionsValue returns conversions or 0 when there is no data
+func (d *metricaDay) conversionsValue() int {
+	if d.conversions == nil {
+		return 0
+	}
+	return *d.conversions
+}
+
+// rates returns visit-weighted bounce rate and average session duration
+func (d *metricaDay) rates() (float64, int) {
+	if d.visits == 0 {
+		return 0, 0
+	}
+	return d.bounceWeighted / float64(d.visits), int(math.Round(d.durationWeighted / float64(d.visits)))
+}
+
+// toModel converts accumulator to daily model
+func (d *metricaDay) toModel(projectID uint, date time.Time) *models.MetricsDaily {
+	bounceRate, duration := d.rates()
+	return &models.MetricsDaily{
+		ProjectID:             projectID,
+		Date:                  date,
+		Visits:                d.visits,
+		Users:                 d.users,
+		BounceRate:            bounceRate,
+		AvgSessionDurationSec: duration,
+		Conversions:           d.conversions,
+	}
+}
+
+// toMonthly converts accumulator to monthly model (Users are set by caller)
+func (d *metricaDay) toMonthly(projectID uint, year, month int) *models.MetricsMonthly {
+	bounceRate, duration := d.rates()
+	return &models.MetricsMonthly{
+		ProjectID:             projectID,
+		Year:                  year,
+		Month:                 month,
+		Visits:                d.visits,
+		BounceRate:            bounceRate,
+		AvgSessionDurationSec: duration,
+		Conversions:           d.conversions,
+	}
+}
+
+// monthRange returns first and last day of a month
+func monthRange(year, month int) (time.Time, time.Time) {
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, 0).AddDate(0, 0, -1) // Last day of month
+	return startDate, endDate
+}
+
+// SyncDirectData synchronizes Yandex.Direct data for a project
+// Each account is queried with its own Client-Login (and token if bound), daily campaign rows
+// are stored and monthly campaign metrics and project totals are recomputed from them
+// Made public for use by queue workers
+func (s *SyncService) SyncDirectData(ctx context.Context, projectID uint, year, month int) error {
+	return s.trackRun(ctx, projectID, models.SyncSourceDirect, year, month, s.syncDirectData)
+}
+
+// syncDirectData performs Direct sync recorded by SyncDirectData
+func (s *SyncService) syncDirectData(ctx context.Context, projectID uint, year, month int) error {
+	// Get all Direct accounts for the project
+	accounts, err := s.directRepo.GetAccountsByProjectID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get Direct accounts: %w", err)
+	}
+
+	if len(accounts) == 0 {
+		return nil // No accounts to sync
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return fmt.Errorf("project not found")
+	}
+
+	conversionSettings, err := s.directConversionSettings(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	synced := 0
+	var lastErr, unitsErr error
+
+	for _, account := range accounts {
+		if err := s.syncDirectAccount(ctx, account, project, conversionSettings, year, month); err != nil {
+			// Log error but continue with other accounts
+			syncWarn(ctx, "Failed to sync Direct account",
+				zap.Uint("account_id", account.ID),
+				zap.String("client_login", account.ClientLogin),
+				zap.Error(err),
+			)
+			lastErr = err
+			if errors.Is(err, errNotEnoughDirectUnits) {
+				unitsErr = err
+			}
+			continue
 		}
-	} else if campaignsArray, ok := dataMap["Campaigns"].([]interface{}); ok {
-		campaigns = campaignsArray
+		synced++
 	}
 
-	if len(campaigns) == 0 {
-		return nil
+	if synced == 0 {
+		return fmt.Errorf("failed to sync any Direct account: %w", lastErr)
 	}
 
-	// Get DirectCampaign records for this account
-	dbCampaigns, err := s.directRepo.GetCampaignsByAccountID(ctx, accountID)
+	if err := s.rollupDirectMonthly(ctx, projectID, year, month, project.CostBasis()); err != nil {
+		return err
+	}
+
+	// Accounts skipped for Units would stay empty for the month, so the task fails to be retried
+	// once Units recover (backfill counts the month only after the last retry)
+	if unitsErr != nil {
+		return fmt.Errorf("some Direct accounts were skipped: %w", unitsErr)
+	}
+	return nil
+}
+
+// directConversionSettings returns conversion goals of the project and its attribution model for Direct reports
+// Without conversion goals Direct counts conversions by key goals of campaigns
+func (s *SyncService) directConversionSettings(ctx context.Context, project *models.Project) (integrations.ConversionSettings, error) {
+	settings := integrations.ConversionSettings{AttributionModel: integrations.AttributionModelAuto}
+	if project.AttributionModel != "" {
+		settings.AttributionModel = project.AttributionModel
+	}
+
+	counters, err := s.counterRepo.GetByProjectID(ctx, project.ID)
+	if err != nil {
+		return settings, fmt.Errorf("failed to get counters: %w", err)
+	}
+	if len(counters) == 0 {
+		return settings, nil
+	}
+
+	counterIDs := make([]uint, 0, len(counters))
+	for _, counter := range counters {
+		counterIDs = append(counterIDs, counter.ID)
+	}
+	goals, err := s.goalRepo.GetByCounterIDs(ctx, counterIDs)
+	if err != nil {
+		return settings, fmt.Errorf("failed to get goals: %w", err)
+	}
+	for _, goal := range goals {
+		if goal.IsConversion && !goal.IsDeleted {
+			settings.Goals = append(settings.Goals, goal.GoalID)
+		}
+	}
+
+	if len(settings.Goals) > integrations.MaxReportGoals {
+		syncWarn(ctx, "Direct reports accept up to 10 goals, extra conversion goals are ignored",
+			zap.Uint("project_id", project.ID),
+			zap.Int("goals", len(settings.Goals)),
+		)
+	}
+
+	return settings, nil
+}
+
+// syncDirectAccount loads daily campaign report of one account and saves daily campaign metrics
+// Costs are converted to the project currency at the rate of the month end (or today for the current month)
+// VAT and discount are requested according to the project cost basis, the agency markup is applied on top
+// Ad group and keyword statistics of the month are synced afterwards, their failure is not fatal
+func (s *SyncService) syncDirectAccount(ctx context.Context, account *models.DirectAccount, project *models.Project, conversionSettings integrations.ConversionSettings, year, month int) error {
+	projectID := project.ID
+
+	// Direct rejects requests when Units are spent; fail early so that the task is retried later
+	if quota, ok := integrations.DefaultTransport().Quota().Get(account.ClientLogin); ok &&
+		quota.Remaining < minDirectUnits && time.Since(quota.UpdatedAt) < time.Hour {
+		return fmt.Errorf("%w for %s: %d left", errNotEnoughDirectUnits, account.ClientLogin, quota.Remaining)
+	}
+
+	directClient, err := s.directClientFor(ctx, projectID, account)
+	if err != nil {
+		return fmt.Errorf("failed to resolve OAuth token: %w", err)
+	}
+	directClient.SetConversionSettings(conversionSettings)
+
+	costBasis := project.CostBasis()
+	directClient.SetCostSettings(integrations.CostSettings{
+		IncludeVAT:      costBasis.IncludeVAT,
+		IncludeDiscount: costBasis.IncludeDiscount,
+	})
+
+	costRate, err := s.directCostRate(ctx, directClient, account, project.Currency, year, month)
 	if err != nil {
 		return err
 	}
+	costRate *= costBasis.Factor()
 
-	// Create map for quick lookup
-	campaignMap := make(map[int64]uint)
-	for _, dbCampaign := range dbCampaigns {
-		campaignMap[dbCampaign.CampaignID] = dbCampaign.ID
+	startDate, endDate := monthRange(year, month)
+	dateFrom := startDate.Format("2006-01-02")
+	dateTo := endDate.Format("2006-01-02")
+
+	reportRows, err := directClient.GetCampaignDailyReport(ctx, dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+
+	// Campaigns list is used for names of campaigns without stats in report; failure is not fatal
+	campaigns, err := directClient.GetCampaigns(ctx)
+	if err != nil {
+		syncWarn(ctx, "Failed to get campaigns from Direct API",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
 	}
 
-	// Parse and save metrics for each campaign
-	for _, campaignData := range campaigns {
-		campaignMapData, ok := campaignData.(map[string]interface{})
+	campaignIDs, err := s.ensureDirectCampaigns(ctx, account.ID, campaigns, reportRows)
+	if err != nil {
+		return err
+	}
+
+	// Report has rows per campaign, day and ad network type, aggregate them by campaign and day
+	type campaignDay struct {
+		campaignID uint
+		date       string
+	}
+	byDay := make(map[campaignDay]*directMetrics)
+	var order []campaignDay
+	for _, row := range reportRows {
+		directCampaignID, ok := campaignIDs[row.CampaignId]
 		if !ok {
 			continue
 		}
+		key := campaignDay{campaignID: directCampaignID, date: row.Date}
+		metrics, ok := byDay[key]
+		if !ok {
+			metrics = &directMetrics{}
+			byDay[key] = metrics
+			order = append(order, key)
+		}
+		metrics.addRow(row)
+	}
 
-		var campaignID int64
-		if id, ok := campaignMapData["Id"].(float64); ok {
-			campaignID = int64(id)
-		} else if id, ok := campaignMapData["CampaignId"].(float64); ok {
-			campaignID = int64(id)
+	dailyRows := make([]*models.DirectCampaignDaily, 0, len(order))
+	for _, key := range order {
+		date, err := time.Parse("2006-01-02", key.date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q in Direct report: %w", key.date, err)
 		}
 
-		if campaignID == 0 {
+		metrics := byDay[key]
+		metrics.scaleCost(costRate)
+		ctr, cpc, cpa := metrics.rates()
+		dailyRows = append(dailyRows, &models.DirectCampaignDaily{
+			ProjectID:          projectID,
+			DirectCampaignID:   key.campaignID,
+			Date:               date,
+			Impressions:        metrics.impressions,
+			Clicks:             metrics.clicks,
+			CTRPct:             ctr,
+			CPC:                cpc,
+			Conversions:        metrics.conversionsPtr(),
+			CPA:                cpa,
+			Cost:               metrics.cost,
+			NetworkImpressions: metrics.networkImpressions,
+			NetworkClicks:      metrics.networkClicks,
+			NetworkConversions: metrics.networkConversions,
+			NetworkCost:        metrics.networkCost,
+		})
+	}
+
+	if err := s.directRepo.SaveCampaignDaily(ctx, dailyRows); err != nil {
+		return fmt.Errorf("failed to save daily campaign metrics: %w", err)
+	}
+	syncRows(ctx, len(dailyRows))
+
+	s.syncDirectAdGroups(ctx, directClient, account, projectID, campaignIDs, costRate, year, month, dateFrom, dateTo)
+	s.syncDirectKeywords(ctx, directClient, account, projectID, campaignIDs, costRate, year, month, dateFrom, dateTo)
+
+	// Units balance is recorded by the shared transport from API responses
+	if quota, ok := integrations.DefaultTransport().Quota().Get(account.ClientLogin); ok && logger.Log != nil {
+		logger.Log.Info("Direct Units balance after sync",
+			zap.Uint("account_id", account.ID),
+			zap.String("client_login", account.ClientLogin),
+			zap.Int64("units_remaining", quota.Remaining),
+			zap.Int64("units_limit", quota.Limit),
+		)
+	}
+
+	return nil
+}
+
+// syncDirectAdGroups loads ad group report of an account for a month and replaces ad group metrics of its campaigns
+// costRate converts costs from the account currency to the project currency and applies the agency markup
+func (s *SyncService) syncDirectAdGroups(ctx context.Context, directClient *integrations.YandexDirectClient, account *models.DirectAccount, projectID uint, campaignIDs map[int64]uint, costRate float64, year, month int, dateFrom, dateTo string) {
+	reportRows, err := directClient.GetAdGroupReport(ctx, dateFrom, dateTo)
+	if err != nil {
+		syncWarn(ctx, "Failed to get ad group report from Direct API",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	type adGroupKey struct {
+		campaignID uint
+		adGroupID  int64
+	}
+	byAdGroup := make(map[adGroupKey]*directMetrics)
+	names := make(map[adGroupKey]string)
+	var order []adGroupKey
+	for _, row := range reportRows {
+		directCampaignID, ok := campaignIDs[row.CampaignId]
+		if !ok {
 			continue
 		}
-
-		// Find or create DirectCampaign record
-		var directCampaignID uint
-		if dbID, exists := campaignMap[campaignID]; exists {
-			directCampaignID = dbID
-		} else {
-			// Create new campaign record
-			newCampaign := &models.DirectCampaign{
-				DirectAccountID: accountID,
-				CampaignID:      campaignID,
-			}
-			if name, ok := campaignMapData["Name"].(string); ok {
-				newCampaign.Name = name
-			}
-			if status, ok := campaignMapData["Status"].(string); ok {
-				newCampaign.Status = status
-			}
-			if err := s.directRepo.CreateCampaign(ctx, newCampaign); err != nil {
-				continue
-			}
-			directCampaignID = newCampaign.ID
+		key := adGroupKey{campaignID: directCampaignID, adGroupID: row.AdGroupId}
+		metrics, ok := byAdGroup[key]
+		if !ok {
+			metrics = &directMetrics{}
+			byAdGroup[key] = metrics
+			names[key] = row.AdGroupName
+			order = append(order, key)
 		}
+		metrics.addValues(row.Impressions, row.Clicks, row.Cost, row.Conversions)
+	}
+
+	rows := make([]*models.DirectAdGroupMonthly, 0, len(order))
+	for _, key := range order {
+		metrics := byAdGroup[key]
+		metrics.cost *= costRate
+		ctr, cpc, cpa := metrics.rates()
+		rows = append(rows, &models.DirectAdGroupMonthly{
+			ProjectID:        projectID,
+			DirectCampaignID: key.campaignID,
+			AdGroupID:        key.adGroupID,
+			AdGroupName:      names[key],
+			Year:             year,
+			Month:            month,
+			Impressions:      metrics.impressions,
+			Clicks:           metrics.clicks,
+			CTRPct:           ctr,
+			CPC:              cpc,
+			Conversions:      metrics.conversionsPtr(),
+			CPA:              cpa,
+			Cost:             metrics.cost,
+		})
+	}
 
-		// Parse metrics (if available in campaign data)
-		var impressions, clicks int
-		var cost float64
-		var ctr, cpc float64
+	if err := s.directRepo.ReplaceAdGroupMonthly(ctx, directCampaignIDList(campaignIDs), year, month, rows); err != nil {
+		syncWarn(ctx, "Failed to save ad group metrics",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	syncRows(ctx, len(rows))
+}
 
-		if stats, ok := campaignMapData["Statistics"].(map[string]interface{}); ok {
-			if v, ok := stats["Impressions"].(float64); ok {
-				impressions = int(v)
-			}
-			if v, ok := stats["Clicks"].(float64); ok {
-				clicks = int(v)
-			}
-			if v, ok := stats["Cost"].(float64); ok {
-				cost = v
-			}
-		}
+// syncDirectKeywords loads criteria report of an account for a month and replaces keyword metrics of its campaigns
+// costRate converts costs from the account currency to the project currency and applies the agency markup
+func (s *SyncService) syncDirectKeywords(ctx context.Context, directClient *integrations.YandexDirectClient, account *models.DirectAccount, projectID uint, campaignIDs map[int64]uint, costRate float64, year, month int, dateFrom, dateTo string) {
+	reportRows, err := directClient.GetCriteriaReport(ctx, dateFrom, dateTo)
+	if err != nil {
+		syncWarn(ctx, "Failed to get criteria report from Direct API",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
+		return
+	}
 
-		// Calculate CTR and CPC
-		if impressions > 0 && clicks > 0 {
-			ctr = (float64(clicks) / float64(impressions)) * 100
+	type criterionKey struct {
+		campaignID  uint
+		adGroupID   int64
+		criterionID int64
+	}
+	byCriterion := make(map[criterionKey]*directMetrics)
+	criteria := make(map[criterionKey]integrations.CriterionReportRow)
+	var order []criterionKey
+	for _, row := range reportRows {
+		directCampaignID, ok := campaignIDs[row.CampaignId]
+		if !ok {
+			continue
 		}
-		if clicks > 0 && cost > 0 {
-			cpc = cost / float64(clicks)
+		key := criterionKey{campaignID: directCampaignID, adGroupID: row.AdGroupId, criterionID: row.CriterionId}
+		metrics, ok := byCriterion[key]
+		if !ok {
+			metrics = &directMetrics{}
+			byCriterion[key] = metrics
+			criteria[key] = row
+			order = append(order, key)
 		}
+		metrics.addValues(row.Impressions, row.Clicks, row.Cost, row.Conversions)
+	}
 
-		// Save campaign monthly metrics
-		existing, err := s.directRepo.GetCampaignMonthlyByCampaignID(ctx, projectID, directCampaignID, year, month)
-
-		campaignMetrics := &models.DirectCampaignMonthly{
+	rows := make([]*models.DirectKeywordMonthly, 0, len(order))
+	for _, key := range order {
+		metrics := byCriterion[key]
+		metrics.cost *= costRate
+		ctr, cpc, cpa := metrics.rates()
+		rows = append(rows, &models.DirectKeywordMonthly{
 			ProjectID:        projectID,
-			DirectCampaignID: directCampaignID,
+			DirectCampaignID: key.campaignID,
+			AdGroupID:        key.adGroupID,
+			CriterionID:      key.criterionID,
+			Criterion:        criteria[key].Criterion,
+			CriterionType:    criteria[key].CriterionType,
 			Year:             year,
 			Month:            month,
-			Impressions:      impressions,
-			Clicks:           clicks,
+			Impressions:      metrics.impressions,
+			Clicks:           metrics.clicks,
 			CTRPct:           ctr,
 			CPC:              cpc,
-			Cost:             cost,
+			Conversions:      metrics.conversionsPtr(),
+			CPA:              cpa,
+			Cost:             metrics.cost,
+		})
+	}
+
+	if err := s.directRepo.ReplaceKeywordMonthly(ctx, directCampaignIDList(campaignIDs), year, month, rows); err != nil {
+		syncWarn(ctx, "Failed to save keyword metrics",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	syncRows(ctx, len(rows))
+}
+
+// directCostRate returns the rate converting costs of an account to the project currency
+// Account currency is read from Direct and stored; if Direct does not return it, the stored or default one is used
+func (s *SyncService) directCostRate(ctx context.Context, directClient *integrations.YandexDirectClient, account *models.DirectAccount, projectCurrency string, year, month int) (float64, error) {
+	currency, err := directClient.GetClientCurrency(ctx)
+	if err != nil {
+		syncWarn(ctx, "Failed to get account currency from Direct API",
+			zap.Uint("account_id", account.ID),
+			zap.Error(err),
+		)
+		currency = account.Currency
+		if currency == "" {
+			currency = s.defaultCurrency
+		}
+	} else if currency != account.Currency {
+		account.Currency = currency
+		if err := s.directRepo.UpdateAccount(ctx, account); err != nil {
+			syncWarn(ctx, "Failed to save account currency",
+				zap.Uint("account_id", account.ID),
+				zap.Error(err),
+			)
 		}
+	}
+
+	return s.costRate(ctx, currency, projectCurrency, year, month)
+}
 
-		if err == nil && existing != nil {
+// costRate returns the rate converting costs of a month from an ad account currency to the project currency
+func (s *SyncService) costRate(ctx context.Context, currency, projectCurrency string, year, month int) (float64, error) {
+	if currency == "" {
+		currency = models.CurrencyRUB
+	}
+	if projectCurrency == "" {
+		projectCurrency = models.CurrencyRUB
+	}
+	if currency == projectCurrency {
+		return 1, nil
+	}
+	if !models.IsValidCurrency(currency) {
+		return 0, fmt.Errorf("unsupported account currency %s", currency)
+	}
+	if s.rates == nil {
+		return 0, fmt.Errorf("exchange rates are not configured to convert %s costs to %s", currency, projectCurrency)
+	}
+
+	rate, err := s.rates.Rate(ctx, currency, projectCurrency, rateDate(year, month, time.Now()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s costs to %s: %w", currency, projectCurrency, err)
+	}
+	return rate, nil
+}
+
+// directCampaignIDList returns database IDs of campaigns from Yandex ID mapping
+func directCampaignIDList(campaignIDs map[int64]uint) []uint {
+	ids := make([]uint, 0, len(campaignIDs))
+	for _, id := range campaignIDs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// rollupDirectMonthly recomputes monthly campaign metrics and project totals from stored daily rows
+// Totals record the cost basis the month was synced with
+func (s *SyncService) rollupDirectMonthly(ctx context.Context, projectID uint, year, month int, costBasis models.CostBasis) error {
+	startDate, endDate := monthRange(year, month)
+	dailyRows, err := s.directRepo.GetCampaignDaily(ctx, projectID, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get daily campaign metrics: %w", err)
+	}
+
+	byCampaign := make(map[uint]*directMetrics)
+	var order []uint
+	for _, row := range dailyRows {
+		metrics, ok := byCampaign[row.DirectCampaignID]
+		if !ok {
+			metrics = &directMetrics{}
+			byCampaign[row.DirectCampaignID] = metrics
+			order = append(order, row.DirectCampaignID)
+		}
+		metrics.addDaily(row)
+	}
+
+	var totals directMetrics
+	for _, directCampaignID := range order {
+		metrics := byCampaign[directCampaignID]
+		totals.add(*metrics)
+
+		ctr, cpc, cpa := metrics.rates()
+		campaignMetrics := &models.DirectCampaignMonthly{
+			ProjectID:          projectID,
+			DirectCampaignID:   directCampaignID,
+			Year:               year,
+			Month:              month,
+			Impressions:        metrics.impressions,
+			Clicks:             metrics.clicks,
+			CTRPct:             ctr,
+			CPC:                cpc,
+			Conversions:        metrics.conversionsPtr(),
+			CPA:                cpa,
+			Cost:               metrics.cost,
+			NetworkImpressions: metrics.networkImpressions,
+			NetworkClicks:      metrics.networkClicks,
+			NetworkConversions: metrics.networkConversions,
+			NetworkCost:        metrics.networkCost,
+		}
+
+		existing, err := s.directRepo.GetCampaignMonthlyByCampaignID(ctx, projectID, directCampaignID, year, month)
+		if err != nil {
+			return fmt.Errorf("failed to get campaign metrics: %w", err)
+		}
+		if existing != nil {
 			campaignMetrics.ID = existing.ID
-		} else if err != nil {
-			continue
 		}
 
 		if err := s.directRepo.SaveCampaignMonthly(campaignMetrics); err != nil {
+			return fmt.Errorf("failed to save campaign metrics: %w", err)
+		}
+	}
+
+	// Save aggregated monthly totals
+	ctr, cpc, cpa := totals.rates()
+	monthlyTotals := &models.DirectTotalsMonthly{
+		ProjectID:   projectID,
+		Year:        year,
+		Month:       month,
+		Impressions: totals.impressions,
+		Clicks:      totals.clicks,
+		CTRPct:      ctr,
+		CPC:         cpc,
+		Conversions: totals.conversionsPtr(),
+		CPA:         cpa,
+		Cost:        totals.cost,
+		CostBasis:   &costBasis,
+	}
+
+	// Check if record exists
+	existing, err := s.directRepo.GetTotalsMonthly(ctx, projectID, year, month)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		monthlyTotals.ID = existing.ID
+	}
+
+	return s.directRepo.SaveTotalsMonthly(monthlyTotals)
+}
+
+// ensureDirectCampaigns creates missing DirectCampaign records of an account
+// Returns map of Direct campaign ID to DirectCampaign record ID
+func (s *SyncService) ensureDirectCampaigns(ctx context.Context, accountID uint, campaigns []integrations.Campaign, reportRows []integrations.ReportRow) (map[int64]uint, error) {
+	dbCampaigns, err := s.directRepo.GetCampaignsByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+
+	campaignIDs := make(map[int64]uint, len(dbCampaigns))
+	existing := make(map[int64]*models.DirectCampaign, len(dbCampaigns))
+	for _, dbCampaign := range dbCampaigns {
+		campaignIDs[dbCampaign.CampaignID] = dbCampaign.ID
+		existing[dbCampaign.CampaignID] = dbCampaign
+	}
+
+	create := func(campaign *models.DirectCampaign) error {
+		if campaign.CampaignID == 0 {
+			return nil
+		}
+		if _, exists := campaignIDs[campaign.CampaignID]; exists {
+			return nil
+		}
+		campaign.DirectAccountID = accountID
+		if err := s.directRepo.CreateCampaign(ctx, campaign); err != nil {
+			return fmt.Errorf("failed to create campaign %d: %w", campaign.CampaignID, err)
+		}
+		campaignIDs[campaign.CampaignID] = campaign.ID
+		return nil
+	}
+
+	// Campaigns list carries type, state and strategies, which classify campaigns into channels
+	for _, campaign := range campaigns {
+		listed := directCampaignFromAPI(campaign)
+		dbCampaign, exists := existing[campaign.Id]
+		if !exists {
+			if err := create(listed); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !directCampaignChanged(dbCampaign, listed) {
 			continue
 		}
+		dbCampaign.Name = listed.Name
+		dbCampaign.Type = listed.Type
+		dbCampaign.State = listed.State
+		dbCampaign.Status = listed.Status
+		dbCampaign.SearchStrategy = listed.SearchStrategy
+		dbCampaign.NetworkStrategy = listed.NetworkStrategy
+		if err := s.directRepo.UpdateCampaign(ctx, dbCampaign); err != nil {
+			return nil, fmt.Errorf("failed to update campaign %d: %w", campaign.Id, err)
+		}
+	}
+	for _, row := range reportRows {
+		if err := create(&models.DirectCampaign{CampaignID: row.CampaignId, Name: row.CampaignName}); err != nil {
+			return nil, err
+		}
 	}
 
+	return campaignIDs, nil
+}
+
+// directCampaignFromAPI converts campaign from Direct API to model
+func directCampaignFromAPI(campaign integrations.Campaign) *models.DirectCampaign {
+	return &models.DirectCampaign{
+		CampaignID:      campaign.Id,
+		Name:            campaign.Name,
+		Type:            campaign.Type,
+		State:           campaign.State,
+		Status:          campaign.Status,
+		SearchStrategy:  campaign.SearchStrategy(),
+		NetworkStrategy: campaign.NetworkStrategy(),
+	}
+}
+
+// directCampaignChanged checks whether stored campaign differs from campaign returned by API
+func directCampaignChanged(stored, listed *models.DirectCampaign) bool {
+	return stored.Name != listed.Name ||
+		stored.Type != listed.Type ||
+		stored.State != listed.State ||
+		stored.Status != listed.Status ||
+		stored.SearchStrategy != listed.SearchStrategy ||
+		stored.NetworkStrategy != listed.NetworkStrategy
+}
+
+// directMetrics accumulates Direct report values
+// Rates are recalculated from sums instead of averaging per-row rates
+type directMetrics struct {
+	impressions    int
+	clicks         int
+	cost           float64
+	conversions    int
+	hasConversions bool
+
+	// Part of the values served in ad networks, tracked for campaign rows
+	networkImpressions int
+	networkClicks      int
+	networkCost        float64
+	networkConversions int
+}
+
+// addRow adds report row values, rows of ad networks are also added to the network part
+func (m *directMetrics) addRow(row integrations.ReportRow) {
+	m.addValues(row.Impressions, row.Clicks, row.Cost, row.Conversions)
+	if row.AdNetworkType == integrations.AdNetworkTypeNetwork {
+		m.networkImpressions += int(row.Impressions)
+		m.networkClicks += int(row.Clicks)
+		m.networkCost += row.Cost
+		m.networkConversions += int(row.Conversions)
+	}
+}
+
+// addValues adds raw values of ad group or keyword report rows
+func (m *directMetrics) addValues(impressions, clicks int64, cost float64, conversions int64) {
+	m.impressions += int(impressions)
+	m.clicks += int(clicks)
+	m.cost += cost
+	if conversions > 0 {
+		m.conversions += int(conversions)
+		m.hasConversions = true
+	}
+}
+
+// addDaily adds stored daily row values
+func (m *directMetrics) addDaily(row *models.DirectCampaignDaily) {
+	m.impressions += row.Impressions
+	m.clicks += row.Clicks
+	m.cost += row.Cost
+	if row.Conversions != nil {
+		m.conversions += *row.Conversions
+		m.hasConversions = true
+	}
+	m.networkImpressions += row.NetworkImpressions
+	m.networkClicks += row.NetworkClicks
+	m.networkCost += row.NetworkCost
+	m.networkConversions += row.NetworkConversions
+}
+
+// addMonthly adds stored monthly campaign row values
+func (m *directMetrics) addMonthly(row *models.DirectCampaignMonthly) {
+	m.impressions += row.Impressions
+	m.clicks += row.Clicks
+	m.cost += row.Cost
+	if row.Conversions != nil {
+		m.conversions += *row.Conversions
+		m.hasConversions = true
+	}
+	m.networkImpressions += row.NetworkImpressions
+	m.networkClicks += row.NetworkClicks
+	m.networkCost += row.NetworkCost
+	m.networkConversions += row.NetworkConversions
+}
+
+// add adds values of another accumulator
+func (m *directMetrics) add(other directMetrics) {
+	m.impressions += other.impressions
+	m.clicks += other.clicks
+	m.cost += other.cost
+	m.conversions += other.conversions
+	m.hasConversions = m.hasConversions || other.hasConversions
+	m.networkImpressions += other.networkImpressions
+	m.networkClicks += other.networkClicks
+	m.networkCost += other.networkCost
+	m.networkConversions += other.networkConversions
+}
+
+// scaleCost multiplies cost and its network part by a currency or cost basis rate
+func (m *directMetrics) scaleCost(rate float64) {
+	m.cost *= rate
+	m.networkCost *= rate
+}
+
+// splitByNetwork returns values served on search and in ad networks
+func (m *directMetrics) splitByNetwork() (search, network directMetrics) {
+	network = directMetrics{
+		impressions:    m.networkImpressions,
+		clicks:         m.networkClicks,
+		cost:           m.networkCost,
+		conversions:    m.networkConversions,
+		hasConversions: m.hasConversions,
+	}
+	search = directMetrics{
+		impressions:    m.impressions - m.networkImpressions,
+		clicks:         m.clicks - m.networkClicks,
+		cost:           m.cost - m.networkCost,
+		conversions:    m.conversions - m.networkConversions,
+		hasConversions: m.hasConversions,
+	}
+	return search, network
+}
+
+// conversionsPtr returns conversions or nil when report has no conversion data
+func (m *directMetrics) conversionsPtr() *int {
+	if !m.hasConversions {
+		return nil
+	}
+	conversions := m.conversions
+	return &conversions
+}
+
+// rates returns CTR (%), CPC and CPA calculated from accumulated values
+func (m *directMetrics) rates() (float64, float64, *float64) {
+	var ctr, cpc float64
+	if m.impressions > 0 {
+		ctr = (float64(m.clicks) / float64(m.impressions)) * 100
+	}
+	if m.clicks > 0 {
+		cpc = m.cost / float64(m.clicks)
+	}
+	var cpa *float64
+	if m.conversions > 0 {
+		value := m.cost / float64(m.conversions)
+		cpa = &value
+	}
+	return ctr, cpc, cpa
+}
+
+// SyncWebmasterData synchronizes Yandex.Webmaster search queries for a project
+// Popular queries of every bound host are stored with average position, clicks and shows
+// Made public for use by queue workers
+func (s *SyncService) SyncWebmasterData(ctx context.Context, projectID uint, year, month int) error {
+	return s.trackRun(ctx, projectID, models.SyncSourceWebmaster, year, month, s.syncWebmasterData)
+}
+
+// syncWebmasterData performs Webmaster sync recorded by SyncWebmasterData
+func (s *SyncService) syncWebmasterData(ctx context.Context, projectID uint, year, month int) error {
+	hosts, err := s.seoRepo.GetHostsByProjectID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get Webmaster hosts: %w", err)
+	}
+
+	if len(hosts) == 0 {
+		return nil // No hosts to sync
+	}
+
+	// Webmaster does not accept dates in the future
+	startDate, endDate := monthRange(year, month)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if endDate.After(today) {
+		endDate = today
+	}
+	if startDate.After(endDate) {
+		return nil
+	}
+	dateFrom := startDate.Format("2006-01-02")
+	dateTo := endDate.Format("2006-01-02")
+
+	var queries []*models.SEOQueriesMonthly
+	synced := 0
+	var lastErr error
+
+	for _, host := range hosts {
+		webmasterClient, err := s.webmasterClientFor(ctx, projectID, host)
+		if err == nil {
+			var hostQueries []*models.SEOQueriesMonthly
+			hostQueries, err = webmasterHostQueries(ctx, webmasterClient, host, year, month, dateFrom, dateTo)
+			queries = append(queries, hostQueries...)
+		}
+		if err != nil {
+			// Log error but continue with other hosts
+			syncWarn(ctx, "Failed to sync Webmaster host",
+				zap.Uint("project_id", projectID),
+				zap.String("host_id", host.HostID),
+				zap.Error(err),
+			)
+			lastErr = err
+			continue
+		}
+		synced++
+	}
+
+	// Keep previously stored queries if nothing could be loaded
+	if synced == 0 {
+		return fmt.Errorf("failed to sync any Webmaster host: %w", lastErr)
+	}
+
+	if err := s.seoRepo.ReplaceSEOQueries(ctx, projectID, year, month, queries); err != nil {
+		return fmt.Errorf("failed to save SEO queries: %w", err)
+	}
+	syncRows(ctx, len(queries))
+
 	return nil
 }
+
+// webmasterHostQueries loads popular queries of a host and converts them to monthly rows
+func webmasterHostQueries(ctx context.Context, client *integrations.YandexWebmasterClient, host *models.WebmasterHost, year, month int, dateFrom, dateTo string) ([]*models.SEOQueriesMonthly, error) {
+	userID, err := client.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	popular, err := client.GetPopularQueries(ctx, userID, host.HostID, dateFrom, dateTo, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostURL *string
+	if host.HostURL != "" {
+		value := host.HostURL
+		hostURL = &value
+	}
+
+	queries := make([]*models.SEOQueriesMonthly, 0, len(popular))
+	for _, query := range popular {
+		queries = append(queries, &models.SEOQueriesMonthly{
+			ProjectID: host.ProjectID,
+			Year:      year,
+			Month:     month,
+			Query:     query.QueryText,
+			Position:  int(math.Round(query.AvgPosition)),
+			Clicks:    query.Clicks,
+			Shows:     query.Shows,
+			URL:       hostURL,
+		})
+	}
+
+	return queries, nil
+}
+
+// ageGroups maps Metrica age interval names to AgeGroup values
+var ageGroups = map[string]models.AgeGroup{
+	"18-24":   models.AgeGroup1824,
+	"25-34":   models.AgeGroup2534,
+	"35-44":   models.AgeGroup3544,
+	"45-54":   models.AgeGroup4554,
+	"55+":     models.AgeGroup55Plus,
+	"unknown": models.AgeGroupUnknown,
+}
+
+// addAgeMetrics adds age breakdown of one counter to the accumulator
+// Unknown intervals are collected into AgeGroupUnknown
+func addAgeMetrics(ages *metricaBreakdown, results []integrations.AgeMetricsResult) {
+	for _, result := range results {
+		ageGroup, exists := ageGroups[result.AgeGroup]
+		if !exists {
+			ageGroup = models.AgeGroupUnknown
+		}
+		ages.add(string(ageGroup), string(ageGroup), int(result.Visits), int(result.Users), result.BounceRate, result.AvgSessionDurationSec)
+	}
+}
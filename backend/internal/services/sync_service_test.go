@@ -0,0 +1,612 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+func TestDirectMetrics_Rates(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            []integrations.ReportRow
+		wantImpressions int
+		wantClicks      int
+		wantCost        float64
+		wantCTR         float64
+		wantCPC         float64
+		wantConversions *int
+		wantCPA         *float64
+	}{
+		{
+			name: "несколько строк одной кампании",
+			rows: []integrations.ReportRow{
+				{CampaignId: 1, Impressions: 1000, Clicks: 50, Cost: 500, Conversions: 5},
+				{CampaignId: 1, Impressions: 3000, Clicks: 50, Cost: 1500, Conversions: 15},
+			},
+			wantImpressions: 4000,
+			wantClicks:      100,
+			wantCost:        2000,
+			wantCTR:         2.5,
+			wantCPC:         20,
+			wantConversions: intPtr(20),
+			wantCPA:         floatPtr(100),
+		},
+		{
+			name: "без конверсий",
+			rows: []integrations.ReportRow{
+				{CampaignId: 1, Impressions: 100, Clicks: 10, Cost: 50},
+			},
+			wantImpressions: 100,
+			wantClicks:      10,
+			wantCost:        50,
+			wantCTR:         10,
+			wantCPC:         5,
+		},
+		{
+			name:            "пустой отчёт",
+			rows:            nil,
+			wantImpressions: 0,
+			wantClicks:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metrics directMetrics
+			for _, row := range tt.rows {
+				metrics.addRow(row)
+			}
+
+			if metrics.impressions != tt.wantImpressions || metrics.clicks != tt.wantClicks || metrics.cost != tt.wantCost {
+				t.Errorf("totals = %d/%d/%v, want %d/%d/%v", metrics.impressions, metrics.clicks, metrics.cost,
+					tt.wantImpressions, tt.wantClicks, tt.wantCost)
+			}
+
+			ctr, cpc, cpa := metrics.rates()
+			if math.Abs(ctr-tt.wantCTR) > 0.0001 {
+				t.Errorf("CTR = %v, want %v", ctr, tt.wantCTR)
+			}
+			if math.Abs(cpc-tt.wantCPC) > 0.0001 {
+				t.Errorf("CPC = %v, want %v", cpc, tt.wantCPC)
+			}
+
+			conversions := metrics.conversionsPtr()
+			if (conversions == nil) != (tt.wantConversions == nil) || (conversions != nil && *conversions != *tt.wantConversions) {
+				t.Errorf("conversions = %v, want %v", conversions, tt.wantConversions)
+			}
+			if (cpa == nil) != (tt.wantCPA == nil) || (cpa != nil && math.Abs(*cpa-*tt.wantCPA) > 0.0001) {
+				t.Errorf("CPA = %v, want %v", cpa, tt.wantCPA)
+			}
+		})
+	}
+}
+
+func TestSyncService_EnsureDirectCampaigns(t *testing.T) {
+	var created, updated []*models.DirectCampaign
+	repo := &MockDirectRepositoryForDirectService{
+		GetCampaignsByAccountIDFunc: func(ctx context.Context, accountID uint) ([]*models.DirectCampaign, error) {
+			return []*models.DirectCampaign{{ID: 10, DirectAccountID: accountID, CampaignID: 100}}, nil
+		},
+		UpdateCampaignFunc: func(ctx context.Context, campaign *models.DirectCampaign) error {
+			updated = append(updated, campaign)
+			return nil
+		},
+		CreateCampaignFunc: func(ctx context.Context, campaign *models.DirectCampaign) error {
+			campaign.ID = uint(20 + len(created))
+			created = append(created, campaign)
+			return nil
+		},
+	}
+	service := &SyncService{directRepo: repo}
+
+	campaignIDs, err := service.ensureDirectCampaigns(context.Background(), 7,
+		[]integrations.Campaign{{Id: 100, Name: "Existing", Type: "UNIFIED_CAMPAIGN", State: "ON"}, {Id: 200, Name: "From list", Type: "SMART_CAMPAIGN"}},
+		[]integrations.ReportRow{{CampaignId: 200, CampaignName: "From list"}, {CampaignId: 300, CampaignName: "Archived"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("created %d campaigns, want 2", len(created))
+	}
+	for _, campaign := range created {
+		if campaign.DirectAccountID != 7 {
+			t.Errorf("campaign %d created for account %d, want 7", campaign.CampaignID, campaign.DirectAccountID)
+		}
+	}
+	if created[1].Name != "Archived" {
+		t.Errorf("campaign name = %q, want %q", created[1].Name, "Archived")
+	}
+	if created[0].Type != "SMART_CAMPAIGN" {
+		t.Errorf("campaign type = %q, want %q", created[0].Type, "SMART_CAMPAIGN")
+	}
+	if len(updated) != 1 || updated[0].ID != 10 || updated[0].Type != "UNIFIED_CAMPAIGN" || updated[0].State != "ON" {
+		t.Errorf("existing campaign should be updated with type and state, got %+v", updated)
+	}
+
+	want := map[int64]uint{100: 10, 200: 20, 300: 21}
+	for campaignID, id := range want {
+		if campaignIDs[campaignID] != id {
+			t.Errorf("campaignIDs[%d] = %d, want %d", campaignID, campaignIDs[campaignID], id)
+		}
+	}
+}
+
+func TestSyncService_RollupMetricsMonthly(t *testing.T) {
+	var saved *models.MetricsMonthly
+	repo := &MockMetricsRepository{
+		GetDailyMetricsFunc: func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.MetricsDaily, error) {
+			if dateFrom.Day() != 1 || dateTo.Day() != 28 {
+				t.Errorf("неверный диапазон дат: %s - %s", dateFrom, dateTo)
+			}
+			return []*models.MetricsDaily{
+				{Visits: 100, Users: 90, BounceRate: 50, AvgSessionDurationSec: 60, Conversions: intPtr(2)},
+				{Visits: 300, Users: 250, BounceRate: 30, AvgSessionDurationSec: 100},
+			}, nil
+		},
+		GetMonthlyMetricsFunc: func(ctx context.Context, projectID uint, year int, month int) (*models.MetricsMonthly, error) {
+			return &models.MetricsMonthly{ID: 5, Users: 111}, nil
+		},
+		SaveMonthlyMetricsFunc: func(metrics *models.MetricsMonthly) error {
+			saved = metrics
+			return nil
+		},
+	}
+	service := &SyncService{metricsRepo: repo}
+
+	if err := service.rollupMetricsMonthly(context.Background(), 1, 2025, 2, nil, nil); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if saved.ID != 5 || saved.Visits != 400 {
+		t.Errorf("неверная запись: ID=%d, Visits=%d", saved.ID, saved.Visits)
+	}
+	if saved.Users != 111 {
+		t.Errorf("пользователи должны сохраниться из существующей записи, получили %d", saved.Users)
+	}
+	if math.Abs(saved.BounceRate-35) > 0.0001 || saved.AvgSessionDurationSec != 90 {
+		t.Errorf("ожидались взвешенные показатели 35/90, получили %v/%d", saved.BounceRate, saved.AvgSessionDurationSec)
+	}
+	if saved.Conversions == nil || *saved.Conversions != 2 {
+		t.Errorf("неверные конверсии: %v", saved.Conversions)
+	}
+	if saved.Revenue != nil {
+		t.Errorf("выручка не должна появляться без синхронизации: %v", *saved.Revenue)
+	}
+
+	revenue := &metricaRevenue{purchases: 4, revenue: 20000, goalRevenue: 1500}
+	if err := service.rollupMetricsMonthly(context.Background(), 1, 2025, 2, nil, revenue); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if saved.Purchases == nil || *saved.Purchases != 4 || *saved.Revenue != 20000 || *saved.GoalRevenue != 1500 {
+		t.Errorf("неверная выручка: %+v", saved)
+	}
+}
+
+func TestSyncService_RollupDirectMonthly(t *testing.T) {
+	var campaigns []*models.DirectCampaignMonthly
+	var totals *models.DirectTotalsMonthly
+	repo := &MockDirectRepositoryForDirectService{
+		GetCampaignDailyFunc: func(ctx context.Context, projectID uint, dateFrom, dateTo time.Time) ([]*models.DirectCampaignDaily, error) {
+			return []*models.DirectCampaignDaily{
+				{DirectCampaignID: 1, Impressions: 1000, Clicks: 10, Cost: 100, Conversions: intPtr(1)},
+				{DirectCampaignID: 2, Impressions: 500, Clicks: 40, Cost: 200},
+				{DirectCampaignID: 1, Impressions: 1000, Clicks: 30, Cost: 300, Conversions: intPtr(3),
+					NetworkImpressions: 800, NetworkClicks: 5, NetworkCost: 50, NetworkConversions: 1},
+			}, nil
+		},
+		SaveCampaignMonthlyFunc: func(campaign *models.DirectCampaignMonthly) error {
+			campaigns = append(campaigns, campaign)
+			return nil
+		},
+		SaveTotalsMonthlyFunc: func(monthly *models.DirectTotalsMonthly) error {
+			totals = monthly
+			return nil
+		},
+	}
+	service := &SyncService{directRepo: repo}
+
+	costBasis := models.CostBasis{IncludeVAT: true, MarkupPct: 10}
+	if err := service.rollupDirectMonthly(context.Background(), 1, 2025, 3, costBasis); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(campaigns) != 2 {
+		t.Fatalf("ожидалось 2 кампании, получили %d", len(campaigns))
+	}
+	first := campaigns[0]
+	if first.DirectCampaignID != 1 || first.Impressions != 2000 || first.Clicks != 40 || first.Cost != 400 {
+		t.Errorf("неверные суммы кампании: %+v", first)
+	}
+	if first.CPA == nil || *first.CPA != 100 {
+		t.Errorf("ожидался CPA 100, получили %v", first.CPA)
+	}
+	if first.NetworkImpressions != 800 || first.NetworkClicks != 5 || first.NetworkCost != 50 || first.NetworkConversions != 1 {
+		t.Errorf("неверная часть РСЯ кампании: %+v", first)
+	}
+
+	if totals.Impressions != 2500 || totals.Clicks != 80 || totals.Cost != 600 {
+		t.Errorf("неверные итоги: %+v", totals)
+	}
+	if totals.Conversions == nil || *totals.Conversions != 4 || math.Abs(totals.CPC-7.5) > 0.0001 {
+		t.Errorf("неверные конверсии или CPC итогов: %v/%v", totals.Conversions, totals.CPC)
+	}
+	if totals.CostBasis == nil || *totals.CostBasis != costBasis {
+		t.Errorf("итоги должны хранить базу расходов %+v, получили %+v", costBasis, totals.CostBasis)
+	}
+}
+
+func TestSyncService_SyncTrafficSources(t *testing.T) {
+	// Two counters: organic is present in both, goals are requested per counter
+	newServer := func(expectedMetrics, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("metrics") != expectedMetrics {
+				t.Errorf("неверные метрики: %s", r.URL.Query().Get("metrics"))
+			}
+			w.Write([]byte(body))
+		}))
+	}
+	server1 := newServer("ym:s:visits,ym:s:users,ym:s:goal11reaches",
+		`{"data": [{"dimensions": [{"name": "Поиск", "id": "organic"}], "metrics": [100, 80, 3]}]}`)
+	defer server1.Close()
+	server2 := newServer("ym:s:visits,ym:s:users",
+		`{"data": [{"dimensions": [{"name": "Поиск", "id": "organic"}], "metrics": [50, 40]},
+			{"dimensions": [{"name": "Реклама", "id": "ad"}], "metrics": [20, 10]}]}`)
+	defer server2.Close()
+
+	counters := []*models.YandexCounter{{ID: 1, CounterID: 1001}, {ID: 2, CounterID: 1002}}
+	clients := map[uint]*integrations.YandexMetricaClient{
+		1: integrations.NewYandexMetricaClientWithURL("token", server1.URL),
+		2: integrations.NewYandexMetricaClientWithURL("token", server2.URL),
+	}
+
+	var saved []*models.MetricsTrafficSourceMonthly
+	service := &SyncService{
+		goalRepo: &MockGoalRepository{
+			GetByCounterIDsFunc: func(ctx context.Context, counterIDs []uint) ([]*models.Goal, error) {
+				return []*models.Goal{
+					{CounterID: 1, GoalID: 11, IsConversion: true},
+					{CounterID: 2, GoalID: 22, IsConversion: false},
+				}, nil
+			},
+		},
+		metricsRepo: &MockMetricsRepository{
+			ReplaceTrafficSourceMetricsFunc: func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsTrafficSourceMonthly) error {
+				saved = metrics
+				return nil
+			},
+		},
+	}
+
+	if err := service.syncTrafficSources(context.Background(), 1, counters, clients, 2025, 1); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("ожидалось 2 источника, получили %d", len(saved))
+	}
+	organic := saved[0]
+	if organic.Source != models.TrafficSourceOrganic || organic.Visits != 150 || organic.Users != 120 {
+		t.Errorf("неверные суммы органики: %+v", organic)
+	}
+	if organic.Conversions == nil || *organic.Conversions != 3 {
+		t.Errorf("ожидалось 3 конверсии органики, получили %v", organic.Conversions)
+	}
+	if saved[1].Source != models.TrafficSourceAd || saved[1].Conversions != nil {
+		t.Errorf("неверная строка рекламы: %+v", saved[1])
+	}
+}
+
+func TestSyncService_SyncMetricaData_NoCounterSynced(t *testing.T) {
+	// No token is configured, so none of the counters can be queried
+	service := &SyncService{
+		counterRepo: &MockCounterRepository{
+			GetByProjectIDFunc: func(ctx context.Context, projectID uint) ([]*models.YandexCounter, error) {
+				return []*models.YandexCounter{{ID: 1, CounterID: 1001}, {ID: 2, CounterID: 1002}}, nil
+			},
+		},
+		projectRepo: &MockProjectRepository{
+			GetByIDFunc: func(ctx context.Context, id uint) (*models.Project, error) {
+				return &models.Project{ID: id, IsActive: true}, nil
+			},
+		},
+		metricsRepo: &MockMetricsRepository{
+			UpdateFunc: func(ctx context.Context, metrics *models.MetricsMonthly) error {
+				t.Error("метрики не должны сохраняться, если ни один счётчик не загружен")
+				return nil
+			},
+		},
+		credentials: NewOAuthCredentialService(&MockOAuthCredentialRepository{}, &MockDirectRepositoryForDirectService{}, &MockYandexOAuthClient{}, testAppKey, ""),
+	}
+
+	err := service.syncMetricaData(context.Background(), 1, 2025, 1)
+	if !errors.Is(err, ErrNoOAuthToken) {
+		t.Errorf("ожидалась ошибка синхронизации с причиной, получили %v", err)
+	}
+}
+
+func TestSyncService_SyncRevenue(t *testing.T) {
+	// Two counters: campaign 101 is present in both, goal revenue is requested per counter
+	// The project is in tenge, revenue of both counters is requested in it
+	newServer := func(expectedMetrics, total, campaigns string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("metrics") != expectedMetrics {
+				t.Errorf("неверные метрики: %s", r.URL.Query().Get("metrics"))
+			}
+			if r.URL.Query().Get("currency") != models.CurrencyKZT {
+				t.Errorf("выручка должна запрашиваться в валюте проекта, получили currency=%s", r.URL.Query().Get("currency"))
+			}
+			if r.URL.Query().Get("dimensions") == "" {
+				w.Write([]byte(total))
+				return
+			}
+			w.Write([]byte(campaigns))
+		}))
+	}
+	server1 := newServer("ym:s:ecommercePurchases,ym:s:ecommerceRevenue,ym:s:goal11revenue",
+		`{"data": [{"dimensions": [], "metrics": [5, 50000, 3000]}]}`,
+		`{"data": [{"dimensions": [{"name": "Бренд", "id": 101}], "metrics": [2, 20000, 1000]}]}`)
+	defer server1.Close()
+	server2 := newServer("ym:s:ecommercePurchases,ym:s:ecommerceRevenue",
+		`{"data": [{"dimensions": [], "metrics": [1, 7000]}]}`,
+		`{"data": [{"dimensions": [{"name": "Бренд", "id": 101}], "metrics": [1, 7000]},
+			{"dimensions": [{"name": "РСЯ", "id": "102"}], "metrics": [0, 0]}]}`)
+	defer server2.Close()
+
+	counters := []*models.YandexCounter{{ID: 1, CounterID: 1001}, {ID: 2, CounterID: 1002}}
+	clients := map[uint]*integrations.YandexMetricaClient{
+		1: integrations.NewYandexMetricaClientWithURL("token", server1.URL),
+		2: integrations.NewYandexMetricaClientWithURL("token", server2.URL),
+	}
+
+	var saved []*models.MetricsCampaignRevenueMonthly
+	service := &SyncService{
+		goalRepo: &MockGoalRepository{
+			GetByCounterIDsFunc: func(ctx context.Context, counterIDs []uint) ([]*models.Goal, error) {
+				return []*models.Goal{
+					{CounterID: 1, GoalID: 11, IsConversion: true},
+					{CounterID: 2, GoalID: 22, IsConversion: false},
+				}, nil
+			},
+		},
+		metricsRepo: &MockMetricsRepository{
+			ReplaceCampaignRevenueFunc: func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsCampaignRevenueMonthly) error {
+				saved = metrics
+				return nil
+			},
+		},
+	}
+
+	revenue, err := service.syncRevenue(context.Background(), 1, counters, clients, models.CurrencyKZT, 2025, 1)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if *revenue != (metricaRevenue{purchases: 6, revenue: 57000, goalRevenue: 3000}) {
+		t.Errorf("неверная выручка проекта: %+v", revenue)
+	}
+	if len(saved) != 2 {
+		t.Fatalf("ожидалось 2 кампании, получили %d", len(saved))
+	}
+	if brand := saved[0]; brand.CampaignID != 101 || brand.Purchases != 3 || brand.Revenue != 27000 || brand.GoalRevenue != 1000 {
+		t.Errorf("неверные суммы кампании: %+v", brand)
+	}
+
+	revenue, err = service.syncRevenue(context.Background(), 1, counters, map[uint]*integrations.YandexMetricaClient{}, models.CurrencyKZT, 2025, 1)
+	if err != nil || revenue != nil {
+		t.Errorf("без счётчиков выручка должна сохраняться прежней, получили %+v, %v", revenue, err)
+	}
+}
+
+func TestWebmasterHostQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"user_id": 7}`))
+		case "/user/7/hosts/https:example.com:443/search-queries/popular":
+			if r.URL.Query().Get("date_from") != "2025-01-01" || r.URL.Query().Get("date_to") != "2025-01-31" {
+				t.Errorf("неверный период: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"queries": [
+				{"query_text": "купить диван", "indicators": {"TOTAL_SHOWS": 900, "TOTAL_CLICKS": 40, "AVG_SHOW_POSITION": 4.6}}
+			]}`))
+		default:
+			t.Errorf("неожиданный запрос: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	host := &models.WebmasterHost{ProjectID: 3, HostID: "https:example.com:443", HostURL: "https://example.com/"}
+	client := integrations.NewYandexWebmasterClientWithURL("token", server.URL)
+
+	queries, err := webmasterHostQueries(context.Background(), client, host, 2025, 1, "2025-01-01", "2025-01-31")
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("ожидался 1 запрос, получили %d", len(queries))
+	}
+	query := queries[0]
+	if query.ProjectID != 3 || query.Year != 2025 || query.Month != 1 || query.Query != "купить диван" {
+		t.Errorf("неверная строка: %+v", query)
+	}
+	if query.Position != 5 || query.Clicks != 40 || query.Shows != 900 {
+		t.Errorf("ожидались позиция 5, клики 40, показы 900, получили %d/%d/%d", query.Position, query.Clicks, query.Shows)
+	}
+	if query.URL == nil || *query.URL != "https://example.com/" {
+		t.Errorf("неверный URL: %v", query.URL)
+	}
+}
+
+func TestSyncService_SyncGoals(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/counter/1001/goals" {
+			t.Errorf("неожиданный запрос: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"goals": [
+			{"id": 1, "name": "Заявка (новое имя)", "type": "action"},
+			{"id": 2, "name": "Звонок", "type": "call"},
+			{"id": 3, "name": "Корзина", "type": "url"},
+			{"id": 5, "name": "Новая цель", "type": "form"}
+		]}`))
+	}))
+	defer server.Close()
+
+	var upserted []*models.Goal
+	service := &SyncService{
+		goalRepo: &MockGoalRepository{
+			GetByCounterIDFunc: func(ctx context.Context, counterID uint) ([]*models.Goal, error) {
+				return []*models.Goal{
+					{ID: 11, CounterID: counterID, GoalID: 1, Name: "Заявка", Type: "action", IsConversion: true},
+					{ID: 12, CounterID: counterID, GoalID: 2, Name: "Звонок", Type: "call"},
+					{ID: 13, CounterID: counterID, GoalID: 3, Name: "Корзина", Type: "url", IsDeleted: true},
+					{ID: 14, CounterID: counterID, GoalID: 4, Name: "Удалённая", Type: "url", IsConversion: true},
+				}, nil
+			},
+			UpsertFunc: func(ctx context.Context, goal *models.Goal) error {
+				upserted = append(upserted, goal)
+				return nil
+			},
+			// Saving whole goals could overwrite IsConversion with a stale value
+			CreateFunc: func(ctx context.Context, goal *models.Goal) error {
+				t.Errorf("цели должны сохраняться через upsert: %+v", goal)
+				return nil
+			},
+			UpdateFunc: func(ctx context.Context, goal *models.Goal) error {
+				t.Errorf("цели должны сохраняться через upsert: %+v", goal)
+				return nil
+			},
+		},
+	}
+
+	counter := &models.YandexCounter{ID: 7, CounterID: 1001}
+	client := integrations.NewYandexMetricaClientWithURL("token", server.URL)
+	if err := service.syncGoals(context.Background(), counter, client); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	byGoalID := make(map[int64]*models.Goal)
+	for _, goal := range upserted {
+		if goal.CounterID != 7 {
+			t.Errorf("цель должна сохраняться в счётчик 7: %+v", goal)
+		}
+		byGoalID[goal.GoalID] = goal
+	}
+	if len(upserted) != 4 || byGoalID[2] != nil {
+		t.Fatalf("ожидалось сохранение целей 1, 3, 4 и 5, получили %d", len(upserted))
+	}
+	if goal := byGoalID[1]; goal.Name != "Заявка (новое имя)" || goal.IsDeleted {
+		t.Errorf("имя должно обновиться: %+v", goal)
+	}
+	if byGoalID[3].IsDeleted {
+		t.Errorf("вернувшаяся цель должна сняться с пометки удаления")
+	}
+	if goal := byGoalID[4]; !goal.IsDeleted || goal.Name != "Удалённая" {
+		t.Errorf("цель, удалённая в Метрике, должна быть помечена удалённой: %+v", goal)
+	}
+	if goal := byGoalID[5]; goal.Type != "form" || goal.IsConversion {
+		t.Errorf("неверно создана новая цель: %+v", goal)
+	}
+}
+
+func TestSyncService_SyncGoalMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("metrics") != "ym:s:goal11visits,ym:s:goal11reaches,ym:s:goal12visits,ym:s:goal12reaches" {
+			t.Errorf("неверные метрики: %s", r.URL.Query().Get("metrics"))
+		}
+		w.Write([]byte(`{"data": [{"dimensions": [], "metrics": [40, 55, 10, 10]}]}`))
+	}))
+	defer server.Close()
+
+	counters := []*models.YandexCounter{{ID: 1, CounterID: 1001}, {ID: 2, CounterID: 1002}}
+	clients := map[uint]*integrations.YandexMetricaClient{
+		1: integrations.NewYandexMetricaClientWithURL("token", server.URL),
+	}
+
+	var saved []*models.MetricsGoalMonthly
+	service := &SyncService{
+		goalRepo: &MockGoalRepository{
+			GetByCounterIDsFunc: func(ctx context.Context, counterIDs []uint) ([]*models.Goal, error) {
+				return []*models.Goal{
+					{CounterID: 1, GoalID: 11, Name: "Заявка", IsConversion: true},
+					{CounterID: 1, GoalID: 12, Name: "Звонок", IsConversion: true},
+					{CounterID: 1, GoalID: 13, Name: "Не конверсия"},
+					{CounterID: 1, GoalID: 14, Name: "Удалённая", IsConversion: true, IsDeleted: true},
+				}, nil
+			},
+		},
+		metricsRepo: &MockMetricsRepository{
+			ReplaceGoalMetricsFunc: func(ctx context.Context, projectID uint, year int, month int, metrics []*models.MetricsGoalMonthly) error {
+				saved = metrics
+				return nil
+			},
+		},
+	}
+
+	if err := service.syncGoalMetrics(context.Background(), 5, counters, clients, map[uint]int{1: 1000}, 2025, 1); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("ожидалось 2 цели, получили %d", len(saved))
+	}
+	lead := saved[0]
+	if lead.ProjectID != 5 || lead.GoalID != 11 || lead.GoalName != "Заявка" || lead.Reaches != 55 || lead.Visits != 40 {
+		t.Errorf("неверная строка цели: %+v", lead)
+	}
+	if math.Abs(lead.ConversionRate-4) > 0.0001 {
+		t.Errorf("ожидалась конверсия 4%%, получили %v", lead.ConversionRate)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestSyncService_CostRate(t *testing.T) {
+	rates := NewExchangeRateService(&MockExchangeRateRepository{Rates: []*models.ExchangeRate{
+		{Base: "USD", Quote: "RUB", Date: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), Rate: 90},
+	}}, nil)
+
+	tests := []struct {
+		name            string
+		service         *SyncService
+		currency        string
+		projectCurrency string
+		wantRate        float64
+		wantErr         bool
+	}{
+		{name: "валюта аккаунта совпадает с валютой проекта", service: &SyncService{}, currency: "KZT", projectCurrency: "KZT", wantRate: 1},
+		{name: "неизвестная валюта аккаунта считается рублями", service: &SyncService{}, currency: "", projectCurrency: "", wantRate: 1},
+		{name: "курс на конец месяца", service: &SyncService{rates: rates}, currency: "USD", projectCurrency: "RUB", wantRate: 90},
+		{name: "курсы не настроены", service: &SyncService{}, currency: "USD", projectCurrency: "RUB", wantErr: true},
+		{name: "условные единицы не поддерживаются", service: &SyncService{rates: rates}, currency: "YND_FIXED", projectCurrency: "RUB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := tt.service.costRate(context.Background(), tt.currency, tt.projectCurrency, 2025, 2)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if rate != tt.wantRate {
+				t.Errorf("ожидался курс %v, получили %v", tt.wantRate, rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportRowError is a validation error of a row of imported data
+type ImportRowError struct {
+	Row     int    `json:"row"` // Line of a file (the header is line 1) or position in a JSON list starting with 1
+	Message string `json:"message"`
+}
+
+// ImportError is returned when rows of imported data are invalid, nothing is saved in this case
+type ImportError struct {
+	Rows []ImportRowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("invalid rows: %d", len(e.Rows))
+}
+
+// tableColumns finds columns of a table by header names (case-insensitive aliases)
+// Returns an "invalid file" error if a required column is missing
+func tableColumns(header []string, aliases map[string]string, required []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		if column, ok := aliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, exists := columns[column]; !exists {
+				columns[column] = i
+			}
+		}
+	}
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("invalid file: missing column %s", column)
+		}
+	}
+	return columns, nil
+}
+
+// tableValue returns a getter of trimmed column values of a record, missing columns are empty
+func tableValue(columns map[string]int, record []string) func(column string) string {
+	return func(column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+}
+
+// isEmptyRecord checks whether all values of a record are blank
+func isEmptyRecord(record []string) bool {
+	return strings.TrimSpace(strings.Join(record, "")) == ""
+}
+
+// parseImportDate parses a date as YYYY-MM-DD, DD.MM.YYYY, RFC 3339 or an Excel date serial number
+// Extra layouts are tried after the default ones
+func parseImportDate(value string, layouts ...string) (time.Time, error) {
+	if serial, err := strconv.Atoi(value); err == nil && serial > 0 {
+		// Excel counts days from 1899-12-30
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, serial), nil
+	}
+	for _, layout := range append([]string{"2006-01-02", "02.01.2006", time.RFC3339, "2006-01-02 15:04:05"}, layouts...) {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date")
+}
+
+// parseImportNumber parses a number written with spaces between thousands and a decimal comma or point
+func parseImportNumber(value string) (float64, error) {
+	value = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(value)
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, errors.New("not a finite number")
+	}
+	return number, nil
+}
+
+// parseImportCount parses a whole number, empty value is zero
+func parseImportCount(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	number, err := parseImportNumber(value)
+	if err != nil || number != float64(int(number)) {
+		return 0, errors.New("not a whole number")
+	}
+	return int(number), nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+)
+
+// VKAdsService handles VK Ads cabinets of projects and syncs them as a connector
+// Campaigns are ad plans of the cabinet, costs are stored in the cabinet currency converted by the connector service
+type VKAdsService struct {
+	vkRepo  VKAdsRepositoryInterface
+	appKey  string
+	baseURL string // For testing: allows overriding VK Ads API URL
+
+	// VK Ads limits active tokens per client, so clients caching their token are kept per cabinet
+	mu      sync.Mutex
+	clients map[string]*vkAdsClient
+}
+
+// vkAdsClient is a cached client of a cabinet and the secret it was created with
+type vkAdsClient struct {
+	secret string
+	client *integrations.VKAdsClient
+}
+
+// NewVKAdsService creates a new VK Ads service
+func NewVKAdsService(vkRepo VKAdsRepositoryInterface, appKey string) *VKAdsService {
+	return &VKAdsService{
+		vkRepo:  vkRepo,
+		appKey:  appKey,
+		clients: make(map[string]*vkAdsClient),
+	}
+}
+
+// vkAdsData is raw data of a month returned by Fetch
+type vkAdsData struct {
+	Plans []integrations.VKAdPlan
+	Rows  []integrations.VKAdsStatRow
+}
+
+// CreateAccount binds a VK Ads cabinet to a project, the client secret is stored encrypted
+func (s *VKAdsService) CreateAccount(ctx context.Context, account *models.VKAdsAccount) error {
+	if account.ProjectID == 0 {
+		return errors.New("project_id is required")
+	}
+	account.ClientID = strings.TrimSpace(account.ClientID)
+	account.ClientSecret = strings.TrimSpace(account.ClientSecret)
+	if account.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+	if account.ClientSecret == "" {
+		return errors.New("client_secret is required")
+	}
+	if account.Currency == "" {
+		account.Currency = models.CurrencyRUB
+	}
+	if !models.IsValidCurrency(account.Currency) {
+		return errors.New("invalid currency")
+	}
+
+	existing, err := s.vkRepo.GetAccountByProjectID(ctx, account.ProjectID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("VK Ads account is already bound to this project")
+	}
+
+	secret, err := utils.EncryptString(s.appKey, account.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt client secret: %w", err)
+	}
+	account.ClientSecret = secret
+
+	return s.vkRepo.CreateAccount(ctx, account)
+}
+
+// GetAccountByProject retrieves VK Ads cabinet of a project, nil if the project has none
+func (s *VKAdsService) GetAccountByProject(ctx context.Context, projectID uint) (*models.VKAdsAccount, error) {
+	return s.vkRepo.GetAccountByProjectID(ctx, projectID)
+}
+
+// DeleteAccount unbinds VK Ads cabinet from a project
+// Already stored campaign metrics are kept
+func (s *VKAdsService) DeleteAccount(ctx context.Context, projectID uint) error {
+	account, err := s.vkRepo.GetAccountByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.New("VK Ads account not found")
+	}
+	if err := s.vkRepo.DeleteAccount(ctx, account.ID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.clients, account.ClientID)
+	s.mu.Unlock()
+	return nil
+}
+
+// Source implements connectors.Connector
+func (s *VKAdsService) Source() string {
+	return models.ChannelVKAds
+}
+
+// Periods implements connectors.Connector
+// Statistics of the current month are loaded by daily sync and replaced until the month is finalized
+func (s *VKAdsService) Periods() connectors.PeriodSupport {
+	return connectors.PeriodSupport{CurrentMonth: true}
+}
+
+// Credentials implements connectors.Connector with client credentials of the project cabinet
+func (s *VKAdsService) Credentials(ctx context.Context, projectID uint) (*connectors.Credentials, error) {
+	account, err := s.vkRepo.GetAccountByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, connectors.ErrNotConfigured
+	}
+
+	secret, err := utils.DecryptString(s.appKey, account.ClientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt client secret: %w", err)
+	}
+
+	return &connectors.Credentials{
+		Token:    secret,
+		Account:  account.ClientID,
+		Currency: account.Currency,
+	}, nil
+}
+
+// Fetch implements connectors.Connector: loads ad plans and their daily statistics for the month
+func (s *VKAdsService) Fetch(ctx context.Context, credentials *connectors.Credentials, period connectors.Period) (connectors.RawData, error) {
+	client := s.clientFor(credentials)
+
+	plans, err := client.GetAdPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return &vkAdsData{}, nil
+	}
+
+	ids := make([]int64, 0, len(plans))
+	for _, plan := range plans {
+		ids = append(ids, plan.ID)
+	}
+	dateFrom, dateTo := period.Range()
+	rows, err := client.GetAdPlanDailyStats(ctx, ids, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &vkAdsData{Plans: plans, Rows: rows}, nil
+}
+
+// clientFor returns the client of a cabinet, a new one if the cabinet is new or its secret has changed
+func (s *VKAdsService) clientFor(credentials *connectors.Credentials) *integrations.VKAdsClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.clients[credentials.Account]; ok && cached.secret == credentials.Token {
+		return cached.client
+	}
+
+	client := integrations.NewVKAdsClient(credentials.Account, credentials.Token)
+	if s.baseURL != "" {
+		client = integrations.NewVKAdsClientWithURL(credentials.Account, credentials.Token, s.baseURL)
+	}
+	s.clients[credentials.Account] = &vkAdsClient{secret: credentials.Token, client: client}
+	return client
+}
+
+// Normalize implements connectors.Connector: every ad plan is a campaign of the VK Ads channel
+func (s *VKAdsService) Normalize(raw connectors.RawData) ([]connectors.MetricRow, error) {
+	data, ok := raw.(*vkAdsData)
+	if !ok {
+		return nil, fmt.Errorf("unexpected VK Ads data: %T", raw)
+	}
+
+	names := make(map[int64]string, len(data.Plans))
+	for _, plan := range data.Plans {
+		names[plan.ID] = plan.Name
+	}
+
+	metrics := make([]connectors.MetricRow, 0, len(data.Rows))
+	for _, row := range data.Rows {
+		date, err := time.Parse("2006-01-02", row.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q of ad plan %d: %w", row.Date, row.AdPlanID, err)
+		}
+		conversions := int(row.Goals)
+		metrics = append(metrics, connectors.MetricRow{
+			Date:         date,
+			Channel:      models.ChannelVKAds,
+			CampaignID:   strconv.FormatInt(row.AdPlanID, 10),
+			CampaignName: names[row.AdPlanID],
+			Impressions:  int(row.Shows),
+			Clicks:       int(row.Clicks),
+			Cost:         row.Spent,
+			Conversions:  &conversions,
+		})
+	}
+	return metrics, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suprt/planica_bi/backend/internal/connectors"
+	"github.com/suprt/planica_bi/backend/internal/models"
+	"github.com/suprt/planica_bi/backend/pkg/utils"
+)
+
+// MockVKAdsRepository implements VKAdsRepositoryInterface over stored cabinets
+type MockVKAdsRepository struct {
+	Accounts []*models.VKAdsAccount
+}
+
+func (m *MockVKAdsRepository) CreateAccount(ctx context.Context, account *models.VKAdsAccount) error {
+	account.ID = uint(len(m.Accounts) + 1)
+	m.Accounts = append(m.Accounts, account)
+	return nil
+}
+
+func (m *MockVKAdsRepository) GetAccountByProjectID(ctx context.Context, projectID uint) (*models.VKAdsAccount, error) {
+	for _, account := range m.Accounts {
+		if account.ProjectID == projectID {
+			return account, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockVKAdsRepository) DeleteAccount(ctx context.Context, id uint) error {
+	for i, account := range m.Accounts {
+		if account.ID == id {
+			m.Accounts = append(m.Accounts[:i], m.Accounts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestVKAdsService_CreateAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		account     models.VKAdsAccount
+		existing    []*models.VKAdsAccount
+		wantErrText string
+	}{
+		{
+			name:    "успешная привязка кабинета",
+			account: models.VKAdsAccount{ProjectID: 1, ClientID: " client ", ClientSecret: "secret"},
+		},
+		{
+			name:        "без client_secret",
+			account:     models.VKAdsAccount{ProjectID: 1, ClientID: "client"},
+			wantErrText: "client_secret is required",
+		},
+		{
+			name:        "неизвестная валюта",
+			account:     models.VKAdsAccount{ProjectID: 1, ClientID: "client", ClientSecret: "secret", Currency: "XXX"},
+			wantErrText: "invalid currency",
+		},
+		{
+			name:        "кабинет уже привязан",
+			account:     models.VKAdsAccount{ProjectID: 1, ClientID: "client", ClientSecret: "secret"},
+			existing:    []*models.VKAdsAccount{{ID: 1, ProjectID: 1, ClientID: "other"}},
+			wantErrText: "VK Ads account is already bound to this project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockVKAdsRepository{Accounts: tt.existing}
+			service := NewVKAdsService(repo, testAppKey)
+
+			account := tt.account
+			err := service.CreateAccount(context.Background(), &account)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили %v", tt.wantErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if account.ClientID != "client" || account.Currency != models.CurrencyRUB {
+				t.Errorf("ожидались очищенный client_id и валюта RUB, получили %+v", account)
+			}
+			if secret, err := utils.DecryptString(testAppKey, account.ClientSecret); err != nil || secret != "secret" {
+				t.Errorf("client_secret должен храниться зашифрованным: %v", err)
+			}
+		})
+	}
+}
+
+func TestVKAdsService_Connector(t *testing.T) {
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/oauth2/token.json":
+			tokenRequests++
+			r.ParseForm()
+			if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token": "vk_token", "expires_in": 86400}`))
+		case "/api/v2/ad_plans.json":
+			w.Write([]byte(`{"count": 1, "items": [{"id": 101, "name": "Бренд", "status": "active"}]}`))
+		case "/api/v2/statistics/ad_plans/day.json":
+			if r.URL.Query().Get("date_from") != "2025-02-01" || r.URL.Query().Get("date_to") != "2025-02-28" {
+				t.Errorf("неверный период статистики: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"items": [{"id": 101, "rows": [
+				{"date": "2025-02-01", "base": {"shows": 1000, "clicks": 10, "goals": 1, "spent": "500.00"}},
+				{"date": "2025-02-02", "base": {"shows": 2000, "clicks": 30, "goals": 2, "spent": "700.00"}}
+			]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := &MockVKAdsRepository{}
+	service := NewVKAdsService(repo, testAppKey)
+	service.baseURL = server.URL
+
+	if _, err := service.Credentials(context.Background(), 1); !errors.Is(err, connectors.ErrNotConfigured) {
+		t.Fatalf("проект без кабинета должен пропускаться, получили %v", err)
+	}
+
+	if err := service.CreateAccount(context.Background(), &models.VKAdsAccount{ProjectID: 1, ClientID: "client", ClientSecret: "secret", Currency: "USD"}); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	credentials, err := service.Credentials(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if credentials.Token != "secret" || credentials.Currency != "USD" {
+		t.Errorf("неверные доступы: %+v", credentials)
+	}
+
+	raw, err := service.Fetch(context.Background(), credentials, connectors.Period{Year: 2025, Month: 2})
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	rows, err := service.Normalize(raw)
+	if err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("ожидалось 2 строки, получили %d", len(rows))
+	}
+	row := rows[1]
+	if row.Channel != models.ChannelVKAds || row.CampaignID != "101" || row.CampaignName != "Бренд" ||
+		row.Impressions != 2000 || row.Clicks != 30 || row.Cost != 700 || row.Conversions == nil || *row.Conversions != 2 ||
+		row.Date.Day() != 2 {
+		t.Errorf("неверная строка: %+v", row)
+	}
+
+	// The next sync of the cabinet reuses its token
+	if _, err := service.Fetch(context.Background(), credentials, connectors.Period{Year: 2025, Month: 2}); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("ожидался 1 запрос токена, получили %d", tokenRequests)
+	}
+}
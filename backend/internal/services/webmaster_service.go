@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/suprt/planica_bi/backend/internal/integrations"
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// WebmasterService handles business logic for Yandex.Webmaster hosts bound to projects
+type WebmasterService struct {
+	seoRepo     SEORepositoryInterface
+	credentials *OAuthCredentialService
+}
+
+// NewWebmasterService creates a new Webmaster service
+func NewWebmasterService(seoRepo SEORepositoryInterface, credentials *OAuthCredentialService) *WebmasterService {
+	return &WebmasterService{
+		seoRepo:     seoRepo,
+		credentials: credentials,
+	}
+}
+
+// BindHost binds a Webmaster host to a project
+// HostURL is derived from host id when not provided
+func (s *WebmasterService) BindHost(ctx context.Context, host *models.WebmasterHost) error {
+	// Validate required fields
+	if host.ProjectID == 0 {
+		return errors.New("project_id is required")
+	}
+	host.HostID = strings.TrimSpace(host.HostID)
+	if host.HostID == "" {
+		return errors.New("host_id is required")
+	}
+
+	existing, err := s.seoRepo.GetHostByHostID(ctx, host.ProjectID, host.HostID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("host is already bound to this project")
+	}
+
+	if host.HostURL == "" {
+		host.HostURL = hostURLFromID(host.HostID)
+	}
+
+	return s.seoRepo.CreateHost(ctx, host)
+}
+
+// GetHostsByProject retrieves Webmaster hosts bound to a project
+func (s *WebmasterService) GetHostsByProject(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error) {
+	return s.seoRepo.GetHostsByProjectID(ctx, projectID)
+}
+
+// DeleteHost unbinds a Webmaster host from a project
+// Already stored SEO queries are kept
+func (s *WebmasterService) DeleteHost(ctx context.Context, projectID uint, id uint) error {
+	host, err := s.seoRepo.GetHostByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if host == nil || host.ProjectID != projectID {
+		return errors.New("host not found")
+	}
+	return s.seoRepo.DeleteHost(ctx, id)
+}
+
+// GetAvailableHosts lists sites available in Webmaster for the project token
+// credentialID selects a specific credential, nil uses the project default
+func (s *WebmasterService) GetAvailableHosts(ctx context.Context, projectID uint, credentialID *uint) ([]integrations.WebmasterHost, error) {
+	token, err := s.credentials.ResolveToken(ctx, projectID, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := integrations.NewYandexWebmasterClient(token)
+	userID, err := client.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Webmaster user: %w", err)
+	}
+
+	return client.GetHosts(ctx, userID)
+}
+
+// hostURLFromID converts Webmaster host id (scheme:host:port) to site URL
+// Default ports are omitted: "https:example.com:443" -> "https://example.com/"
+func hostURLFromID(hostID string) string {
+	parts := strings.Split(hostID, ":")
+	if len(parts) != 3 {
+		return ""
+	}
+	scheme, hostname, port := parts[0], parts[1], parts[2]
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		return fmt.Sprintf("%s://%s/", scheme, hostname)
+	}
+	return fmt.Sprintf("%s://%s:%s/", scheme, hostname, port)
+}
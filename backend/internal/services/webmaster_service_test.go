@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/suprt/planica_bi/backend/internal/models"
+)
+
+// MockSEORepository implements SEORepositoryInterface for testing
+type MockSEORepository struct {
+	GetSEOQueriesFunc       func(ctx context.Context, projectID uint, year int, month int) ([]*models.SEOQueriesMonthly, error)
+	ReplaceSEOQueriesFunc   func(ctx context.Context, projectID uint, year int, month int, queries []*models.SEOQueriesMonthly) error
+	CreateHostFunc          func(ctx context.Context, host *models.WebmasterHost) error
+	GetHostsByProjectIDFunc func(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error)
+	GetHostByHostIDFunc     func(ctx context.Context, projectID uint, hostID string) (*models.WebmasterHost, error)
+	GetHostByIDFunc         func(ctx context.Context, id uint) (*models.WebmasterHost, error)
+	DeleteHostFunc          func(ctx context.Context, id uint) error
+}
+
+func (m *MockSEORepository) GetSEOQueries(ctx context.Context, projectID uint, year int, month int) ([]*models.SEOQueriesMonthly, error) {
+	if m.GetSEOQueriesFunc != nil {
+		return m.GetSEOQueriesFunc(ctx, projectID, year, month)
+	}
+	return nil, nil
+}
+
+func (m *MockSEORepository) ReplaceSEOQueries(ctx context.Context, projectID uint, year int, month int, queries []*models.SEOQueriesMonthly) error {
+	if m.ReplaceSEOQueriesFunc != nil {
+		return m.ReplaceSEOQueriesFunc(ctx, projectID, year, month, queries)
+	}
+	return nil
+}
+
+func (m *MockSEORepository) CreateHost(ctx context.Context, host *models.WebmasterHost) error {
+	if m.CreateHostFunc != nil {
+		return m.CreateHostFunc(ctx, host)
+	}
+	return nil
+}
+
+func (m *MockSEORepository) GetHostsByProjectID(ctx context.Context, projectID uint) ([]*models.WebmasterHost, error) {
+	if m.GetHostsByProjectIDFunc != nil {
+		return m.GetHostsByProjectIDFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockSEORepository) GetHostByHostID(ctx context.Context, projectID uint, hostID string) (*models.WebmasterHost, error) {
+	if m.GetHostByHostIDFunc != nil {
+		return m.GetHostByHostIDFunc(ctx, projectID, hostID)
+	}
+	return nil, nil
+}
+
+func (m *MockSEORepository) GetHostByID(ctx context.Context, id uint) (*models.WebmasterHost, error) {
+	if m.GetHostByIDFunc != nil {
+		return m.GetHostByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockSEORepository) DeleteHost(ctx context.Context, id uint) error {
+	if m.DeleteHostFunc != nil {
+		return m.DeleteHostFunc(ctx, id)
+	}
+	return nil
+}
+
+func TestWebmasterService_BindHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        *models.WebmasterHost
+		existing    *models.WebmasterHost
+		wantErrText string
+		wantHostURL string
+	}{
+		{
+			name:        "успешная привязка, URL из host_id",
+			host:        &models.WebmasterHost{ProjectID: 1, HostID: " https:example.com:443 "},
+			wantHostURL: "https://example.com/",
+		},
+		{
+			name:        "нестандартный порт",
+			host:        &models.WebmasterHost{ProjectID: 1, HostID: "http:example.com:8080"},
+			wantHostURL: "http://example.com:8080/",
+		},
+		{
+			name:        "URL передан явно",
+			host:        &models.WebmasterHost{ProjectID: 1, HostID: "https:example.com:443", HostURL: "https://www.example.com/"},
+			wantHostURL: "https://www.example.com/",
+		},
+		{
+			name:        "пустой host_id",
+			host:        &models.WebmasterHost{ProjectID: 1},
+			wantErrText: "host_id is required",
+		},
+		{
+			name:        "хост уже привязан",
+			host:        &models.WebmasterHost{ProjectID: 1, HostID: "https:example.com:443"},
+			existing:    &models.WebmasterHost{ID: 3, ProjectID: 1, HostID: "https:example.com:443"},
+			wantErrText: "host is already bound to this project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *models.WebmasterHost
+			repo := &MockSEORepository{
+				GetHostByHostIDFunc: func(ctx context.Context, projectID uint, hostID string) (*models.WebmasterHost, error) {
+					return tt.existing, nil
+				},
+				CreateHostFunc: func(ctx context.Context, host *models.WebmasterHost) error {
+					created = host
+					return nil
+				},
+			}
+			service := NewWebmasterService(repo, nil)
+
+			err := service.BindHost(context.Background(), tt.host)
+
+			if tt.wantErrText != "" {
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Errorf("ожидалась ошибка '%s', но получили '%v'", tt.wantErrText, err)
+				}
+				if created != nil {
+					t.Errorf("хост не должен быть создан")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+			}
+			if created == nil || created.HostURL != tt.wantHostURL {
+				t.Errorf("ожидался URL %q, получили %+v", tt.wantHostURL, created)
+			}
+			if created != nil && created.HostID != "https:example.com:443" && created.HostID != "http:example.com:8080" {
+				t.Errorf("host_id не очищен от пробелов: %q", created.HostID)
+			}
+		})
+	}
+}
+
+func TestWebmasterService_DeleteHost(t *testing.T) {
+	deleted := false
+	repo := &MockSEORepository{
+		GetHostByIDFunc: func(ctx context.Context, id uint) (*models.WebmasterHost, error) {
+			return &models.WebmasterHost{ID: id, ProjectID: 1}, nil
+		},
+		DeleteHostFunc: func(ctx context.Context, id uint) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := NewWebmasterService(repo, nil)
+
+	if err := service.DeleteHost(context.Background(), 2, 5); err == nil || err.Error() != "host not found" {
+		t.Errorf("ожидалась ошибка 'host not found' для чужого проекта, получили %v", err)
+	}
+	if deleted {
+		t.Fatalf("хост чужого проекта не должен удаляться")
+	}
+
+	if err := service.DeleteHost(context.Background(), 1, 5); err != nil {
+		t.Fatalf("не ожидалась ошибка, но получили: %v", err)
+	}
+	if !deleted {
+		t.Errorf("хост не удалён")
+	}
+}
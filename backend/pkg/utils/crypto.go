@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrEmptyKey is returned when encryption is requested without APP_KEY configured
+var ErrEmptyKey = errors.New("encryption key is empty (set APP_KEY)")
+
+// EncryptString encrypts plaintext with AES-256-GCM using a key derived from appKey
+// Returns base64-encoded nonce+ciphertext suitable for storing in a text column
+func EncryptString(appKey, plaintext string) (string, error) {
+	gcm, err := newGCM(appKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString decrypts a value produced by EncryptString
+func DecryptString(appKey, encoded string) (string, error) {
+	gcm, err := newGCM(appKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// newGCM builds AES-GCM cipher from SHA-256 of the application key
+func newGCM(appKey string) (cipher.AEAD, error) {
+	if appKey == "" {
+		return nil, ErrEmptyKey
+	}
+
+	key := sha256.Sum256([]byte(appKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedTable is returned by ReadTable for files other than CSV and XLSX
+var ErrUnsupportedTable = errors.New("unsupported file format, expected .csv or .xlsx")
+
+// ReadTable reads rows of a CSV file or of the first sheet of an XLSX file
+// The format is selected by the file name extension; CSV may be separated by commas or semicolons
+func ReadTable(fileName string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return readCSV(r)
+	case ".xlsx":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedTable
+	}
+}
+
+// readCSV reads CSV rows, the separator is detected by the header line
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM added by Excel
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	return rows, nil
+}
+
+// xlsxRelationships is xl/_rels/workbook.xml.rels
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxWorkbook is xl/workbook.xml
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxText is a plain or rich text string of a cell
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String joins text of all runs
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// xlsxSheet is a worksheet of an XLSX file
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"` // Cell reference, e.g. B3
+			Type   string   `xml:"t,attr"` // s - shared string, inlineStr, str, b, n (default)
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads values of the first sheet of an XLSX file
+// Only values are read: dates are returned as serial numbers and formulas as their cached results
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("failed to parse XLSX: workbook has no sheets")
+	}
+	var relationships xlsxRelationships
+	if err := decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range relationships.Relationships {
+		if rel.ID == workbook.Sheets[0].RelationshipID {
+			sheetPath = rel.Target
+		}
+	}
+	if strings.HasPrefix(sheetPath, "/") {
+		sheetPath = strings.TrimPrefix(sheetPath, "/")
+	} else {
+		sheetPath = path.Join("xl", sheetPath)
+	}
+
+	var sharedStrings struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(files, "xl/sharedStrings.xml", &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxSheet
+	if err := decodeXLSXPart(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, sheetRow := range sheet.Rows {
+		var row []string
+		for _, cell := range sheetRow.Cells {
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("failed to parse XLSX: invalid shared string in cell %s", cell.Ref)
+				}
+				value = sharedStrings.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+
+			column := len(row)
+			if cell.Ref != "" {
+				if column, err = xlsxColumn(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(row) < column {
+				row = append(row, "")
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeXLSXPart decodes an XML part of an XLSX archive
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("failed to parse XLSX: %s not found", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to parse XLSX: %w", err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(reader).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse XLSX %s: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxColumn returns a zero-based column index of a cell reference: "A1" -> 0, "AB12" -> 27
+func xlsxColumn(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("failed to parse XLSX: invalid cell reference %s", ref)
+	}
+	return column - 1, nil
+}
@@ -204,8 +204,12 @@ const Dashboard: React.FC = () => {
         toggleTheme();
     }, [toggleTheme]);
 
-    const handleYandexAuth = useCallback(() => {
-        oauthService.initiateYandexAuth();
+    const handleYandexAuth = useCallback(async () => {
+        try {
+            await oauthService.initiateYandexAuth();
+        } catch (error: any) {
+            console.error('[Dashboard] Failed to initiate Yandex OAuth:', error.response?.data || error.message);
+        }
     }, []);
 
     const handleNavItemClick = useCallback((itemId: string) => {
@@ -17,10 +17,11 @@ export const oauthService = {
         }
     },
     
-    initiateYandexAuth(): void {
-        // Redirect to backend OAuth endpoint
-        const backendURL = process.env.REACT_APP_API_URL || 'http://localhost:8080/api';
-        window.location.href = `${backendURL}/oauth/yandex`;
+    async initiateYandexAuth(projectId?: number): Promise<void> {
+        // Backend requires authorization, so it returns Yandex URL instead of redirecting
+        const path = projectId ? `/projects/${projectId}/oauth/yandex` : '/oauth/yandex';
+        const response = await api.get<{ url: string }>(path);
+        window.location.href = response.data.url;
     },
 };
 